@@ -0,0 +1,16 @@
+/*
+  Разовое заполнение user_owned из ai_products.owner для ИИ, созданных до событий владения.
+  Не входит в docker-entrypoint-initdb.d: запускается вручную на users_db, повторный запуск ничего не меняет.
+  docker exec -i users-database psql -U user -d users_db < migrations/backfill-users-owned.sql
+*/
+
+CREATE EXTENSION IF NOT EXISTS dblink;
+
+INSERT INTO user_owned (ai_id, user_id)
+SELECT ai.id, ai.owner
+FROM dblink(
+  'port=5432 user=ai dbname=ai_db host=db-ai password=aipass',
+  'SELECT id, owner FROM ai_products WHERE archived_at IS NULL'
+) AS ai(id uuid, owner uuid)
+JOIN users ON users.id = ai.owner
+ON CONFLICT (user_id, ai_id) DO NOTHING;
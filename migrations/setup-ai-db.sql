@@ -14,7 +14,8 @@ CREATE TABLE IF NOT EXISTS ai_products (
   used INTEGER NOT NULL DEFAULT 0,
   background_url VARCHAR(255) NOT NULL,
   updated_at TIMESTAMP DEFAULT now() NOT NULL,
-  created_at TIMESTAMP DEFAULT now() NOT NULL
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  archived_at TIMESTAMP
 );
 
 CREATE OR REPLACE FUNCTION update_updated_at_ai_product()
@@ -68,8 +69,11 @@ CREATE TABLE IF NOT EXISTS ai_rates (
   by_user_id uuid NOT NULL,
   ai_id uuid NOT NULL,
   rate INTEGER CHECK (rate >= 0 AND rate <= 5),
+  verified_usage BOOLEAN NOT NULL DEFAULT FALSE,
+  new_account BOOLEAN NOT NULL DEFAULT FALSE,
   created_at TIMESTAMP DEFAULT now() NOT NULL,
-  updated_at TIMESTAMP DEFAULT now() NOT NULL
+  updated_at TIMESTAMP DEFAULT now() NOT NULL,
+  deleted_at TIMESTAMP
 );
 
 CREATE OR REPLACE FUNCTION update_updated_at_ai_rate()
@@ -85,4 +89,38 @@ CREATE TRIGGER update_rate_updated_at
     ON
         ai_rates
     FOR EACH ROW
-EXECUTE PROCEDURE update_updated_at_ai_rate();
\ No newline at end of file
+EXECUTE PROCEDURE update_updated_at_ai_rate();
+
+CREATE TABLE IF NOT EXISTS ai_rate_flags (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  rate_id INTEGER REFERENCES ai_rates(id) ON DELETE CASCADE,
+  ai_id uuid REFERENCES ai_products(id) ON DELETE CASCADE,
+  flagged_by uuid,
+  source VARCHAR(10) NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  status VARCHAR(10) NOT NULL DEFAULT 'pending',
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (rate_id, source)
+);
+
+-- EXECUTIONS
+CREATE TABLE IF NOT EXISTS ai_executions (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  ai_id uuid REFERENCES ai_products(id) ON DELETE CASCADE,
+  command_id uuid NOT NULL,
+  user_id uuid NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ai_executions_ai_user_idx ON ai_executions (ai_id, user_id);
+
+-- AUDIT
+CREATE TABLE IF NOT EXISTS ai_product_audits (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  ai_id uuid REFERENCES ai_products(id) ON DELETE CASCADE,
+  changed_by uuid NOT NULL,
+  field VARCHAR(40) NOT NULL,
+  old_value TEXT NOT NULL DEFAULT '',
+  new_value TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT now() NOT NULL
+);
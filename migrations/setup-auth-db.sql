@@ -15,4 +15,56 @@ CREATE TABLE IF NOT EXISTS verification_tokens (
   token VARCHAR(255) NOT NULL,
   expires_at TIMESTAMP DEFAULT now() + INTERVAL '10 minutes' NOT NULL,
   created_at TIMESTAMP DEFAULT now() NOT NULL
-);
\ No newline at end of file
+);
+
+-- Для фоновой чистки протухших токенов
+CREATE INDEX IF NOT EXISTS reset_tokens_expires_at_idx ON reset_tokens (expires_at);
+CREATE INDEX IF NOT EXISTS verification_tokens_expires_at_idx ON verification_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS oidc_identities (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  user_id uuid NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (issuer, subject)
+);
+
+CREATE TABLE IF NOT EXISTS two_factors (
+  user_id uuid PRIMARY KEY,
+  secret VARCHAR(64) NOT NULL,
+  enabled BOOLEAN DEFAULT false NOT NULL,
+  last_used_step BIGINT DEFAULT 0 NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  updated_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL REFERENCES two_factors (user_id) ON DELETE CASCADE,
+  code_hash VARCHAR(64) NOT NULL,
+  used_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_user_id_idx ON recovery_codes (user_id);
+
+-- Журнал безопасности только пополняется: изменения и удаления молча игнорируются
+CREATE TABLE IF NOT EXISTS audit_events (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  actor_id uuid,
+  action VARCHAR(64) NOT NULL,
+  target VARCHAR(255) NOT NULL,
+  ip VARCHAR(64),
+  user_agent VARCHAR(512),
+  result VARCHAR(16) NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_created_at_idx ON audit_events (created_at);
+CREATE INDEX IF NOT EXISTS audit_events_actor_id_idx ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target);
+
+CREATE OR REPLACE RULE audit_events_no_update AS ON UPDATE TO audit_events DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE audit_events_no_delete AS ON DELETE TO audit_events DO INSTEAD NOTHING;
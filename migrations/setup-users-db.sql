@@ -9,29 +9,162 @@ CREATE TABLE IF NOT EXISTS users (
   lastname VARCHAR(255) NOT NULL,
   username VARCHAR(255) NOT NULL UNIQUE,
   picture VARCHAR(255),
+  bio VARCHAR(500),
   password VARCHAR(72) NOT NULL,
   email VARCHAR(255) NOT NULL UNIQUE,
   verified BOOLEAN NOT NULL DEFAULT FALSE,
   via_google BOOLEAN NOT NULL DEFAULT FALSE,
   is_developer BOOLEAN NOT NULL DEFAULT FALSE,
+  email_digest BOOLEAN NOT NULL DEFAULT FALSE,
   created_at TIMESTAMP DEFAULT now() NOT NULL,
   updated_at TIMESTAMP DEFAULT now() NOT NULL
 );
 
 CREATE TABLE IF NOT EXISTS user_favorites (
-  id INTEGER PRIMARY KEY,
+  id SERIAL PRIMARY KEY,
   ai_id uuid NOT NULL,
   user_id uuid NOT NULL,
+  UNIQUE (user_id, ai_id),
   FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS favorite_collections (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL,
+  name VARCHAR(64) NOT NULL,
+  position INTEGER DEFAULT 0 NOT NULL,
+  is_public BOOLEAN DEFAULT FALSE NOT NULL,
+  share_token VARCHAR(64) UNIQUE,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  updated_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS favorite_collection_items (
+  id SERIAL PRIMARY KEY,
+  collection_id uuid NOT NULL,
+  ai_id uuid NOT NULL,
+  position INTEGER DEFAULT 0 NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (collection_id, ai_id),
+  FOREIGN KEY (collection_id) REFERENCES favorite_collections(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS favorite_collection_items_page_idx ON favorite_collection_items (collection_id, position, id);
+
 CREATE TABLE IF NOT EXISTS user_owned (
-  id INTEGER PRIMARY KEY,
+  id SERIAL PRIMARY KEY,
   ai_id uuid NOT NULL,
   user_id uuid NOT NULL,
+  UNIQUE (user_id, ai_id),
   FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS user_follows (
+  id SERIAL PRIMARY KEY,
+  follower_id uuid NOT NULL,
+  developer_id uuid NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (follower_id, developer_id),
+  FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (developer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS user_follows_developer_idx ON user_follows (developer_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id SERIAL PRIMARY KEY,
+  user_id uuid NOT NULL,
+  type VARCHAR(32) NOT NULL,
+  event_id VARCHAR(128) NOT NULL,
+  data JSON NOT NULL,
+  read_at TIMESTAMP,
+  emailed_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (user_id, event_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, id);
+
+CREATE TABLE IF NOT EXISTS email_changes (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id uuid NOT NULL UNIQUE,
+  new_email VARCHAR(255) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  expires_at TIMESTAMP DEFAULT now() + INTERVAL '1 hour' NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Первого администратора выдаём вручную:
+-- INSERT INTO user_roles (user_id, role, granted_by) VALUES ('<user id>', 'ADMIN', '<user id>');
+CREATE TABLE IF NOT EXISTS user_roles (
+  id SERIAL PRIMARY KEY,
+  user_id uuid NOT NULL,
+  role VARCHAR(32) NOT NULL,
+  granted_by uuid NOT NULL,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (user_id, role),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS developer_applications (
+  id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  user_id uuid NOT NULL UNIQUE,
+  display_name VARCHAR(64) NOT NULL,
+  website VARCHAR(255),
+  terms_accepted_at TIMESTAMP NOT NULL,
+  status VARCHAR(16) DEFAULT 'pending' NOT NULL,
+  reviewed_by uuid,
+  comment VARCHAR(500),
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  updated_at TIMESTAMP DEFAULT now() NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Удаление аккаунта: записи переживают пользователя, поэтому без внешнего ключа на users
+CREATE TABLE IF NOT EXISTS account_deletions (
+  id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  user_id uuid NOT NULL,
+  status VARCHAR(16) DEFAULT 'scheduled' NOT NULL,
+  step VARCHAR(16),
+  error VARCHAR(500),
+  scheduled_for TIMESTAMP NOT NULL,
+  confirm_token_hash VARCHAR(64) UNIQUE,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  updated_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS account_deletions_active_idx ON account_deletions (user_id) WHERE status IN ('scheduled', 'in_progress', 'compensating');
+CREATE INDEX IF NOT EXISTS account_deletions_due_idx ON account_deletions (scheduled_for) WHERE status = 'scheduled';
+
+CREATE TABLE IF NOT EXISTS account_deletion_steps (
+  id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  deletion_id uuid NOT NULL,
+  step VARCHAR(16) NOT NULL,
+  status VARCHAR(16) DEFAULT 'pending' NOT NULL,
+  error VARCHAR(500),
+  updated_at TIMESTAMP DEFAULT now() NOT NULL,
+  UNIQUE (deletion_id, step),
+  FOREIGN KEY (deletion_id) REFERENCES account_deletions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+  id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  user_id uuid NOT NULL,
+  status VARCHAR(16) DEFAULT 'pending' NOT NULL,
+  file_key VARCHAR(255),
+  error VARCHAR(500),
+  expires_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  updated_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_idx ON data_exports (user_id, created_at);
+CREATE INDEX IF NOT EXISTS data_exports_expires_idx ON data_exports (expires_at) WHERE status = 'ready';
+
 CREATE OR REPLACE FUNCTION update_updated_at_users()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -0,0 +1,9 @@
+package adapter
+
+import m "warehouseai/ai/model"
+
+type EventProducerInterface interface {
+	SendOwnershipEvent(event m.AiOwnershipEvent) error
+	SendReleaseEvent(event m.AiReleaseEvent) error
+	SendRatingEvent(event m.AiRatingEvent) error
+}
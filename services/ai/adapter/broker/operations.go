@@ -0,0 +1,157 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"warehouseai/ai/dataservice"
+	m "warehouseai/ai/model"
+	"warehouseai/ai/service/account"
+
+	rmq "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+)
+
+type Broker struct {
+	Connection *rmq.Connection
+	Channel    *rmq.Channel
+}
+
+func (b Broker) SendDeletionReply(reply m.AccountDeletionReply) error {
+	messageStr, err := json.Marshal(reply)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.AccountDeleteReply),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b Broker) SendOwnershipEvent(event m.AiOwnershipEvent) error {
+	messageStr, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.AiOwnership),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b Broker) SendReleaseEvent(event m.AiReleaseEvent) error {
+	messageStr, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.AiRelease),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b Broker) SendRatingEvent(event m.AiRatingEvent) error {
+	messageStr, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.AiRating),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Сообщение подтверждается только после отправки ответа, шаги идемпотентны
+func (b Broker) ReceiveAccountDeletion(ai dataservice.AiInterface, rating dataservice.RatingInterface, logger *logrus.Logger) {
+	messages, err := b.Channel.Consume(
+		string(m.AccountDeleteAi),
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for message := range messages {
+		var command m.AccountDeletionCommand
+
+		if err := json.Unmarshal(message.Body, &command); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Account deletion")
+			message.Nack(false, false)
+			continue
+		}
+
+		reply := account.HandleAccountDeletion(command, ai, rating, b, logger)
+
+		if reply != nil {
+			if err := b.SendDeletionReply(*reply); err != nil {
+				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Account deletion")
+				message.Nack(false, true)
+				continue
+			}
+		}
+
+		message.Ack(false)
+	}
+}
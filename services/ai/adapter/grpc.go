@@ -1,11 +1,17 @@
 package adapter
 
-import e "warehouseai/ai/errors"
+import (
+	"warehouseai/ai/adapter/grpc/gen"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+)
 
 type AuthGrpcInterface interface {
-	Authenticate(sessionId string) (string, string, *e.HttpErrorResponse)
+	Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.HttpErrorResponse)
+	VerifyAccessToken(token string) (*m.AccessClaims, *e.HttpErrorResponse)
 }
 
 type UserGrpcInterface interface {
 	GetFavorite(aiId string, userId string) (bool, *e.HttpErrorResponse)
+	GetById(userId string) (*gen.User, *e.HttpErrorResponse)
 }
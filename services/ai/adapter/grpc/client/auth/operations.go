@@ -2,8 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"sync"
+	"time"
 	"warehouseai/ai/adapter/grpc/gen"
 	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -13,6 +17,11 @@ import (
 
 type AuthGrpcClient struct {
 	conn *grpc.ClientConn
+
+	// Публичные ключи auth для локальной проверки access-токенов
+	keysMu        sync.Mutex
+	keys          map[string]ed25519.PublicKey
+	keysFetchedAt time.Time
 }
 
 func NewAuthGrpcClient(grpcUrl string) *AuthGrpcClient {
@@ -27,7 +36,7 @@ func NewAuthGrpcClient(grpcUrl string) *AuthGrpcClient {
 	}
 }
 
-func (c *AuthGrpcClient) Authenticate(sessionId string) (string, string, *e.HttpErrorResponse) {
+func (c *AuthGrpcClient) Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.HttpErrorResponse) {
 	client := gen.NewAuthServiceClient(c.conn)
 	resp, err := client.Authenticate(context.Background(), &gen.AuthenticationRequest{SessionId: sessionId})
 
@@ -35,11 +44,22 @@ func (c *AuthGrpcClient) Authenticate(sessionId string) (string, string, *e.Http
 		s, _ := status.FromError(err)
 
 		if s.Code() == codes.Aborted {
-			return "", "", e.NewErrorResponse(e.HttpUnauthorized, s.Message())
+			return nil, e.NewErrorResponse(e.HttpUnauthorized, s.Message())
 		}
 
-		return "", "", e.NewErrorResponse(e.HttpInternalError, s.Message())
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return resp, nil
+}
+
+// Проверяет access-токен без обращения к auth (кроме редкого обновления ключей)
+func (c *AuthGrpcClient) VerifyAccessToken(token string) (*m.AccessClaims, *e.HttpErrorResponse) {
+	claims, err := verifyAccessToken(token, c.publicKey, time.Now())
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpUnauthorized, err.Error())
 	}
 
-	return resp.UserId, resp.SessionId, nil
+	return claims, nil
 }
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	m "warehouseai/ai/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testKeys struct {
+	kid     string
+	private ed25519.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &testKeys{kid: "test-kid", private: private}
+}
+
+func (k *testKeys) lookup(kid string) (ed25519.PublicKey, error) {
+	if kid != k.kid {
+		return nil, errors.New("unknown key id")
+	}
+
+	return k.private.Public().(ed25519.PublicKey), nil
+}
+
+func (k *testKeys) sign(t *testing.T, alg string, claims m.AccessClaims) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": k.kid})
+	payload, _ := json.Marshal(claims)
+
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(k.private, []byte(input)))
+}
+
+func validClaims(now time.Time) m.AccessClaims {
+	return m.AccessClaims{
+		Id:          "token-id",
+		Issuer:      accessTokenIssuer,
+		Subject:     "user-id",
+		Roles:       []string{"BASE", "DEVELOPER"},
+		IsDeveloper: true,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	keys := newTestKeys(t)
+	now := time.Now()
+
+	claims, err := verifyAccessToken(keys.sign(t, accessTokenAlgorithm, validClaims(now)), keys.lookup, now)
+
+	require.NoError(t, err)
+	require.Equal(t, "user-id", claims.Subject)
+	require.Equal(t, []string{"BASE", "DEVELOPER"}, claims.Roles)
+	require.True(t, claims.IsDeveloper)
+}
+
+func TestVerifyAccessTokenRejects(t *testing.T) {
+	keys := newTestKeys(t)
+	other := newTestKeys(t)
+	now := time.Now()
+
+	expired := validClaims(now.Add(-time.Hour))
+	wrongIssuer := validClaims(now)
+	wrongIssuer.Issuer = "someone-else"
+
+	valid := keys.sign(t, accessTokenAlgorithm, validClaims(now))
+	parts := strings.Split(valid, ".")
+	forged, _ := json.Marshal(m.AccessClaims{Issuer: accessTokenIssuer, Subject: "admin-id", ExpiresAt: now.Add(time.Hour).Unix()})
+
+	cases := map[string]string{
+		"expired":         keys.sign(t, accessTokenAlgorithm, expired),
+		"wrong issuer":    keys.sign(t, accessTokenAlgorithm, wrongIssuer),
+		"wrong algorithm": keys.sign(t, "none", validClaims(now)),
+		// Тот же kid, но другой ключ
+		"foreign key":     other.sign(t, accessTokenAlgorithm, validClaims(now)),
+		"tampered claims": parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2],
+		"no signature":    parts[0] + "." + parts[1] + ".",
+		"malformed":       "not-a-token",
+	}
+
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			claims, err := verifyAccessToken(token, keys.lookup, now)
+
+			require.Error(t, err)
+			require.Nil(t, claims)
+		})
+	}
+}
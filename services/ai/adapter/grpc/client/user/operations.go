@@ -42,3 +42,20 @@ func (s *UserGrpcClient) GetFavorite(aiId string, userId string) (bool, *e.HttpE
 
 	return true, nil
 }
+
+func (s *UserGrpcClient) GetById(userId string) (*gen.User, *e.HttpErrorResponse) {
+	client := gen.NewUserServiceClient(s.conn)
+	resp, err := client.GetUserById(context.Background(), &gen.GetUserByIdMsg{UserId: userId})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+
+		if s.Code() == codes.NotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, s.Message())
+		}
+
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return resp, nil
+}
@@ -88,3 +88,60 @@ func ProtoToCommand(cmd *gen.Command) m.AiCommand {
 		UpdatedAt:   updatedAt,
 	}
 }
+
+func UserDataToProto(ownedAis []m.AiProduct, ratings []m.AiRate, executions []m.AiExecution) *gen.UserDataResponse {
+	response := &gen.UserDataResponse{
+		Owned:      make([]*gen.OwnedAi, 0, len(ownedAis)),
+		Ratings:    make([]*gen.UserRating, 0, len(ratings)),
+		Executions: make([]*gen.UserExecution, 0, len(executions)),
+	}
+
+	for _, ai := range ownedAis {
+		response.Owned = append(response.Owned, &gen.OwnedAi{
+			Id:          ai.ID.String(),
+			Name:        ai.Name,
+			Description: ai.Description,
+			Used:        int64(ai.Used),
+			CreatedAt:   ai.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	for _, rate := range ratings {
+		response.Ratings = append(response.Ratings, &gen.UserRating{
+			AiId:          rate.AiId.String(),
+			Rate:          int32(rate.Rate),
+			VerifiedUsage: rate.VerifiedUsage,
+			CreatedAt:     rate.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	for _, execution := range executions {
+		response.Executions = append(response.Executions, &gen.UserExecution{
+			Id:        execution.ID.String(),
+			AiId:      execution.AiId.String(),
+			CommandId: execution.CommandId.String(),
+			CreatedAt: execution.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return response
+}
+
+func PublishedAisToProto(ais []m.PublishedAi) *gen.PublishedAisResponse {
+	response := &gen.PublishedAisResponse{Ais: make([]*gen.PublishedAi, 0, len(ais))}
+
+	for _, ai := range ais {
+		response.Ais = append(response.Ais, &gen.PublishedAi{
+			Id:            ai.ID.String(),
+			Name:          ai.Name,
+			Description:   ai.Description,
+			BackgroundUrl: ai.BackgroundUrl,
+			Used:          int64(ai.Used),
+			AvgRating:     ai.AverageRating,
+			CountRating:   ai.RatingCount,
+			CreatedAt:     ai.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return response
+}
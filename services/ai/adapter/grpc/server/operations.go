@@ -6,6 +6,7 @@ import (
 	"warehouseai/ai/adapter/grpc/mapper"
 	"warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
+	"warehouseai/ai/service/account"
 	"warehouseai/ai/service/ai"
 
 	"github.com/sirupsen/logrus"
@@ -15,8 +16,10 @@ import (
 
 type AiGrpcServer struct {
 	gen.UnimplementedAiServiceServer
-	DB     dataservice.AiInterface
-	Logger *logrus.Logger
+	DB          dataservice.AiInterface
+	RatingDB    dataservice.RatingInterface
+	ExecutionDB dataservice.ExecutionInterface
+	Logger      *logrus.Logger
 }
 
 func (s *AiGrpcServer) GetAiById(ctx context.Context, req *gen.GetAiByIdMsg) (*gen.AI, error) {
@@ -36,3 +39,33 @@ func (s *AiGrpcServer) GetAiById(ctx context.Context, req *gen.GetAiByIdMsg) (*g
 
 	return mapper.AiToProto(&ai.AiProduct), nil
 }
+
+// Вызывается сервисом пользователей при выгрузке персональных данных
+func (s *AiGrpcServer) GetUserData(ctx context.Context, req *gen.UserDataRequest) (*gen.UserDataResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "Empty request data")
+	}
+
+	data, err := account.GetUserData(req.UserId, s.DB, s.RatingDB, s.ExecutionDB, s.Logger)
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.ErrorMessage[0])
+	}
+
+	return mapper.UserDataToProto(data.Owned, data.Ratings, data.Executions), nil
+}
+
+// Вызывается сервисом пользователей для публичного профиля разработчика
+func (s *AiGrpcServer) GetPublishedAis(ctx context.Context, req *gen.PublishedAisRequest) (*gen.PublishedAisResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "Empty request data")
+	}
+
+	ais, err := ai.GetPublished(req.Ids, s.DB, s.RatingDB, s.Logger)
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.ErrorMessage[0])
+	}
+
+	return mapper.PublishedAisToProto(*ais), nil
+}
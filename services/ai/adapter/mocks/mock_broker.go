@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/ai/adapter/broker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/ai/adapter/broker.go -destination=services/ai/adapter/mocks/mock_broker.go
+//
+// Package mock_adapter is a generated GoMock package.
+package mock_adapter
+
+import (
+	reflect "reflect"
+	model "warehouseai/ai/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventProducerInterface is a mock of EventProducerInterface interface.
+type MockEventProducerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventProducerInterfaceMockRecorder
+}
+
+// MockEventProducerInterfaceMockRecorder is the mock recorder for MockEventProducerInterface.
+type MockEventProducerInterfaceMockRecorder struct {
+	mock *MockEventProducerInterface
+}
+
+// NewMockEventProducerInterface creates a new mock instance.
+func NewMockEventProducerInterface(ctrl *gomock.Controller) *MockEventProducerInterface {
+	mock := &MockEventProducerInterface{ctrl: ctrl}
+	mock.recorder = &MockEventProducerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventProducerInterface) EXPECT() *MockEventProducerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SendOwnershipEvent mocks base method.
+func (m *MockEventProducerInterface) SendOwnershipEvent(event model.AiOwnershipEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendOwnershipEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendOwnershipEvent indicates an expected call of SendOwnershipEvent.
+func (mr *MockEventProducerInterfaceMockRecorder) SendOwnershipEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendOwnershipEvent", reflect.TypeOf((*MockEventProducerInterface)(nil).SendOwnershipEvent), event)
+}
+
+// SendRatingEvent mocks base method.
+func (m *MockEventProducerInterface) SendRatingEvent(event model.AiRatingEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRatingEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendRatingEvent indicates an expected call of SendRatingEvent.
+func (mr *MockEventProducerInterfaceMockRecorder) SendRatingEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRatingEvent", reflect.TypeOf((*MockEventProducerInterface)(nil).SendRatingEvent), event)
+}
+
+// SendReleaseEvent mocks base method.
+func (m *MockEventProducerInterface) SendReleaseEvent(event model.AiReleaseEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendReleaseEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendReleaseEvent indicates an expected call of SendReleaseEvent.
+func (mr *MockEventProducerInterfaceMockRecorder) SendReleaseEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendReleaseEvent", reflect.TypeOf((*MockEventProducerInterface)(nil).SendReleaseEvent), event)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/ai/adapter/grpc.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/ai/adapter/grpc.go -destination=services/ai/adapter/mocks/mock_grpc.go
+//
+// Package mock_adapter is a generated GoMock package.
+package mock_adapter
+
+import (
+	reflect "reflect"
+	gen "warehouseai/ai/adapter/grpc/gen"
+	errors "warehouseai/ai/errors"
+	model "warehouseai/ai/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthGrpcInterface is a mock of AuthGrpcInterface interface.
+type MockAuthGrpcInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthGrpcInterfaceMockRecorder
+}
+
+// MockAuthGrpcInterfaceMockRecorder is the mock recorder for MockAuthGrpcInterface.
+type MockAuthGrpcInterfaceMockRecorder struct {
+	mock *MockAuthGrpcInterface
+}
+
+// NewMockAuthGrpcInterface creates a new mock instance.
+func NewMockAuthGrpcInterface(ctrl *gomock.Controller) *MockAuthGrpcInterface {
+	mock := &MockAuthGrpcInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthGrpcInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthGrpcInterface) EXPECT() *MockAuthGrpcInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthGrpcInterface) Authenticate(sessionId string) (*gen.AuthenticationResponse, *errors.HttpErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", sessionId)
+	ret0, _ := ret[0].(*gen.AuthenticationResponse)
+	ret1, _ := ret[1].(*errors.HttpErrorResponse)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthGrpcInterfaceMockRecorder) Authenticate(sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthGrpcInterface)(nil).Authenticate), sessionId)
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockAuthGrpcInterface) VerifyAccessToken(token string) (*model.AccessClaims, *errors.HttpErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", token)
+	ret0, _ := ret[0].(*model.AccessClaims)
+	ret1, _ := ret[1].(*errors.HttpErrorResponse)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockAuthGrpcInterfaceMockRecorder) VerifyAccessToken(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockAuthGrpcInterface)(nil).VerifyAccessToken), token)
+}
+
+// MockUserGrpcInterface is a mock of UserGrpcInterface interface.
+type MockUserGrpcInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserGrpcInterfaceMockRecorder
+}
+
+// MockUserGrpcInterfaceMockRecorder is the mock recorder for MockUserGrpcInterface.
+type MockUserGrpcInterfaceMockRecorder struct {
+	mock *MockUserGrpcInterface
+}
+
+// NewMockUserGrpcInterface creates a new mock instance.
+func NewMockUserGrpcInterface(ctrl *gomock.Controller) *MockUserGrpcInterface {
+	mock := &MockUserGrpcInterface{ctrl: ctrl}
+	mock.recorder = &MockUserGrpcInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserGrpcInterface) EXPECT() *MockUserGrpcInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetById mocks base method.
+func (m *MockUserGrpcInterface) GetById(userId string) (*gen.User, *errors.HttpErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", userId)
+	ret0, _ := ret[0].(*gen.User)
+	ret1, _ := ret[1].(*errors.HttpErrorResponse)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockUserGrpcInterfaceMockRecorder) GetById(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUserGrpcInterface)(nil).GetById), userId)
+}
+
+// GetFavorite mocks base method.
+func (m *MockUserGrpcInterface) GetFavorite(aiId, userId string) (bool, *errors.HttpErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFavorite", aiId, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*errors.HttpErrorResponse)
+	return ret0, ret1
+}
+
+// GetFavorite indicates an expected call of GetFavorite.
+func (mr *MockUserGrpcInterfaceMockRecorder) GetFavorite(aiId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavorite", reflect.TypeOf((*MockUserGrpcInterface)(nil).GetFavorite), aiId, userId)
+}
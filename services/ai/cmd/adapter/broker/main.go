@@ -0,0 +1,45 @@
+package broker
+
+import (
+	"fmt"
+	"warehouseai/ai/adapter/broker"
+	"warehouseai/ai/config"
+	m "warehouseai/ai/model"
+
+	rmq "github.com/rabbitmq/amqp091-go"
+)
+
+func NewBroker() *broker.Broker {
+	cfg := config.NewBrokerCfg()
+
+	conn, err := rmq.Dial(fmt.Sprintf("amqp://%s:%s@%s:%s/", cfg.User, cfg.Password, cfg.Host, cfg.Port))
+
+	if err != nil {
+		panic(fmt.Sprintf("Unable to open connect to RabbitMQ; %s", err))
+	}
+
+	ch, err := conn.Channel()
+
+	if err != nil {
+		panic(fmt.Sprintf("Unable to open channel; %s", err))
+	}
+
+	// Очереди саги удаления аккаунта и событий сервиса ИИ переживают перезапуск RabbitMQ
+	for _, name := range []m.QueueName{m.AccountDeleteAi, m.AccountDeleteReply, m.AiOwnership, m.AiRelease, m.AiRating} {
+		if _, err := ch.QueueDeclare(
+			string(name),
+			true,
+			false,
+			false,
+			false,
+			nil,
+		); err != nil {
+			panic(fmt.Sprintf("Unable to create queue in the channel; %s", err))
+		}
+	}
+
+	return &broker.Broker{
+		Channel:    ch,
+		Connection: conn,
+	}
+}
@@ -12,9 +12,9 @@ import (
 	"google.golang.org/grpc"
 )
 
-func Start(host string, db dataservice.AiInterface, logger *logrus.Logger) func() {
+func Start(host string, db dataservice.AiInterface, ratingDB dataservice.RatingInterface, executionDB dataservice.ExecutionInterface, logger *logrus.Logger) func() {
 	grpc := grpc.NewServer()
-	server := newAiGrpcServer(db, logger)
+	server := newAiGrpcServer(db, ratingDB, executionDB, logger)
 	listener, err := net.Listen("tcp", host)
 
 	if err != nil {
@@ -34,9 +34,11 @@ func Start(host string, db dataservice.AiInterface, logger *logrus.Logger) func(
 	}
 }
 
-func newAiGrpcServer(database dataservice.AiInterface, logger *logrus.Logger) *server.AiGrpcServer {
+func newAiGrpcServer(database dataservice.AiInterface, ratingDB dataservice.RatingInterface, executionDB dataservice.ExecutionInterface, logger *logrus.Logger) *server.AiGrpcServer {
 	return &server.AiGrpcServer{
-		DB:     database,
-		Logger: logger,
+		DB:          database,
+		RatingDB:    ratingDB,
+		ExecutionDB: executionDB,
+		Logger:      logger,
 	}
 }
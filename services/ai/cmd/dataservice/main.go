@@ -3,10 +3,19 @@ package dataservice
 import (
 	"fmt"
 	"warehouseai/ai/config"
+	d "warehouseai/ai/dataservice"
+	fsinput "warehouseai/ai/dataservice/fs/inputdata"
+	fspicture "warehouseai/ai/dataservice/fs/picturedata"
+	meminput "warehouseai/ai/dataservice/memory/inputdata"
+	mempicture "warehouseai/ai/dataservice/memory/picturedata"
 	"warehouseai/ai/dataservice/psql/aidata"
+	"warehouseai/ai/dataservice/psql/auditdata"
 	"warehouseai/ai/dataservice/psql/commanddata"
+	"warehouseai/ai/dataservice/psql/executiondata"
+	"warehouseai/ai/dataservice/psql/flagdata"
 	"warehouseai/ai/dataservice/psql/ratingdata"
-	"warehouseai/ai/dataservice/s3/picturedata"
+	s3input "warehouseai/ai/dataservice/s3/inputdata"
+	s3picture "warehouseai/ai/dataservice/s3/picturedata"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -55,9 +64,93 @@ func NewRatingDatabase() *ratingdata.Database {
 	return &ratingdata.Database{DB: db}
 }
 
-func NewPictureStorage() *picturedata.Storage {
-	config := config.NewStorageCfg()
+func NewExecutionDatabase() *executiondata.Database {
+	cfg := config.NewAiDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &executiondata.Database{DB: db}
+}
+
+func NewFlagDatabase() *flagdata.Database {
+	cfg := config.NewAiDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &flagdata.Database{DB: db}
+}
 
+func NewAuditDatabase() *auditdata.Database {
+	cfg := config.NewAiDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &auditdata.Database{DB: db}
+}
+
+func NewPictureStorage() d.PictureInterface {
+	cfg := config.NewStorageCfg()
+
+	switch cfg.Driver {
+	case config.FileStorage:
+		return &fspicture.Storage{Root: cfg.Root, Domain: cfg.Domain}
+
+	case config.MemoryStorage:
+		return mempicture.NewStorage(cfg.Domain)
+
+	case config.S3Storage:
+		return newS3PictureStorage(cfg)
+	}
+
+	panic(fmt.Sprintf("❌Unknown storage driver %q.", cfg.Driver))
+}
+
+func NewInputStorage() d.InputFileInterface {
+	cfg := config.NewStorageCfg()
+
+	switch cfg.Driver {
+	case config.FileStorage:
+		// С пустым ключом HMAC подписи ссылок загрузки может подделать кто угодно
+		if cfg.SigningKey == "" {
+			panic("❌STORAGE_SIGNING_KEY is required for the fs storage driver.")
+		}
+
+		return &fsinput.Storage{Root: cfg.InputRoot, UploadLink: cfg.UploadLink, SigningKey: cfg.SigningKey}
+
+	case config.MemoryStorage:
+		return meminput.NewStorage()
+
+	case config.S3Storage:
+		return &s3input.Storage{Bucket: cfg.Bucket, Session: newS3Session(cfg)}
+	}
+
+	panic(fmt.Sprintf("❌Unknown storage driver %q.", cfg.Driver))
+}
+
+func newS3PictureStorage(config config.StorageCfg) *s3picture.Storage {
+	return &s3picture.Storage{
+		Bucket:  config.Bucket,
+		Domain:  config.Domain,
+		Session: newS3Session(config),
+	}
+}
+
+func newS3Session(config config.StorageCfg) *session.Session {
 	sess, err := session.NewSession(
 		&aws.Config{
 			Endpoint:            aws.String(config.Endpoint),
@@ -76,9 +169,5 @@ func NewPictureStorage() *picturedata.Storage {
 		panic(err)
 	}
 
-	return &picturedata.Storage{
-		Bucket:  config.Bucket,
-		Domain:  config.Domain,
-		Session: sess,
-	}
+	return sess
 }
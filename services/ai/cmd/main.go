@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"time"
+	"warehouseai/ai/cmd/adapter/broker"
 	"warehouseai/ai/cmd/adapter/grpc"
 	"warehouseai/ai/cmd/dataservice"
 	"warehouseai/ai/cmd/server"
@@ -26,15 +27,27 @@ func main() {
 	aiDB := dataservice.NewAiDatabase()
 	commandDB := dataservice.NewCommandDatabase()
 	ratingDB := dataservice.NewRatingDatabase()
+	executionDB := dataservice.NewExecutionDatabase()
+	flagDB := dataservice.NewFlagDatabase()
+	auditDB := dataservice.NewAuditDatabase()
 	pictureStorage := dataservice.NewPictureStorage()
+	inputStorage := dataservice.NewInputStorage()
+	broker := broker.NewBroker()
 	fmt.Println("✅Database successfully connected.")
 
-	grpcServer := grpc.Start("ai:8021", aiDB, log)
+	go broker.ReceiveAccountDeletion(aiDB, ratingDB, log)
+
+	grpcServer := grpc.Start("ai:8021", aiDB, ratingDB, executionDB, log)
 	go grpcServer()
 
-	if err := server.StartServer(":8020", ratingDB, aiDB, commandDB, pictureStorage, log); err != nil {
+	if err := server.StartServer(":8020", ratingDB, aiDB, commandDB, executionDB, flagDB, auditDB, pictureStorage, inputStorage, broker, log); err != nil {
 		fmt.Println("❌Failed to start the HTTP Handler.")
 		log.WithFields(logrus.Fields{"time": time.Now().String(), "error": err.Error()}).Info("AI Microservice")
 		panic(err)
 	}
+
+	defer func() {
+		broker.Channel.Close()
+		broker.Connection.Close()
+	}()
 }
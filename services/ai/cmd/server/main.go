@@ -1,12 +1,22 @@
 package server
 
 import (
+	"path/filepath"
+	"warehouseai/ai/adapter/broker"
 	"warehouseai/ai/adapter/grpc/client/auth"
 	"warehouseai/ai/adapter/grpc/client/user"
+	"warehouseai/ai/config"
+	"warehouseai/ai/dataservice"
+	fsinput "warehouseai/ai/dataservice/fs/inputdata"
+	fspicture "warehouseai/ai/dataservice/fs/picturedata"
+	mempicture "warehouseai/ai/dataservice/memory/picturedata"
 	"warehouseai/ai/dataservice/psql/aidata"
+	"warehouseai/ai/dataservice/psql/auditdata"
 	"warehouseai/ai/dataservice/psql/commanddata"
+	"warehouseai/ai/dataservice/psql/executiondata"
+	"warehouseai/ai/dataservice/psql/flagdata"
 	"warehouseai/ai/dataservice/psql/ratingdata"
-	"warehouseai/ai/dataservice/s3/picturedata"
+	"warehouseai/ai/model"
 	"warehouseai/ai/server/handlers/ai"
 	"warehouseai/ai/server/handlers/commands"
 	"warehouseai/ai/server/handlers/rating"
@@ -18,66 +28,125 @@ import (
 )
 
 // TODO: Добавить error handler в инициализацию app - https://docs.gofiber.io/guide/error-handling/#custom-error-handler
-func StartServer(port string, ratingDB *ratingdata.Database, aiDB *aidata.Database, commandDB *commanddata.Database, pictureStorage *picturedata.Storage, logger *logrus.Logger) error {
-	aiHandler := newHttpAiHandler(aiDB, pictureStorage, logger)
-	commandHandler := newHttpCommandHandler(commandDB, aiDB, logger)
-	ratingHandler := newRatingHandler(ratingDB, aiDB, logger)
-	app := fiber.New()
+func StartServer(port string, ratingDB *ratingdata.Database, aiDB *aidata.Database, commandDB *commanddata.Database, executionDB *executiondata.Database, flagDB *flagdata.Database, auditDB *auditdata.Database, pictureStorage dataservice.PictureInterface, inputStorage dataservice.InputFileInterface, brk *broker.Broker, logger *logrus.Logger) error {
+	aiHandler := newHttpAiHandler(aiDB, auditDB, pictureStorage, brk, logger)
+	commandHandler := newHttpCommandHandler(commandDB, aiDB, executionDB, inputStorage, brk, logger)
+	ratingHandler := newRatingHandler(ratingDB, aiDB, executionDB, flagDB, brk, logger)
+	app := fiber.New(fiber.Config{BodyLimit: bodyLimit(inputStorage)})
 	app.Use(setupCORS())
+	app.Use(middleware.Csrf(middleware.AccessTokenVerifier(aiHandler.AuthClient)))
 
-	sessionStrictMw := middleware.SessionStrict(logger, aiHandler.AuthClient)
-	sessionMw := middleware.Session(logger, aiHandler.AuthClient)
-	pictureMW := middleware.Image(logger, pictureStorage)
+	// Картинки с диска раздаём сами, ссылки вида S3_LINK/backgrounds/<file> должны указывать сюда.
+	// Раздаётся только каталог картинок, остальное в Root наружу не попадает.
+	if storage, ok := pictureStorage.(*fspicture.Storage); ok {
+		app.Static("/ai/static/backgrounds", filepath.Join(storage.Root, "backgrounds"))
+	}
+
+	// Картинки из памяти отдаются по тому же адресу, что и с диска
+	if storage, ok := pictureStorage.(*mempicture.Storage); ok {
+		app.Get("/ai/static/backgrounds/:file", func(c *fiber.Ctx) error {
+			payload, ok := storage.Get(c.Params("file"))
+
+			if !ok {
+				return c.SendStatus(fiber.StatusNotFound)
+			}
+
+			c.Type(filepath.Ext(c.Params("file")))
+			return c.Send(payload)
+		})
+	}
+
+	cookieCfg := config.NewCookieCfg()
+	sessionStrictMw := middleware.SessionStrict(logger, aiHandler.AuthClient, cookieCfg)
+	sessionMw := middleware.Session(logger, aiHandler.AuthClient, cookieCfg)
+	moderateRatingsMw := middleware.RequirePermission(model.PermissionModerateRatings)
+	publishAiMw := middleware.RequirePermission(model.PermissionPublishAi)
+	imageCfg := config.NewImageCfg()
+	pictureMW := middleware.Image(logger, pictureStorage, imageCfg)
+	optionalPictureMW := middleware.ImageOptional(logger, pictureStorage, imageCfg)
+
+	// Загрузка входных файлов регистрируется до группы /ai, чтобы на неё не действовал общий лимит тела
+	if _, ok := inputStorage.(*fsinput.Storage); ok {
+		app.Put("/ai/command/upload/file", commandHandler.LocalUploadHandler)
+	}
 
-	route := app.Group("/ai")
-	route.Post("/create/generate", sessionStrictMw, pictureMW, aiHandler.CreateAiWithoutKeyHandler)
-	route.Post("/create/exist", sessionStrictMw, pictureMW, aiHandler.CreateAiWithKeyHandler)
+	route := app.Group("/ai", middleware.BodyLimit(fiber.DefaultBodyLimit))
+	route.Post("/create/generate", sessionStrictMw, publishAiMw, pictureMW, aiHandler.CreateAiWithoutKeyHandler)
+	route.Post("/create/exist", sessionStrictMw, publishAiMw, pictureMW, aiHandler.CreateAiWithKeyHandler)
+	route.Patch("/update", sessionStrictMw, optionalPictureMW, aiHandler.UpdateAiHandler)
 	route.Get("/get", sessionMw, aiHandler.GetAIHandler)
 	route.Get("/get/many", aiHandler.GetAisHandler)
 	route.Get("/search", aiHandler.SearchHandler)
 	route.Post("/command/create", sessionStrictMw, commandHandler.CreateCommandHandler)
 	route.Post("/command/execute", sessionStrictMw, commandHandler.ExecuteCommandHandler)
+	route.Post("/command/upload", sessionStrictMw, commandHandler.RequestUploadHandler)
 	route.Get("/rating/get", ratingHandler.GetAiRatingHandler)
 	route.Post("/rating/set", sessionStrictMw, ratingHandler.SetRatingForAiHandler)
+	route.Post("/rating/flag", sessionStrictMw, ratingHandler.FlagRatingHandler)
+	route.Get("/rating/flags", sessionStrictMw, moderateRatingsMw, ratingHandler.GetPendingFlagsHandler)
+	route.Patch("/rating/flags/review", sessionStrictMw, moderateRatingsMw, ratingHandler.ReviewFlagHandler)
 
 	return app.Listen(port)
 }
 
-func newHttpAiHandler(db *aidata.Database, pictureStorage *picturedata.Storage, logger *logrus.Logger) *ai.Handler {
+func newHttpAiHandler(db *aidata.Database, auditDB *auditdata.Database, pictureStorage dataservice.PictureInterface, brk *broker.Broker, logger *logrus.Logger) *ai.Handler {
 	authClient := auth.NewAuthGrpcClient("auth:8041")
 	userClient := user.NewUserGrpcClient("user:8001")
 
 	return &ai.Handler{
 		DB:             db,
+		AuditDB:        auditDB,
 		Logger:         logger,
 		PictureStorage: pictureStorage,
 		UserClient:     userClient,
 		AuthClient:     authClient,
+		Broker:         brk,
 	}
 }
 
-func newHttpCommandHandler(commandDB *commanddata.Database, aiDB *aidata.Database, logger *logrus.Logger) *commands.Handler {
+func newHttpCommandHandler(commandDB *commanddata.Database, aiDB *aidata.Database, executionDB *executiondata.Database, inputStorage dataservice.InputFileInterface, brk *broker.Broker, logger *logrus.Logger) *commands.Handler {
 	authClient := auth.NewAuthGrpcClient("auth:8041")
 
 	return &commands.Handler{
-		CommandDB:  commandDB,
-		AiDB:       aiDB,
-		Logger:     logger,
-		AuthClient: authClient,
+		CommandDB:    commandDB,
+		AiDB:         aiDB,
+		ExecutionDB:  executionDB,
+		InputStorage: inputStorage,
+		UploadCfg:    config.NewUploadCfg(),
+		Logger:       logger,
+		AuthClient:   authClient,
+		Broker:       brk,
 	}
 }
 
-func newRatingHandler(ratingDB *ratingdata.Database, aiDB *aidata.Database, logger *logrus.Logger) *rating.Handler {
+// Без S3 входные файлы грузятся через сам сервис, поэтому лимит сервера поднимается до лимита загрузки.
+// Остальные маршруты ограничивает middleware.BodyLimit.
+func bodyLimit(inputStorage dataservice.InputFileInterface) int {
+	if _, ok := inputStorage.(*fsinput.Storage); ok {
+		return int(config.NewUploadCfg().MaxSize)
+	}
+
+	return fiber.DefaultBodyLimit
+}
+
+func newRatingHandler(ratingDB *ratingdata.Database, aiDB *aidata.Database, executionDB *executiondata.Database, flagDB *flagdata.Database, brk *broker.Broker, logger *logrus.Logger) *rating.Handler {
+	userClient := user.NewUserGrpcClient("user:8001")
+
 	return &rating.Handler{
-		RatingRepository: ratingDB,
-		AiRepository:     aiDB,
-		Logger:           logger,
+		RatingRepository:    ratingDB,
+		AiRepository:        aiDB,
+		ExecutionRepository: executionDB,
+		FlagRepository:      flagDB,
+		UserClient:          userClient,
+		Broker:              brk,
+		Config:              config.NewRatingCfg(),
+		Logger:              logger,
 	}
 }
 
 func setupCORS() func(*fiber.Ctx) error {
 	return cors.New(cors.Config{
-		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,X-CSRF-Token",
 		AllowOrigins:     "http://localhost:3000, https://warehouse-ai-frontend.vercel.app, https://warehousai.com",
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
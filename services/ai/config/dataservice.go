@@ -12,13 +12,28 @@ type DatabaseCfg struct {
 	Port     string
 }
 
+type StorageDriver string
+
+const (
+	S3Storage     StorageDriver = "s3"
+	FileStorage   StorageDriver = "fs"
+	MemoryStorage StorageDriver = "memory"
+)
+
+// Для fs: Root - картинки, которые раздаются статикой, InputRoot - входные файлы команд, наружу не раздаются
 type StorageCfg struct {
+	Driver    StorageDriver
+	Root      string
+	InputRoot string
 	Endpoint  string
 	AccessKey string
 	SecretKey string
 	Region    string
 	Domain    string
 	Bucket    string
+	// Только для fs: адрес роута загрузки и ключ подписи ссылок на него
+	UploadLink string
+	SigningKey string
 }
 
 func NewAiDatabaseCfg() DatabaseCfg {
@@ -32,12 +47,36 @@ func NewAiDatabaseCfg() DatabaseCfg {
 }
 
 func NewStorageCfg() StorageCfg {
+	driver := StorageDriver(os.Getenv("STORAGE_DRIVER"))
+
+	if driver == "" {
+		driver = S3Storage
+	}
+
+	root := os.Getenv("STORAGE_ROOT")
+
+	if root == "" {
+		root = "./storage"
+	}
+
+	inputRoot := os.Getenv("STORAGE_INPUT_ROOT")
+
+	if inputRoot == "" {
+		inputRoot = "./storage-inputs"
+	}
+
+	// Для fs и memory S3_LINK - это публичный адрес статики, например http://localhost/api/ai/static
 	return StorageCfg{
-		Endpoint:  os.Getenv("S3_HOST"),
-		AccessKey: os.Getenv("S3_ACCESSKEY"),
-		SecretKey: os.Getenv("S3_SECRETKEY"),
-		Domain:    os.Getenv("S3_LINK"),
-		Bucket:    os.Getenv("S3_BUCKET"),
-		Region:    os.Getenv("S3_REGION"),
+		Driver:     driver,
+		Root:       root,
+		InputRoot:  inputRoot,
+		Endpoint:   os.Getenv("S3_HOST"),
+		AccessKey:  os.Getenv("S3_ACCESSKEY"),
+		SecretKey:  os.Getenv("S3_SECRETKEY"),
+		Domain:     os.Getenv("S3_LINK"),
+		Bucket:     os.Getenv("S3_BUCKET"),
+		Region:     os.Getenv("S3_REGION"),
+		UploadLink: os.Getenv("STORAGE_UPLOAD_LINK"),
+		SigningKey: os.Getenv("STORAGE_SIGNING_KEY"),
 	}
 }
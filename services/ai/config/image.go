@@ -0,0 +1,15 @@
+package config
+
+type ImageCfg struct {
+	MaxSize     int64
+	MaxPixels   int
+	JpegQuality int
+}
+
+func NewImageCfg() ImageCfg {
+	return ImageCfg{
+		MaxSize:     int64(intFromEnv("IMAGE_MAX_SIZE", 5<<20)),
+		MaxPixels:   intFromEnv("IMAGE_MAX_PIXELS", 40_000_000),
+		JpegQuality: intFromEnv("IMAGE_JPEG_QUALITY", 85),
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type RatingCfg struct {
+	BurstWindow    time.Duration
+	BurstThreshold int
+	NewAccountAge  time.Duration
+}
+
+func NewRatingCfg() RatingCfg {
+	return RatingCfg{
+		BurstWindow:    durationFromEnv("RATING_BURST_WINDOW", time.Hour),
+		BurstThreshold: intFromEnv("RATING_BURST_THRESHOLD", 5),
+		NewAccountAge:  durationFromEnv("RATING_NEW_ACCOUNT_AGE", 72*time.Hour),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+func intFromEnv(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+type UploadCfg struct {
+	MaxSize int64
+	TTL     time.Duration
+}
+
+func NewUploadCfg() UploadCfg {
+	return UploadCfg{
+		MaxSize: int64(intFromEnv("UPLOAD_MAX_SIZE", 100<<20)),
+		TTL:     durationFromEnv("UPLOAD_URL_TTL", 15*time.Minute),
+	}
+}
@@ -1,7 +1,8 @@
 package dataservice
 
 import (
-	"mime/multipart"
+	"io"
+	"time"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
 )
@@ -13,6 +14,13 @@ type AiInterface interface {
 	GetLike(field string, value string) (*[]m.AiProduct, *e.DBError)
 	GetWithPreload(conditions map[string]interface{}, preload string) (*m.AiProduct, *e.DBError)
 	Update(ai *m.AiProduct, updatedFields map[string]interface{}) *e.DBError
+	GetByOwner(ownerId string) (*[]m.AiProduct, *e.DBError)
+	ArchiveByOwner(ownerId string) (int64, *e.DBError)
+	RestoreByOwner(ownerId string) (int64, *e.DBError)
+}
+
+type AuditInterface interface {
+	Add(entries *[]m.AiProductAudit) *e.DBError
 }
 
 type CommandInterface interface {
@@ -22,14 +30,40 @@ type CommandInterface interface {
 }
 
 type PictureInterface interface {
-	UploadFile(file multipart.File, fileName string) (string, error)
+	UploadFile(file io.Reader, fileName string) (string, error)
 	DeleteImage(fileName string) error
 }
 
+// Входные файлы команд, которые клиент загружает напрямую в хранилище по подписанной ссылке
+type InputFileInterface interface {
+	PresignUpload(key string, size int64, expires time.Duration) (string, error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
 type RatingInterface interface {
-	Update(existRate *m.AiRate, newRate int16) *e.DBError
+	Update(existRate *m.AiRate, updatedFields map[string]interface{}) *e.DBError
 	GetAverageAiRating(aiId string) (*float64, *e.DBError)
 	GetCountAiRating(aiId string) (*int64, *e.DBError)
+	GetSummaries(aiIds []string) (*[]m.AiRatingSummary, *e.DBError)
 	Get(conditions map[string]interface{}) (*m.AiRate, *e.DBError)
+	GetRecentByNewAccounts(aiId string, since time.Time) (*[]m.AiRate, *e.DBError)
 	Add(rate *m.AiRate) *e.DBError
+	GetByUser(userId string) (*[]m.AiRate, *e.DBError)
+	DeleteByUser(userId string) (int64, *e.DBError)
+	RestoreByUser(userId string) (int64, *e.DBError)
+	PurgeByUser(userId string) (int64, *e.DBError)
+}
+
+type RatingFlagInterface interface {
+	Add(flag *m.AiRateFlag) *e.DBError
+	Get(conditions map[string]interface{}) (*m.AiRateFlag, *e.DBError)
+	GetMany(conditions map[string]interface{}, limit int) (*[]m.AiRateFlag, *e.DBError)
+	Update(flag *m.AiRateFlag, updatedFields map[string]interface{}) *e.DBError
+}
+
+type ExecutionInterface interface {
+	Add(execution *m.AiExecution) *e.DBError
+	Get(conditions map[string]interface{}) (*m.AiExecution, *e.DBError)
+	GetByUser(userId string) (*[]m.AiExecution, *e.DBError)
 }
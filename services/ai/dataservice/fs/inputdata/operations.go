@@ -0,0 +1,139 @@
+package inputdata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Все ключи входных файлов лежат под этим префиксом, другие ключи драйвер не принимает
+const keyPrefix = "inputs/"
+
+// Хранит входные файлы на диске. Вместо подписи S3 ссылка подписывается HMAC'ом
+// и ведёт на роут загрузки самого сервиса (UploadLink).
+type Storage struct {
+	Root       string
+	UploadLink string
+	SigningKey string
+}
+
+func (s *Storage) PresignUpload(key string, size int64, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("size", strconv.FormatInt(size, 10))
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", s.sign(key, size, expiresAt))
+
+	return s.UploadLink + "?" + query.Encode(), nil
+}
+
+// Проверяет подпись ссылки из PresignUpload и возвращает заявленный размер файла
+func (s *Storage) Verify(key string, size string, expires string, signature string) (int64, error) {
+	if _, err := s.path(key); err != nil {
+		return 0, err
+	}
+
+	parsedSize, err := strconv.ParseInt(size, 10, 64)
+
+	if err != nil {
+		return 0, errors.New("invalid size")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+
+	if err != nil {
+		return 0, errors.New("invalid expires")
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return 0, errors.New("upload link expired")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, parsedSize, expiresAt))) {
+		return 0, errors.New("invalid signature")
+	}
+
+	return parsedSize, nil
+}
+
+func (s *Storage) Save(key string, file io.Reader) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *Storage) Delete(key string) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) sign(key string, size int64, expiresAt int64) string {
+	mac := hmac.New(sha256.New, []byte(s.SigningKey))
+	fmt.Fprintf(mac, "%s\n%d\n%d", key, size, expiresAt)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Storage) path(key string) (string, error) {
+	cleanKey := filepath.Clean(key)
+
+	if key == "" || filepath.IsAbs(cleanKey) || !strings.HasPrefix(cleanKey, keyPrefix) {
+		return "", errors.New("invalid file key")
+	}
+
+	return filepath.Join(s.Root, cleanKey), nil
+}
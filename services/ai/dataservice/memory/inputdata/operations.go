@@ -0,0 +1,58 @@
+package inputdata
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Хранит входные файлы в памяти процесса. Подписанная ссылка фиктивная, файл кладётся через Save.
+type Storage struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+func NewStorage() *Storage {
+	return &Storage{files: map[string][]byte{}}
+}
+
+func (s *Storage) PresignUpload(key string, size int64, expires time.Duration) (string, error) {
+	return "memory://" + key, nil
+}
+
+func (s *Storage) Save(key string, file io.Reader) error {
+	payload, err := io.ReadAll(file)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.files[key] = payload
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Storage) Open(key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payload, ok := s.files[key]
+
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+
+	return io.NopCloser(bytes.NewReader(payload)), nil
+}
+
+func (s *Storage) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.files, key)
+	s.mu.Unlock()
+
+	return nil
+}
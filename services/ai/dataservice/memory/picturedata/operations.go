@@ -0,0 +1,48 @@
+package picturedata
+
+import (
+	"io"
+	"sync"
+)
+
+// Хранит картинки в памяти процесса. Нужен для тестов и локального запуска без S3.
+// Ссылки ведут на Domain/backgrounds/<file>, картинки по ним отдаёт сервер через Get, пока процесс жив.
+type Storage struct {
+	Domain string
+	mu     sync.RWMutex
+	files  map[string][]byte
+}
+
+func NewStorage(domain string) *Storage {
+	return &Storage{Domain: domain, files: map[string][]byte{}}
+}
+
+func (s *Storage) UploadFile(file io.Reader, fileName string) (string, error) {
+	payload, err := io.ReadAll(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.files[fileName] = payload
+	s.mu.Unlock()
+
+	return s.Domain + "/backgrounds/" + fileName, nil
+}
+
+func (s *Storage) DeleteImage(fileName string) error {
+	s.mu.Lock()
+	delete(s.files, fileName)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Storage) Get(fileName string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payload, ok := s.files[fileName]
+	return payload, ok
+}
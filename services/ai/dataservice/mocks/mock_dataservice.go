@@ -3,14 +3,15 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=services/ai/dataservice/dataservice.go -destination=services/ai/dataservice/mocks/mock_dataservice.go
+//	mockgen -source=services/ai/dataservice/dataservice.go -destination=services/ai/dataservice/mocks/mock_dataservice.go -package=mock_dataservice
 //
 // Package mock_dataservice is a generated GoMock package.
 package mock_dataservice
 
 import (
-	multipart "mime/multipart"
+	io "io"
 	reflect "reflect"
+	time "time"
 	errors "warehouseai/ai/errors"
 	model "warehouseai/ai/model"
 
@@ -40,6 +41,21 @@ func (m *MockAiInterface) EXPECT() *MockAiInterfaceMockRecorder {
 	return m.recorder
 }
 
+// ArchiveByOwner mocks base method.
+func (m *MockAiInterface) ArchiveByOwner(ownerId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveByOwner", ownerId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// ArchiveByOwner indicates an expected call of ArchiveByOwner.
+func (mr *MockAiInterfaceMockRecorder) ArchiveByOwner(ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveByOwner", reflect.TypeOf((*MockAiInterface)(nil).ArchiveByOwner), ownerId)
+}
+
 // Create mocks base method.
 func (m *MockAiInterface) Create(token *model.AiProduct) *errors.DBError {
 	m.ctrl.T.Helper()
@@ -69,6 +85,21 @@ func (mr *MockAiInterfaceMockRecorder) Get(conditions any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAiInterface)(nil).Get), conditions)
 }
 
+// GetByOwner mocks base method.
+func (m *MockAiInterface) GetByOwner(ownerId string) (*[]model.AiProduct, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOwner", ownerId)
+	ret0, _ := ret[0].(*[]model.AiProduct)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetByOwner indicates an expected call of GetByOwner.
+func (mr *MockAiInterfaceMockRecorder) GetByOwner(ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOwner", reflect.TypeOf((*MockAiInterface)(nil).GetByOwner), ownerId)
+}
+
 // GetLike mocks base method.
 func (m *MockAiInterface) GetLike(field, value string) (*[]model.AiProduct, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -114,6 +145,21 @@ func (mr *MockAiInterfaceMockRecorder) GetWithPreload(conditions, preload any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithPreload", reflect.TypeOf((*MockAiInterface)(nil).GetWithPreload), conditions, preload)
 }
 
+// RestoreByOwner mocks base method.
+func (m *MockAiInterface) RestoreByOwner(ownerId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreByOwner", ownerId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// RestoreByOwner indicates an expected call of RestoreByOwner.
+func (mr *MockAiInterfaceMockRecorder) RestoreByOwner(ownerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreByOwner", reflect.TypeOf((*MockAiInterface)(nil).RestoreByOwner), ownerId)
+}
+
 // Update mocks base method.
 func (m *MockAiInterface) Update(ai *model.AiProduct, updatedFields map[string]any) *errors.DBError {
 	m.ctrl.T.Helper()
@@ -128,6 +174,43 @@ func (mr *MockAiInterfaceMockRecorder) Update(ai, updatedFields any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAiInterface)(nil).Update), ai, updatedFields)
 }
 
+// MockAuditInterface is a mock of AuditInterface interface.
+type MockAuditInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditInterfaceMockRecorder
+}
+
+// MockAuditInterfaceMockRecorder is the mock recorder for MockAuditInterface.
+type MockAuditInterfaceMockRecorder struct {
+	mock *MockAuditInterface
+}
+
+// NewMockAuditInterface creates a new mock instance.
+func NewMockAuditInterface(ctrl *gomock.Controller) *MockAuditInterface {
+	mock := &MockAuditInterface{ctrl: ctrl}
+	mock.recorder = &MockAuditInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditInterface) EXPECT() *MockAuditInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAuditInterface) Add(entries *[]model.AiProductAudit) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", entries)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAuditInterfaceMockRecorder) Add(entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAuditInterface)(nil).Add), entries)
+}
+
 // MockCommandInterface is a mock of CommandInterface interface.
 type MockCommandInterface struct {
 	ctrl     *gomock.Controller
@@ -233,7 +316,7 @@ func (mr *MockPictureInterfaceMockRecorder) DeleteImage(fileName any) *gomock.Ca
 }
 
 // UploadFile mocks base method.
-func (m *MockPictureInterface) UploadFile(file multipart.File, fileName string) (string, error) {
+func (m *MockPictureInterface) UploadFile(file io.Reader, fileName string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UploadFile", file, fileName)
 	ret0, _ := ret[0].(string)
@@ -247,6 +330,73 @@ func (mr *MockPictureInterfaceMockRecorder) UploadFile(file, fileName any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockPictureInterface)(nil).UploadFile), file, fileName)
 }
 
+// MockInputFileInterface is a mock of InputFileInterface interface.
+type MockInputFileInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInputFileInterfaceMockRecorder
+}
+
+// MockInputFileInterfaceMockRecorder is the mock recorder for MockInputFileInterface.
+type MockInputFileInterfaceMockRecorder struct {
+	mock *MockInputFileInterface
+}
+
+// NewMockInputFileInterface creates a new mock instance.
+func NewMockInputFileInterface(ctrl *gomock.Controller) *MockInputFileInterface {
+	mock := &MockInputFileInterface{ctrl: ctrl}
+	mock.recorder = &MockInputFileInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInputFileInterface) EXPECT() *MockInputFileInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockInputFileInterface) Delete(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInputFileInterfaceMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInputFileInterface)(nil).Delete), key)
+}
+
+// Open mocks base method.
+func (m *MockInputFileInterface) Open(key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockInputFileInterfaceMockRecorder) Open(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockInputFileInterface)(nil).Open), key)
+}
+
+// PresignUpload mocks base method.
+func (m *MockInputFileInterface) PresignUpload(key string, size int64, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignUpload", key, size, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignUpload indicates an expected call of PresignUpload.
+func (mr *MockInputFileInterfaceMockRecorder) PresignUpload(key, size, expires any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignUpload", reflect.TypeOf((*MockInputFileInterface)(nil).PresignUpload), key, size, expires)
+}
+
 // MockRatingInterface is a mock of RatingInterface interface.
 type MockRatingInterface struct {
 	ctrl     *gomock.Controller
@@ -284,6 +434,21 @@ func (mr *MockRatingInterfaceMockRecorder) Add(rate any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRatingInterface)(nil).Add), rate)
 }
 
+// DeleteByUser mocks base method.
+func (m *MockRatingInterface) DeleteByUser(userId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockRatingInterfaceMockRecorder) DeleteByUser(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockRatingInterface)(nil).DeleteByUser), userId)
+}
+
 // Get mocks base method.
 func (m *MockRatingInterface) Get(conditions map[string]any) (*model.AiRate, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -314,6 +479,21 @@ func (mr *MockRatingInterfaceMockRecorder) GetAverageAiRating(aiId any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAverageAiRating", reflect.TypeOf((*MockRatingInterface)(nil).GetAverageAiRating), aiId)
 }
 
+// GetByUser mocks base method.
+func (m *MockRatingInterface) GetByUser(userId string) (*[]model.AiRate, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userId)
+	ret0, _ := ret[0].(*[]model.AiRate)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockRatingInterfaceMockRecorder) GetByUser(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockRatingInterface)(nil).GetByUser), userId)
+}
+
 // GetCountAiRating mocks base method.
 func (m *MockRatingInterface) GetCountAiRating(aiId string) (*int64, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -329,16 +509,224 @@ func (mr *MockRatingInterfaceMockRecorder) GetCountAiRating(aiId any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountAiRating", reflect.TypeOf((*MockRatingInterface)(nil).GetCountAiRating), aiId)
 }
 
+// GetRecentByNewAccounts mocks base method.
+func (m *MockRatingInterface) GetRecentByNewAccounts(aiId string, since time.Time) (*[]model.AiRate, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentByNewAccounts", aiId, since)
+	ret0, _ := ret[0].(*[]model.AiRate)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetRecentByNewAccounts indicates an expected call of GetRecentByNewAccounts.
+func (mr *MockRatingInterfaceMockRecorder) GetRecentByNewAccounts(aiId, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentByNewAccounts", reflect.TypeOf((*MockRatingInterface)(nil).GetRecentByNewAccounts), aiId, since)
+}
+
+// GetSummaries mocks base method.
+func (m *MockRatingInterface) GetSummaries(aiIds []string) (*[]model.AiRatingSummary, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummaries", aiIds)
+	ret0, _ := ret[0].(*[]model.AiRatingSummary)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetSummaries indicates an expected call of GetSummaries.
+func (mr *MockRatingInterfaceMockRecorder) GetSummaries(aiIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummaries", reflect.TypeOf((*MockRatingInterface)(nil).GetSummaries), aiIds)
+}
+
+// PurgeByUser mocks base method.
+func (m *MockRatingInterface) PurgeByUser(userId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeByUser", userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// PurgeByUser indicates an expected call of PurgeByUser.
+func (mr *MockRatingInterfaceMockRecorder) PurgeByUser(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeByUser", reflect.TypeOf((*MockRatingInterface)(nil).PurgeByUser), userId)
+}
+
+// RestoreByUser mocks base method.
+func (m *MockRatingInterface) RestoreByUser(userId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreByUser", userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// RestoreByUser indicates an expected call of RestoreByUser.
+func (mr *MockRatingInterfaceMockRecorder) RestoreByUser(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreByUser", reflect.TypeOf((*MockRatingInterface)(nil).RestoreByUser), userId)
+}
+
 // Update mocks base method.
-func (m *MockRatingInterface) Update(existRate *model.AiRate, newRate int16) *errors.DBError {
+func (m *MockRatingInterface) Update(existRate *model.AiRate, updatedFields map[string]any) *errors.DBError {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", existRate, newRate)
+	ret := m.ctrl.Call(m, "Update", existRate, updatedFields)
 	ret0, _ := ret[0].(*errors.DBError)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRatingInterfaceMockRecorder) Update(existRate, newRate any) *gomock.Call {
+func (mr *MockRatingInterfaceMockRecorder) Update(existRate, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRatingInterface)(nil).Update), existRate, updatedFields)
+}
+
+// MockRatingFlagInterface is a mock of RatingFlagInterface interface.
+type MockRatingFlagInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRatingFlagInterfaceMockRecorder
+}
+
+// MockRatingFlagInterfaceMockRecorder is the mock recorder for MockRatingFlagInterface.
+type MockRatingFlagInterfaceMockRecorder struct {
+	mock *MockRatingFlagInterface
+}
+
+// NewMockRatingFlagInterface creates a new mock instance.
+func NewMockRatingFlagInterface(ctrl *gomock.Controller) *MockRatingFlagInterface {
+	mock := &MockRatingFlagInterface{ctrl: ctrl}
+	mock.recorder = &MockRatingFlagInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRatingFlagInterface) EXPECT() *MockRatingFlagInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRatingFlagInterface) Add(flag *model.AiRateFlag) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", flag)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRatingFlagInterfaceMockRecorder) Add(flag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRatingFlagInterface)(nil).Add), flag)
+}
+
+// Get mocks base method.
+func (m *MockRatingFlagInterface) Get(conditions map[string]any) (*model.AiRateFlag, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.AiRateFlag)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRatingFlagInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRatingFlagInterface)(nil).Get), conditions)
+}
+
+// GetMany mocks base method.
+func (m *MockRatingFlagInterface) GetMany(conditions map[string]any, limit int) (*[]model.AiRateFlag, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", conditions, limit)
+	ret0, _ := ret[0].(*[]model.AiRateFlag)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockRatingFlagInterfaceMockRecorder) GetMany(conditions, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockRatingFlagInterface)(nil).GetMany), conditions, limit)
+}
+
+// Update mocks base method.
+func (m *MockRatingFlagInterface) Update(flag *model.AiRateFlag, updatedFields map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", flag, updatedFields)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRatingFlagInterfaceMockRecorder) Update(flag, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRatingFlagInterface)(nil).Update), flag, updatedFields)
+}
+
+// MockExecutionInterface is a mock of ExecutionInterface interface.
+type MockExecutionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExecutionInterfaceMockRecorder
+}
+
+// MockExecutionInterfaceMockRecorder is the mock recorder for MockExecutionInterface.
+type MockExecutionInterfaceMockRecorder struct {
+	mock *MockExecutionInterface
+}
+
+// NewMockExecutionInterface creates a new mock instance.
+func NewMockExecutionInterface(ctrl *gomock.Controller) *MockExecutionInterface {
+	mock := &MockExecutionInterface{ctrl: ctrl}
+	mock.recorder = &MockExecutionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExecutionInterface) EXPECT() *MockExecutionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockExecutionInterface) Add(execution *model.AiExecution) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", execution)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockExecutionInterfaceMockRecorder) Add(execution any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockExecutionInterface)(nil).Add), execution)
+}
+
+// Get mocks base method.
+func (m *MockExecutionInterface) Get(conditions map[string]any) (*model.AiExecution, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.AiExecution)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExecutionInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExecutionInterface)(nil).Get), conditions)
+}
+
+// GetByUser mocks base method.
+func (m *MockExecutionInterface) GetByUser(userId string) (*[]model.AiExecution, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userId)
+	ret0, _ := ret[0].(*[]model.AiExecution)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockExecutionInterfaceMockRecorder) GetByUser(userId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRatingInterface)(nil).Update), existRate, newRate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockExecutionInterface)(nil).GetByUser), userId)
 }
@@ -1,6 +1,7 @@
 package aidata
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	e "warehouseai/ai/errors"
@@ -19,6 +20,10 @@ func (d *Database) errorHandle(err error) *e.DBError {
 		return nil
 	}
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "AI not found", err.Error())
+	}
+
 	// Добавлять новые ошибки в этот свитч и использовать потом внутри if с ошибкой
 	pgErr, ok := err.(*pgconn.PgError)
 	if ok {
@@ -96,3 +101,34 @@ func (d *Database) Update(ai *m.AiProduct, updatedFields map[string]interface{})
 
 	return nil
 }
+
+func (d *Database) GetByOwner(ownerId string) (*[]m.AiProduct, *e.DBError) {
+	var ais []m.AiProduct
+
+	if err := d.DB.Where("owner = ?", ownerId).Order("created_at").Find(&ais).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &ais, nil
+}
+
+// Мягкое удаление: archived_at скрывает ИИ из всех запросов
+func (d *Database) ArchiveByOwner(ownerId string) (int64, *e.DBError) {
+	result := d.DB.Where("owner = ?", ownerId).Delete(&m.AiProduct{})
+
+	if result.Error != nil {
+		return 0, d.errorHandle(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (d *Database) RestoreByOwner(ownerId string) (int64, *e.DBError) {
+	result := d.DB.Unscoped().Model(&m.AiProduct{}).Where("owner = ? AND archived_at IS NOT NULL", ownerId).Update("archived_at", nil)
+
+	if result.Error != nil {
+		return 0, d.errorHandle(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,37 @@
+package auditdata
+
+import (
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) errorHandle(err error) *e.DBError {
+	if err == nil {
+		return nil
+	}
+
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		switch pgErr.Code {
+		case "23503":
+			return e.NewDBError(e.DbNotFound, "AI not found", err.Error())
+		}
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong", err.Error())
+}
+
+func (d *Database) Add(entries *[]m.AiProductAudit) *e.DBError {
+	if err := d.DB.Create(entries).Error; err != nil {
+		return d.errorHandle(err)
+	}
+
+	return nil
+}
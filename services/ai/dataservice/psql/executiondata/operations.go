@@ -0,0 +1,62 @@
+package executiondata
+
+import (
+	"errors"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) errorHandle(err error) *e.DBError {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Execution not found", err.Error())
+	}
+
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		switch pgErr.Code {
+		case "23503":
+			return e.NewDBError(e.DbNotFound, "Invalid ai_id or command_id value", err.Error())
+		}
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong", err.Error())
+}
+
+func (d *Database) Add(execution *m.AiExecution) *e.DBError {
+	if err := d.DB.Create(execution).Error; err != nil {
+		return d.errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) Get(conditions map[string]interface{}) (*m.AiExecution, *e.DBError) {
+	var execution m.AiExecution
+
+	if err := d.DB.Where(conditions).First(&execution).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &execution, nil
+}
+
+func (d *Database) GetByUser(userId string) (*[]m.AiExecution, *e.DBError) {
+	var executions []m.AiExecution
+
+	if err := d.DB.Where("user_id = ?", userId).Order("created_at").Find(&executions).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &executions, nil
+}
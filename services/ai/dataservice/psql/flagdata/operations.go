@@ -0,0 +1,73 @@
+package flagdata
+
+import (
+	"errors"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) errorHandle(err error) *e.DBError {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Flag not found", err.Error())
+	}
+
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		switch pgErr.Code {
+		case "23505":
+			return e.NewDBError(e.DbExist, "This rate is already flagged.", err.Error())
+
+		case "23503":
+			return e.NewDBError(e.DbNotFound, "Invalid rate_id value", err.Error())
+		}
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong", err.Error())
+}
+
+func (d *Database) Add(flag *m.AiRateFlag) *e.DBError {
+	if err := d.DB.Create(flag).Error; err != nil {
+		return d.errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) Get(conditions map[string]interface{}) (*m.AiRateFlag, *e.DBError) {
+	var flag m.AiRateFlag
+
+	if err := d.DB.Where(conditions).First(&flag).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &flag, nil
+}
+
+func (d *Database) GetMany(conditions map[string]interface{}, limit int) (*[]m.AiRateFlag, *e.DBError) {
+	var flags []m.AiRateFlag
+
+	if err := d.DB.Where(conditions).Order("created_at").Limit(limit).Find(&flags).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &flags, nil
+}
+
+func (d *Database) Update(flag *m.AiRateFlag, updatedFields map[string]interface{}) *e.DBError {
+	if err := d.DB.Model(flag).Updates(updatedFields).Error; err != nil {
+		return d.errorHandle(err)
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package ratingdata
 
 import (
+	"errors"
+	"time"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
 
@@ -17,6 +19,10 @@ func (d *Database) errorHandle(err error) *e.DBError {
 		return nil
 	}
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Rate not found", err.Error())
+	}
+
 	// Добавлять новые ошибки в этот свитч и использовать потом внутри if с ошибкой
 	pgErr, ok := err.(*pgconn.PgError)
 	if ok {
@@ -73,10 +79,76 @@ func (d *Database) GetCountAiRating(aiId string) (*int64, *e.DBError) {
 	return &result, nil
 }
 
-func (d *Database) Update(existRate *m.AiRate, newRate int16) *e.DBError {
-	if err := d.DB.Model(existRate).Updates(map[string]interface{}{"rate": newRate}).Error; err != nil {
+// Оценки сразу по нескольким ИИ одним запросом. ИИ без оценок в результат не попадают.
+func (d *Database) GetSummaries(aiIds []string) (*[]m.AiRatingSummary, *e.DBError) {
+	var summaries []m.AiRatingSummary
+
+	if err := d.DB.Model(&m.AiRate{}).
+		Select("ai_id, AVG(rate) AS average, COUNT(DISTINCT by_user_id) AS count").
+		Where("ai_id IN ?", aiIds).
+		Group("ai_id").
+		Scan(&summaries).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &summaries, nil
+}
+
+func (d *Database) GetRecentByNewAccounts(aiId string, since time.Time) (*[]m.AiRate, *e.DBError) {
+	var rates []m.AiRate
+
+	if err := d.DB.Where("ai_id = ? AND new_account = ? AND created_at >= ?", aiId, true, since).Find(&rates).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &rates, nil
+}
+
+func (d *Database) Update(existRate *m.AiRate, updatedFields map[string]interface{}) *e.DBError {
+	if err := d.DB.Model(existRate).Updates(updatedFields).Error; err != nil {
 		return d.errorHandle(err)
 	}
 
 	return nil
 }
+
+func (d *Database) GetByUser(userId string) (*[]m.AiRate, *e.DBError) {
+	var rates []m.AiRate
+
+	if err := d.DB.Where("by_user_id = ?", userId).Order("created_at").Find(&rates).Error; err != nil {
+		return nil, d.errorHandle(err)
+	}
+
+	return &rates, nil
+}
+
+func (d *Database) DeleteByUser(userId string) (int64, *e.DBError) {
+	result := d.DB.Where("by_user_id = ?", userId).Delete(&m.AiRate{})
+
+	if result.Error != nil {
+		return 0, d.errorHandle(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (d *Database) RestoreByUser(userId string) (int64, *e.DBError) {
+	result := d.DB.Unscoped().Model(&m.AiRate{}).Where("by_user_id = ? AND deleted_at IS NOT NULL", userId).Update("deleted_at", nil)
+
+	if result.Error != nil {
+		return 0, d.errorHandle(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Окончательно удаляет только ранее скрытые оценки
+func (d *Database) PurgeByUser(userId string) (int64, *e.DBError) {
+	result := d.DB.Unscoped().Where("by_user_id = ? AND deleted_at IS NOT NULL", userId).Delete(&m.AiRate{})
+
+	if result.Error != nil {
+		return 0, d.errorHandle(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
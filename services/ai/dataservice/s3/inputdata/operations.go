@@ -0,0 +1,51 @@
+package inputdata
+
+import (
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type Storage struct {
+	Bucket  string
+	Session *session.Session
+}
+
+// Размер входит в подпись, поэтому загрузить больше заявленного не получится
+func (s *Storage) PresignUpload(key string, size int64, expires time.Duration) (string, error) {
+	svc := s3.New(s.Session)
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		ContentLength: aws.Int64(size),
+	})
+
+	return req.Presign(expires)
+}
+
+func (s *Storage) Open(key string) (io.ReadCloser, error) {
+	svc := s3.New(s.Session)
+	object, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Body, nil
+}
+
+func (s *Storage) Delete(key string) error {
+	svc := s3.New(s.Session)
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
@@ -1,7 +1,9 @@
 package picturedata
 
 import (
-	"mime/multipart"
+	"io"
+	"mime"
+	"path/filepath"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -15,14 +17,15 @@ type Storage struct {
 	Session *session.Session
 }
 
-func (s *Storage) UploadFile(file multipart.File, fileName string) (string, error) {
+func (s *Storage) UploadFile(file io.Reader, fileName string) (string, error) {
 	uploader := s3manager.NewUploader(s.Session)
 
 	_, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(s.Bucket),
-		ACL:    aws.String("public-read"),
-		Key:    aws.String("/backgrounds/" + fileName),
-		Body:   file,
+		Bucket:      aws.String(s.Bucket),
+		ACL:         aws.String("public-read"),
+		Key:         aws.String("/backgrounds/" + fileName),
+		ContentType: aws.String(mime.TypeByExtension(filepath.Ext(fileName))),
+		Body:        file,
 	})
 
 	if err != nil {
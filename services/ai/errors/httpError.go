@@ -5,14 +5,16 @@ import (
 )
 
 const (
-	HttpInternalError       int = fiber.StatusInternalServerError
-	HttpAlreadyExist        int = fiber.StatusConflict
-	HttpNotFound            int = fiber.StatusNotFound
-	HttpBadRequest          int = fiber.StatusBadRequest
-	HttpForbidden           int = fiber.StatusForbidden
-	HttpUnauthorized        int = fiber.StatusUnauthorized
-	HttpTimeout             int = fiber.StatusGatewayTimeout
-	HttpUnprocessableEntity int = fiber.StatusUnprocessableEntity
+	HttpInternalError        int = fiber.StatusInternalServerError
+	HttpAlreadyExist         int = fiber.StatusConflict
+	HttpNotFound             int = fiber.StatusNotFound
+	HttpBadRequest           int = fiber.StatusBadRequest
+	HttpForbidden            int = fiber.StatusForbidden
+	HttpUnauthorized         int = fiber.StatusUnauthorized
+	HttpTimeout              int = fiber.StatusGatewayTimeout
+	HttpUnprocessableEntity  int = fiber.StatusUnprocessableEntity
+	HttpPayloadTooLarge      int = fiber.StatusRequestEntityTooLarge
+	HttpUnsupportedMediaType int = fiber.StatusUnsupportedMediaType
 )
 
 type (
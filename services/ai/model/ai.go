@@ -4,11 +4,11 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
 )
 
 type AuthScheme string
 
-// TODO: Синхронизация с сервисом пользователей.
 type AiProduct struct {
 	ID                uuid.UUID   `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
 	Owner             uuid.UUID   `json:"owner" gorm:"type:uuid;not null"`
@@ -21,4 +21,13 @@ type AiProduct struct {
 	Used              int         `json:"used" gorm:"type:int;default:0"`
 	CreatedAt         time.Time   `json:"created_at" gorm:"type:time"`
 	UpdatedAt         time.Time   `json:"updated_at" gorm:"type:time"`
+	// ИИ удалённого пользователя скрывается из выдачи, но остаётся в базе
+	ArchivedAt gorm.DeletedAt `json:"-" gorm:"type:timestamp"`
+}
+
+// ИИ разработчика с рейтингом для публичного профиля
+type PublishedAi struct {
+	AiProduct
+	AverageRating float64
+	RatingCount   int64
 }
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type AiProductAudit struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	AiId      uuid.UUID `json:"ai_id" gorm:"type:uuid;not null"`
+	ChangedBy uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	Field     string    `json:"field" gorm:"type:string;not null"`
+	OldValue  string    `json:"old_value" gorm:"type:string"`
+	NewValue  string    `json:"new_value" gorm:"type:string"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:time"`
+}
@@ -0,0 +1,39 @@
+package model
+
+type (
+	DeletionStep   string
+	DeletionAction string
+)
+
+// Шаги саги удаления аккаунта. Оркестратор - сервис пользователей.
+const (
+	DeletionStepAuth   DeletionStep = "auth"
+	DeletionStepAi     DeletionStep = "ai"
+	DeletionStepAvatar DeletionStep = "avatar"
+	DeletionStepUser   DeletionStep = "user"
+)
+
+const (
+	DeletionExecute    DeletionAction = "execute"
+	DeletionCompensate DeletionAction = "compensate"
+	// Необратимая зачистка после завершения саги, ответ на неё не отправляется
+	DeletionPurge DeletionAction = "purge"
+)
+
+type (
+	AccountDeletionCommand struct {
+		DeletionId string         `json:"deletion_id"`
+		UserId     string         `json:"user_id"`
+		Step       DeletionStep   `json:"step"`
+		Action     DeletionAction `json:"action"`
+		Picture    string         `json:"picture,omitempty"`
+	}
+
+	AccountDeletionReply struct {
+		DeletionId string         `json:"deletion_id"`
+		Step       DeletionStep   `json:"step"`
+		Action     DeletionAction `json:"action"`
+		Success    bool           `json:"success"`
+		Error      string         `json:"error,omitempty"`
+	}
+)
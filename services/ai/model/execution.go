@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type AiExecution struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	AiId      uuid.UUID `json:"ai_id" gorm:"type:uuid;not null"`
+	CommandId uuid.UUID `json:"command_id" gorm:"type:uuid;not null"`
+	UserId    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:time"`
+}
@@ -0,0 +1,15 @@
+package model
+
+type AiOwnershipAction string
+
+const (
+	AiCreated AiOwnershipAction = "created"
+	AiDeleted AiOwnershipAction = "deleted"
+)
+
+// Сервис пользователей по этим событиям ведёт список ИИ разработчика (user_owned)
+type AiOwnershipEvent struct {
+	AiId   string            `json:"ai_id"`
+	UserId string            `json:"user_id"`
+	Action AiOwnershipAction `json:"action"`
+}
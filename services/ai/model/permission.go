@@ -0,0 +1,9 @@
+package model
+
+// Права выдаются сервисом пользователей и приходят в access-токене
+type Permission string
+
+const (
+	PermissionPublishAi       Permission = "ai:publish"
+	PermissionModerateRatings Permission = "ratings:moderate"
+)
@@ -0,0 +1,11 @@
+package model
+
+type QueueName string
+
+const (
+	AccountDeleteAi    QueueName = "account_delete_ai"
+	AccountDeleteReply QueueName = "account_delete_reply"
+	AiOwnership        QueueName = "ai_ownership"
+	AiRelease          QueueName = "ai_release"
+	AiRating           QueueName = "ai_rating"
+)
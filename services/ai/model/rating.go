@@ -1,10 +1,53 @@
 package model
 
-import "github.com/gofrs/uuid"
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type RateFlagSource string
+
+const (
+	OwnerFlag RateFlagSource = "owner"
+	BurstFlag RateFlagSource = "burst"
+)
+
+type RateFlagStatus string
+
+const (
+	FlagPending  RateFlagStatus = "pending"
+	FlagResolved RateFlagStatus = "resolved"
+	FlagRejected RateFlagStatus = "rejected"
+)
 
 type AiRate struct {
-	ID       int       `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
-	ByUserId uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	AiId     uuid.UUID `json:"ai_id" gorm:"type:uuid;not null"`
-	Rate     int16     `json:"rate" gorm:"check:rate > 0;check:rate <= 5;not null"`
+	ID            int       `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	ByUserId      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	AiId          uuid.UUID `json:"ai_id" gorm:"type:uuid;not null"`
+	Rate          int16     `json:"rate" gorm:"check:rate > 0;check:rate <= 5;not null"`
+	VerifiedUsage bool      `json:"verified_usage" gorm:"default:false;not null"`
+	NewAccount    bool      `json:"-" gorm:"default:false;not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"type:time"`
+	// Оценки удаляемого пользователя скрываются до завершения саги, затем удаляются окончательно
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"type:timestamp"`
+}
+
+// Средняя оценка и число оценивших по одному ИИ
+type AiRatingSummary struct {
+	AiId    uuid.UUID
+	Average float64
+	Count   int64
+}
+
+type AiRateFlag struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	RateId    int            `json:"rate_id" gorm:"not null"`
+	AiId      uuid.UUID      `json:"ai_id" gorm:"type:uuid;not null"`
+	FlaggedBy uuid.NullUUID  `json:"flagged_by" gorm:"type:uuid"`
+	Source    RateFlagSource `json:"source" gorm:"type:string;not null"`
+	Reason    string         `json:"reason" gorm:"type:string;not null"`
+	Status    RateFlagStatus `json:"status" gorm:"type:string;default:pending;not null"`
+	CreatedAt time.Time      `json:"created_at" gorm:"type:time"`
 }
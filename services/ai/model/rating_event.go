@@ -0,0 +1,10 @@
+package model
+
+// Новая оценка ИИ. Сервис пользователей показывает её владельцу ИИ как уведомление.
+type AiRatingEvent struct {
+	EventId string `json:"event_id"`
+	AiId    string `json:"ai_id"`
+	AiName  string `json:"ai_name"`
+	OwnerId string `json:"owner_id"`
+	Rate    int16  `json:"rate"`
+}
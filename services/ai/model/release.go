@@ -0,0 +1,19 @@
+package model
+
+type AiReleaseKind string
+
+const (
+	ReleaseAiCreated      AiReleaseKind = "ai_created"
+	ReleaseCommandCreated AiReleaseKind = "command_created"
+)
+
+// Публикация разработчика. Сервис пользователей рассылает по ней уведомления подписчикам.
+// EventId защищает от дублей при повторной доставке.
+type AiReleaseEvent struct {
+	EventId     string        `json:"event_id"`
+	Kind        AiReleaseKind `json:"kind"`
+	AiId        string        `json:"ai_id"`
+	AiName      string        `json:"ai_name"`
+	DeveloperId string        `json:"developer_id"`
+	CommandName string        `json:"command_name,omitempty"`
+}
@@ -3,10 +3,12 @@ package ai
 import (
 	"fmt"
 	"strings"
+	"warehouseai/ai/adapter/broker"
 	"warehouseai/ai/adapter/grpc/client/auth"
 	"warehouseai/ai/adapter/grpc/client/user"
+	"warehouseai/ai/dataservice"
 	"warehouseai/ai/dataservice/psql/aidata"
-	"warehouseai/ai/dataservice/s3/picturedata"
+	"warehouseai/ai/dataservice/psql/auditdata"
 	e "warehouseai/ai/errors"
 	"warehouseai/ai/service/ai"
 
@@ -16,10 +18,12 @@ import (
 
 type Handler struct {
 	DB             *aidata.Database
+	AuditDB        *auditdata.Database
 	Logger         *logrus.Logger
-	PictureStorage *picturedata.Storage
+	PictureStorage dataservice.PictureInterface
 	UserClient     *user.UserGrpcClient
 	AuthClient     *auth.AuthGrpcClient
+	Broker         *broker.Broker
 }
 
 func (h *Handler) CreateAiWithKeyHandler(c *fiber.Ctx) error {
@@ -40,7 +44,7 @@ func (h *Handler) CreateAiWithKeyHandler(c *fiber.Ctx) error {
 		Image:             imageUrl,
 	}
 
-	newAi, svcErr := ai.CreateWithOwnKey(&request, userId, h.DB, h.Logger)
+	newAi, svcErr := ai.CreateWithOwnKey(&request, userId, h.DB, h.Broker, h.Logger)
 
 	if svcErr != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(err)
@@ -66,7 +70,7 @@ func (h *Handler) CreateAiWithoutKeyHandler(c *fiber.Ctx) error {
 		Image:          imageUrl,
 	}
 
-	newAi, svcErr := ai.CreateWithGeneratedKey(&request, userId, h.DB, h.Logger)
+	newAi, svcErr := ai.CreateWithGeneratedKey(&request, userId, h.DB, h.Broker, h.Logger)
 
 	if svcErr != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(err)
@@ -75,6 +79,29 @@ func (h *Handler) CreateAiWithoutKeyHandler(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(newAi)
 }
 
+func (h *Handler) UpdateAiHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	imageUrl := c.Locals("imageUrl").(string)
+
+	request := ai.UpdateRequest{
+		ID:                c.Query("id"),
+		Name:              c.FormValue("name"),
+		Description:       c.FormValue("description"),
+		AuthHeaderName:    c.FormValue("auth_header_name"),
+		AuthHeaderContent: c.FormValue("auth_header_content"),
+		Image:             imageUrl,
+		RegenerateKey:     c.FormValue("regenerate_key") == "true",
+	}
+
+	updatedAi, svcErr := ai.Update(&request, userId, h.DB, h.PictureStorage, h.AuditDB, h.Logger)
+
+	if svcErr != nil {
+		return c.Status(svcErr.ErrorCode).JSON(svcErr)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(updatedAi)
+}
+
 func (h *Handler) GetAIHandler(c *fiber.Ctx) error {
 	aiId := c.Query("id")
 	sessionId := c.Cookies("sessionId")
@@ -1,25 +1,37 @@
 package commands
 
 import (
+	"bytes"
 	"mime/multipart"
+	"time"
+	"warehouseai/ai/adapter/broker"
 	"warehouseai/ai/adapter/grpc/client/auth"
+	"warehouseai/ai/config"
+	"warehouseai/ai/dataservice"
+	"warehouseai/ai/dataservice/fs/inputdata"
 	"warehouseai/ai/dataservice/psql/aidata"
 	"warehouseai/ai/dataservice/psql/commanddata"
+	"warehouseai/ai/dataservice/psql/executiondata"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
 	"warehouseai/ai/service/command/create"
 	"warehouseai/ai/service/command/execute"
 	"warehouseai/ai/service/command/get"
+	"warehouseai/ai/service/command/upload"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
 type Handler struct {
-	CommandDB  *commanddata.Database
-	AiDB       *aidata.Database
-	Logger     *logrus.Logger
-	AuthClient *auth.AuthGrpcClient
+	CommandDB    *commanddata.Database
+	AiDB         *aidata.Database
+	ExecutionDB  *executiondata.Database
+	InputStorage dataservice.InputFileInterface
+	UploadCfg    config.UploadCfg
+	Logger       *logrus.Logger
+	AuthClient   *auth.AuthGrpcClient
+	Broker       *broker.Broker
 }
 
 func (h *Handler) CreateCommandHandler(c *fiber.Ctx) error {
@@ -30,7 +42,7 @@ func (h *Handler) CreateCommandHandler(c *fiber.Ctx) error {
 		return c.Status(response.ErrorCode).JSON(response)
 	}
 
-	if svcErr := create.CreateCommand(&commandCreds, h.CommandDB, h.Logger); svcErr != nil {
+	if svcErr := create.CreateCommand(&commandCreds, c.Locals("userId").(string), h.AiDB, h.CommandDB, h.Broker, h.Logger); svcErr != nil {
 		return c.Status(svcErr.ErrorCode).JSON(svcErr)
 	}
 
@@ -40,6 +52,7 @@ func (h *Handler) CreateCommandHandler(c *fiber.Ctx) error {
 // Решил логику определения типа запроса, для корректного парсинга, перенести сюда.
 // Все таки она не относится к бизнес-логике, а скорее к логике обработки запросов, и код в общем становится чище.
 func (h *Handler) ExecuteCommandHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
 	aiID := c.Query("ai_id")
 	commandName := c.Query("command_name")
 
@@ -57,14 +70,14 @@ func (h *Handler) ExecuteCommandHandler(c *fiber.Ctx) error {
 			return c.Status(resp.ErrorCode).JSON(resp.ErrorMessage)
 		}
 
-		boundary := string(c.Request().Header.MultipartFormBoundary())
 		request := execute.ExecuteCommandRequest[*multipart.Form]{
+			UserId:  userId,
 			AI:      existCommandInfo.AI,
 			Command: existCommandInfo.Command,
 			Payload: formPayload,
 		}
 
-		resp, exeErr := execute.ExecuteFormCommand(request, boundary, h.AiDB, h.Logger)
+		resp, exeErr := execute.ExecuteFormCommand(request, h.AiDB, h.ExecutionDB, h.InputStorage, h.Logger)
 
 		if exeErr != nil {
 			return c.Status(exeErr.ErrorCode).JSON(exeErr)
@@ -84,12 +97,13 @@ func (h *Handler) ExecuteCommandHandler(c *fiber.Ctx) error {
 		}
 
 		request := execute.ExecuteCommandRequest[map[string]interface{}]{
+			UserId:  userId,
 			AI:      existCommandInfo.AI,
 			Command: existCommandInfo.Command,
 			Payload: jsonPayload,
 		}
 
-		resp, exeErr := execute.ExecuteJSONCommand(request, h.AiDB, h.Logger)
+		resp, exeErr := execute.ExecuteJSONCommand(request, h.AiDB, h.ExecutionDB, h.Logger)
 
 		if exeErr != nil {
 			return c.Status(exeErr.ErrorCode).JSON(exeErr)
@@ -102,3 +116,51 @@ func (h *Handler) ExecuteCommandHandler(c *fiber.Ctx) error {
 		return c.Status(resp.Status).Send(resp.Raw.Bytes())
 	}
 }
+
+func (h *Handler) RequestUploadHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	var request upload.RequestUploadRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	response, err := upload.RequestUpload(userId, request, h.AiDB, h.InputStorage, h.UploadCfg, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// Заменяет S3 при STORAGE_DRIVER=fs: принимает файл по ссылке, подписанной inputdata.Storage
+func (h *Handler) LocalUploadHandler(c *fiber.Ctx) error {
+	storage, ok := h.InputStorage.(*inputdata.Storage)
+
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	key := c.Query("key")
+	size, err := storage.Verify(key, c.Query("size"), c.Query("expires"), c.Query("signature"))
+
+	if err != nil {
+		response := e.NewErrorResponse(e.HttpForbidden, err.Error())
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	if int64(len(c.Body())) != size {
+		response := e.NewErrorResponse(e.HttpBadRequest, "File size doesn't match the signed size.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	if err := storage.Save(key, bytes.NewReader(c.Body())); err != nil {
+		h.Logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Local upload")
+		response := e.NewErrorResponse(e.HttpInternalError, "Can't save file.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
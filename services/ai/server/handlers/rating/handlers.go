@@ -1,9 +1,15 @@
 package rating
 
 import (
+	"warehouseai/ai/adapter/broker"
+	"warehouseai/ai/adapter/grpc/client/user"
+	"warehouseai/ai/config"
 	"warehouseai/ai/dataservice/psql/aidata"
+	"warehouseai/ai/dataservice/psql/executiondata"
+	"warehouseai/ai/dataservice/psql/flagdata"
 	"warehouseai/ai/dataservice/psql/ratingdata"
 	e "warehouseai/ai/errors"
+	"warehouseai/ai/service/rating/flag"
 	"warehouseai/ai/service/rating/get"
 	"warehouseai/ai/service/rating/set"
 
@@ -12,9 +18,14 @@ import (
 )
 
 type Handler struct {
-	AiRepository     *aidata.Database
-	RatingRepository *ratingdata.Database
-	Logger           *logrus.Logger
+	AiRepository        *aidata.Database
+	RatingRepository    *ratingdata.Database
+	ExecutionRepository *executiondata.Database
+	FlagRepository      *flagdata.Database
+	UserClient          *user.UserGrpcClient
+	Broker              *broker.Broker
+	Config              config.RatingCfg
+	Logger              *logrus.Logger
 }
 
 func (h *Handler) GetAiRatingHandler(c *fiber.Ctx) error {
@@ -38,9 +49,56 @@ func (h *Handler) SetRatingForAiHandler(c *fiber.Ctx) error {
 		return c.Status(response.ErrorCode).JSON(response)
 	}
 
-	if err := set.SetAiRating(userId, rate, h.AiRepository, h.RatingRepository, h.Logger); err != nil {
+	response, err := set.SetAiRating(userId, rate, h.AiRepository, h.RatingRepository, h.ExecutionRepository, h.FlagRepository, h.UserClient, h.Broker, h.Config, h.Logger)
+
+	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err.ErrorMessage)
 	}
 
-	return c.SendStatus(fiber.StatusOK)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) FlagRatingHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	var request flag.FlagRatingRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	newFlag, err := flag.FlagRating(userId, request, h.AiRepository, h.RatingRepository, h.FlagRepository, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newFlag)
+}
+
+func (h *Handler) GetPendingFlagsHandler(c *fiber.Ctx) error {
+	flags, err := flag.GetPendingFlags(h.FlagRepository, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(flags)
+}
+
+func (h *Handler) ReviewFlagHandler(c *fiber.Ctx) error {
+	var request flag.ReviewFlagRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	reviewed, err := flag.ReviewFlag(request, h.FlagRepository, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reviewed)
 }
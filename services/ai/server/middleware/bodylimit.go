@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	e "warehouseai/ai/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Лимит тела для отдельных маршрутов. Лимит сервера (fiber.Config.BodyLimit) общий для всех маршрутов,
+// поэтому, если его поднимают ради загрузки файлов, остальным маршрутам прежний лимит возвращает этот мидлварь.
+func BodyLimit(limit int) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		if c.Request().Header.ContentLength() > limit || len(c.Request().Body()) > limit {
+			return c.Status(e.HttpPayloadTooLarge).JSON(e.NewErrorResponse(e.HttpPayloadTooLarge, "Request body is too large."))
+		}
+
+		return c.Next()
+	}
+}
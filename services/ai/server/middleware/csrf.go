@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+	e "warehouseai/ai/errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	CsrfCookie = "csrfToken"
+	CsrfHeader = "X-CSRF-Token"
+)
+
+// Double submit: auth выдаёт токен в cookie и в ответе GET /auth/csrf, клиент повторяет его в заголовке.
+// Чужая страница может заставить браузер отправить cookie, но не может узнать токен и выставить заголовок.
+// Клиенты с access-токеном в Authorization cookie не используют, поэтому пропускаются, но только если
+// verifyBearer подтвердил токен: сам заголовок ничего не доказывает. nil - исключений нет.
+func Csrf(verifyBearer func(token string) bool) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		if token := BearerToken(c); token != "" && verifyBearer != nil && verifyBearer(token) {
+			return c.Next()
+		}
+
+		// Без cookie сессии подделывать нечего
+		if c.Cookies("sessionId") == "" && c.Cookies("accessToken") == "" {
+			return c.Next()
+		}
+
+		token := c.Cookies(CsrfCookie)
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.Get(CsrfHeader))) != 1 {
+			return c.Status(e.HttpForbidden).JSON(e.NewErrorResponse(e.HttpForbidden, "Invalid CSRF token."))
+		}
+
+		return c.Next()
+	}
+}
+
+func BearerToken(c *fiber.Ctx) string {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"warehouseai/ai/config"
 	"warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	"warehouseai/ai/service"
@@ -9,7 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func Image(logger *logrus.Logger, picture dataservice.PictureInterface) func(c *fiber.Ctx) error {
+func Image(logger *logrus.Logger, picture dataservice.PictureInterface, cfg config.ImageCfg) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		pic, err := c.FormFile("image")
 
@@ -18,13 +19,36 @@ func Image(logger *logrus.Logger, picture dataservice.PictureInterface) func(c *
 			return c.Status(resp.ErrorCode).JSON(resp)
 		}
 
-		url, svcErr := service.UploadImage(pic, picture, logger)
+		urls, svcErr := service.UploadImage(pic, picture, cfg, logger)
 
 		if svcErr != nil {
 			return c.Status(svcErr.ErrorCode).JSON(svcErr)
 		}
 
-		c.Locals("imageUrl", url)
+		c.Locals("imageUrl", urls.Full)
+		c.Locals("imageUrls", urls)
+		return c.Next()
+	}
+}
+
+// То же, что и Image, но пропускает запрос без картинки - imageUrl тогда пустой.
+func ImageOptional(logger *logrus.Logger, picture dataservice.PictureInterface, cfg config.ImageCfg) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		pic, err := c.FormFile("image")
+
+		if err != nil {
+			c.Locals("imageUrl", "")
+			return c.Next()
+		}
+
+		urls, svcErr := service.UploadImage(pic, picture, cfg, logger)
+
+		if svcErr != nil {
+			return c.Status(svcErr.ErrorCode).JSON(svcErr)
+		}
+
+		c.Locals("imageUrl", urls.Full)
+		c.Locals("imageUrls", urls)
 		return c.Next()
 	}
 }
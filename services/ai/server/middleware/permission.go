@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	e "warehouseai/ai/errors"
+	"warehouseai/ai/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Для прав, которые пользователь может получить сам, подсказываем как это сделать
+var deniedMessages = map[model.Permission]string{
+	model.PermissionPublishAi: "Only developers can publish AI. Apply to become a developer first.",
+}
+
+// Ставится после SessionStrict: права берутся из access-токена или ответа Authenticate
+func RequirePermission(permission model.Permission) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		permissions, _ := c.Locals("permissions").([]string)
+
+		for _, granted := range permissions {
+			if granted == string(permission) {
+				return c.Next()
+			}
+		}
+
+		message, ok := deniedMessages[permission]
+
+		if !ok {
+			message = "Not enough permissions."
+		}
+
+		return c.Status(e.HttpForbidden).JSON(e.NewErrorResponse(e.HttpForbidden, message))
+	}
+}
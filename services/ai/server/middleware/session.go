@@ -1,63 +1,129 @@
 package middleware
 
 import (
+	"time"
 	"warehouseai/ai/adapter"
+	"warehouseai/ai/config"
 	e "warehouseai/ai/errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
-func SessionStrict(logger *logrus.Logger, auth adapter.AuthGrpcInterface) func(c *fiber.Ctx) error {
+func SessionStrict(logger *logrus.Logger, auth adapter.AuthGrpcInterface, cookie config.CookieCfg) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
+		if verifyAccessToken(c, auth) {
+			return c.Next()
+		}
+
 		sessionId := c.Cookies("sessionId")
 
 		if sessionId == "" {
 			return c.Status(e.HttpUnauthorized).JSON(e.NewErrorResponse(e.HttpUnauthorized, "Empty session key."))
 		}
 
-		userId, newSessionId, authErr := auth.Authenticate(sessionId)
-
-		if authErr != nil {
+		if authErr := authenticate(c, sessionId, auth, cookie); authErr != nil {
 			return c.Status(authErr.ErrorCode).JSON(authErr)
 		}
 
-		c.ClearCookie("sessionId")
-		c.Cookie(&fiber.Cookie{
-			Name:     "sessionId",
-			Value:    newSessionId,
-			SameSite: fiber.CookieSameSiteNoneMode,
-			Secure:   true,
-		})
-
-		c.Locals("userId", userId)
 		return c.Next()
 	}
 }
 
-func Session(logger *logrus.Logger, auth adapter.AuthGrpcInterface) func(c *fiber.Ctx) error {
+func Session(logger *logrus.Logger, auth adapter.AuthGrpcInterface, cookie config.CookieCfg) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
+		if verifyAccessToken(c, auth) {
+			return c.Next()
+		}
+
 		sessionId := c.Cookies("sessionId")
 
 		if sessionId == "" {
 			return c.Next()
 		}
 
-		userId, newSessionId, authErr := auth.Authenticate(sessionId)
-
-		if authErr != nil {
+		if authErr := authenticate(c, sessionId, auth, cookie); authErr != nil {
 			return c.Status(authErr.ErrorCode).JSON(authErr)
 		}
 
-		c.ClearCookie("sessionId")
+		return c.Next()
+	}
+}
+
+// Access-токен (из Authorization или cookie) проверяется локально. Если его нет или он истёк, идём в auth по gRPC.
+func verifyAccessToken(c *fiber.Ctx, auth adapter.AuthGrpcInterface) bool {
+	token := BearerToken(c)
+
+	if token == "" {
+		token = c.Cookies("accessToken")
+	}
+
+	if token == "" {
+		return false
+	}
+
+	claims, err := auth.VerifyAccessToken(token)
+
+	if err != nil {
+		return false
+	}
+
+	c.Locals("userId", claims.Subject)
+	c.Locals("roles", claims.Roles)
+	c.Locals("permissions", claims.Permissions)
+	c.Locals("isDeveloper", claims.IsDeveloper)
+	return true
+}
+
+func authenticate(c *fiber.Ctx, sessionId string, auth adapter.AuthGrpcInterface, cookie config.CookieCfg) *e.HttpErrorResponse {
+	resp, authErr := auth.Authenticate(sessionId)
+
+	if authErr != nil {
+		return authErr
+	}
+
+	// Сессия продлевается на стороне auth, cookie меняется только после ротации ID
+	if resp.SessionId != sessionId {
 		c.Cookie(&fiber.Cookie{
 			Name:     "sessionId",
-			Value:    newSessionId,
-			SameSite: fiber.CookieSameSiteNoneMode,
+			Value:    resp.SessionId,
+			SameSite: cookie.SameSite,
 			Secure:   true,
 		})
+	}
 
-		c.Locals("userId", userId)
-		return c.Next()
+	c.Locals("userId", resp.UserId)
+	c.Locals("roles", resp.Roles)
+	c.Locals("permissions", resp.Permissions)
+
+	// Свежий токен сразу проверяем: так заодно подтягиваются ключи для следующих запросов
+	if resp.AccessToken == "" {
+		return nil
+	}
+
+	claims, err := auth.VerifyAccessToken(resp.AccessToken)
+
+	if err != nil {
+		return nil
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "accessToken",
+		Value:    resp.AccessToken,
+		Expires:  time.Unix(claims.ExpiresAt, 0),
+		HTTPOnly: true,
+		SameSite: cookie.SameSite,
+		Secure:   true,
+	})
+
+	c.Locals("isDeveloper", claims.IsDeveloper)
+	return nil
+}
+
+// Для Csrf: Bearer-токен засчитывается, только если его подпись и срок действительны
+func AccessTokenVerifier(auth adapter.AuthGrpcInterface) func(token string) bool {
+	return func(token string) bool {
+		_, err := auth.VerifyAccessToken(token)
+		return err == nil
 	}
 }
@@ -0,0 +1,96 @@
+package account
+
+import (
+	"fmt"
+	"time"
+	"warehouseai/ai/adapter"
+	"warehouseai/ai/dataservice"
+	m "warehouseai/ai/model"
+	"warehouseai/ai/service"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Шаг саги удаления аккаунта: ИИ пользователя архивируются, его оценки скрываются.
+// Сервис пользователей узнаёт об архивации и восстановлении ИИ из событий владения.
+// Возвращает nil, если ответ оркестратору не нужен.
+func HandleAccountDeletion(command m.AccountDeletionCommand, ai dataservice.AiInterface, rating dataservice.RatingInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) *m.AccountDeletionReply {
+	reply := &m.AccountDeletionReply{DeletionId: command.DeletionId, Step: command.Step, Action: command.Action, Success: true}
+
+	var err error
+
+	switch {
+	case command.Action == m.DeletionPurge:
+		purge(command.UserId, rating, logger)
+		return nil
+	case command.Step != m.DeletionStepAi:
+		err = fmt.Errorf("unknown step %s", command.Step)
+	case command.Action == m.DeletionExecute:
+		err = archive(command.UserId, ai, rating, producer, logger)
+	case command.Action == m.DeletionCompensate:
+		err = restore(command.UserId, ai, rating, producer, logger)
+	default:
+		err = fmt.Errorf("unknown action %s", command.Action)
+	}
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error(), "deletion_id": command.DeletionId, "action": command.Action}).Info("Account deletion")
+		reply.Success = false
+		reply.Error = err.Error()
+	}
+
+	return reply
+}
+
+// При повторной доставке ИИ уже в архиве, поэтому события не отправляются второй раз
+func archive(userId string, ai dataservice.AiInterface, rating dataservice.RatingInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) error {
+	owned, err := ai.GetByOwner(userId)
+
+	if err != nil {
+		return fmt.Errorf("get AI: %s", err.Payload)
+	}
+
+	if _, err := ai.ArchiveByOwner(userId); err != nil {
+		return fmt.Errorf("archive AI: %s", err.Payload)
+	}
+
+	if _, err := rating.DeleteByUser(userId); err != nil {
+		return fmt.Errorf("delete ratings: %s", err.Payload)
+	}
+
+	for i := range *owned {
+		service.PublishOwnership(&(*owned)[i], m.AiDeleted, producer, logger)
+	}
+
+	return nil
+}
+
+// AiCreated для уже известных сервису пользователей ИИ ничего не меняет
+func restore(userId string, ai dataservice.AiInterface, rating dataservice.RatingInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) error {
+	if _, err := rating.RestoreByUser(userId); err != nil {
+		return fmt.Errorf("restore ratings: %s", err.Payload)
+	}
+
+	if _, err := ai.RestoreByOwner(userId); err != nil {
+		return fmt.Errorf("restore AI: %s", err.Payload)
+	}
+
+	owned, err := ai.GetByOwner(userId)
+
+	if err != nil {
+		return fmt.Errorf("get AI: %s", err.Payload)
+	}
+
+	for i := range *owned {
+		service.PublishOwnership(&(*owned)[i], m.AiCreated, producer, logger)
+	}
+
+	return nil
+}
+
+// Архив ИИ остаётся: на него ссылаются команды и история запусков
+func purge(userId string, rating dataservice.RatingInterface, logger *logrus.Logger) {
+	if _, err := rating.PurgeByUser(userId); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge ratings")
+	}
+}
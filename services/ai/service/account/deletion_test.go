@@ -0,0 +1,115 @@
+package account
+
+import (
+	"testing"
+	aMock "warehouseai/ai/adapter/mocks"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newCommand(action m.DeletionAction) m.AccountDeletionCommand {
+	return m.AccountDeletionCommand{
+		DeletionId: uuid.Must(uuid.NewV4()).String(),
+		UserId:     uuid.Must(uuid.NewV4()).String(),
+		Step:       m.DeletionStepAi,
+		Action:     action,
+	}
+}
+
+func newOwned(userId string) *[]m.AiProduct {
+	owner := uuid.FromStringOrNil(userId)
+
+	return &[]m.AiProduct{
+		{ID: uuid.Must(uuid.NewV4()), Owner: owner},
+		{ID: uuid.Must(uuid.NewV4()), Owner: owner},
+	}
+}
+
+func TestHandleAccountDeletionExecute(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	command := newCommand(m.DeletionExecute)
+	owned := newOwned(command.UserId)
+
+	aiMock.EXPECT().GetByOwner(command.UserId).Return(owned, nil).Times(1)
+	aiMock.EXPECT().ArchiveByOwner(command.UserId).Return(int64(2), nil).Times(1)
+	ratingMock.EXPECT().DeleteByUser(command.UserId).Return(int64(5), nil).Times(1)
+
+	for _, existAI := range *owned {
+		producerMock.EXPECT().SendOwnershipEvent(m.AiOwnershipEvent{AiId: existAI.ID.String(), UserId: command.UserId, Action: m.AiDeleted}).Return(nil).Times(1)
+	}
+
+	reply := HandleAccountDeletion(command, aiMock, ratingMock, producerMock, logger)
+
+	require.Equal(t, &m.AccountDeletionReply{DeletionId: command.DeletionId, Step: m.DeletionStepAi, Action: m.DeletionExecute, Success: true}, reply)
+}
+
+func TestHandleAccountDeletionExecuteError(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	command := newCommand(m.DeletionExecute)
+
+	aiMock.EXPECT().GetByOwner(command.UserId).Return(newOwned(command.UserId), nil).Times(1)
+	aiMock.EXPECT().ArchiveByOwner(command.UserId).Return(int64(0), e.NewDBError(e.DbSystem, "Something went wrong", "connection refused")).Times(1)
+
+	reply := HandleAccountDeletion(command, aiMock, ratingMock, producerMock, logger)
+
+	require.False(t, reply.Success)
+	require.Equal(t, "archive AI: connection refused", reply.Error)
+}
+
+func TestHandleAccountDeletionCompensate(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	command := newCommand(m.DeletionCompensate)
+	owned := newOwned(command.UserId)
+
+	ratingMock.EXPECT().RestoreByUser(command.UserId).Return(int64(5), nil).Times(1)
+	aiMock.EXPECT().RestoreByOwner(command.UserId).Return(int64(2), nil).Times(1)
+	aiMock.EXPECT().GetByOwner(command.UserId).Return(owned, nil).Times(1)
+
+	for _, existAI := range *owned {
+		producerMock.EXPECT().SendOwnershipEvent(m.AiOwnershipEvent{AiId: existAI.ID.String(), UserId: command.UserId, Action: m.AiCreated}).Return(nil).Times(1)
+	}
+
+	reply := HandleAccountDeletion(command, aiMock, ratingMock, producerMock, logger)
+
+	require.True(t, reply.Success)
+	require.Equal(t, m.DeletionCompensate, reply.Action)
+}
+
+func TestHandleAccountDeletionPurge(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	command := newCommand(m.DeletionPurge)
+
+	ratingMock.EXPECT().PurgeByUser(command.UserId).Return(int64(5), nil).Times(1)
+
+	require.Nil(t, HandleAccountDeletion(command, aiMock, ratingMock, producerMock, logger))
+}
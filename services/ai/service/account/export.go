@@ -0,0 +1,42 @@
+package account
+
+import (
+	"time"
+	"warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Всё, что сервис AI хранит о пользователе. Нужно сервису пользователей для выгрузки персональных данных.
+type UserData struct {
+	Owned      []m.AiProduct
+	Ratings    []m.AiRate
+	Executions []m.AiExecution
+}
+
+func GetUserData(userId string, ai dataservice.AiInterface, rating dataservice.RatingInterface, execution dataservice.ExecutionInterface, logger *logrus.Logger) (*UserData, *e.HttpErrorResponse) {
+	owned, dbErr := ai.GetByOwner(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get user data")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	ratings, dbErr := rating.GetByUser(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get user data")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	executions, dbErr := execution.GetByUser(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get user data")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return &UserData{Owned: *owned, Ratings: *ratings, Executions: *executions}, nil
+}
@@ -0,0 +1,57 @@
+package account
+
+import (
+	"testing"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetUserData(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4())
+	aiId := uuid.Must(uuid.NewV4())
+
+	owned := &[]m.AiProduct{{ID: aiId, Owner: userId, Name: "Summarizer"}}
+	ratings := &[]m.AiRate{{ID: 1, ByUserId: userId, AiId: aiId, Rate: 5}}
+	executions := &[]m.AiExecution{{ID: uuid.Must(uuid.NewV4()), AiId: aiId, UserId: userId}}
+
+	aiMock.EXPECT().GetByOwner(userId.String()).Return(owned, nil).Times(1)
+	ratingMock.EXPECT().GetByUser(userId.String()).Return(ratings, nil).Times(1)
+	executionMock.EXPECT().GetByUser(userId.String()).Return(executions, nil).Times(1)
+
+	data, err := GetUserData(userId.String(), aiMock, ratingMock, executionMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &UserData{Owned: *owned, Ratings: *ratings, Executions: *executions}, data)
+}
+
+func TestGetUserDataError(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	aiMock.EXPECT().GetByOwner(userId).Return(&[]m.AiProduct{}, nil).Times(1)
+	ratingMock.EXPECT().GetByUser(userId).Return(nil, e.NewDBError(e.DbSystem, "Something went wrong", "connection refused")).Times(1)
+
+	data, err := GetUserData(userId, aiMock, ratingMock, executionMock, logger)
+
+	require.Nil(t, data)
+	require.Equal(t, e.NewErrorResponse(e.HttpInternalError, "Something went wrong"), err)
+}
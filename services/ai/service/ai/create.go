@@ -5,9 +5,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"time"
+	"warehouseai/ai/adapter"
 	"warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
+	"warehouseai/ai/service"
 
 	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
@@ -30,12 +32,13 @@ type CreateWithKeyRequest struct {
 }
 
 type CreateResponse struct {
-	ID                string `json:"id"`
-	AuthHeaderContent string `json:"auth_header_content"`
-	AuthHeaderName    string `json:"auth_header_name"`
+	ID                string            `json:"id"`
+	AuthHeaderContent string            `json:"auth_header_content"`
+	AuthHeaderName    string            `json:"auth_header_name"`
+	Images            service.ImageUrls `json:"images"`
 }
 
-func CreateWithGeneratedKey(aiInfo *CreateWithoutKeyRequest, userId string, ai dataservice.AiInterface, logger *logrus.Logger) (*CreateResponse, *e.HttpErrorResponse) {
+func CreateWithGeneratedKey(aiInfo *CreateWithoutKeyRequest, userId string, ai dataservice.AiInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) (*CreateResponse, *e.HttpErrorResponse) {
 	key, err := generateToken(32)
 
 	if err != nil {
@@ -56,19 +59,23 @@ func CreateWithGeneratedKey(aiInfo *CreateWithoutKeyRequest, userId string, ai d
 		UpdatedAt:         time.Now(),
 	}
 
-	if dbErr := ai.Create(newAI); err != nil {
+	if dbErr := ai.Create(newAI); dbErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Create new AI")
 		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
+	service.PublishOwnership(newAI, m.AiCreated, producer, logger)
+	service.PublishRelease(m.AiReleaseEvent{Kind: m.ReleaseAiCreated, AiId: newAI.ID.String(), AiName: newAI.Name, DeveloperId: newAI.Owner.String()}, producer, logger)
+
 	return &CreateResponse{
 		ID:                newAI.ID.String(),
 		AuthHeaderContent: newAI.AuthHeaderContent,
 		AuthHeaderName:    newAI.AuthHeaderName,
+		Images:            service.ImageUrlsFrom(newAI.BackgroundUrl),
 	}, nil
 }
 
-func CreateWithOwnKey(aiInfo *CreateWithKeyRequest, userId string, ai dataservice.AiInterface, logger *logrus.Logger) (*CreateResponse, *e.HttpErrorResponse) {
+func CreateWithOwnKey(aiInfo *CreateWithKeyRequest, userId string, ai dataservice.AiInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) (*CreateResponse, *e.HttpErrorResponse) {
 	newAI := &m.AiProduct{
 		Name:              aiInfo.Name,
 		Description:       aiInfo.Description,
@@ -85,10 +92,14 @@ func CreateWithOwnKey(aiInfo *CreateWithKeyRequest, userId string, ai dataservic
 		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
+	service.PublishOwnership(newAI, m.AiCreated, producer, logger)
+	service.PublishRelease(m.AiReleaseEvent{Kind: m.ReleaseAiCreated, AiId: newAI.ID.String(), AiName: newAI.Name, DeveloperId: newAI.Owner.String()}, producer, logger)
+
 	return &CreateResponse{
 		ID:                newAI.ID.String(),
 		AuthHeaderContent: newAI.AuthHeaderContent,
 		AuthHeaderName:    newAI.AuthHeaderName,
+		Images:            service.ImageUrlsFrom(newAI.BackgroundUrl),
 	}, nil
 }
 
@@ -1,18 +1,22 @@
 package ai
 
 import (
+	"math"
 	"time"
 	"warehouseai/ai/adapter"
 	"warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
+	"warehouseai/ai/service"
 
+	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type GetAiResponse struct {
 	m.AiProduct
-	IsFavorite bool `json:"is_favorite"`
+	IsFavorite bool              `json:"is_favorite"`
+	Images     service.ImageUrls `json:"images"`
 }
 
 func GetById(id string, ai dataservice.AiInterface, logger *logrus.Logger) (*m.AiProduct, *e.HttpErrorResponse) {
@@ -37,6 +41,57 @@ func GetManyById(ids []string, ai dataservice.AiInterface, logger *logrus.Logger
 	return existAis, nil
 }
 
+// Снятые с публикации (архивные) ИИ не возвращаются
+func GetPublished(ids []string, ai dataservice.AiInterface, rating dataservice.RatingInterface, logger *logrus.Logger) (*[]m.PublishedAi, *e.HttpErrorResponse) {
+	published := make([]m.PublishedAi, 0, len(ids))
+
+	if len(ids) == 0 {
+		return &published, nil
+	}
+
+	existAis, dbErr := ai.GetMany(ids)
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return &published, nil
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get published AIs")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	aiIds := make([]string, 0, len(*existAis))
+
+	for _, existAi := range *existAis {
+		aiIds = append(aiIds, existAi.ID.String())
+	}
+
+	summaries, dbErr := rating.GetSummaries(aiIds)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get published AIs")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	summaryByAi := make(map[uuid.UUID]m.AiRatingSummary, len(*summaries))
+
+	for _, summary := range *summaries {
+		summaryByAi[summary.AiId] = summary
+	}
+
+	for _, existAi := range *existAis {
+		summary := summaryByAi[existAi.ID]
+
+		published = append(published, m.PublishedAi{
+			AiProduct:     existAi,
+			AverageRating: math.Round(summary.Average*100) / 100,
+			RatingCount:   summary.Count,
+		})
+	}
+
+	return &published, nil
+}
+
 func GetLike(field string, value string, ai dataservice.AiInterface, logger *logrus.Logger) (*[]m.AiProduct, *e.HttpErrorResponse) {
 	if field == "auth_scheme" || field == "api_key" {
 		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid parameters")
@@ -64,7 +119,7 @@ func GetByIdPreload(id string, ai dataservice.AiInterface, logger *logrus.Logger
 		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
-	return &GetAiResponse{*existAI, false}, nil
+	return &GetAiResponse{*existAI, false, service.ImageUrlsFrom(existAI.BackgroundUrl)}, nil
 }
 
 func GetByIdPreloadAuthed(userId string, aiId string, ai dataservice.AiInterface, user adapter.UserGrpcInterface, logger *logrus.Logger) (*GetAiResponse, *e.HttpErrorResponse) {
@@ -80,5 +135,5 @@ func GetByIdPreloadAuthed(userId string, aiId string, ai dataservice.AiInterface
 		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
-	return &GetAiResponse{*existAI, isAiFavorite}, nil
+	return &GetAiResponse{*existAI, isAiFavorite, service.ImageUrlsFrom(existAI.BackgroundUrl)}, nil
 }
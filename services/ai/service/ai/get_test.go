@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"testing"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetPublished(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	logger := logrus.New()
+
+	existAi := newExistAi(uuid.Must(uuid.NewV4()))
+	// У второго ИИ оценок нет, в сводке его нет
+	unratedAi := newExistAi(uuid.Must(uuid.NewV4()))
+	ids := []string{existAi.ID.String(), unratedAi.ID.String()}
+
+	aiMock.EXPECT().GetMany(ids).Return(&[]m.AiProduct{*existAi, *unratedAi}, nil).Times(1)
+	ratingMock.EXPECT().GetSummaries(ids).Return(&[]m.AiRatingSummary{{AiId: existAi.ID, Average: 4.3333, Count: 3}}, nil).Times(1)
+
+	published, err := GetPublished(ids, aiMock, ratingMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &[]m.PublishedAi{
+		{AiProduct: *existAi, AverageRating: 4.33, RatingCount: 3},
+		{AiProduct: *unratedAi, AverageRating: 0, RatingCount: 0},
+	}, published)
+}
+
+func TestGetPublishedEmpty(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	logger := logrus.New()
+
+	// Все ИИ архивные: GetMany ничего не нашёл
+	ids := []string{uuid.Must(uuid.NewV4()).String()}
+	aiMock.EXPECT().GetMany(ids).Return(nil, e.NewDBError(e.DbNotFound, "AIs not found.", "")).Times(1)
+
+	published, err := GetPublished(ids, aiMock, ratingMock, logger)
+
+	require.Nil(t, err)
+	require.Empty(t, *published)
+
+	published, err = GetPublished([]string{}, aiMock, ratingMock, logger)
+
+	require.Nil(t, err)
+	require.Empty(t, *published)
+}
@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+	"warehouseai/ai/service"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type UpdateRequest struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	AuthHeaderName    string `json:"auth_header_name"`
+	AuthHeaderContent string `json:"auth_header_content"`
+	Image             string `json:"image"`
+	RegenerateKey     bool   `json:"regenerate_key"`
+}
+
+type UpdateResponse struct {
+	ID                string            `json:"id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	BackgroundUrl     string            `json:"background_url"`
+	Images            service.ImageUrls `json:"images"`
+	AuthHeaderName    string            `json:"auth_header_name"`
+	AuthHeaderContent string            `json:"auth_header_content,omitempty"`
+}
+
+// Значения ключей в аудит не пишем, только факт изменения
+var secretFields = map[string]bool{"auth_header_content": true}
+
+func validateUpdateRequest(request *UpdateRequest) *e.HttpErrorResponse {
+	if _, err := uuid.FromString(request.ID); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid id value.")
+	}
+
+	if request.Name == "" && request.Description == "" && request.AuthHeaderName == "" && request.AuthHeaderContent == "" && request.Image == "" && !request.RegenerateKey {
+		return e.NewErrorResponse(e.HttpBadRequest, "Nothing to update.")
+	}
+
+	if request.RegenerateKey && request.AuthHeaderContent != "" {
+		return e.NewErrorResponse(e.HttpBadRequest, "Provide either auth_header_content or regenerate_key, not both.")
+	}
+
+	return nil
+}
+
+func Update(request *UpdateRequest, userId string, ai dataservice.AiInterface, picture dataservice.PictureInterface, audit dataservice.AuditInterface, logger *logrus.Logger) (*UpdateResponse, *e.HttpErrorResponse) {
+	// Новая картинка уже загружена мидлварей, поэтому при любой ошибке её нужно удалить
+	fail := func(err *e.HttpErrorResponse) (*UpdateResponse, *e.HttpErrorResponse) {
+		service.DeleteImage(request.Image, picture, logger)
+		return nil, err
+	}
+
+	if err := validateUpdateRequest(request); err != nil {
+		return fail(err)
+	}
+
+	existAI, dbErr := ai.Get(map[string]interface{}{"id": request.ID})
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update AI")
+		return fail(e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message))
+	}
+
+	if existAI.Owner.String() != userId {
+		return fail(e.NewErrorResponse(e.HttpForbidden, "Only the owner can update this AI."))
+	}
+
+	if request.RegenerateKey && !strings.HasPrefix(existAI.AuthHeaderContent, "wh.") {
+		return fail(e.NewErrorResponse(e.HttpBadRequest, "This AI uses its own key, provide auth_header_content instead."))
+	}
+
+	oldValues := map[string]string{
+		"name":                existAI.Name,
+		"description":         existAI.Description,
+		"auth_header_name":    existAI.AuthHeaderName,
+		"auth_header_content": existAI.AuthHeaderContent,
+		"background_url":      existAI.BackgroundUrl,
+	}
+
+	updatedFields := map[string]interface{}{}
+	setIfChanged(updatedFields, oldValues, "name", request.Name)
+	setIfChanged(updatedFields, oldValues, "description", request.Description)
+	setIfChanged(updatedFields, oldValues, "auth_header_name", request.AuthHeaderName)
+	setIfChanged(updatedFields, oldValues, "auth_header_content", request.AuthHeaderContent)
+	setIfChanged(updatedFields, oldValues, "background_url", request.Image)
+
+	if request.RegenerateKey {
+		key, err := generateToken(32)
+
+		if err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Update AI")
+			return fail(e.NewErrorResponse(e.HttpInternalError, err.Error()))
+		}
+
+		updatedFields["auth_header_content"] = fmt.Sprintf("wh.%s", key)
+	}
+
+	if len(updatedFields) != 0 {
+		if dbErr := ai.Update(existAI, updatedFields); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update AI")
+			return fail(e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message))
+		}
+
+		entries := newAuditEntries(existAI.ID, userId, oldValues, updatedFields)
+
+		if dbErr := audit.Add(&entries); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update AI audit")
+		}
+	}
+
+	if _, ok := updatedFields["background_url"]; ok {
+		service.DeleteImage(oldValues["background_url"], picture, logger)
+	}
+
+	response := &UpdateResponse{
+		ID:             existAI.ID.String(),
+		Name:           valueOf(updatedFields, oldValues, "name"),
+		Description:    valueOf(updatedFields, oldValues, "description"),
+		BackgroundUrl:  valueOf(updatedFields, oldValues, "background_url"),
+		AuthHeaderName: valueOf(updatedFields, oldValues, "auth_header_name"),
+	}
+
+	response.Images = service.ImageUrlsFrom(response.BackgroundUrl)
+
+	if request.RegenerateKey {
+		response.AuthHeaderContent = valueOf(updatedFields, oldValues, "auth_header_content")
+	}
+
+	return response, nil
+}
+
+func setIfChanged(updatedFields map[string]interface{}, oldValues map[string]string, field string, value string) {
+	if value != "" && value != oldValues[field] {
+		updatedFields[field] = value
+	}
+}
+
+func valueOf(updatedFields map[string]interface{}, oldValues map[string]string, field string) string {
+	if value, ok := updatedFields[field]; ok {
+		return value.(string)
+	}
+
+	return oldValues[field]
+}
+
+func newAuditEntries(aiId uuid.UUID, userId string, oldValues map[string]string, updatedFields map[string]interface{}) []m.AiProductAudit {
+	var entries []m.AiProductAudit
+
+	for field, value := range updatedFields {
+		entry := m.AiProductAudit{
+			AiId:      aiId,
+			ChangedBy: uuid.Must(uuid.FromString(userId)),
+			Field:     field,
+			CreatedAt: time.Now(),
+		}
+
+		if !secretFields[field] {
+			entry.OldValue = oldValues[field]
+			entry.NewValue = value.(string)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newExistAi(owner uuid.UUID) *m.AiProduct {
+	return &m.AiProduct{
+		ID:                uuid.Must(uuid.NewV4()),
+		Owner:             owner,
+		Name:              "Old name",
+		Description:       "Old description",
+		AuthHeaderName:    "Authorization",
+		AuthHeaderContent: "wh.old-key",
+		BackgroundUrl:     "https://storage.example/backgrounds/background.old.png",
+	}
+}
+
+func TestUpdateAi(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	pictureMock := dMock.NewMockPictureInterface(ctl)
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4())
+	existAi := newExistAi(userId)
+	request := &UpdateRequest{
+		ID:    existAi.ID.String(),
+		Name:  "New name",
+		Image: "https://storage.example/backgrounds/background.new.png",
+	}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.ID}).Return(existAi, nil).Times(1)
+	aiMock.EXPECT().Update(existAi, map[string]interface{}{"name": request.Name, "background_url": request.Image}).Return(nil).Times(1)
+	auditMock.EXPECT().Add(gomock.Any()).DoAndReturn(func(entries *[]m.AiProductAudit) *e.DBError {
+		require.Len(t, *entries, 2)
+		return nil
+	}).Times(1)
+	pictureMock.EXPECT().DeleteImage("background.old.png").Return(nil).Times(1)
+
+	response, err := Update(request, userId.String(), aiMock, pictureMock, auditMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, request.Name, response.Name)
+	require.Equal(t, request.Image, response.BackgroundUrl)
+	require.Empty(t, response.AuthHeaderContent)
+}
+
+func TestUpdateAiRegenerateKey(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	pictureMock := dMock.NewMockPictureInterface(ctl)
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4())
+	existAi := newExistAi(userId)
+	request := &UpdateRequest{ID: existAi.ID.String(), RegenerateKey: true}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.ID}).Return(existAi, nil).Times(1)
+	aiMock.EXPECT().Update(existAi, gomock.Any()).Return(nil).Times(1)
+	auditMock.EXPECT().Add(gomock.Any()).DoAndReturn(func(entries *[]m.AiProductAudit) *e.DBError {
+		require.Len(t, *entries, 1)
+		require.Equal(t, "auth_header_content", (*entries)[0].Field)
+		require.Empty(t, (*entries)[0].NewValue)
+		return nil
+	}).Times(1)
+
+	response, err := Update(request, userId.String(), aiMock, pictureMock, auditMock, logger)
+
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(response.AuthHeaderContent, "wh."))
+	require.NotEqual(t, existAi.AuthHeaderContent, response.AuthHeaderContent)
+}
+
+func TestUpdateAiNotOwner(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	pictureMock := dMock.NewMockPictureInterface(ctl)
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	existAi := newExistAi(uuid.Must(uuid.NewV4()))
+	request := &UpdateRequest{
+		ID:    existAi.ID.String(),
+		Image: "https://storage.example/backgrounds/background.new.png",
+	}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.ID}).Return(existAi, nil).Times(1)
+	// Загруженная мидлварей картинка должна быть удалена
+	pictureMock.EXPECT().DeleteImage("background.new.png").Return(nil).Times(1)
+
+	response, err := Update(request, uuid.Must(uuid.NewV4()).String(), aiMock, pictureMock, auditMock, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpForbidden, "Only the owner can update this AI."), err)
+}
+
+func TestUpdateAiInvalidId(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	pictureMock := dMock.NewMockPictureInterface(ctl)
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	request := &UpdateRequest{
+		ID:    "ai",
+		Image: "https://storage.example/backgrounds/background.new.png",
+	}
+
+	// До базы запрос не доходит, загруженная картинка удаляется
+	pictureMock.EXPECT().DeleteImage("background.new.png").Return(nil).Times(1)
+
+	response, err := Update(request, uuid.Must(uuid.NewV4()).String(), aiMock, pictureMock, auditMock, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid id value."), err)
+}
+
+func TestUpdateAiValidateError(t *testing.T) {
+	cases := []struct {
+		name          string
+		request       *UpdateRequest
+		expectedError *e.HttpErrorResponse
+	}{
+		{
+			name:          "Invalid id.",
+			request:       &UpdateRequest{ID: "ai", Name: "New name"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid id value."),
+		},
+		{
+			name:          "Missing id.",
+			request:       &UpdateRequest{Name: "New name"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid id value."),
+		},
+		{
+			name:          "Empty request.",
+			request:       &UpdateRequest{ID: uuid.Must(uuid.NewV4()).String()},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Nothing to update."),
+		},
+		{
+			name:          "Own key and regenerate.",
+			request:       &UpdateRequest{ID: uuid.Must(uuid.NewV4()).String(), AuthHeaderContent: "key", RegenerateKey: true},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Provide either auth_header_content or regenerate_key, not both."),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			err := validateUpdateRequest(tCase.request)
+
+			require.Equal(t, tCase.expectedError, err)
+		})
+	}
+}
@@ -2,9 +2,11 @@ package create
 
 import (
 	"time"
+	"warehouseai/ai/adapter"
 	"warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
+	"warehouseai/ai/service"
 
 	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
@@ -21,11 +23,23 @@ type CreateCommandRequest struct {
 	URL         string                 `json:"url"`
 }
 
-func CreateCommand(request *CreateCommandRequest, command dataservice.CommandInterface, logger *logrus.Logger) *e.HttpErrorResponse {
+func CreateCommand(request *CreateCommandRequest, userId string, ai dataservice.AiInterface, command dataservice.CommandInterface, producer adapter.EventProducerInterface, logger *logrus.Logger) *e.HttpErrorResponse {
 	if err := validateRequest(request); err != nil {
 		return err
 	}
 
+	existAI, dbErr := ai.Get(map[string]interface{}{"id": request.AiID})
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Add new command to AI")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	// Команда публикуется от имени владельца и рассылается его подписчикам
+	if existAI.Owner.String() != userId {
+		return e.NewErrorResponse(e.HttpForbidden, "Only the owner can add commands to this AI.")
+	}
+
 	newCommand := &m.AiCommand{
 		Name:        request.Name,
 		AIID:        uuid.FromStringOrNil(request.AiID),
@@ -39,11 +53,18 @@ func CreateCommand(request *CreateCommandRequest, command dataservice.CommandInt
 		UpdatedAt:   time.Now(),
 	}
 
-	// TODO: Пофиксить 500 ошибку на добавление команды к несуществующей нейронке
 	if dbErr := command.Create(newCommand); dbErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Add new command to AI")
 		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
+	service.PublishRelease(m.AiReleaseEvent{
+		Kind:        m.ReleaseCommandCreated,
+		AiId:        existAI.ID.String(),
+		AiName:      existAI.Name,
+		DeveloperId: existAI.Owner.String(),
+		CommandName: newCommand.Name,
+	}, producer, logger)
+
 	return nil
 }
@@ -0,0 +1,58 @@
+package create
+
+import (
+	"testing"
+	aMock "warehouseai/ai/adapter/mocks"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateCommand(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	commandMock := dMock.NewMockCommandInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.NewV4()), Owner: uuid.Must(uuid.NewV4()), Name: "Summarizer"}
+	request := &CreateCommandRequest{Name: "summarize", AiID: existAi.ID.String()}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiID}).Return(existAi, nil).Times(1)
+	commandMock.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	producerMock.EXPECT().SendReleaseEvent(gomock.Any()).DoAndReturn(func(event m.AiReleaseEvent) error {
+		require.Equal(t, m.ReleaseCommandCreated, event.Kind)
+		require.Equal(t, existAi.Owner.String(), event.DeveloperId)
+		require.Equal(t, "summarize", event.CommandName)
+		require.NotEmpty(t, event.EventId)
+		return nil
+	}).Times(1)
+
+	err := CreateCommand(request, existAi.Owner.String(), aiMock, commandMock, producerMock, logger)
+
+	require.Nil(t, err)
+}
+
+func TestCreateCommandNotOwner(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	commandMock := dMock.NewMockCommandInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.NewV4()), Owner: uuid.Must(uuid.NewV4()), Name: "Summarizer"}
+	request := &CreateCommandRequest{Name: "summarize", AiID: existAi.ID.String()}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiID}).Return(existAi, nil).Times(1)
+
+	err := CreateCommand(request, uuid.Must(uuid.NewV4()).String(), aiMock, commandMock, producerMock, logger)
+
+	require.Equal(t, e.NewErrorResponse(e.HttpForbidden, "Only the owner can add commands to this AI."), err)
+}
@@ -9,16 +9,19 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"path"
 	"strconv"
 	"time"
 	d "warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
 
+	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 type ExecuteCommandRequest[T *multipart.Form | map[string]interface{}] struct {
+	UserId  string
 	AI      *m.AiProduct
 	Command *m.AiCommand
 	Payload T
@@ -38,6 +41,7 @@ type makeRequestResponse struct {
 func ExecuteJSONCommand(
 	request ExecuteCommandRequest[map[string]interface{}],
 	aiRepository d.AiInterface,
+	executionRepository d.ExecutionInterface,
 	logger *logrus.Logger,
 ) (*ExecuteCommandResponse, *e.HttpErrorResponse) {
 	if err := validateJSONPayload(&request); err != nil {
@@ -74,6 +78,10 @@ func ExecuteJSONCommand(
 		return nil, err
 	}
 
+	if *responseStatus < http.StatusBadRequest {
+		recordExecution(request.UserId, request.AI, request.Command, executionRepository, logger)
+	}
+
 	return &ExecuteCommandResponse{
 		Raw:     cmdResponse,
 		Headers: *responseHeaders,
@@ -83,58 +91,33 @@ func ExecuteJSONCommand(
 
 func ExecuteFormCommand(
 	request ExecuteCommandRequest[*multipart.Form],
-	boundary string,
 	aiRepository d.AiInterface,
+	executionRepository d.ExecutionInterface,
+	inputFiles d.InputFileInterface,
 	logger *logrus.Logger,
 ) (*ExecuteCommandResponse, *e.HttpErrorResponse) {
-	if err := validateFormDataPayload(&request); err != nil {
+	objectKeys, err := validateFormDataPayload(&request)
+
+	if err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("Execute Command")
 		return nil, err
 	}
 
+	// Тело не собирается в памяти: поля, файлы и объекты из хранилища пишутся в pipe по мере чтения запросом
+	bodyReader, bodyWriter := io.Pipe()
+	writer := multipart.NewWriter(bodyWriter)
+	defer bodyReader.Close()
+
 	headers := make(map[string]string)
-	headers["Content-Type"] = fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
+	headers["Content-Type"] = writer.FormDataContentType()
 	headers[request.AI.AuthHeaderName] = request.AI.AuthHeaderContent
 
-	// Конвертим mutlipart/form-data в буффер
-	var buffer bytes.Buffer
-	writer := multipart.NewWriter(&buffer)
-	defer writer.Close()
-
-	// Iterate over the form fields and add them to the writer
-	for fieldName, fieldValues := range request.Payload.Value {
-		for _, fieldValue := range fieldValues {
-			writer.WriteField(fieldName, fieldValue)
-		}
-	}
-
-	// Iterate over the form files and add them to the writer
-	for fieldName, fileHeaders := range request.Payload.File {
-		for _, fileHeader := range fileHeaders {
-			fileWriter, err := writer.CreateFormFile(fieldName, fileHeader.Filename)
-			if err != nil {
-				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Execute Command")
-				return nil, e.NewErrorResponse(e.HttpInternalError, fmt.Sprintf("Error creating form file: %s", err))
-			}
-
-			// Open the file and copy its content to the form
-			file, err := fileHeader.Open()
-			if err != nil {
-				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Execute Command")
-				return nil, e.NewErrorResponse(e.HttpInternalError, fmt.Sprintf("Error opening file: %s", err))
-			}
-			defer file.Close()
-
-			_, err = io.Copy(fileWriter, file)
-			if err != nil {
-				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Execute Command")
-				return nil, e.NewErrorResponse(e.HttpInternalError, fmt.Sprintf("Error copying file: %s", err))
-			}
-		}
-	}
+	go func() {
+		bodyWriter.CloseWithError(writeFormPayload(writer, request.Payload, objectKeys, inputFiles))
+	}()
 
 	executeCtx := context.Background()
-	reqResponse, reqErr := makeHTTPRequest(executeCtx, request.Command.URL, request.Command.RequestType, headers, &buffer)
+	reqResponse, reqErr := makeHTTPRequest(executeCtx, request.Command.URL, request.Command.RequestType, headers, bodyReader)
 
 	if reqErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": reqErr.ErrorMessage}).Info("Execute Command")
@@ -152,6 +135,17 @@ func ExecuteFormCommand(
 		return nil, err
 	}
 
+	if *responseStatus < http.StatusBadRequest {
+		recordExecution(request.UserId, request.AI, request.Command, executionRepository, logger)
+
+		// Загруженные объекты одноразовые, брошенные загрузки чистит lifecycle-правило бакета
+		for _, objectKey := range objectKeys {
+			if err := inputFiles.Delete(objectKey); err != nil {
+				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Delete command input")
+			}
+		}
+	}
+
 	return &ExecuteCommandResponse{
 		Raw:     cmdResponse,
 		Headers: *responseHeaders,
@@ -159,6 +153,69 @@ func ExecuteFormCommand(
 	}, nil
 }
 
+func writeFormPayload(writer *multipart.Writer, payload *multipart.Form, objectKeys map[string]string, inputFiles d.InputFileInterface) error {
+	for fieldName, fieldValues := range payload.Value {
+		// Значение такого поля - ключ объекта, вместо него отправляем сам объект
+		if _, ok := objectKeys[fieldName]; ok {
+			continue
+		}
+
+		for _, fieldValue := range fieldValues {
+			if err := writer.WriteField(fieldName, fieldValue); err != nil {
+				return err
+			}
+		}
+	}
+
+	for fieldName, fileHeaders := range payload.File {
+		for _, fileHeader := range fileHeaders {
+			file, err := fileHeader.Open()
+
+			if err != nil {
+				return fmt.Errorf("error opening file: %w", err)
+			}
+
+			err = copyFormFile(writer, fieldName, fileHeader.Filename, file)
+			file.Close()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for fieldName, objectKey := range objectKeys {
+		object, err := inputFiles.Open(objectKey)
+
+		if err != nil {
+			return fmt.Errorf("error opening uploaded object: %w", err)
+		}
+
+		err = copyFormFile(writer, fieldName, path.Base(objectKey), object)
+		object.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func copyFormFile(writer *multipart.Writer, fieldName string, fileName string, file io.Reader) error {
+	fileWriter, err := writer.CreateFormFile(fieldName, fileName)
+
+	if err != nil {
+		return fmt.Errorf("error creating form file: %w", err)
+	}
+
+	if _, err := io.Copy(fileWriter, file); err != nil {
+		return fmt.Errorf("error copying file: %w", err)
+	}
+
+	return nil
+}
+
 func updateUsageCount(existAi *m.AiProduct, ai d.AiInterface) *e.HttpErrorResponse {
 	if err := ai.Update(existAi, map[string]interface{}{"used": existAi.Used + 1}); err != nil {
 		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
@@ -167,7 +224,20 @@ func updateUsageCount(existAi *m.AiProduct, ai d.AiInterface) *e.HttpErrorRespon
 	return nil
 }
 
-func makeHTTPRequest(executeCtx context.Context, fullUrl string, httpMethod string, headers map[string]string, body *bytes.Buffer) (*http.Response, *e.HttpErrorResponse) {
+// Запись о запуске нужна для проверки права на оценку, поэтому ошибка только логируется и не ломает ответ нейронки
+func recordExecution(userId string, existAi *m.AiProduct, command *m.AiCommand, execution d.ExecutionInterface, logger *logrus.Logger) {
+	newExecution := &m.AiExecution{
+		AiId:      existAi.ID,
+		CommandId: command.ID,
+		UserId:    uuid.FromStringOrNil(userId),
+	}
+
+	if err := execution.Add(newExecution); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Record command execution")
+	}
+}
+
+func makeHTTPRequest(executeCtx context.Context, fullUrl string, httpMethod string, headers map[string]string, body io.Reader) (*http.Response, *e.HttpErrorResponse) {
 	httpClient := http.Client{}
 
 	url, err := url.Parse(fullUrl)
@@ -0,0 +1,106 @@
+package execute
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"warehouseai/ai/dataservice/memory/inputdata"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+	"warehouseai/ai/service/command/upload"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/datatypes"
+)
+
+func newFormCommand(url string) *m.AiCommand {
+	return &m.AiCommand{
+		ID:          uuid.Must(uuid.NewV4()),
+		Name:        "transcribe",
+		PayloadType: string(m.FormData),
+		RequestType: http.MethodPost,
+		OutputType:  string(m.Text),
+		URL:         url,
+		Payload: datatypes.JSONMap{
+			"audio": map[string]interface{}{"type": "input", "requirement": "require", "data": "file"},
+		},
+	}
+}
+
+func TestExecuteFormCommandWithUploadedObject(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	inputFiles := inputdata.NewStorage()
+	logger := logrus.New()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("audio")
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "recording.mp3", header.Filename)
+		require.Equal(t, "large audio payload", string(content))
+
+		w.Write([]byte(`{"text": "ok"}`))
+	}))
+	defer upstream.Close()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.NewV4()), AuthHeaderName: "Authorization", AuthHeaderContent: "key"}
+	command := newFormCommand(upstream.URL)
+	objectKey := upload.ObjectKeyPrefix(userId, command.ID.String(), "audio") + uuid.Must(uuid.NewV4()).String() + "/recording.mp3"
+
+	require.NoError(t, inputFiles.Save(objectKey, strings.NewReader("large audio payload")))
+
+	request := ExecuteCommandRequest[*multipart.Form]{
+		UserId:  userId,
+		AI:      existAi,
+		Command: command,
+		Payload: &multipart.Form{Value: map[string][]string{"audio": {objectKey}}},
+	}
+
+	aiMock.EXPECT().Update(existAi, map[string]interface{}{"used": 1}).Return(nil).Times(1)
+	executionMock.EXPECT().Add(gomock.Any()).Return(nil).Times(1)
+
+	response, err := ExecuteFormCommand(request, aiMock, executionMock, inputFiles, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, response.Status)
+
+	// После успешного запуска объект удаляется
+	_, openErr := inputFiles.Open(objectKey)
+	require.Error(t, openErr)
+}
+
+func TestExecuteFormCommandForeignObject(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	logger := logrus.New()
+
+	command := newFormCommand("http://upstream.invalid")
+	foreignKey := upload.ObjectKeyPrefix(uuid.Must(uuid.NewV4()).String(), command.ID.String(), "audio") + "id/recording.mp3"
+
+	request := ExecuteCommandRequest[*multipart.Form]{
+		UserId:  uuid.Must(uuid.NewV4()).String(),
+		AI:      &m.AiProduct{},
+		Command: command,
+		Payload: &multipart.Form{Value: map[string][]string{"audio": {foreignKey}}},
+	}
+
+	response, err := ExecuteFormCommand(request, aiMock, executionMock, inputdata.NewStorage(), logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpForbidden, `field "audio" has incorrect. Object key wasn't issued for this field`), err)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
+	"warehouseai/ai/service/command/upload"
 )
 
 type rule func(originField m.AiCommandField, originFieldName string, actualFieldValue interface{}) error
@@ -104,20 +105,23 @@ func validateJSONPayload(request *ExecuteCommandRequest[map[string]interface{}])
 	return nil
 }
 
-func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) *e.HttpErrorResponse {
+// Файловое поле можно передать либо файлом, либо ключом объекта, загруженного по подписанной ссылке.
+// Возвращает ключи таких объектов по именам полей.
+func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) (map[string]string, *e.HttpErrorResponse) {
 	var typedOriginPayload map[string]m.AiCommandField // не инициализируем мапу, так как в нее будет парситься payload из бд
 	requiredFields := make(map[string]bool)
 	formTextFields := make(map[string][]string)
+	objectKeys := make(map[string]string)
 
 	// Конвертим оригинальную типизацию полей для упрощения проверки
 	rawOriginPayload, err := json.Marshal(request.Command.Payload)
 
 	if err != nil {
-		return e.NewErrorResponse(e.HttpInternalError, err.Error())
+		return nil, e.NewErrorResponse(e.HttpInternalError, err.Error())
 	}
 
 	if err := json.Unmarshal(rawOriginPayload, &typedOriginPayload); err != nil {
-		return e.NewErrorResponse(e.HttpInternalError, err.Error())
+		return nil, e.NewErrorResponse(e.HttpInternalError, err.Error())
 	}
 
 	// Получаем все обязательные поля
@@ -131,7 +135,13 @@ func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) *e
 	for fieldName, fieldDeclaration := range typedOriginPayload {
 		if fieldDeclaration.Data == m.File {
 			if _, ok := request.Payload.File[fieldName]; !ok {
-				return e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`field "%s" has incorrect. Field must be provided as File type`, fieldName))
+				objectKey, err := validateObjectKey(request, fieldName)
+
+				if err != nil {
+					return nil, err
+				}
+
+				objectKeys[fieldName] = objectKey
 			}
 
 			delete(requiredFields, fieldName) // удаляем из обязательных, так как мы проверили его наличие
@@ -141,7 +151,7 @@ func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) *e
 			values, ok := request.Payload.Value[fieldName]
 
 			if !ok {
-				return e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`field "%s" has incorrect. Field must be provided as Text type`, fieldName))
+				return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`field "%s" has incorrect. Field must be provided as Text type`, fieldName))
 			}
 
 			formTextFields[fieldName] = values
@@ -156,7 +166,7 @@ func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) *e
 		fieldDeclaration, found := typedOriginPayload[formTextFieldName]
 
 		if !found {
-			return e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`Field "%s" not found in origin command payload.`, formTextFieldName))
+			return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`Field "%s" not found in origin command payload.`, formTextFieldName))
 		}
 
 		// Валидируем поле по обозначеным правилам
@@ -167,11 +177,27 @@ func validateFormDataPayload(request *ExecuteCommandRequest[*multipart.Form]) *e
 				messages = append(messages, err.Error())
 			}
 
-			return e.NewErrorResponseMultiple(e.HttpUnprocessableEntity, messages)
+			return nil, e.NewErrorResponseMultiple(e.HttpUnprocessableEntity, messages)
 		}
 	}
 
-	return nil
+	return objectKeys, nil
+}
+
+func validateObjectKey(request *ExecuteCommandRequest[*multipart.Form], fieldName string) (string, *e.HttpErrorResponse) {
+	values, ok := request.Payload.Value[fieldName]
+
+	if !ok || len(values) != 1 {
+		return "", e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`field "%s" has incorrect. Field must be provided as File type or uploaded object key`, fieldName))
+	}
+
+	prefix := upload.ObjectKeyPrefix(request.UserId, request.Command.ID.String(), fieldName)
+
+	if !strings.HasPrefix(values[0], prefix) || strings.Contains(values[0], "..") {
+		return "", e.NewErrorResponse(e.HttpForbidden, fmt.Sprintf(`field "%s" has incorrect. Object key wasn't issued for this field`, fieldName))
+	}
+
+	return values[0], nil
 }
 
 func validateSelectionValue() rule {
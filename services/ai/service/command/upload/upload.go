@@ -0,0 +1,99 @@
+package upload
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"time"
+	"warehouseai/ai/config"
+	d "warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+	"warehouseai/ai/service/command/get"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type RequestUploadRequest struct {
+	AiId        string `json:"ai_id"`
+	CommandName string `json:"command_name"`
+	Field       string `json:"field"`
+	FileName    string `json:"file_name"`
+	Size        int64  `json:"size"`
+}
+
+type RequestUploadResponse struct {
+	ObjectKey string    `json:"object_key"`
+	UploadUrl string    `json:"upload_url"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var unsafeKeySymbols = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Все загрузки пользователя для поля команды лежат под этим префиксом, по нему execute проверяет,
+// что переданный ключ действительно выдан этому пользователю и для этого поля.
+func ObjectKeyPrefix(userId string, commandId string, field string) string {
+	return fmt.Sprintf("inputs/%s/%s/%s/", userId, commandId, sanitize(field))
+}
+
+func sanitize(value string) string {
+	value = unsafeKeySymbols.ReplaceAllString(value, "_")
+
+	if len(value) > 100 {
+		value = value[len(value)-100:]
+	}
+
+	return value
+}
+
+func RequestUpload(
+	userId string,
+	request RequestUploadRequest,
+	aiRepository d.AiInterface,
+	inputFiles d.InputFileInterface,
+	cfg config.UploadCfg,
+	logger *logrus.Logger,
+) (*RequestUploadResponse, *e.HttpErrorResponse) {
+	if request.Size <= 0 || request.Size > cfg.MaxSize {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf("Invalid file size, provide value between 1 and %d bytes.", cfg.MaxSize))
+	}
+
+	existCommand, err := get.GetCommand(get.GetCommandRequest{AiID: request.AiId, Name: request.CommandName}, aiRepository, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existCommand.Command.PayloadType != string(m.FormData) {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Command doesn't accept files.")
+	}
+
+	fieldDeclaration, found := existCommand.Command.Payload[request.Field].(map[string]interface{})
+
+	if !found || fieldDeclaration["data"] != string(m.File) {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf(`Field "%s" is not a file field of this command.`, request.Field))
+	}
+
+	fileName := sanitize(path.Base(request.FileName))
+
+	if fileName == "." || fileName == "_" || fileName == "" {
+		fileName = "file"
+	}
+
+	objectKey := ObjectKeyPrefix(userId, existCommand.Command.ID.String(), request.Field) + uuid.Must(uuid.NewV4()).String() + "/" + fileName
+	uploadUrl, presignErr := inputFiles.PresignUpload(objectKey, request.Size, cfg.TTL)
+
+	if presignErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": presignErr.Error()}).Info("Request upload")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Can't create upload link.")
+	}
+
+	return &RequestUploadResponse{
+		ObjectKey: objectKey,
+		UploadUrl: uploadUrl,
+		Method:    "PUT",
+		ExpiresAt: time.Now().Add(cfg.TTL),
+	}, nil
+}
@@ -0,0 +1,92 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"warehouseai/ai/config"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/datatypes"
+)
+
+var testCfg = config.UploadCfg{MaxSize: 100 << 20, TTL: 15 * time.Minute}
+
+func newExistAi() *m.AiProduct {
+	return &m.AiProduct{
+		ID: uuid.Must(uuid.NewV4()),
+		Commands: []m.AiCommand{{
+			ID:          uuid.Must(uuid.NewV4()),
+			Name:        "transcribe",
+			PayloadType: string(m.FormData),
+			Payload: datatypes.JSONMap{
+				"audio":    map[string]interface{}{"type": "input", "requirement": "require", "data": "file"},
+				"language": map[string]interface{}{"type": "input", "requirement": "optional", "data": "string"},
+			},
+		}},
+	}
+}
+
+func TestRequestUpload(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	inputMock := dMock.NewMockInputFileInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	existAi := newExistAi()
+	request := RequestUploadRequest{AiId: existAi.ID.String(), CommandName: "transcribe", Field: "audio", FileName: "../my recording.mp3", Size: 50 << 20}
+	prefix := ObjectKeyPrefix(userId, existAi.Commands[0].ID.String(), "audio")
+
+	aiMock.EXPECT().GetWithPreload(map[string]interface{}{"id": request.AiId}, "Commands").Return(existAi, nil).Times(1)
+	inputMock.EXPECT().PresignUpload(gomock.Any(), request.Size, testCfg.TTL).Return("https://storage.example/signed", nil).Times(1)
+
+	response, err := RequestUpload(userId, request, aiMock, inputMock, testCfg, logger)
+
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(response.ObjectKey, prefix))
+	require.True(t, strings.HasSuffix(response.ObjectKey, "/my_recording.mp3"))
+	require.Equal(t, "https://storage.example/signed", response.UploadUrl)
+	require.Equal(t, "PUT", response.Method)
+}
+
+func TestRequestUploadError(t *testing.T) {
+	cases := []struct {
+		name          string
+		request       RequestUploadRequest
+		expectedError *e.HttpErrorResponse
+	}{
+		{
+			name:          "Too large file.",
+			request:       RequestUploadRequest{CommandName: "transcribe", Field: "audio", Size: 200 << 20},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid file size, provide value between 1 and 104857600 bytes."),
+		},
+		{
+			name:          "Not a file field.",
+			request:       RequestUploadRequest{CommandName: "transcribe", Field: "language", Size: 1},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, `Field "language" is not a file field of this command.`),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			aiMock := dMock.NewMockAiInterface(ctl)
+			inputMock := dMock.NewMockInputFileInterface(ctl)
+			aiMock.EXPECT().GetWithPreload(gomock.Any(), "Commands").Return(newExistAi(), nil).AnyTimes()
+
+			response, err := RequestUpload(uuid.Must(uuid.NewV4()).String(), tCase.request, aiMock, inputMock, testCfg, logrus.New())
+
+			require.Nil(t, response)
+			require.Equal(t, tCase.expectedError, err)
+		})
+	}
+}
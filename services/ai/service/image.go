@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"warehouseai/ai/config"
+	e "warehouseai/ai/errors"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+type ImageFormat string
+
+const (
+	FormatJPEG ImageFormat = "jpeg"
+	FormatPNG  ImageFormat = "png"
+	FormatGIF  ImageFormat = "gif"
+	FormatWEBP ImageFormat = "webp"
+)
+
+type ImageSize struct {
+	Name    string
+	MaxSide int
+}
+
+// Все картинки сохраняются в JPEG в трёх размерах, оригинал не хранится
+var imageSizes = []ImageSize{
+	{Name: "thumbnail", MaxSide: 160},
+	{Name: "card", MaxSide: 640},
+	{Name: "full", MaxSide: 1920},
+}
+
+type ImageUrls struct {
+	Thumbnail string `json:"thumbnail"`
+	Card      string `json:"card"`
+	Full      string `json:"full"`
+}
+
+// Определяет формат по сигнатуре файла, расширение и Content-Type клиента не учитываются.
+func DetectImageFormat(header []byte) (ImageFormat, bool) {
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG, true
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, true
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return FormatGIF, true
+	case len(header) >= 12 && bytes.Equal(header[:4], []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP")):
+		return FormatWEBP, true
+	}
+
+	return "", false
+}
+
+// Проверяет и перекодирует картинку. Возвращает JPEG для каждого размера из imageSizes,
+// метаданные (в том числе EXIF) при перекодировании отбрасываются.
+func ProcessImage(payload io.Reader, cfg config.ImageCfg) (map[string][]byte, *e.HttpErrorResponse) {
+	data, err := io.ReadAll(io.LimitReader(payload, cfg.MaxSize+1))
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Can't read provided image.")
+	}
+
+	if int64(len(data)) > cfg.MaxSize {
+		return nil, e.NewErrorResponse(e.HttpPayloadTooLarge, "Image is too large.")
+	}
+
+	format, ok := DetectImageFormat(data)
+
+	if !ok {
+		return nil, e.NewErrorResponse(e.HttpUnsupportedMediaType, "Unsupported image format, provide JPEG, PNG, GIF or WebP.")
+	}
+
+	imgCfg, err := decodeConfig(format, data)
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid image.")
+	}
+
+	// Проверяем размеры до декодирования, чтобы не раскрывать в память огромные картинки
+	if imgCfg.Width <= 0 || imgCfg.Height <= 0 || imgCfg.Width*imgCfg.Height > cfg.MaxPixels {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Image dimensions are too large.")
+	}
+
+	img, err := decode(format, data)
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid image.")
+	}
+
+	if format == FormatJPEG {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	result := make(map[string][]byte, len(imageSizes))
+
+	for _, size := range imageSizes {
+		var buf bytes.Buffer
+
+		if err := jpeg.Encode(&buf, resize(img, size.MaxSide), &jpeg.Options{Quality: cfg.JpegQuality}); err != nil {
+			return nil, e.NewErrorResponse(e.HttpInternalError, "Can't encode image.")
+		}
+
+		result[size.Name] = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+func decodeConfig(format ImageFormat, data []byte) (image.Config, error) {
+	reader := bytes.NewReader(data)
+
+	switch format {
+	case FormatJPEG:
+		return jpeg.DecodeConfig(reader)
+	case FormatPNG:
+		return png.DecodeConfig(reader)
+	case FormatGIF:
+		return gif.DecodeConfig(reader)
+	default:
+		return webp.DecodeConfig(reader)
+	}
+}
+
+func decode(format ImageFormat, data []byte) (image.Image, error) {
+	reader := bytes.NewReader(data)
+
+	switch format {
+	case FormatJPEG:
+		return jpeg.Decode(reader)
+	case FormatPNG:
+		return png.Decode(reader)
+	case FormatGIF:
+		return gif.Decode(reader)
+	default:
+		return webp.Decode(reader)
+	}
+}
+
+// Вписывает картинку в квадрат maxSide x maxSide без увеличения. Прозрачность заливается белым, т.к. JPEG без альфа-канала.
+func resize(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			height = height * maxSide / width
+			width = maxSide
+		} else {
+			width = width * maxSide / height
+			height = maxSide
+		}
+	}
+
+	if width < 1 {
+		width = 1
+	}
+
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	return dst
+}
+
+// Достаёт тег Orientation из EXIF. Если тега нет или EXIF битый - 1 (без поворота).
+func jpegOrientation(data []byte) int {
+	offset := 2
+
+	for offset+4 <= len(data) && data[offset] == 0xFF {
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		segment := offset + 4
+
+		if marker == 0xDA || length < 2 || segment+length-2 > len(data) {
+			return 1
+		}
+
+		if marker == 0xE1 && length >= 8 && bytes.Equal(data[segment:segment+6], []byte("Exif\x00\x00")) {
+			return exifOrientation(data[segment+6 : segment+length-2])
+		}
+
+		offset = segment + length - 2
+	}
+
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// Поворачивает/отражает картинку согласно EXIF Orientation, т.к. сам тег после перекодирования теряется.
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+
+			dst.Set(dx, dy, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
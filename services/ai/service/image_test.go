@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"warehouseai/ai/config"
+	e "warehouseai/ai/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testImageCfg = config.ImageCfg{MaxSize: 5 << 20, MaxPixels: 40_000_000, JpegQuality: 85}
+
+func newTestImage(width int, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// Вставляет сразу после SOI сегмент APP1 с EXIF, в котором есть только тег Orientation
+func withOrientation(data []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(payload) + 2
+	segment := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, payload...)
+
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+func TestDetectImageFormat(t *testing.T) {
+	cases := []struct {
+		name     string
+		header   []byte
+		expected ImageFormat
+		ok       bool
+	}{
+		{name: "JPEG", header: []byte{0xFF, 0xD8, 0xFF, 0xE0}, expected: FormatJPEG, ok: true},
+		{name: "PNG", header: []byte("\x89PNG\r\n\x1a\n...."), expected: FormatPNG, ok: true},
+		{name: "GIF", header: []byte("GIF89a...."), expected: FormatGIF, ok: true},
+		{name: "WebP", header: []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), expected: FormatWEBP, ok: true},
+		{name: "Text", header: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\">"), ok: false},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			format, ok := DetectImageFormat(tCase.header)
+
+			require.Equal(t, tCase.ok, ok)
+			require.Equal(t, tCase.expected, format)
+		})
+	}
+}
+
+func TestProcessImage(t *testing.T) {
+	variants, err := ProcessImage(bytes.NewReader(encodePNG(t, newTestImage(2000, 1000))), testImageCfg)
+
+	require.Nil(t, err)
+	require.Len(t, variants, len(imageSizes))
+
+	expected := map[string]image.Point{
+		"thumbnail": {160, 80},
+		"card":      {640, 320},
+		"full":      {1920, 960},
+	}
+
+	for name, size := range expected {
+		format, ok := DetectImageFormat(variants[name])
+		require.True(t, ok)
+		require.Equal(t, FormatJPEG, format)
+
+		cfg, decodeErr := jpeg.DecodeConfig(bytes.NewReader(variants[name]))
+		require.NoError(t, decodeErr)
+		require.Equal(t, size, image.Point{cfg.Width, cfg.Height})
+	}
+}
+
+func TestProcessImageNoUpscale(t *testing.T) {
+	variants, err := ProcessImage(bytes.NewReader(encodePNG(t, newTestImage(100, 50))), testImageCfg)
+
+	require.Nil(t, err)
+
+	cfg, decodeErr := jpeg.DecodeConfig(bytes.NewReader(variants["full"]))
+	require.NoError(t, decodeErr)
+	require.Equal(t, image.Point{100, 50}, image.Point{cfg.Width, cfg.Height})
+}
+
+func TestProcessImageOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, newTestImage(200, 100), nil))
+
+	variants, err := ProcessImage(bytes.NewReader(withOrientation(buf.Bytes(), 6)), testImageCfg)
+
+	require.Nil(t, err)
+
+	cfg, decodeErr := jpeg.DecodeConfig(bytes.NewReader(variants["full"]))
+	require.NoError(t, decodeErr)
+	require.Equal(t, image.Point{100, 200}, image.Point{cfg.Width, cfg.Height})
+	require.Equal(t, 1, jpegOrientation(variants["full"]))
+}
+
+func TestProcessImageError(t *testing.T) {
+	cases := []struct {
+		name          string
+		payload       []byte
+		cfg           config.ImageCfg
+		expectedError *e.HttpErrorResponse
+	}{
+		{
+			name:          "Not an image.",
+			payload:       []byte("#!/bin/sh\necho hello"),
+			cfg:           testImageCfg,
+			expectedError: e.NewErrorResponse(e.HttpUnsupportedMediaType, "Unsupported image format, provide JPEG, PNG, GIF or WebP."),
+		},
+		{
+			name:          "Too large file.",
+			payload:       encodePNG(t, newTestImage(100, 100)),
+			cfg:           config.ImageCfg{MaxSize: 64, MaxPixels: 40_000_000, JpegQuality: 85},
+			expectedError: e.NewErrorResponse(e.HttpPayloadTooLarge, "Image is too large."),
+		},
+		{
+			name:          "Too many pixels.",
+			payload:       encodePNG(t, newTestImage(100, 100)),
+			cfg:           config.ImageCfg{MaxSize: 5 << 20, MaxPixels: 1000, JpegQuality: 85},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Image dimensions are too large."),
+		},
+		{
+			name:          "Broken image.",
+			payload:       []byte("\x89PNG\r\n\x1a\nbroken"),
+			cfg:           testImageCfg,
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid image."),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			variants, err := ProcessImage(bytes.NewReader(tCase.payload), tCase.cfg)
+
+			require.Nil(t, variants)
+			require.Equal(t, tCase.expectedError, err)
+		})
+	}
+}
+
+func TestImageUrlsFrom(t *testing.T) {
+	urls := ImageUrlsFrom("https://storage.example/backgrounds/background.id_full.jpg")
+
+	require.Equal(t, ImageUrls{
+		Thumbnail: "https://storage.example/backgrounds/background.id_thumbnail.jpg",
+		Card:      "https://storage.example/backgrounds/background.id_card.jpg",
+		Full:      "https://storage.example/backgrounds/background.id_full.jpg",
+	}, urls)
+
+	legacy := "https://storage.example/backgrounds/background.id.png"
+	require.Equal(t, ImageUrls{Thumbnail: legacy, Card: legacy, Full: legacy}, ImageUrlsFrom(legacy))
+}
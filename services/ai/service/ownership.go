@@ -0,0 +1,18 @@
+package service
+
+import (
+	"time"
+	"warehouseai/ai/adapter"
+	m "warehouseai/ai/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Изменение в ИИ уже сохранено, поэтому ошибка отправки только логируется
+func PublishOwnership(existAI *m.AiProduct, action m.AiOwnershipAction, producer adapter.EventProducerInterface, logger *logrus.Logger) {
+	event := m.AiOwnershipEvent{AiId: existAI.ID.String(), UserId: existAI.Owner.String(), Action: action}
+
+	if err := producer.SendOwnershipEvent(event); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send ownership event")
+	}
+}
@@ -0,0 +1,75 @@
+package flag
+
+import (
+	"time"
+	d "warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type FlagRatingRequest struct {
+	RateId int    `json:"rate_id"`
+	Reason string `json:"reason"`
+}
+
+func validateFlagRatingRequest(request *FlagRatingRequest) *e.HttpErrorResponse {
+	if request.Reason == "" {
+		return e.NewErrorResponse(e.HttpBadRequest, "Provide the reason of the flag.")
+	}
+
+	if len(request.Reason) > 500 {
+		return e.NewErrorResponse(e.HttpBadRequest, "Reason is too long, provide less than 500 characters.")
+	}
+
+	return nil
+}
+
+func FlagRating(
+	userId string,
+	request FlagRatingRequest,
+	aiRepository d.AiInterface,
+	ratingRepository d.RatingInterface,
+	flagRepository d.RatingFlagInterface,
+	logger *logrus.Logger,
+) (*m.AiRateFlag, *e.HttpErrorResponse) {
+	if err := validateFlagRatingRequest(&request); err != nil {
+		return nil, err
+	}
+
+	existRate, err := ratingRepository.Get(map[string]interface{}{"id": request.RateId})
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Flag AI rating")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	existAi, err := aiRepository.Get(map[string]interface{}{"id": existRate.AiId})
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Flag AI rating")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if existAi.Owner.String() != userId {
+		return nil, e.NewErrorResponse(e.HttpForbidden, "Only the owner of the AI can flag its ratings.")
+	}
+
+	newFlag := m.AiRateFlag{
+		RateId:    existRate.ID,
+		AiId:      existRate.AiId,
+		FlaggedBy: uuid.NullUUID{UUID: existAi.Owner, Valid: true},
+		Source:    m.OwnerFlag,
+		Reason:    request.Reason,
+		Status:    m.FlagPending,
+	}
+
+	if err := flagRepository.Add(&newFlag); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Flag AI rating")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return &newFlag, nil
+}
@@ -0,0 +1,68 @@
+package flag
+
+import (
+	"time"
+	d "warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const pendingFlagsLimit = 100
+
+type ReviewFlagRequest struct {
+	FlagId string           `json:"flag_id"`
+	Status m.RateFlagStatus `json:"status"`
+}
+
+func validateReviewFlagRequest(request *ReviewFlagRequest) *e.HttpErrorResponse {
+	if _, err := uuid.FromString(request.FlagId); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid flag_id value.")
+	}
+
+	if request.Status != m.FlagResolved && request.Status != m.FlagRejected {
+		return e.NewErrorResponse(e.HttpBadRequest, "Status must be resolved or rejected.")
+	}
+
+	return nil
+}
+
+// Очередь модерации: самые старые жалобы первыми
+func GetPendingFlags(flagRepository d.RatingFlagInterface, logger *logrus.Logger) (*[]m.AiRateFlag, *e.HttpErrorResponse) {
+	flags, err := flagRepository.GetMany(map[string]interface{}{"status": m.FlagPending}, pendingFlagsLimit)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get pending flags")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return flags, nil
+}
+
+func ReviewFlag(request ReviewFlagRequest, flagRepository d.RatingFlagInterface, logger *logrus.Logger) (*m.AiRateFlag, *e.HttpErrorResponse) {
+	if err := validateReviewFlagRequest(&request); err != nil {
+		return nil, err
+	}
+
+	existFlag, err := flagRepository.Get(map[string]interface{}{"id": request.FlagId})
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Review flag")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if existFlag.Status != m.FlagPending {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Flag is already reviewed.")
+	}
+
+	if err := flagRepository.Update(existFlag, map[string]interface{}{"status": request.Status}); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Review flag")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	existFlag.Status = request.Status
+
+	return existFlag, nil
+}
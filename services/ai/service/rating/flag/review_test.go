@@ -0,0 +1,66 @@
+package flag
+
+import (
+	"testing"
+	dMock "warehouseai/ai/dataservice/mocks"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReviewFlagValidateError(t *testing.T) {
+	cases := []struct {
+		name          string
+		request       *ReviewFlagRequest
+		expectedError *e.HttpErrorResponse
+	}{
+		{
+			name:          "Invalid flag id.",
+			request:       &ReviewFlagRequest{FlagId: "flag", Status: m.FlagResolved},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid flag_id value."),
+		},
+		{
+			name:          "Back to pending.",
+			request:       &ReviewFlagRequest{FlagId: uuid.Must(uuid.NewV4()).String(), Status: m.FlagPending},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Status must be resolved or rejected."),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			err := validateReviewFlagRequest(tCase.request)
+
+			require.NotNil(t, err)
+			require.Equal(t, tCase.expectedError, err)
+		})
+	}
+}
+
+func TestReviewFlag(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	logger := logrus.New()
+
+	existFlag := &m.AiRateFlag{ID: uuid.Must(uuid.NewV4()), RateId: 1, Source: m.BurstFlag, Status: m.FlagPending}
+	request := ReviewFlagRequest{FlagId: existFlag.ID.String(), Status: m.FlagRejected}
+
+	flagMock.EXPECT().Get(map[string]interface{}{"id": request.FlagId}).Return(existFlag, nil).Times(1)
+	flagMock.EXPECT().Update(existFlag, map[string]interface{}{"status": m.FlagRejected}).Return(nil).Times(1)
+
+	reviewed, err := ReviewFlag(request, flagMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, m.FlagRejected, reviewed.Status)
+
+	// Повторное решение по той же жалобе не принимается
+	flagMock.EXPECT().Get(map[string]interface{}{"id": request.FlagId}).Return(existFlag, nil).Times(1)
+
+	_, err = ReviewFlag(ReviewFlagRequest{FlagId: request.FlagId, Status: m.FlagResolved}, flagMock, logger)
+
+	require.Equal(t, e.NewErrorResponse(e.HttpAlreadyExist, "Flag is already reviewed."), err)
+}
@@ -0,0 +1,57 @@
+package set
+
+import (
+	"time"
+	"warehouseai/ai/config"
+	d "warehouseai/ai/dataservice"
+	e "warehouseai/ai/errors"
+	m "warehouseai/ai/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+func isNewAccount(createdAt string, maxAge time.Duration) bool {
+	created, err := time.Parse(time.RFC3339, createdAt)
+
+	// Если дату создания не удалось распознать, считаем аккаунт новым - лишняя проверка админом лучше накрутки
+	if err != nil {
+		return true
+	}
+
+	return time.Since(created) < maxAge
+}
+
+// Отправляет на модерацию все оценки от новых аккаунтов, если за окно их набралось больше порога.
+// Ошибки только логируются, чтобы не блокировать саму оценку.
+func detectBurst(aiId string, rating d.RatingInterface, flag d.RatingFlagInterface, cfg config.RatingCfg, logger *logrus.Logger) {
+	recentRates, err := rating.GetRecentByNewAccounts(aiId, time.Now().Add(-cfg.BurstWindow))
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Detect rating burst")
+		return
+	}
+
+	if len(*recentRates) < cfg.BurstThreshold {
+		return
+	}
+
+	logger.WithFields(logrus.Fields{"time": time.Now(), "ai_id": aiId, "count": len(*recentRates)}).Info("Detect rating burst")
+
+	for _, rate := range *recentRates {
+		if _, err := flag.Get(map[string]interface{}{"rate_id": rate.ID, "source": m.BurstFlag}); err == nil {
+			continue
+		}
+
+		burstFlag := m.AiRateFlag{
+			RateId: rate.ID,
+			AiId:   rate.AiId,
+			Source: m.BurstFlag,
+			Reason: "Burst of ratings from new accounts",
+			Status: m.FlagPending,
+		}
+
+		if err := flag.Add(&burstFlag); err != nil && err.ErrorType != e.DbExist {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Detect rating burst")
+		}
+	}
+}
@@ -2,6 +2,8 @@ package set
 
 import (
 	"time"
+	"warehouseai/ai/adapter"
+	"warehouseai/ai/config"
 	d "warehouseai/ai/dataservice"
 	e "warehouseai/ai/errors"
 	"warehouseai/ai/model"
@@ -15,6 +17,12 @@ type SetAiRatingRequest struct {
 	Rate int16  `json:"rate"`
 }
 
+type SetAiRatingResponse struct {
+	AiId          string `json:"ai_id"`
+	Rate          int16  `json:"rate"`
+	VerifiedUsage bool   `json:"verified_usage"`
+}
+
 func validateSetRatingRequest(request *SetAiRatingRequest) *e.HttpErrorResponse {
 	if request.Rate < 1 || request.Rate > 5 {
 		return e.NewErrorResponse(e.HttpBadRequest, "Invalid rate value, provide value between 1 and 5")
@@ -23,15 +31,43 @@ func validateSetRatingRequest(request *SetAiRatingRequest) *e.HttpErrorResponse
 	return nil
 }
 
-func SetAiRating(userId string, request SetAiRatingRequest, aiRepository d.AiInterface, ratingRepository d.RatingInterface, logger *logrus.Logger) *e.HttpErrorResponse {
+func SetAiRating(
+	userId string,
+	request SetAiRatingRequest,
+	aiRepository d.AiInterface,
+	ratingRepository d.RatingInterface,
+	executionRepository d.ExecutionInterface,
+	flagRepository d.RatingFlagInterface,
+	user adapter.UserGrpcInterface,
+	producer adapter.EventProducerInterface,
+	cfg config.RatingCfg,
+	logger *logrus.Logger,
+) (*SetAiRatingResponse, *e.HttpErrorResponse) {
 	if err := validateSetRatingRequest(&request); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": "Invalid rate value"}).Info("Get AI rating")
-		return err
+		return nil, err
 	}
 
-	if _, err := aiRepository.Get(map[string]interface{}{"id": request.AiId}); err != nil {
+	existAi, err := aiRepository.Get(map[string]interface{}{"id": request.AiId})
+
+	if err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": "Invalid rate value"}).Info("Get AI rating")
-		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	// Оценивать можно только те нейронки, которые пользователь хотя бы раз запускал.
+	// Владелец может оценить свой ИИ и без запуска, но такая оценка не отмечается как проверенная.
+	_, err = executionRepository.Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId})
+
+	if err != nil && err.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Set AI rating")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	verifiedUsage := err == nil
+
+	if !verifiedUsage && existAi.Owner.String() != userId {
+		return nil, e.NewErrorResponse(e.HttpForbidden, "You can only rate AIs you have used.")
 	}
 
 	// Если такой рейтинг уже существует, то обновляем существующую оценку
@@ -39,28 +75,62 @@ func SetAiRating(userId string, request SetAiRatingRequest, aiRepository d.AiInt
 
 	if err != nil && err.ErrorType == e.DbSystem {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Set AI rating")
-		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
 
 	if existRate != nil {
-		if err := ratingRepository.Update(existRate, request.Rate); err != nil {
+		if err := ratingRepository.Update(existRate, map[string]interface{}{"rate": request.Rate, "verified_usage": verifiedUsage}); err != nil {
 			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Set AI rating")
-			return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+			return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 		}
 
-		return nil
+		return &SetAiRatingResponse{AiId: request.AiId, Rate: request.Rate, VerifiedUsage: verifiedUsage}, nil
+	}
+
+	existUser, gwErr := user.GetById(userId)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Set AI rating")
+		return nil, gwErr
 	}
 
 	newRate := model.AiRate{
-		ByUserId: uuid.Must(uuid.FromString(userId)),
-		AiId:     uuid.Must(uuid.FromString(request.AiId)),
-		Rate:     request.Rate,
+		ByUserId:      uuid.Must(uuid.FromString(userId)),
+		AiId:          uuid.Must(uuid.FromString(request.AiId)),
+		Rate:          request.Rate,
+		VerifiedUsage: verifiedUsage,
+		NewAccount:    isNewAccount(existUser.CreatedAt, cfg.NewAccountAge),
 	}
 
 	if err := ratingRepository.Add(&newRate); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Set AI rating")
-		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
 
-	return nil
+	if newRate.NewAccount {
+		detectBurst(request.AiId, ratingRepository, flagRepository, cfg, logger)
+	}
+
+	notifyOwner(existAi, userId, newRate.Rate, producer, logger)
+
+	return &SetAiRatingResponse{AiId: request.AiId, Rate: newRate.Rate, VerifiedUsage: newRate.VerifiedUsage}, nil
+}
+
+// Владелец узнаёт только о новых оценках, изменение своей оценки пользователем не оповещает
+func notifyOwner(existAi *model.AiProduct, userId string, rate int16, producer adapter.EventProducerInterface, logger *logrus.Logger) {
+	if existAi.Owner.String() == userId {
+		return
+	}
+
+	event := model.AiRatingEvent{
+		EventId: uuid.Must(uuid.NewV4()).String(),
+		AiId:    existAi.ID.String(),
+		AiName:  existAi.Name,
+		OwnerId: existAi.Owner.String(),
+		Rate:    rate,
+	}
+
+	if err := producer.SendRatingEvent(event); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send rating event")
+	}
 }
@@ -2,6 +2,10 @@ package set
 
 import (
 	"testing"
+	"time"
+	"warehouseai/ai/adapter/grpc/gen"
+	aMock "warehouseai/ai/adapter/mocks"
+	"warehouseai/ai/config"
 	dMock "warehouseai/ai/dataservice/mocks"
 	e "warehouseai/ai/errors"
 	m "warehouseai/ai/model"
@@ -12,6 +16,8 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+var testCfg = config.RatingCfg{BurstWindow: time.Hour, BurstThreshold: 5, NewAccountAge: 72 * time.Hour}
+
 func TestRatingValidate(t *testing.T) {
 	request := &SetAiRatingRequest{AiId: uuid.Must(uuid.NewV4()).String(), Rate: 5}
 
@@ -53,22 +59,119 @@ func TestRatingSet(t *testing.T) {
 
 	aiMock := dMock.NewMockAiInterface(ctl)
 	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
 	logger := logrus.New()
 
 	userId := uuid.Must(uuid.NewV4()).String()
 	request := SetAiRatingRequest{AiId: uuid.Must(uuid.NewV4()).String(), Rate: 5}
+	existUser := &gen.User{Id: userId, CreatedAt: time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)}
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.FromString(request.AiId)), Owner: uuid.Must(uuid.NewV4()), Name: "Summarizer"}
 
-	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(nil, nil).Times(1)
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(existAi, nil).Times(1)
+	executionMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId}).Return(&m.AiExecution{}, nil).Times(1)
 	ratingMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId}).Return(nil, nil).Times(1)
+	userMock.EXPECT().GetById(userId).Return(existUser, nil).Times(1)
 	ratingMock.EXPECT().Add(&m.AiRate{
-		ByUserId: uuid.Must(uuid.FromString(userId)),
-		AiId:     uuid.Must(uuid.FromString(request.AiId)),
-		Rate:     request.Rate,
+		ByUserId:      uuid.Must(uuid.FromString(userId)),
+		AiId:          uuid.Must(uuid.FromString(request.AiId)),
+		Rate:          request.Rate,
+		VerifiedUsage: true,
 	}).Return(nil).Times(1)
+	producerMock.EXPECT().SendRatingEvent(gomock.Any()).DoAndReturn(func(event m.AiRatingEvent) error {
+		require.Equal(t, existAi.Owner.String(), event.OwnerId)
+		require.Equal(t, request.Rate, event.Rate)
+		require.NotEmpty(t, event.EventId)
+		return nil
+	}).Times(1)
 
-	err := SetAiRating(userId, request, aiMock, ratingMock, logger)
+	response, err := SetAiRating(userId, request, aiMock, ratingMock, executionMock, flagMock, userMock, producerMock, testCfg, logger)
 
 	require.Nil(t, err)
+	require.Equal(t, &SetAiRatingResponse{AiId: request.AiId, Rate: request.Rate, VerifiedUsage: true}, response)
+}
+
+func TestRatingSetWithoutExecution(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	request := SetAiRatingRequest{AiId: uuid.Must(uuid.NewV4()).String(), Rate: 5}
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.FromString(request.AiId)), Owner: uuid.Must(uuid.NewV4()), Name: "Summarizer"}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(existAi, nil).Times(1)
+	executionMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId}).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+
+	response, err := SetAiRating(userId, request, aiMock, ratingMock, executionMock, flagMock, userMock, producerMock, testCfg, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpForbidden, "You can only rate AIs you have used."), err)
+}
+
+func TestRatingSetByOwnerWithoutExecution(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	ownerId := uuid.Must(uuid.NewV4())
+	request := SetAiRatingRequest{AiId: uuid.Must(uuid.NewV4()).String(), Rate: 5}
+	existUser := &gen.User{Id: ownerId.String(), CreatedAt: time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339)}
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.FromString(request.AiId)), Owner: ownerId, Name: "Summarizer"}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(existAi, nil).Times(1)
+	executionMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": ownerId.String()}).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	ratingMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": ownerId.String()}).Return(nil, nil).Times(1)
+	userMock.EXPECT().GetById(ownerId.String()).Return(existUser, nil).Times(1)
+	ratingMock.EXPECT().Add(&m.AiRate{
+		ByUserId:      ownerId,
+		AiId:          existAi.ID,
+		Rate:          request.Rate,
+		VerifiedUsage: false,
+	}).Return(nil).Times(1)
+
+	response, err := SetAiRating(ownerId.String(), request, aiMock, ratingMock, executionMock, flagMock, userMock, producerMock, testCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &SetAiRatingResponse{AiId: request.AiId, Rate: request.Rate, VerifiedUsage: false}, response)
+}
+
+func TestRatingSetExecutionError(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	aiMock := dMock.NewMockAiInterface(ctl)
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	request := SetAiRatingRequest{AiId: uuid.Must(uuid.NewV4()).String(), Rate: 5}
+	existAi := &m.AiProduct{ID: uuid.Must(uuid.FromString(request.AiId)), Owner: uuid.Must(uuid.NewV4()), Name: "Summarizer"}
+
+	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(existAi, nil).Times(1)
+	executionMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId}).Return(nil, e.NewDBError(e.DbSystem, "Something went wrong.", "connection refused")).Times(1)
+
+	response, err := SetAiRating(userId, request, aiMock, ratingMock, executionMock, flagMock, userMock, producerMock, testCfg, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponseFromDBError(e.DbSystem, "Something went wrong."), err)
 }
 
 func TestRatingUpdate(t *testing.T) {
@@ -76,6 +179,10 @@ func TestRatingUpdate(t *testing.T) {
 
 	aiMock := dMock.NewMockAiInterface(ctl)
 	ratingMock := dMock.NewMockRatingInterface(ctl)
+	executionMock := dMock.NewMockExecutionInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	producerMock := aMock.NewMockEventProducerInterface(ctl)
 	logger := logrus.New()
 
 	userId := uuid.Must(uuid.NewV4())
@@ -89,10 +196,41 @@ func TestRatingUpdate(t *testing.T) {
 	}
 
 	aiMock.EXPECT().Get(map[string]interface{}{"id": request.AiId}).Return(nil, nil).Times(1)
+	executionMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId.String()}).Return(&m.AiExecution{}, nil).Times(1)
 	ratingMock.EXPECT().Get(map[string]interface{}{"ai_id": request.AiId, "user_id": userId.String()}).Return(existRating, nil).Times(1)
-	ratingMock.EXPECT().Update(existRating, request.Rate).Return(nil).Times(1)
+	ratingMock.EXPECT().Update(existRating, map[string]interface{}{"rate": request.Rate, "verified_usage": true}).Return(nil).Times(1)
 
-	err := SetAiRating(userId.String(), request, aiMock, ratingMock, logger)
+	_, err := SetAiRating(userId.String(), request, aiMock, ratingMock, executionMock, flagMock, userMock, producerMock, testCfg, logger)
 
 	require.Nil(t, err)
 }
+
+func TestRatingBurstFlag(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	ratingMock := dMock.NewMockRatingInterface(ctl)
+	flagMock := dMock.NewMockRatingFlagInterface(ctl)
+	logger := logrus.New()
+
+	aiId := uuid.Must(uuid.NewV4())
+	recentRates := []m.AiRate{{ID: 1, AiId: aiId}, {ID: 2, AiId: aiId}}
+
+	ratingMock.EXPECT().GetRecentByNewAccounts(aiId.String(), gomock.Any()).Return(&recentRates, nil).Times(1)
+	flagMock.EXPECT().Get(map[string]interface{}{"rate_id": 1, "source": m.BurstFlag}).Return(&m.AiRateFlag{}, nil).Times(1)
+	flagMock.EXPECT().Get(map[string]interface{}{"rate_id": 2, "source": m.BurstFlag}).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	flagMock.EXPECT().Add(&m.AiRateFlag{
+		RateId: 2,
+		AiId:   aiId,
+		Source: m.BurstFlag,
+		Reason: "Burst of ratings from new accounts",
+		Status: m.FlagPending,
+	}).Return(nil).Times(1)
+
+	detectBurst(aiId.String(), ratingMock, flagMock, config.RatingCfg{BurstWindow: time.Hour, BurstThreshold: 2}, logger)
+}
+
+func TestRatingNewAccount(t *testing.T) {
+	require.True(t, isNewAccount(time.Now().Add(-time.Hour).Format(time.RFC3339), 72*time.Hour))
+	require.False(t, isNewAccount(time.Now().Add(-100*time.Hour).Format(time.RFC3339), 72*time.Hour))
+	require.True(t, isNewAccount("", 72*time.Hour))
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"time"
+	"warehouseai/ai/adapter"
+	m "warehouseai/ai/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Уведомления подписчикам не должны ломать публикацию, поэтому ошибка отправки только логируется
+func PublishRelease(event m.AiReleaseEvent, producer adapter.EventProducerInterface, logger *logrus.Logger) {
+	event.EventId = uuid.Must(uuid.NewV4()).String()
+
+	if err := producer.SendReleaseEvent(event); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send release event")
+	}
+}
@@ -15,7 +15,17 @@ type UserGrpcInterface interface {
 	UpdateVerificationStatus(ctx context.Context, userId string) (bool, *e.ErrorResponse)
 }
 
+type OidcProviderInterface interface {
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string) (string, error)
+	Verify(ctx context.Context, rawIdToken string) (*model.IdTokenClaims, error)
+}
+
 type BrokerInterface interface {
 	SendEmail(email model.Email) error
 	SendUserReject(userId string) error
 }
+
+type SecurityAlertProducerInterface interface {
+	SendSecurityAlert(alert model.SecurityAlertEvent) error
+}
@@ -3,9 +3,13 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"time"
+	"warehouseai/auth/dataservice"
 	m "warehouseai/auth/model"
+	"warehouseai/auth/service"
 
 	rmq "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
 )
 
 type Broker struct {
@@ -66,3 +70,93 @@ func (b Broker) SendUserReject(userId string) error {
 
 	return nil
 }
+
+func (b Broker) SendDeletionReply(reply m.AccountDeletionReply) error {
+	messageStr, err := json.Marshal(reply)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.AccountDeleteReply),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (b Broker) SendSecurityAlert(alert m.SecurityAlertEvent) error {
+	messageStr, err := json.Marshal(alert)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(m.SecurityAlert),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Шаги саги удаления аккаунта. Сообщение подтверждается только после отправки ответа,
+// поэтому при падении сервиса шаг выполнится повторно - шаги идемпотентны.
+func (b Broker) ReceiveAccountDeletion(session dataservice.SessionInterface, resetToken dataservice.ResetTokenInterface, verificationToken dataservice.VerificationTokenInterface, identity dataservice.IdentityInterface, twoFactor dataservice.TwoFactorInterface, picture dataservice.PictureInterface, logger *logrus.Logger) {
+	messages, err := b.Channel.Consume(
+		string(m.AccountDeleteAuth),
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for message := range messages {
+		var command m.AccountDeletionCommand
+
+		if err := json.Unmarshal(message.Body, &command); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Account deletion")
+			message.Nack(false, false)
+			continue
+		}
+
+		reply := service.HandleAccountDeletion(command, session, resetToken, verificationToken, identity, twoFactor, picture, logger)
+
+		if reply != nil {
+			if err := b.SendDeletionReply(*reply); err != nil {
+				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Account deletion")
+				message.Nack(false, true)
+				continue
+			}
+		}
+
+		message.Ack(false)
+	}
+}
@@ -2,10 +2,16 @@ package server
 
 import (
 	"context"
+	"time"
+	"warehouseai/auth/adapter"
 	"warehouseai/auth/adapter/grpc/gen"
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
 	"warehouseai/auth/service"
+	"warehouseai/auth/service/accesstoken"
+	"warehouseai/auth/service/audit"
+	"warehouseai/auth/service/password"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -14,8 +20,13 @@ import (
 
 type AuthGrpcServer struct {
 	gen.UnimplementedAuthServiceServer
-	DB     dataservice.SessionInterface
-	Logger *logrus.Logger
+	DB         dataservice.SessionInterface
+	AuditDB    dataservice.AuditInterface
+	Signer     *accesstoken.Signer
+	Policy     *password.Policy
+	UserClient adapter.UserGrpcInterface
+	Alerts     adapter.SecurityAlertProducerInterface
+	Logger     *logrus.Logger
 }
 
 func (s *AuthGrpcServer) Authenticate(ctx context.Context, req *gen.AuthenticationRequest) (*gen.AuthenticationResponse, error) {
@@ -34,5 +45,131 @@ func (s *AuthGrpcServer) Authenticate(ctx context.Context, req *gen.Authenticati
 		return nil, status.Error(codes.Internal, err.ErrorMessage)
 	}
 
-	return &gen.AuthenticationResponse{UserId: *userId, SessionId: session.ID}, nil
+	// Без токена клиент просто снова придёт сюда, поэтому ошибка выпуска не ломает аутентификацию
+	accessToken, existUser, tokenErr := accesstoken.Issue(*userId, s.Signer, s.UserClient, s.Logger)
+
+	if tokenErr != nil {
+		s.Logger.WithFields(logrus.Fields{"time": time.Now(), "error": tokenErr.ErrorMessage}).Info("Authenticate")
+	}
+
+	response := &gen.AuthenticationResponse{UserId: *userId, SessionId: session.ID, AccessToken: accessToken}
+
+	// Без пользователя роли неизвестны: сервисы откажут в правах, но не в аутентификации
+	if existUser != nil {
+		response.Roles = existUser.Roles
+		response.Permissions = existUser.Permissions
+	}
+
+	return response, nil
+}
+
+// Вызывается сервисом пользователей после смены пароля
+func (s *AuthGrpcServer) CheckSession(ctx context.Context, req *gen.CheckSessionRequest) (*gen.CheckSessionResponse, error) {
+	if req == nil || req.SessionId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty request data")
+	}
+
+	session, err := service.CheckSession(req.SessionId, s.DB, s.Logger)
+
+	if err != nil {
+		if err.ErrorCode == e.HttpNotFound {
+			return nil, status.Errorf(codes.Aborted, err.ErrorMessage)
+		}
+
+		return nil, status.Error(codes.Internal, err.ErrorMessage)
+	}
+
+	return &gen.CheckSessionResponse{
+		UserId:    session.Payload.UserId,
+		SessionId: session.ID,
+		ExpiresAt: time.Now().Add(session.TTL).Unix(),
+	}, nil
+}
+
+func (s *AuthGrpcServer) RevokeUserSessions(ctx context.Context, req *gen.RevokeUserSessionsRequest) (*gen.RevokeUserSessionsResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty request data")
+	}
+
+	resp, err := service.RevokeSessions(req.UserId, req.ExceptSessionId, s.DB, s.Logger)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.ErrorMessage)
+	}
+
+	return &gen.RevokeUserSessionsResponse{Revoked: resp.Revoked}, nil
+}
+
+func (s *AuthGrpcServer) GetPublicKeys(ctx context.Context, req *gen.GetPublicKeysRequest) (*gen.GetPublicKeysResponse, error) {
+	return &gen.GetPublicKeysResponse{Keys: []*gen.PublicKey{{
+		Kid: s.Signer.KeyId(),
+		Alg: accesstoken.Algorithm,
+		Key: s.Signer.PublicKey(),
+	}}}, nil
+}
+
+// Вызывается сервисом пользователей при смене пароля, политика паролей одна на все сервисы
+func (s *AuthGrpcServer) ValidatePassword(ctx context.Context, req *gen.ValidatePasswordRequest) (*gen.ValidatePasswordResponse, error) {
+	if req == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty request data")
+	}
+
+	violations := s.Policy.Validate(req.Password, req.Username, req.Email)
+	resp := &gen.ValidatePasswordResponse{Violations: make([]*gen.PasswordViolation, 0, len(violations))}
+
+	for _, violation := range violations {
+		resp.Violations = append(resp.Violations, &gen.PasswordViolation{Rule: violation.Rule, Message: violation.Message})
+	}
+
+	return resp, nil
+}
+
+// Вызывается сервисом пользователей: журнал безопасности общий и хранится в auth
+func (s *AuthGrpcServer) RecordAuditEvent(ctx context.Context, req *gen.AuditEventRequest) (*gen.AuditEventResponse, error) {
+	if req == nil || req.Action == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty request data")
+	}
+
+	event := audit.NewEvent(m.AuditAction(req.Action), req.ActorId, req.Target, m.SessionMeta{IP: req.Ip, UserAgent: req.UserAgent}, nil)
+	event.Result = m.AuditResult(req.Result)
+
+	if err := audit.RecordExternal(event, s.AuditDB, s.Logger); err != nil {
+		if err.ErrorCode == e.HttpBadRequest {
+			return nil, status.Error(codes.InvalidArgument, err.ErrorMessage)
+		}
+
+		return nil, status.Error(codes.Internal, err.ErrorMessage)
+	}
+
+	audit.Alert(event, s.Alerts, s.Logger)
+
+	return &gen.AuditEventResponse{}, nil
+}
+
+// Вызывается сервисом пользователей при выгрузке персональных данных
+func (s *AuthGrpcServer) GetUserAuditEvents(ctx context.Context, req *gen.UserAuditEventsRequest) (*gen.UserAuditEventsResponse, error) {
+	if req == nil || req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty request data")
+	}
+
+	events, err := audit.GetUserEvents(req.UserId, req.Email, s.AuditDB, s.Logger)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.ErrorMessage)
+	}
+
+	resp := &gen.UserAuditEventsResponse{Events: make([]*gen.AuditRecord, 0, len(*events))}
+
+	for _, event := range *events {
+		resp.Events = append(resp.Events, &gen.AuditRecord{
+			Action:    string(event.Action),
+			Target:    event.Target,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			Result:    string(event.Result),
+			CreatedAt: event.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
 }
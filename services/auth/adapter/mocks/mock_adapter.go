@@ -116,6 +116,74 @@ func (mr *MockUserGrpcInterfaceMockRecorder) UpdateVerificationStatus(ctx, userI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerificationStatus", reflect.TypeOf((*MockUserGrpcInterface)(nil).UpdateVerificationStatus), ctx, userId)
 }
 
+// MockOidcProviderInterface is a mock of OidcProviderInterface interface.
+type MockOidcProviderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOidcProviderInterfaceMockRecorder
+}
+
+// MockOidcProviderInterfaceMockRecorder is the mock recorder for MockOidcProviderInterface.
+type MockOidcProviderInterfaceMockRecorder struct {
+	mock *MockOidcProviderInterface
+}
+
+// NewMockOidcProviderInterface creates a new mock instance.
+func NewMockOidcProviderInterface(ctrl *gomock.Controller) *MockOidcProviderInterface {
+	mock := &MockOidcProviderInterface{ctrl: ctrl}
+	mock.recorder = &MockOidcProviderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOidcProviderInterface) EXPECT() *MockOidcProviderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOidcProviderInterface) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, state, nonce, codeChallenge)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOidcProviderInterfaceMockRecorder) AuthCodeURL(ctx, state, nonce, codeChallenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOidcProviderInterface)(nil).AuthCodeURL), ctx, state, nonce, codeChallenge)
+}
+
+// Exchange mocks base method.
+func (m *MockOidcProviderInterface) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, codeVerifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOidcProviderInterfaceMockRecorder) Exchange(ctx, code, codeVerifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOidcProviderInterface)(nil).Exchange), ctx, code, codeVerifier)
+}
+
+// Verify mocks base method.
+func (m *MockOidcProviderInterface) Verify(ctx context.Context, rawIdToken string) (*model.IdTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, rawIdToken)
+	ret0, _ := ret[0].(*model.IdTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockOidcProviderInterfaceMockRecorder) Verify(ctx, rawIdToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockOidcProviderInterface)(nil).Verify), ctx, rawIdToken)
+}
+
 // MockBrokerInterface is a mock of BrokerInterface interface.
 type MockBrokerInterface struct {
 	ctrl     *gomock.Controller
@@ -166,3 +234,40 @@ func (mr *MockBrokerInterfaceMockRecorder) SendUserReject(userId any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendUserReject", reflect.TypeOf((*MockBrokerInterface)(nil).SendUserReject), userId)
 }
+
+// MockSecurityAlertProducerInterface is a mock of SecurityAlertProducerInterface interface.
+type MockSecurityAlertProducerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecurityAlertProducerInterfaceMockRecorder
+}
+
+// MockSecurityAlertProducerInterfaceMockRecorder is the mock recorder for MockSecurityAlertProducerInterface.
+type MockSecurityAlertProducerInterfaceMockRecorder struct {
+	mock *MockSecurityAlertProducerInterface
+}
+
+// NewMockSecurityAlertProducerInterface creates a new mock instance.
+func NewMockSecurityAlertProducerInterface(ctrl *gomock.Controller) *MockSecurityAlertProducerInterface {
+	mock := &MockSecurityAlertProducerInterface{ctrl: ctrl}
+	mock.recorder = &MockSecurityAlertProducerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecurityAlertProducerInterface) EXPECT() *MockSecurityAlertProducerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SendSecurityAlert mocks base method.
+func (m *MockSecurityAlertProducerInterface) SendSecurityAlert(alert model.SecurityAlertEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendSecurityAlert", alert)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendSecurityAlert indicates an expected call of SendSecurityAlert.
+func (mr *MockSecurityAlertProducerInterfaceMockRecorder) SendSecurityAlert(alert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendSecurityAlert", reflect.TypeOf((*MockSecurityAlertProducerInterface)(nil).SendSecurityAlert), alert)
+}
@@ -0,0 +1,373 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"warehouseai/auth/config"
+	"warehouseai/auth/model"
+)
+
+// Допустимое расхождение часов с провайдером при проверке exp/iat
+const clockSkew = time.Minute
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = multiple
+	return nil
+}
+
+type idTokenPayload struct {
+	model.IdTokenClaims
+	Audience        audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	ExpiresAt       int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+}
+
+// OpenID Connect провайдер (по умолчанию Google). Конфигурация и ключи подтягиваются
+// из discovery документа issuer'а при первом обращении, ключи перечитываются при неизвестном kid.
+type Provider struct {
+	Cfg    config.OidcCfg
+	Client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+func NewProvider(cfg config.OidcCfg) *Provider {
+	return &Provider{
+		Cfg:    cfg,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	doc, err := p.getDiscovery(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Cfg.ClientId)
+	query.Set("redirect_uri", p.Cfg.RedirectUrl)
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	return doc.AuthorizationEndpoint + "?" + query.Encode(), nil
+}
+
+// Меняет authorization code на токены и возвращает непроверенный ID token
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (string, error) {
+	doc, err := p.getDiscovery(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Cfg.RedirectUrl)
+	form.Set("client_id", p.Cfg.ClientId)
+	form.Set("client_secret", p.Cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	var tokens struct {
+		IdToken string `json:"id_token"`
+	}
+
+	if err := p.doJSON(request, &tokens); err != nil {
+		return "", err
+	}
+
+	if tokens.IdToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+
+	return tokens.IdToken, nil
+}
+
+// Проверяет подпись ID token по JWKS провайдера и стандартные claims (iss, aud, exp).
+// Nonce сверяется вызывающим кодом.
+func (p *Provider) Verify(ctx context.Context, rawIdToken string) (*model.IdTokenClaims, error) {
+	parts := strings.Split(rawIdToken, ".")
+
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, errors.New("malformed id token signature")
+	}
+
+	key, err := p.getKey(ctx, header.Kid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("invalid id token signature")
+	}
+
+	var payload idTokenPayload
+
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return nil, err
+	}
+
+	if err := p.validateClaims(&payload, time.Now()); err != nil {
+		return nil, err
+	}
+
+	claims := payload.IdTokenClaims
+	claims.Issuer = p.Cfg.Issuer
+
+	return &claims, nil
+}
+
+func (p *Provider) validateClaims(payload *idTokenPayload, now time.Time) error {
+	// Google может отдавать iss без схемы
+	if payload.Issuer != p.Cfg.Issuer && "https://"+payload.Issuer != p.Cfg.Issuer {
+		return fmt.Errorf("unexpected issuer %q", payload.Issuer)
+	}
+
+	if !contains(payload.Audience, p.Cfg.ClientId) {
+		return errors.New("id token issued for another client")
+	}
+
+	if len(payload.Audience) > 1 && payload.AuthorizedParty != p.Cfg.ClientId {
+		return errors.New("id token issued for another client")
+	}
+
+	if now.Add(-clockSkew).Unix() > payload.ExpiresAt {
+		return errors.New("id token expired")
+	}
+
+	if now.Add(clockSkew).Unix() < payload.IssuedAt {
+		return errors.New("id token issued in the future")
+	}
+
+	if payload.Subject == "" {
+		return errors.New("id token has no subject")
+	}
+
+	return nil
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.Cfg.Issuer, "/")+"/.well-known/openid-configuration", nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var doc discovery
+
+	if err := p.doJSON(request, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Issuer != p.Cfg.Issuer {
+		return nil, fmt.Errorf("discovery issuer %q doesn't match %q", doc.Issuer, p.Cfg.Issuer)
+	}
+
+	p.discovery = &doc
+
+	return p.discovery, nil
+}
+
+func (p *Provider) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	doc, err := p.getDiscovery(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Провайдер ротирует ключи, поэтому на незнакомый kid перечитываем JWKS
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JwksUri, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := p.doJSON(request, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+
+		key, err := parseRSAKey(jwk)
+
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	p.keys = keys
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *Provider) doJSON(request *http.Request, target interface{}) error {
+	response, err := p.Client.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: unexpected status %d", request.Method, request.URL.Path, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid rsa exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+
+	if err != nil {
+		return errors.New("malformed id token")
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return errors.New("malformed id token")
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
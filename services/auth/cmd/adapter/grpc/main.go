@@ -4,17 +4,21 @@ import (
 	"fmt"
 	"net"
 	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/adapter/grpc/client/user"
 	"warehouseai/auth/adapter/grpc/gen"
 	"warehouseai/auth/adapter/grpc/server"
 	"warehouseai/auth/dataservice"
+	"warehouseai/auth/service/accesstoken"
+	"warehouseai/auth/service/password"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
 
-func Start(host string, db dataservice.SessionInterface, logger *logrus.Logger) func() {
+func Start(host string, db dataservice.SessionInterface, audit dataservice.AuditInterface, signer *accesstoken.Signer, policy *password.Policy, alerts adapter.SecurityAlertProducerInterface, logger *logrus.Logger) func() {
 	grpc := grpc.NewServer()
-	server := newAuthGrpcServer(db, logger)
+	server := newAuthGrpcServer(db, audit, signer, policy, alerts, logger)
 	listener, err := net.Listen("tcp", host)
 
 	if err != nil {
@@ -34,9 +38,14 @@ func Start(host string, db dataservice.SessionInterface, logger *logrus.Logger)
 	}
 }
 
-func newAuthGrpcServer(database dataservice.SessionInterface, logger *logrus.Logger) *server.AuthGrpcServer {
+func newAuthGrpcServer(database dataservice.SessionInterface, audit dataservice.AuditInterface, signer *accesstoken.Signer, policy *password.Policy, alerts adapter.SecurityAlertProducerInterface, logger *logrus.Logger) *server.AuthGrpcServer {
 	return &server.AuthGrpcServer{
-		DB:     database,
-		Logger: logger,
+		DB:         database,
+		AuditDB:    audit,
+		Signer:     signer,
+		Policy:     policy,
+		UserClient: user.NewUserGrpcClient("user:8001"),
+		Alerts:     alerts,
+		Logger:     logger,
 	}
 }
@@ -3,9 +3,17 @@ package dataservice
 import (
 	"fmt"
 	"warehouseai/auth/config"
+	d "warehouseai/auth/dataservice"
+	"warehouseai/auth/dataservice/attemptdata"
+	"warehouseai/auth/dataservice/auditdata"
+	"warehouseai/auth/dataservice/identitydata"
+	"warehouseai/auth/dataservice/magiclinkdata"
+	"warehouseai/auth/dataservice/oidcdata"
+	"warehouseai/auth/dataservice/pendingdata"
 	"warehouseai/auth/dataservice/picturedata"
 	"warehouseai/auth/dataservice/sessiondata"
 	"warehouseai/auth/dataservice/tokendata"
+	"warehouseai/auth/dataservice/twofactordata"
 	m "warehouseai/auth/model"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,9 +25,24 @@ import (
 	"gorm.io/gorm"
 )
 
-func NewPictureStorage() *picturedata.Storage {
-	config := config.NewStorageCfg()
+func NewPictureStorage() d.PictureInterface {
+	cfg := config.NewStorageCfg()
 
+	switch cfg.Driver {
+	case config.FileStorage:
+		return &picturedata.FileStorage{Root: cfg.Root, Domain: cfg.Domain}
+
+	case config.MemoryStorage:
+		return picturedata.NewMemoryStorage(cfg.Domain)
+
+	case config.S3Storage:
+		return newS3PictureStorage(cfg)
+	}
+
+	panic(fmt.Sprintf("❌Unknown storage driver %q.", cfg.Driver))
+}
+
+func newS3PictureStorage(config config.StorageCfg) *picturedata.Storage {
 	sess, err := session.NewSession(
 		&aws.Config{
 			Endpoint:            aws.String(config.Endpoint),
@@ -46,17 +69,44 @@ func NewPictureStorage() *picturedata.Storage {
 }
 
 func NewSessionDatabase() *sessiondata.Database {
+	return &sessiondata.Database{
+		DB:     newRedisClient(),
+		Policy: config.NewSessionPolicyCfg(),
+	}
+}
+
+func NewOidcStateDatabase() *oidcdata.Database {
+	return &oidcdata.Database{
+		DB: newRedisClient(),
+	}
+}
+
+func NewPendingLoginDatabase() *pendingdata.Database {
+	return &pendingdata.Database{
+		DB: newRedisClient(),
+	}
+}
+
+func NewAttemptDatabase() *attemptdata.Database {
+	return &attemptdata.Database{
+		DB: newRedisClient(),
+	}
+}
+
+func NewMagicLinkDatabase() *magiclinkdata.Database {
+	return &magiclinkdata.Database{
+		DB: newRedisClient(),
+	}
+}
+
+func newRedisClient() *redis.Client {
 	config := config.NewSessionCfg()
 
-	rClient := redis.NewClient(&redis.Options{
+	return redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", config.Host, config.Port),
 		Password: config.Password,
 		DB:       0,
 	})
-
-	return &sessiondata.Database{
-		DB: rClient,
-	}
 }
 
 func NewResetTokenDatabase() *tokendata.Database[m.ResetToken] {
@@ -84,3 +134,42 @@ func NewVerificationTokenDatabase() *tokendata.Database[m.VerificationToken] {
 
 	return &tokendata.Database[m.VerificationToken]{DB: db}
 }
+
+func NewIdentityDatabase() *identitydata.Database {
+	cfg := config.NewTokenDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &identitydata.Database{DB: db}
+}
+
+func NewTwoFactorDatabase() *twofactordata.Database {
+	cfg := config.NewTokenDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &twofactordata.Database{DB: db}
+}
+
+func NewAuditDatabase() *auditdata.Database {
+	cfg := config.NewTokenDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &auditdata.Database{DB: db}
+}
@@ -8,6 +8,10 @@ import (
 	"warehouseai/auth/cmd/adapter/grpc"
 	"warehouseai/auth/cmd/dataservice"
 	"warehouseai/auth/cmd/server"
+	"warehouseai/auth/config"
+	"warehouseai/auth/service"
+	"warehouseai/auth/service/accesstoken"
+	"warehouseai/auth/service/password"
 
 	"github.com/sirupsen/logrus"
 )
@@ -27,21 +31,51 @@ func main() {
 	sessionDB := dataservice.NewSessionDatabase()
 	resetTokenDB := dataservice.NewResetTokenDatabase()
 	verificationTokenDB := dataservice.NewVerificationTokenDatabase()
+	oidcStateDB := dataservice.NewOidcStateDatabase()
+	identityDB := dataservice.NewIdentityDatabase()
+	twoFactorDB := dataservice.NewTwoFactorDatabase()
+	pendingLoginDB := dataservice.NewPendingLoginDatabase()
+	attemptDB := dataservice.NewAttemptDatabase()
+	magicLinkDB := dataservice.NewMagicLinkDatabase()
+	auditDB := dataservice.NewAuditDatabase()
 	pictureStorage := dataservice.NewPictureStorage()
 	broker := broker.NewBroker()
 
 	fmt.Println("✅Database successfully connected.")
 
-	grpcServer := grpc.Start("auth:8041", sessionDB, log)
+	// Один ключ на HTTP и gRPC: токены выпускаются в обоих местах
+	signer, err := accesstoken.NewSigner(config.NewAccessTokenCfg())
+
+	if err != nil {
+		fmt.Println("❌Failed to load the access token signing key.")
+		panic(err)
+	}
+
+	stopCleanup := make(chan struct{})
+	go service.StartTokenCleanup(config.NewCleanupCfg().Interval, resetTokenDB, verificationTokenDB, log, stopCleanup)
+
+	policy, err := password.NewPolicy(config.NewPasswordPolicyCfg())
+
+	if err != nil {
+		fmt.Println("❌Failed to load the breached passwords list.")
+		panic(err)
+	}
+
+	fmt.Printf("✅Password policy loaded, %d breached hashes.\n", policy.Breached.Len())
+
+	go broker.ReceiveAccountDeletion(sessionDB, resetTokenDB, verificationTokenDB, identityDB, twoFactorDB, pictureStorage, log)
+
+	grpcServer := grpc.Start("auth:8041", sessionDB, auditDB, signer, policy, broker, log)
 	go grpcServer()
 
-	if err := server.StartServer(":8040", resetTokenDB, verificationTokenDB, sessionDB, pictureStorage, broker, log); err != nil {
+	if err := server.StartServer(":8040", resetTokenDB, verificationTokenDB, sessionDB, oidcStateDB, identityDB, twoFactorDB, pendingLoginDB, attemptDB, magicLinkDB, auditDB, pictureStorage, broker, signer, policy, log); err != nil {
 		fmt.Println("❌Failed to start the HTTP Handler.")
 		log.WithFields(logrus.Fields{"time": time.Now().String(), "error": err.Error()}).Info("User Microservice")
 		panic(err)
 	}
 
 	defer func() {
+		close(stopCleanup)
 		broker.Channel.Close()
 		broker.Connection.Close()
 	}()
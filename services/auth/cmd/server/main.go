@@ -1,14 +1,27 @@
 package server
 
 import (
+	"path/filepath"
 	"warehouseai/auth/adapter/broker"
 	"warehouseai/auth/adapter/grpc/client/user"
+	"warehouseai/auth/adapter/oidc"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	"warehouseai/auth/dataservice/attemptdata"
+	"warehouseai/auth/dataservice/auditdata"
+	"warehouseai/auth/dataservice/identitydata"
+	"warehouseai/auth/dataservice/magiclinkdata"
+	"warehouseai/auth/dataservice/oidcdata"
+	"warehouseai/auth/dataservice/pendingdata"
 	"warehouseai/auth/dataservice/picturedata"
 	"warehouseai/auth/dataservice/sessiondata"
 	"warehouseai/auth/dataservice/tokendata"
+	"warehouseai/auth/dataservice/twofactordata"
 	m "warehouseai/auth/model"
 	h "warehouseai/auth/server/handlers"
 	"warehouseai/auth/server/middleware"
+	"warehouseai/auth/service/accesstoken"
+	"warehouseai/auth/service/password"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -20,27 +33,77 @@ func StartServer(
 	resetTokenDB *tokendata.Database[m.ResetToken],
 	verificationTokenDB *tokendata.Database[m.VerificationToken],
 	sessionDB *sessiondata.Database,
-	pictureStorage *picturedata.Storage,
+	oidcStateDB *oidcdata.Database,
+	identityDB *identitydata.Database,
+	twoFactorDB *twofactordata.Database,
+	pendingLoginDB *pendingdata.Database,
+	attemptDB *attemptdata.Database,
+	magicLinkDB *magiclinkdata.Database,
+	auditDB *auditdata.Database,
+	pictureStorage dataservice.PictureInterface,
 	mailProducer *broker.Broker,
+	signer *accesstoken.Signer,
+	policy *password.Policy,
 	logger *logrus.Logger,
 ) error {
 
-	handler := newHttpHandler(resetTokenDB, verificationTokenDB, sessionDB, pictureStorage, mailProducer, logger)
+	handler := newHttpHandler(resetTokenDB, verificationTokenDB, sessionDB, oidcStateDB, identityDB, twoFactorDB, pendingLoginDB, attemptDB, magicLinkDB, auditDB, pictureStorage, mailProducer, signer, policy, logger)
 	app := fiber.New()
 	app.Use(setupCORS())
+	// Маршруты auth привязаны к cookie сессии, access-токен их не заменяет, поэтому исключений для Bearer нет
+	app.Use(middleware.Csrf(nil))
+
+	// Аватарки с диска раздаём сами, ссылки вида S3_LINK/avatars/<file> должны указывать сюда.
+	// Раздаётся только каталог аватарок, остальное в Root наружу не попадает.
+	if storage, ok := pictureStorage.(*picturedata.FileStorage); ok {
+		app.Static("/auth/static/avatars", filepath.Join(storage.Root, "avatars"))
+	}
+
+	// Аватарки из памяти отдаются по тому же адресу, что и с диска
+	if storage, ok := pictureStorage.(*picturedata.MemoryStorage); ok {
+		app.Get("/auth/static/avatars/:file", func(c *fiber.Ctx) error {
+			payload, ok := storage.Get(c.Params("file"))
+
+			if !ok {
+				return c.SendStatus(fiber.StatusNotFound)
+			}
+
+			c.Type(filepath.Ext(c.Params("file")))
+			return c.Send(payload)
+		})
+	}
 
 	route := app.Group("/auth")
 
-	pictureMw := middleware.Image(logger, pictureStorage)
+	pictureMw := middleware.Image(logger, pictureStorage, config.NewImageCfg())
+	sessionMw := middleware.SessionStrict(logger, sessionDB, handler.CookieCfg)
+	readAuditMw := middleware.RequirePermission(m.PermissionReadAudit, handler.UserClient)
 
 	route.Post("/register", pictureMw, handler.RegisterHandler)
 	route.Get("/register/confirm", handler.RegisterVerifyHandler)
+	route.Post("/register/resend", handler.ResendVerificationHandler)
 	route.Post("/login", handler.LoginHandler)
+	route.Post("/login/2fa", handler.LoginTwoFactorHandler)
+	route.Post("/magic", handler.MagicLinkHandler)
+	route.Get("/magic/confirm", handler.MagicLinkConfirmHandler)
 	route.Post("/reset/request", handler.SendResetHandler)
 	route.Get("/reset/verify", handler.VerifyReset)
 	route.Post("/reset/confirm", handler.PasswordReset)
 	route.Delete("/logout", handler.LogoutHandler)
 	route.Get("/whoami", handler.WhoAmIHandler)
+	route.Get("/csrf", handler.CsrfHandler)
+	route.Get("/.well-known/jwks.json", handler.JwksHandler)
+	route.Get("/google/login", handler.GoogleLoginHandler)
+	route.Get("/google/callback", handler.GoogleCallbackHandler)
+	route.Post("/google/link", handler.GoogleLinkHandler)
+	route.Post("/2fa/enroll", sessionMw, handler.TwoFactorEnrollHandler)
+	route.Post("/2fa/confirm", sessionMw, handler.TwoFactorConfirmHandler)
+	route.Post("/2fa/disable", sessionMw, handler.TwoFactorDisableHandler)
+	route.Get("/sessions", sessionMw, handler.ListSessionsHandler)
+	route.Delete("/sessions", sessionMw, handler.RevokeSessionHandler)
+	route.Delete("/sessions/others", sessionMw, handler.RevokeOtherSessionsHandler)
+	route.Get("/security/activity", sessionMw, handler.SecurityActivityHandler)
+	route.Get("/audit", sessionMw, readAuditMw, handler.AuditQueryHandler)
 
 	return app.Listen(port)
 }
@@ -49,27 +112,52 @@ func newHttpHandler(
 	resetTokenDB *tokendata.Database[m.ResetToken],
 	verificationTokenDB *tokendata.Database[m.VerificationToken],
 	sessionDB *sessiondata.Database,
-	pictureStorage *picturedata.Storage,
+	oidcStateDB *oidcdata.Database,
+	identityDB *identitydata.Database,
+	twoFactorDB *twofactordata.Database,
+	pendingLoginDB *pendingdata.Database,
+	attemptDB *attemptdata.Database,
+	magicLinkDB *magiclinkdata.Database,
+	auditDB *auditdata.Database,
+	pictureStorage dataservice.PictureInterface,
 	mailProducer *broker.Broker,
+	signer *accesstoken.Signer,
+	policy *password.Policy,
 	logger *logrus.Logger,
 ) *h.Handler {
 
 	userClient := user.NewUserGrpcClient("user:8001")
+	oidcCfg := config.NewOidcCfg()
 
 	return &h.Handler{
 		ResetTokenDB:        resetTokenDB,
 		VerificationTokenDB: verificationTokenDB,
 		SessionDB:           sessionDB,
+		OidcStateDB:         oidcStateDB,
+		IdentityDB:          identityDB,
+		TwoFactorDB:         twoFactorDB,
+		PendingLoginDB:      pendingLoginDB,
+		AttemptDB:           attemptDB,
+		MagicLinkDB:         magicLinkDB,
+		AuditDB:             auditDB,
 		PictureStorage:      pictureStorage,
 		Broker:              mailProducer,
 		Logger:              logger,
 		UserClient:          userClient,
+		OidcProvider:        oidc.NewProvider(oidcCfg),
+		OidcCfg:             oidcCfg,
+		TwoFactorCfg:        config.NewTwoFactorCfg(),
+		LockoutCfg:          config.NewLockoutCfg(),
+		MagicLinkCfg:        config.NewMagicLinkCfg(),
+		CookieCfg:           config.NewCookieCfg(),
+		Signer:              signer,
+		PasswordPolicy:      policy,
 	}
 }
 
 func setupCORS() func(*fiber.Ctx) error {
 	return cors.New(cors.Config{
-		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,X-CSRF-Token",
 		AllowOrigins:     "http://localhost:3000, https://warehouse-ai-frontend.vercel.app, https://warehousai.com",
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
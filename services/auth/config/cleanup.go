@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+type CleanupCfg struct {
+	// Как часто удалять протухшие токены сброса пароля и подтверждения почты, 0 - не удалять
+	Interval time.Duration
+}
+
+func NewCleanupCfg() CleanupCfg {
+	return CleanupCfg{
+		Interval: durationFromEnv("TOKEN_CLEANUP_INTERVAL", 10*time.Minute),
+	}
+}
@@ -2,7 +2,17 @@ package config
 
 import "os"
 
+type StorageDriver string
+
+const (
+	S3Storage     StorageDriver = "s3"
+	FileStorage   StorageDriver = "fs"
+	MemoryStorage StorageDriver = "memory"
+)
+
 type StorageCfg struct {
+	Driver    StorageDriver
+	Root      string
 	Endpoint  string
 	AccessKey string
 	SecretKey string
@@ -26,7 +36,22 @@ type DatabaseCfg struct {
 }
 
 func NewStorageCfg() StorageCfg {
+	driver := StorageDriver(os.Getenv("STORAGE_DRIVER"))
+
+	if driver == "" {
+		driver = S3Storage
+	}
+
+	root := os.Getenv("STORAGE_ROOT")
+
+	if root == "" {
+		root = "./storage"
+	}
+
+	// Для fs и memory S3_LINK - это публичный адрес статики, например http://localhost/api/auth/static
 	return StorageCfg{
+		Driver:    driver,
+		Root:      root,
 		Endpoint:  os.Getenv("S3_HOST"),
 		AccessKey: os.Getenv("S3_ACCESSKEY"),
 		SecretKey: os.Getenv("S3_SECRETKEY"),
@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+// Защита от перебора. После FreeAttempts неудач каждая следующая попытка
+// возможна только после задержки BaseDelay*2^n (не больше MaxDelay), после
+// MaxFailures ключ блокируется на LockoutTTL.
+type LockoutCfg struct {
+	FreeAttempts  int
+	MaxFailures   int
+	IpMaxFailures int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	// Сколько помним неудачи с последней попытки
+	Window     time.Duration
+	LockoutTTL time.Duration
+	// После стольких неверных кодов токен сброса пароля удаляется
+	ResetCodeAttempts int
+	// Сколько писем подтверждения можно запросить повторно за Window
+	ResendLimit int
+}
+
+func NewLockoutCfg() LockoutCfg {
+	return LockoutCfg{
+		FreeAttempts:      intFromEnv("LOCKOUT_FREE_ATTEMPTS", 3),
+		MaxFailures:       intFromEnv("LOCKOUT_MAX_FAILURES", 10),
+		IpMaxFailures:     intFromEnv("LOCKOUT_IP_MAX_FAILURES", 100),
+		BaseDelay:         durationFromEnv("LOCKOUT_BASE_DELAY", time.Second),
+		MaxDelay:          durationFromEnv("LOCKOUT_MAX_DELAY", time.Minute),
+		Window:            durationFromEnv("LOCKOUT_WINDOW", time.Hour),
+		LockoutTTL:        durationFromEnv("LOCKOUT_TTL", 15*time.Minute),
+		ResetCodeAttempts: intFromEnv("RESET_CODE_MAX_ATTEMPTS", 5),
+		ResendLimit:       intFromEnv("RESEND_VERIFICATION_LIMIT", 5),
+	}
+}
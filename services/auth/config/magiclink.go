@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+type MagicLinkCfg struct {
+	// Сколько живёт ссылка для входа и cookie с nonce
+	TTL time.Duration
+}
+
+func NewMagicLinkCfg() MagicLinkCfg {
+	return MagicLinkCfg{
+		TTL: durationFromEnv("MAGIC_LINK_TTL", 15*time.Minute),
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type OidcCfg struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	StateTTL     time.Duration
+	LinkTTL      time.Duration
+}
+
+// Issuer настраивается, чтобы в тестах и локально вместо Google можно было поднять свой IdP
+func NewOidcCfg() OidcCfg {
+	issuer := os.Getenv("GOOGLE_ISSUER")
+
+	if issuer == "" {
+		issuer = "https://accounts.google.com"
+	}
+
+	return OidcCfg{
+		Issuer:       issuer,
+		ClientId:     os.Getenv("GOOGLE_CLIENT_ID"),
+		ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		RedirectUrl:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		StateTTL:     durationFromEnv("GOOGLE_STATE_TTL", 10*time.Minute),
+		LinkTTL:      durationFromEnv("GOOGLE_LINK_TTL", 10*time.Minute),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,22 @@
+package config
+
+import "os"
+
+type PasswordPolicyCfg struct {
+	MinLength int
+	// bcrypt учитывает только первые 72 байта
+	MaxLength int
+	// Сколько классов символов нужно из четырёх: строчные, заглавные, цифры, остальные
+	MinClasses int
+	// Файл утёкших паролей: строки "<SHA1 в hex>[:<сколько раз встречался>]", пустой путь - без проверки
+	BreachedFile string
+}
+
+func NewPasswordPolicyCfg() PasswordPolicyCfg {
+	return PasswordPolicyCfg{
+		MinLength:    intFromEnv("PASSWORD_MIN_LENGTH", 10),
+		MaxLength:    intFromEnv("PASSWORD_MAX_LENGTH", 72),
+		MinClasses:   intFromEnv("PASSWORD_MIN_CLASSES", 3),
+		BreachedFile: os.Getenv("PASSWORD_BREACHED_FILE"),
+	}
+}
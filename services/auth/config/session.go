@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// Политика жизни сессии. ID стабилен между запросами: каждый запрос продлевает
+// IdleTTL, а сам ID меняется не чаще RotateEvery. Старый ID ещё GraceTTL указывает
+// на новый, чтобы параллельные запросы со старой cookie не получали 401.
+type SessionPolicyCfg struct {
+	IdleTTL time.Duration
+	// 0 - без ограничения
+	MaxLifetime time.Duration
+	// 0 - не ротировать
+	RotateEvery time.Duration
+	GraceTTL    time.Duration
+}
+
+func NewSessionPolicyCfg() SessionPolicyCfg {
+	return SessionPolicyCfg{
+		IdleTTL:     durationFromEnv("SESSION_IDLE_TTL", 24*time.Hour),
+		MaxLifetime: durationFromEnv("SESSION_MAX_LIFETIME", 30*24*time.Hour),
+		RotateEvery: durationFromEnv("SESSION_ROTATE_EVERY", 15*time.Minute),
+		GraceTTL:    durationFromEnv("SESSION_GRACE_TTL", 30*time.Second),
+	}
+}
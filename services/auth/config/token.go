@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type AccessTokenCfg struct {
+	// Seed ключа Ed25519 в base64. Если не задан, ключ генерируется при старте,
+	// и выданные токены перестают проверяться после перезапуска.
+	SigningKey string
+	TTL        time.Duration
+}
+
+func NewAccessTokenCfg() AccessTokenCfg {
+	return AccessTokenCfg{
+		SigningKey: os.Getenv("ACCESS_TOKEN_SIGNING_KEY"),
+		TTL:        durationFromEnv("ACCESS_TOKEN_TTL", 5*time.Minute),
+	}
+}
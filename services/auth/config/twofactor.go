@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type TwoFactorCfg struct {
+	Issuer        string
+	PendingTTL    time.Duration
+	MaxAttempts   int
+	RecoveryCodes int
+}
+
+func NewTwoFactorCfg() TwoFactorCfg {
+	issuer := os.Getenv("TOTP_ISSUER")
+
+	if issuer == "" {
+		issuer = "WarehouseAI"
+	}
+
+	return TwoFactorCfg{
+		Issuer:        issuer,
+		PendingTTL:    durationFromEnv("TOTP_PENDING_TTL", 5*time.Minute),
+		MaxAttempts:   intFromEnv("TOTP_MAX_ATTEMPTS", 5),
+		RecoveryCodes: intFromEnv("TOTP_RECOVERY_CODES", 10),
+	}
+}
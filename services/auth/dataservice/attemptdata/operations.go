@@ -0,0 +1,62 @@
+package attemptdata
+
+import (
+	"context"
+	"time"
+	e "warehouseai/auth/errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	failuresPrefix = "lockout:failures:"
+	blockedPrefix  = "lockout:blocked:"
+)
+
+type Database struct {
+	DB *redis.Client
+}
+
+// Увеличивает счётчик неудач ключа. Счётчик живёт window с последней неудачи.
+func (d *Database) Fail(ctx context.Context, key string, window time.Duration) (int64, *e.DBError) {
+	pipe := d.DB.TxPipeline()
+	incr := pipe.Incr(ctx, failuresPrefix+key)
+	pipe.Expire(ctx, failuresPrefix+key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return incr.Val(), nil
+}
+
+func (d *Database) Block(ctx context.Context, key string, ttl time.Duration) *e.DBError {
+	if err := d.DB.Set(ctx, blockedPrefix+key, 1, ttl).Err(); err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+// Сколько ещё ключ заблокирован, 0 - не заблокирован
+func (d *Database) BlockedFor(ctx context.Context, key string) (time.Duration, *e.DBError) {
+	ttl, err := d.DB.PTTL(ctx, blockedPrefix+key).Result()
+
+	if err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (d *Database) Reset(ctx context.Context, key string) *e.DBError {
+	if err := d.DB.Del(ctx, failuresPrefix+key, blockedPrefix+key).Err(); err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
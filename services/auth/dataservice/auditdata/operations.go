@@ -0,0 +1,66 @@
+package auditdata
+
+import (
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Create(event *m.AuditEvent) *e.DBError {
+	if err := d.DB.Create(event).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+// События, где пользователь исполнитель или цель. Неудачные входы пишутся на почту, поэтому ищем и по ней.
+func (d *Database) GetByUser(userId string, email string, limit int) (*[]m.AuditEvent, *e.DBError) {
+	var events []m.AuditEvent
+
+	if err := d.DB.Where("actor_id = ? OR target IN ?", userId, []string{userId, email}).Order("created_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &events, nil
+}
+
+func (d *Database) GetMany(filter m.AuditFilter) (*[]m.AuditEvent, *e.DBError) {
+	var events []m.AuditEvent
+	query := d.DB
+
+	if filter.ActorId != "" {
+		query = query.Where("actor_id = ?", filter.ActorId)
+	}
+
+	if filter.Target != "" {
+		query = query.Where("target = ?", filter.Target)
+	}
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	if err := query.Order("created_at DESC").Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &events, nil
+}
@@ -2,31 +2,86 @@ package dataservice
 
 import (
 	"context"
-	"mime/multipart"
+	"io"
+	"time"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
 )
 
 type ResetTokenInterface interface {
 	Create(newResetToken *m.ResetToken) *e.DBError
+	Replace(userId string, newResetToken *m.ResetToken) *e.DBError
 	Get(condition map[string]interface{}) (*m.ResetToken, *e.DBError)
 	Delete(condition map[string]interface{}) *e.DBError
+	DeleteExpired() (int64, *e.DBError)
 }
 
 type VerificationTokenInterface interface {
 	Create(newVerificationToken *m.VerificationToken) *e.DBError
+	Replace(userId string, newVerificationToken *m.VerificationToken) *e.DBError
 	Get(condition map[string]interface{}) (*m.VerificationToken, *e.DBError)
 	Delete(condition map[string]interface{}) *e.DBError
+	DeleteExpired() (int64, *e.DBError)
 }
 
 type SessionInterface interface {
-	Create(ctx context.Context, userId string) (*m.Session, *e.DBError)
+	Create(ctx context.Context, userId string, meta m.SessionMeta) (*m.Session, *e.DBError)
 	Get(ctx context.Context, sessionId string) (*m.Session, *e.DBError)
 	Delete(ctx context.Context, sessionId string) *e.DBError
 	Update(ctx context.Context, sessionId string) (*string, *m.Session, *e.DBError)
+	List(ctx context.Context, userId string) ([]m.Session, *e.DBError)
+	DeleteAll(ctx context.Context, userId string, exceptSessionId string) (int64, *e.DBError)
+}
+
+type OidcStateInterface interface {
+	SaveState(ctx context.Context, state string, payload m.OidcState, ttl time.Duration) *e.DBError
+	TakeState(ctx context.Context, state string) (*m.OidcState, *e.DBError)
+	SaveLink(ctx context.Context, token string, payload m.OidcLink, ttl time.Duration) *e.DBError
+	TakeLink(ctx context.Context, token string) (*m.OidcLink, *e.DBError)
+}
+
+type IdentityInterface interface {
+	Create(newIdentity *m.OidcIdentity) *e.DBError
+	Get(condition map[string]interface{}) (*m.OidcIdentity, *e.DBError)
+	Delete(condition map[string]interface{}) *e.DBError
+}
+
+type TwoFactorInterface interface {
+	Get(userId string) (*m.TwoFactor, *e.DBError)
+	Save(twoFactor *m.TwoFactor) *e.DBError
+	Enable(userId string, step int64, recoveryCodes []m.RecoveryCode) *e.DBError
+	UseStep(userId string, step int64) *e.DBError
+	UseRecoveryCode(userId string, codeHash string) *e.DBError
+	Delete(userId string) *e.DBError
+}
+
+type PendingLoginInterface interface {
+	Create(ctx context.Context, userId string, ttl time.Duration) (string, *e.DBError)
+	Get(ctx context.Context, token string) (*m.PendingLogin, *e.DBError)
+	Attempt(ctx context.Context, token string) (int64, *e.DBError)
+	Delete(ctx context.Context, token string) *e.DBError
 }
 
 type PictureInterface interface {
-	UploadFile(file multipart.File, fileName string) (string, error)
+	UploadFile(file io.Reader, fileName string) (string, error)
 	DeleteImage(fileName string) error
 }
+
+type AttemptInterface interface {
+	Fail(ctx context.Context, key string, window time.Duration) (int64, *e.DBError)
+	Block(ctx context.Context, key string, ttl time.Duration) *e.DBError
+	BlockedFor(ctx context.Context, key string) (time.Duration, *e.DBError)
+	Reset(ctx context.Context, key string) *e.DBError
+}
+
+type MagicLinkInterface interface {
+	Save(ctx context.Context, tokenHash string, payload m.MagicLink, ttl time.Duration) *e.DBError
+	Get(ctx context.Context, tokenHash string) (*m.MagicLink, *e.DBError)
+	Take(ctx context.Context, tokenHash string) (*m.MagicLink, *e.DBError)
+}
+
+type AuditInterface interface {
+	Create(event *m.AuditEvent) *e.DBError
+	GetByUser(userId string, email string, limit int) (*[]m.AuditEvent, *e.DBError)
+	GetMany(filter m.AuditFilter) (*[]m.AuditEvent, *e.DBError)
+}
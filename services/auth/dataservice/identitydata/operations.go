@@ -0,0 +1,52 @@
+package identitydata
+
+import (
+	"errors"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Create(newIdentity *m.OidcIdentity) *e.DBError {
+	if err := d.DB.Create(newIdentity).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) Get(condition map[string]interface{}) (*m.OidcIdentity, *e.DBError) {
+	var identity m.OidcIdentity
+
+	if err := d.DB.Where(condition).First(&identity).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &identity, nil
+}
+
+func (d *Database) Delete(condition map[string]interface{}) *e.DBError {
+	if err := d.DB.Where(condition).Delete(&m.OidcIdentity{}).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func errorHandle(err error) *e.DBError {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Entity not found.", err.Error())
+	}
+
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return e.NewDBError(e.DbExist, "Identity is already linked.", err.Error())
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+}
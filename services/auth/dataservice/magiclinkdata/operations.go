@@ -0,0 +1,64 @@
+package magiclinkdata
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const linkPrefix = "magic:link:"
+
+// Ссылки для входа по почте. Get нужен, чтобы проверить nonce не тратя ссылку:
+// иначе её сожжёт любой сканер писем, открывший ссылку без cookie.
+type Database struct {
+	DB *redis.Client
+}
+
+func (d *Database) Save(ctx context.Context, tokenHash string, payload m.MagicLink, ttl time.Duration) *e.DBError {
+	marshaledPayload, err := json.Marshal(payload)
+
+	if err != nil {
+		return e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error())
+	}
+
+	if err := d.DB.Set(ctx, linkPrefix+tokenHash, marshaledPayload, ttl).Err(); err != nil {
+		return e.NewDBError(e.DbSystem, "Can't save JSON in DB", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) Get(ctx context.Context, tokenHash string) (*m.MagicLink, *e.DBError) {
+	record, err := d.DB.Get(ctx, linkPrefix+tokenHash).Result()
+
+	return unmarshal(record, err)
+}
+
+// Читает и удаляет ссылку одной командой: из двух одновременных запросов войдёт только один
+func (d *Database) Take(ctx context.Context, tokenHash string) (*m.MagicLink, *e.DBError) {
+	record, err := d.DB.GetDel(ctx, linkPrefix+tokenHash).Result()
+
+	return unmarshal(record, err)
+}
+
+func unmarshal(record string, err error) (*m.MagicLink, *e.DBError) {
+	if err == redis.Nil {
+		return nil, e.NewDBError(e.DbNotFound, "Entity not found.", err.Error())
+	}
+
+	if err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	var payload m.MagicLink
+
+	if err := json.Unmarshal([]byte(record), &payload); err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Can't unmarhal entity payload", err.Error())
+	}
+
+	return &payload, nil
+}
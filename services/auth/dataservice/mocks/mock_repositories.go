@@ -10,8 +10,9 @@ package mock_dataservice
 
 import (
 	context "context"
-	multipart "mime/multipart"
+	io "io"
 	reflect "reflect"
+	time "time"
 	errors "warehouseai/auth/errors"
 	model "warehouseai/auth/model"
 
@@ -69,6 +70,21 @@ func (mr *MockResetTokenInterfaceMockRecorder) Delete(condition any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockResetTokenInterface)(nil).Delete), condition)
 }
 
+// DeleteExpired mocks base method.
+func (m *MockResetTokenInterface) DeleteExpired() (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockResetTokenInterfaceMockRecorder) DeleteExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockResetTokenInterface)(nil).DeleteExpired))
+}
+
 // Get mocks base method.
 func (m *MockResetTokenInterface) Get(condition map[string]any) (*model.ResetToken, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -84,6 +100,20 @@ func (mr *MockResetTokenInterfaceMockRecorder) Get(condition any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockResetTokenInterface)(nil).Get), condition)
 }
 
+// Replace mocks base method.
+func (m *MockResetTokenInterface) Replace(userId string, newResetToken *model.ResetToken) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", userId, newResetToken)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockResetTokenInterfaceMockRecorder) Replace(userId, newResetToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockResetTokenInterface)(nil).Replace), userId, newResetToken)
+}
+
 // MockVerificationTokenInterface is a mock of VerificationTokenInterface interface.
 type MockVerificationTokenInterface struct {
 	ctrl     *gomock.Controller
@@ -135,6 +165,21 @@ func (mr *MockVerificationTokenInterfaceMockRecorder) Delete(condition any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVerificationTokenInterface)(nil).Delete), condition)
 }
 
+// DeleteExpired mocks base method.
+func (m *MockVerificationTokenInterface) DeleteExpired() (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockVerificationTokenInterfaceMockRecorder) DeleteExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockVerificationTokenInterface)(nil).DeleteExpired))
+}
+
 // Get mocks base method.
 func (m *MockVerificationTokenInterface) Get(condition map[string]any) (*model.VerificationToken, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -150,6 +195,20 @@ func (mr *MockVerificationTokenInterfaceMockRecorder) Get(condition any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVerificationTokenInterface)(nil).Get), condition)
 }
 
+// Replace mocks base method.
+func (m *MockVerificationTokenInterface) Replace(userId string, newVerificationToken *model.VerificationToken) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", userId, newVerificationToken)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockVerificationTokenInterfaceMockRecorder) Replace(userId, newVerificationToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockVerificationTokenInterface)(nil).Replace), userId, newVerificationToken)
+}
+
 // MockSessionInterface is a mock of SessionInterface interface.
 type MockSessionInterface struct {
 	ctrl     *gomock.Controller
@@ -174,18 +233,18 @@ func (m *MockSessionInterface) EXPECT() *MockSessionInterfaceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockSessionInterface) Create(ctx context.Context, userId string) (*model.Session, *errors.DBError) {
+func (m *MockSessionInterface) Create(ctx context.Context, userId string, meta model.SessionMeta) (*model.Session, *errors.DBError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, userId)
+	ret := m.ctrl.Call(m, "Create", ctx, userId, meta)
 	ret0, _ := ret[0].(*model.Session)
 	ret1, _ := ret[1].(*errors.DBError)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockSessionInterfaceMockRecorder) Create(ctx, userId any) *gomock.Call {
+func (mr *MockSessionInterfaceMockRecorder) Create(ctx, userId, meta any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionInterface)(nil).Create), ctx, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionInterface)(nil).Create), ctx, userId, meta)
 }
 
 // Delete mocks base method.
@@ -202,6 +261,21 @@ func (mr *MockSessionInterfaceMockRecorder) Delete(ctx, sessionId any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionInterface)(nil).Delete), ctx, sessionId)
 }
 
+// DeleteAll mocks base method.
+func (m *MockSessionInterface) DeleteAll(ctx context.Context, userId, exceptSessionId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, userId, exceptSessionId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockSessionInterfaceMockRecorder) DeleteAll(ctx, userId, exceptSessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockSessionInterface)(nil).DeleteAll), ctx, userId, exceptSessionId)
+}
+
 // Get mocks base method.
 func (m *MockSessionInterface) Get(ctx context.Context, sessionId string) (*model.Session, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -217,6 +291,21 @@ func (mr *MockSessionInterfaceMockRecorder) Get(ctx, sessionId any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSessionInterface)(nil).Get), ctx, sessionId)
 }
 
+// List mocks base method.
+func (m *MockSessionInterface) List(ctx context.Context, userId string) ([]model.Session, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSessionInterfaceMockRecorder) List(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSessionInterface)(nil).List), ctx, userId)
+}
+
 // Update mocks base method.
 func (m *MockSessionInterface) Update(ctx context.Context, sessionId string) (*string, *model.Session, *errors.DBError) {
 	m.ctrl.T.Helper()
@@ -233,6 +322,343 @@ func (mr *MockSessionInterfaceMockRecorder) Update(ctx, sessionId any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSessionInterface)(nil).Update), ctx, sessionId)
 }
 
+// MockOidcStateInterface is a mock of OidcStateInterface interface.
+type MockOidcStateInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOidcStateInterfaceMockRecorder
+}
+
+// MockOidcStateInterfaceMockRecorder is the mock recorder for MockOidcStateInterface.
+type MockOidcStateInterfaceMockRecorder struct {
+	mock *MockOidcStateInterface
+}
+
+// NewMockOidcStateInterface creates a new mock instance.
+func NewMockOidcStateInterface(ctrl *gomock.Controller) *MockOidcStateInterface {
+	mock := &MockOidcStateInterface{ctrl: ctrl}
+	mock.recorder = &MockOidcStateInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOidcStateInterface) EXPECT() *MockOidcStateInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SaveLink mocks base method.
+func (m *MockOidcStateInterface) SaveLink(ctx context.Context, token string, payload model.OidcLink, ttl time.Duration) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLink", ctx, token, payload, ttl)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// SaveLink indicates an expected call of SaveLink.
+func (mr *MockOidcStateInterfaceMockRecorder) SaveLink(ctx, token, payload, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLink", reflect.TypeOf((*MockOidcStateInterface)(nil).SaveLink), ctx, token, payload, ttl)
+}
+
+// SaveState mocks base method.
+func (m *MockOidcStateInterface) SaveState(ctx context.Context, state string, payload model.OidcState, ttl time.Duration) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveState", ctx, state, payload, ttl)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState.
+func (mr *MockOidcStateInterfaceMockRecorder) SaveState(ctx, state, payload, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*MockOidcStateInterface)(nil).SaveState), ctx, state, payload, ttl)
+}
+
+// TakeLink mocks base method.
+func (m *MockOidcStateInterface) TakeLink(ctx context.Context, token string) (*model.OidcLink, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeLink", ctx, token)
+	ret0, _ := ret[0].(*model.OidcLink)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// TakeLink indicates an expected call of TakeLink.
+func (mr *MockOidcStateInterfaceMockRecorder) TakeLink(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeLink", reflect.TypeOf((*MockOidcStateInterface)(nil).TakeLink), ctx, token)
+}
+
+// TakeState mocks base method.
+func (m *MockOidcStateInterface) TakeState(ctx context.Context, state string) (*model.OidcState, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeState", ctx, state)
+	ret0, _ := ret[0].(*model.OidcState)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// TakeState indicates an expected call of TakeState.
+func (mr *MockOidcStateInterfaceMockRecorder) TakeState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeState", reflect.TypeOf((*MockOidcStateInterface)(nil).TakeState), ctx, state)
+}
+
+// MockIdentityInterface is a mock of IdentityInterface interface.
+type MockIdentityInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityInterfaceMockRecorder
+}
+
+// MockIdentityInterfaceMockRecorder is the mock recorder for MockIdentityInterface.
+type MockIdentityInterfaceMockRecorder struct {
+	mock *MockIdentityInterface
+}
+
+// NewMockIdentityInterface creates a new mock instance.
+func NewMockIdentityInterface(ctrl *gomock.Controller) *MockIdentityInterface {
+	mock := &MockIdentityInterface{ctrl: ctrl}
+	mock.recorder = &MockIdentityInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityInterface) EXPECT() *MockIdentityInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityInterface) Create(newIdentity *model.OidcIdentity) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", newIdentity)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityInterfaceMockRecorder) Create(newIdentity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityInterface)(nil).Create), newIdentity)
+}
+
+// Delete mocks base method.
+func (m *MockIdentityInterface) Delete(condition map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", condition)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIdentityInterfaceMockRecorder) Delete(condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIdentityInterface)(nil).Delete), condition)
+}
+
+// Get mocks base method.
+func (m *MockIdentityInterface) Get(condition map[string]any) (*model.OidcIdentity, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", condition)
+	ret0, _ := ret[0].(*model.OidcIdentity)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIdentityInterfaceMockRecorder) Get(condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIdentityInterface)(nil).Get), condition)
+}
+
+// MockTwoFactorInterface is a mock of TwoFactorInterface interface.
+type MockTwoFactorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorInterfaceMockRecorder
+}
+
+// MockTwoFactorInterfaceMockRecorder is the mock recorder for MockTwoFactorInterface.
+type MockTwoFactorInterfaceMockRecorder struct {
+	mock *MockTwoFactorInterface
+}
+
+// NewMockTwoFactorInterface creates a new mock instance.
+func NewMockTwoFactorInterface(ctrl *gomock.Controller) *MockTwoFactorInterface {
+	mock := &MockTwoFactorInterface{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorInterface) EXPECT() *MockTwoFactorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTwoFactorInterface) Delete(userId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTwoFactorInterfaceMockRecorder) Delete(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTwoFactorInterface)(nil).Delete), userId)
+}
+
+// Enable mocks base method.
+func (m *MockTwoFactorInterface) Enable(userId string, step int64, recoveryCodes []model.RecoveryCode) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", userId, step, recoveryCodes)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTwoFactorInterfaceMockRecorder) Enable(userId, step, recoveryCodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTwoFactorInterface)(nil).Enable), userId, step, recoveryCodes)
+}
+
+// Get mocks base method.
+func (m *MockTwoFactorInterface) Get(userId string) (*model.TwoFactor, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", userId)
+	ret0, _ := ret[0].(*model.TwoFactor)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTwoFactorInterfaceMockRecorder) Get(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTwoFactorInterface)(nil).Get), userId)
+}
+
+// Save mocks base method.
+func (m *MockTwoFactorInterface) Save(twoFactor *model.TwoFactor) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", twoFactor)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockTwoFactorInterfaceMockRecorder) Save(twoFactor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTwoFactorInterface)(nil).Save), twoFactor)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorInterface) UseRecoveryCode(userId, codeHash string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userId, codeHash)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorInterfaceMockRecorder) UseRecoveryCode(userId, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorInterface)(nil).UseRecoveryCode), userId, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockTwoFactorInterface) UseStep(userId string, step int64) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", userId, step)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockTwoFactorInterfaceMockRecorder) UseStep(userId, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockTwoFactorInterface)(nil).UseStep), userId, step)
+}
+
+// MockPendingLoginInterface is a mock of PendingLoginInterface interface.
+type MockPendingLoginInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingLoginInterfaceMockRecorder
+}
+
+// MockPendingLoginInterfaceMockRecorder is the mock recorder for MockPendingLoginInterface.
+type MockPendingLoginInterfaceMockRecorder struct {
+	mock *MockPendingLoginInterface
+}
+
+// NewMockPendingLoginInterface creates a new mock instance.
+func NewMockPendingLoginInterface(ctrl *gomock.Controller) *MockPendingLoginInterface {
+	mock := &MockPendingLoginInterface{ctrl: ctrl}
+	mock.recorder = &MockPendingLoginInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingLoginInterface) EXPECT() *MockPendingLoginInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Attempt mocks base method.
+func (m *MockPendingLoginInterface) Attempt(ctx context.Context, token string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attempt", ctx, token)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Attempt indicates an expected call of Attempt.
+func (mr *MockPendingLoginInterfaceMockRecorder) Attempt(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attempt", reflect.TypeOf((*MockPendingLoginInterface)(nil).Attempt), ctx, token)
+}
+
+// Create mocks base method.
+func (m *MockPendingLoginInterface) Create(ctx context.Context, userId string, ttl time.Duration) (string, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, ttl)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPendingLoginInterfaceMockRecorder) Create(ctx, userId, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPendingLoginInterface)(nil).Create), ctx, userId, ttl)
+}
+
+// Delete mocks base method.
+func (m *MockPendingLoginInterface) Delete(ctx context.Context, token string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, token)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPendingLoginInterfaceMockRecorder) Delete(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPendingLoginInterface)(nil).Delete), ctx, token)
+}
+
+// Get mocks base method.
+func (m *MockPendingLoginInterface) Get(ctx context.Context, token string) (*model.PendingLogin, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, token)
+	ret0, _ := ret[0].(*model.PendingLogin)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPendingLoginInterfaceMockRecorder) Get(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPendingLoginInterface)(nil).Get), ctx, token)
+}
+
 // MockPictureInterface is a mock of PictureInterface interface.
 type MockPictureInterface struct {
 	ctrl     *gomock.Controller
@@ -271,7 +697,7 @@ func (mr *MockPictureInterfaceMockRecorder) DeleteImage(fileName any) *gomock.Ca
 }
 
 // UploadFile mocks base method.
-func (m *MockPictureInterface) UploadFile(file multipart.File, fileName string) (string, error) {
+func (m *MockPictureInterface) UploadFile(file io.Reader, fileName string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UploadFile", file, fileName)
 	ret0, _ := ret[0].(string)
@@ -284,3 +710,218 @@ func (mr *MockPictureInterfaceMockRecorder) UploadFile(file, fileName any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockPictureInterface)(nil).UploadFile), file, fileName)
 }
+
+// MockAttemptInterface is a mock of AttemptInterface interface.
+type MockAttemptInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttemptInterfaceMockRecorder
+}
+
+// MockAttemptInterfaceMockRecorder is the mock recorder for MockAttemptInterface.
+type MockAttemptInterfaceMockRecorder struct {
+	mock *MockAttemptInterface
+}
+
+// NewMockAttemptInterface creates a new mock instance.
+func NewMockAttemptInterface(ctrl *gomock.Controller) *MockAttemptInterface {
+	mock := &MockAttemptInterface{ctrl: ctrl}
+	mock.recorder = &MockAttemptInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttemptInterface) EXPECT() *MockAttemptInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockAttemptInterface) Block(ctx context.Context, key string, ttl time.Duration) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, key, ttl)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockAttemptInterfaceMockRecorder) Block(ctx, key, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockAttemptInterface)(nil).Block), ctx, key, ttl)
+}
+
+// BlockedFor mocks base method.
+func (m *MockAttemptInterface) BlockedFor(ctx context.Context, key string) (time.Duration, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockedFor", ctx, key)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// BlockedFor indicates an expected call of BlockedFor.
+func (mr *MockAttemptInterfaceMockRecorder) BlockedFor(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockedFor", reflect.TypeOf((*MockAttemptInterface)(nil).BlockedFor), ctx, key)
+}
+
+// Fail mocks base method.
+func (m *MockAttemptInterface) Fail(ctx context.Context, key string, window time.Duration) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockAttemptInterfaceMockRecorder) Fail(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockAttemptInterface)(nil).Fail), ctx, key, window)
+}
+
+// Reset mocks base method.
+func (m *MockAttemptInterface) Reset(ctx context.Context, key string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockAttemptInterfaceMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockAttemptInterface)(nil).Reset), ctx, key)
+}
+
+// MockMagicLinkInterface is a mock of MagicLinkInterface interface.
+type MockMagicLinkInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkInterfaceMockRecorder
+}
+
+// MockMagicLinkInterfaceMockRecorder is the mock recorder for MockMagicLinkInterface.
+type MockMagicLinkInterfaceMockRecorder struct {
+	mock *MockMagicLinkInterface
+}
+
+// NewMockMagicLinkInterface creates a new mock instance.
+func NewMockMagicLinkInterface(ctrl *gomock.Controller) *MockMagicLinkInterface {
+	mock := &MockMagicLinkInterface{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkInterface) EXPECT() *MockMagicLinkInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockMagicLinkInterface) Get(ctx context.Context, tokenHash string) (*model.MagicLink, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.MagicLink)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMagicLinkInterfaceMockRecorder) Get(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMagicLinkInterface)(nil).Get), ctx, tokenHash)
+}
+
+// Save mocks base method.
+func (m *MockMagicLinkInterface) Save(ctx context.Context, tokenHash string, payload model.MagicLink, ttl time.Duration) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, tokenHash, payload, ttl)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMagicLinkInterfaceMockRecorder) Save(ctx, tokenHash, payload, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMagicLinkInterface)(nil).Save), ctx, tokenHash, payload, ttl)
+}
+
+// Take mocks base method.
+func (m *MockMagicLinkInterface) Take(ctx context.Context, tokenHash string) (*model.MagicLink, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.MagicLink)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockMagicLinkInterfaceMockRecorder) Take(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockMagicLinkInterface)(nil).Take), ctx, tokenHash)
+}
+
+// MockAuditInterface is a mock of AuditInterface interface.
+type MockAuditInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditInterfaceMockRecorder
+}
+
+// MockAuditInterfaceMockRecorder is the mock recorder for MockAuditInterface.
+type MockAuditInterfaceMockRecorder struct {
+	mock *MockAuditInterface
+}
+
+// NewMockAuditInterface creates a new mock instance.
+func NewMockAuditInterface(ctrl *gomock.Controller) *MockAuditInterface {
+	mock := &MockAuditInterface{ctrl: ctrl}
+	mock.recorder = &MockAuditInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditInterface) EXPECT() *MockAuditInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditInterface) Create(event *model.AuditEvent) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", event)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditInterfaceMockRecorder) Create(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditInterface)(nil).Create), event)
+}
+
+// GetByUser mocks base method.
+func (m *MockAuditInterface) GetByUser(userId, email string, limit int) (*[]model.AuditEvent, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userId, email, limit)
+	ret0, _ := ret[0].(*[]model.AuditEvent)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockAuditInterfaceMockRecorder) GetByUser(userId, email, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockAuditInterface)(nil).GetByUser), userId, email, limit)
+}
+
+// GetMany mocks base method.
+func (m *MockAuditInterface) GetMany(filter model.AuditFilter) (*[]model.AuditEvent, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", filter)
+	ret0, _ := ret[0].(*[]model.AuditEvent)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockAuditInterfaceMockRecorder) GetMany(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockAuditInterface)(nil).GetMany), filter)
+}
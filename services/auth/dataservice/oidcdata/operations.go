@@ -0,0 +1,82 @@
+package oidcdata
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	statePrefix = "oidc:state:"
+	linkPrefix  = "oidc:link:"
+)
+
+// Одноразовые записи OIDC-флоу. Take* читает и удаляет запись одной командой,
+// поэтому повторно использовать state или ссылку на привязку нельзя.
+type Database struct {
+	DB *redis.Client
+}
+
+func (d *Database) SaveState(ctx context.Context, state string, payload m.OidcState, ttl time.Duration) *e.DBError {
+	return d.save(ctx, statePrefix+state, payload, ttl)
+}
+
+func (d *Database) TakeState(ctx context.Context, state string) (*m.OidcState, *e.DBError) {
+	var payload m.OidcState
+
+	if err := d.take(ctx, statePrefix+state, &payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (d *Database) SaveLink(ctx context.Context, token string, payload m.OidcLink, ttl time.Duration) *e.DBError {
+	return d.save(ctx, linkPrefix+token, payload, ttl)
+}
+
+func (d *Database) TakeLink(ctx context.Context, token string) (*m.OidcLink, *e.DBError) {
+	var payload m.OidcLink
+
+	if err := d.take(ctx, linkPrefix+token, &payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (d *Database) save(ctx context.Context, key string, payload interface{}, ttl time.Duration) *e.DBError {
+	marshaledPayload, err := json.Marshal(payload)
+
+	if err != nil {
+		return e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error())
+	}
+
+	if err := d.DB.Set(ctx, key, marshaledPayload, ttl).Err(); err != nil {
+		return e.NewDBError(e.DbSystem, "Can't save JSON in DB", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) take(ctx context.Context, key string, payload interface{}) *e.DBError {
+	record, err := d.DB.GetDel(ctx, key).Result()
+
+	if err == redis.Nil {
+		return e.NewDBError(e.DbNotFound, "Entity not found.", err.Error())
+	}
+
+	if err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	if err := json.Unmarshal([]byte(record), payload); err != nil {
+		return e.NewDBError(e.DbSystem, "Can't unmarhal entity payload", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package pendingdata
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	pendingPrefix  = "2fa:pending:"
+	attemptsPrefix = "2fa:attempts:"
+)
+
+type Database struct {
+	DB *redis.Client
+}
+
+func (d *Database) Create(ctx context.Context, userId string, ttl time.Duration) (string, *e.DBError) {
+	token := uuid.Must(uuid.NewV4()).String()
+
+	marshaledPayload, err := json.Marshal(m.PendingLogin{UserId: userId, CreatedAt: time.Now()})
+
+	if err != nil {
+		return "", e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error())
+	}
+
+	pipe := d.DB.TxPipeline()
+	pipe.Set(ctx, pendingPrefix+token, marshaledPayload, ttl)
+	pipe.Set(ctx, attemptsPrefix+token, 0, ttl)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", e.NewDBError(e.DbSystem, "Can't save JSON in DB", err.Error())
+	}
+
+	return token, nil
+}
+
+func (d *Database) Get(ctx context.Context, token string) (*m.PendingLogin, *e.DBError) {
+	var payload m.PendingLogin
+
+	record, err := d.DB.Get(ctx, pendingPrefix+token).Result()
+
+	if err == redis.Nil {
+		return nil, e.NewDBError(e.DbNotFound, "Pending login not found.", err.Error())
+	}
+
+	if err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	if err := json.Unmarshal([]byte(record), &payload); err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Can't unmarhal pending login payload", err.Error())
+	}
+
+	return &payload, nil
+}
+
+// Увеличивает счётчик попыток ввода кода и возвращает его новое значение.
+// Счётчик без TTL означает, что вход уже истёк и INCR создал ключ заново.
+func (d *Database) Attempt(ctx context.Context, token string) (int64, *e.DBError) {
+	pipe := d.DB.TxPipeline()
+	incr := pipe.Incr(ctx, attemptsPrefix+token)
+	ttl := pipe.TTL(ctx, attemptsPrefix+token)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	if ttl.Val() < 0 {
+		d.DB.Del(ctx, attemptsPrefix+token)
+		return 0, e.NewDBError(e.DbNotFound, "Pending login not found.", "")
+	}
+
+	return incr.Val(), nil
+}
+
+func (d *Database) Delete(ctx context.Context, token string) *e.DBError {
+	if err := d.DB.Del(ctx, pendingPrefix+token, attemptsPrefix+token).Err(); err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
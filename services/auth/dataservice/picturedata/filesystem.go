@@ -0,0 +1,65 @@
+package picturedata
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Хранит картинки на диске. Ссылки такие же, как у S3: Domain + "/avatars/" + fileName,
+// раздаются сервером через статический роут, смотрящий в Root.
+type FileStorage struct {
+	Root   string
+	Domain string
+}
+
+func (s *FileStorage) UploadFile(file io.Reader, fileName string) (string, error) {
+	path, err := s.path(fileName)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	dst, err := os.Create(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return s.Domain + "/avatars/" + fileName, nil
+}
+
+func (s *FileStorage) DeleteImage(fileName string) error {
+	path, err := s.path(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	// Как и в S3, удаление несуществующего файла не ошибка
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileStorage) path(fileName string) (string, error) {
+	if fileName == "" || filepath.Base(fileName) != fileName {
+		return "", errors.New("invalid file name")
+	}
+
+	return filepath.Join(s.Root, "avatars", fileName), nil
+}
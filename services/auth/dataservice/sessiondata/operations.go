@@ -3,7 +3,9 @@ package sessiondata
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
+	"warehouseai/auth/config"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
 
@@ -11,72 +13,361 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	defaultIdleTTL = 24 * time.Hour
+	// Множество ID сессий пользователя. Живёт не меньше самой долгой сессии,
+	// протухшие ID вычищаются при чтении.
+	userSessionsPrefix = "user_sessions:"
+	// Сколько раз повторяем транзакцию, если ключ сессии поменял параллельный запрос
+	maxTxRetries = 10
+	// Чаще продлевать не нужно: пачка параллельных запросов делает одну запись, остальные только читают
+	slideEvery = time.Minute
+)
+
 type Database struct {
-	DB *redis.Client
+	DB     *redis.Client
+	Policy config.SessionPolicyCfg
 }
 
-func (d *Database) Create(ctx context.Context, userId string) (*m.Session, *e.DBError) {
-	TTL := 24 * time.Hour
-	sessionId := uuid.Must(uuid.NewV4()).String()
+func (d *Database) Create(ctx context.Context, userId string, meta m.SessionMeta) (*m.Session, *e.DBError) {
+	now := time.Now()
 
 	sessionPayload := m.SessionPayload{
-		UserId:    userId,
-		CreatedAt: time.Now(),
+		UserId:     userId,
+		CreatedAt:  now,
+		Device:     describeDevice(meta.UserAgent),
+		UserAgent:  meta.UserAgent,
+		IP:         meta.IP,
+		LastSeenAt: now,
+		RotatedAt:  now,
 	}
 
+	sessionId := uuid.Must(uuid.NewV4()).String()
+	ttl := d.ttl(sessionPayload, now)
+	index := userSessionsPrefix + userId
+
 	marshaledPayload, err := json.Marshal(sessionPayload)
 
 	if err != nil {
 		return nil, e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error())
 	}
 
-	if err := d.DB.Set(ctx, sessionId, marshaledPayload, TTL).Err(); err != nil {
+	pipe := d.DB.TxPipeline()
+	pipe.Set(ctx, sessionId, marshaledPayload, ttl)
+	pipe.SAdd(ctx, index, sessionId)
+	pipe.Expire(ctx, index, d.idleTTL())
+
+	if _, err := pipe.Exec(ctx); err != nil {
 		return nil, e.NewDBError(e.DbSystem, "Can't save JSON in DB", err.Error())
 	}
 
-	return &m.Session{ID: sessionId, Payload: sessionPayload, TTL: TTL}, nil
+	return &m.Session{ID: sessionId, Payload: sessionPayload, TTL: ttl}, nil
 }
 
 func (d *Database) Get(ctx context.Context, sessionId string) (*m.Session, *e.DBError) {
-	var sessionPayload m.SessionPayload
+	return get(ctx, d.DB, sessionId)
+}
 
-	record := d.DB.Get(ctx, sessionId)
-	recordTTL := d.DB.TTL(ctx, sessionId)
+// Удаляет сессию. Для старого ID в grace-окне удаляется и сессия, на которую он ведёт.
+func (d *Database) Delete(ctx context.Context, sessionId string) *e.DBError {
+	keys := []string{sessionId}
+	session, dbErr := d.Get(ctx, sessionId)
 
-	if record.Err() != nil {
-		return nil, e.NewDBError(e.DbNotFound, "Session not found.", record.Err().Error())
+	if dbErr == nil && session.Payload.ReplacedBy != "" {
+		keys = append(keys, session.Payload.ReplacedBy)
 	}
 
-	recordInfo, _ := record.Result()
-	TTLInfo, _ := recordTTL.Result()
+	pipe := d.DB.TxPipeline()
+	pipe.Del(ctx, keys...)
 
-	if err := json.Unmarshal([]byte(recordInfo), &sessionPayload); err != nil {
-		return nil, e.NewDBError(e.DbSystem, "Can't unmarhal session payload", err.Error())
+	if dbErr == nil {
+		for _, key := range keys {
+			pipe.SRem(ctx, userSessionsPrefix+session.Payload.UserId, key)
+		}
 	}
 
-	return &m.Session{ID: sessionId, Payload: sessionPayload, TTL: TTLInfo}, nil
-}
-
-func (d *Database) Delete(ctx context.Context, sessionId string) *e.DBError {
-	if err := d.DB.Del(ctx, sessionId).Err(); err != nil {
+	if _, err := pipe.Exec(ctx); err != nil {
 		return e.NewDBError(e.DbNotFound, "Session not found.", err.Error())
 	}
 
 	return nil
 }
 
+// Продлевает сессию при каждом запросе и при необходимости ротирует её ID.
+// Возвращает ту же сессию, если ротация не понадобилась. Параллельные запросы
+// со старым ID получают одну и ту же новую сессию.
 func (d *Database) Update(ctx context.Context, sessionId string) (*string, *m.Session, *e.DBError) {
-	session, err := d.Get(ctx, sessionId)
+	for attempt := 0; attempt < maxTxRetries; attempt++ {
+		var session *m.Session
+		var dbErr *e.DBError
+
+		err := d.DB.Watch(ctx, func(tx *redis.Tx) (err error) {
+			session, dbErr, err = d.touch(ctx, tx, sessionId)
+			return err
+		}, sessionId)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+		}
+
+		if dbErr != nil {
+			return nil, nil, dbErr
+		}
+
+		return &session.Payload.UserId, session, nil
+	}
+
+	return nil, nil, e.NewDBError(e.DbSystem, "Session is busy, try again.", "too many concurrent updates")
+}
+
+// Ошибка Redis в транзакции возвращается отдельно от DBError, чтобы Watch мог её повторить
+func (d *Database) touch(ctx context.Context, tx *redis.Tx, sessionId string) (*m.Session, *e.DBError, error) {
+	session, dbErr := get(ctx, tx, sessionId)
+
+	if dbErr != nil {
+		return nil, dbErr, nil
+	}
+
+	// ID уже ротирован параллельным запросом - отдаём новую сессию как есть
+	if session.Payload.ReplacedBy != "" {
+		successor, dbErr := get(ctx, tx, session.Payload.ReplacedBy)
+
+		if dbErr != nil || successor.Payload.ReplacedBy != "" {
+			return nil, e.NewDBError(e.DbNotFound, "Session not found.", "session was rotated"), nil
+		}
+
+		return successor, nil, nil
+	}
+
+	now := time.Now()
+	payload := session.Payload
+	index := userSessionsPrefix + payload.UserId
+
+	if d.expired(payload, now) {
+		if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, sessionId)
+			pipe.SRem(ctx, index, sessionId)
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, e.NewDBError(e.DbNotFound, "Session not found.", "session reached max lifetime"), nil
+	}
+
+	rotate := d.Policy.RotateEvery > 0 && now.Sub(rotatedAt(payload)) >= d.Policy.RotateEvery
+
+	if !rotate && now.Sub(payload.LastSeenAt) < slideEvery {
+		return session, nil, nil
+	}
+
+	payload.LastSeenAt = now
+	ttl := d.ttl(payload, now)
+
+	if !rotate {
+		marshaledPayload, err := json.Marshal(payload)
+
+		if err != nil {
+			return nil, e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error()), nil
+		}
+
+		if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, sessionId, marshaledPayload, ttl)
+			pipe.Expire(ctx, index, d.idleTTL())
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+
+		return &m.Session{ID: sessionId, Payload: payload, TTL: ttl}, nil, nil
+	}
+
+	newSessionId := uuid.Must(uuid.NewV4()).String()
+	payload.RotatedAt = now
+
+	alias := payload
+	alias.ReplacedBy = newSessionId
+
+	marshaledPayload, err := json.Marshal(payload)
 
 	if err != nil {
-		return nil, nil, err
+		return nil, e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error()), nil
 	}
 
-	if err := d.Delete(ctx, sessionId); err != nil {
+	marshaledAlias, err := json.Marshal(alias)
+
+	if err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Can't marshal entity to JSON", err.Error()), nil
+	}
+
+	if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, newSessionId, marshaledPayload, ttl)
+		if d.Policy.GraceTTL > 0 {
+			pipe.Set(ctx, sessionId, marshaledAlias, d.Policy.GraceTTL)
+		} else {
+			pipe.Del(ctx, sessionId)
+		}
+		pipe.SAdd(ctx, index, newSessionId)
+		pipe.SRem(ctx, index, sessionId)
+		pipe.Expire(ctx, index, d.idleTTL())
+		return nil
+	}); err != nil {
 		return nil, nil, err
 	}
 
-	newSession, err := d.Create(ctx, session.Payload.UserId)
+	return &m.Session{ID: newSessionId, Payload: payload, TTL: ttl}, nil, nil
+}
+
+func (d *Database) List(ctx context.Context, userId string) ([]m.Session, *e.DBError) {
+	sessionIds, err := d.DB.SMembers(ctx, userSessionsPrefix+userId).Result()
+
+	if err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	sessions := make([]m.Session, 0, len(sessionIds))
+	stale := make([]interface{}, 0)
+
+	for _, sessionId := range sessionIds {
+		session, dbErr := d.Get(ctx, sessionId)
+
+		if dbErr != nil {
+			if dbErr.ErrorType == e.DbNotFound {
+				stale = append(stale, sessionId)
+				continue
+			}
+
+			return nil, dbErr
+		}
+
+		if session.Payload.ReplacedBy != "" {
+			stale = append(stale, sessionId)
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	if len(stale) > 0 {
+		d.DB.SRem(ctx, userSessionsPrefix+userId, stale...)
+	}
+
+	return sessions, nil
+}
+
+// Удаляет все сессии пользователя, кроме exceptSessionId (пустая строка - удалить все).
+// Индекс наблюдается через WATCH: если параллельно прошла ротация, транзакция повторяется.
+func (d *Database) DeleteAll(ctx context.Context, userId string, exceptSessionId string) (int64, *e.DBError) {
+	index := userSessionsPrefix + userId
+
+	for attempt := 0; attempt < maxTxRetries; attempt++ {
+		var deleted int64
+
+		err := d.DB.Watch(ctx, func(tx *redis.Tx) error {
+			sessionIds, err := tx.SMembers(ctx, index).Result()
+
+			if err != nil {
+				return err
+			}
+
+			revoke := make([]string, 0, len(sessionIds))
+			members := make([]interface{}, 0, len(sessionIds))
+
+			for _, sessionId := range sessionIds {
+				if sessionId != exceptSessionId {
+					revoke = append(revoke, sessionId)
+					members = append(members, sessionId)
+				}
+			}
+
+			if len(revoke) == 0 {
+				return nil
+			}
+
+			var del *redis.IntCmd
+
+			if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				del = pipe.Del(ctx, revoke...)
+				pipe.SRem(ctx, index, members...)
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			deleted = del.Val()
+			return nil
+		}, index)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+
+		if err != nil {
+			return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+		}
+
+		return deleted, nil
+	}
+
+	return 0, e.NewDBError(e.DbSystem, "Sessions are busy, try again.", "too many concurrent updates")
+}
+
+func (d *Database) idleTTL() time.Duration {
+	if d.Policy.IdleTTL <= 0 {
+		return defaultIdleTTL
+	}
+
+	return d.Policy.IdleTTL
+}
+
+// TTL ключа: скользящий IdleTTL, но не дольше оставшегося абсолютного времени жизни
+func (d *Database) ttl(payload m.SessionPayload, now time.Time) time.Duration {
+	ttl := d.idleTTL()
+
+	if d.Policy.MaxLifetime > 0 {
+		if remaining := payload.CreatedAt.Add(d.Policy.MaxLifetime).Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	return ttl
+}
+
+func (d *Database) expired(payload m.SessionPayload, now time.Time) bool {
+	return d.Policy.MaxLifetime > 0 && !now.Before(payload.CreatedAt.Add(d.Policy.MaxLifetime))
+}
+
+// У сессий, созданных до появления ротации, RotatedAt пустой
+func rotatedAt(payload m.SessionPayload) time.Time {
+	if payload.RotatedAt.IsZero() {
+		return payload.CreatedAt
+	}
+
+	return payload.RotatedAt
+}
+
+func get(ctx context.Context, client redis.Cmdable, sessionId string) (*m.Session, *e.DBError) {
+	var sessionPayload m.SessionPayload
+
+	record, err := client.Get(ctx, sessionId).Result()
+
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, e.NewDBError(e.DbNotFound, "Session not found.", err.Error())
+		}
+
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	ttl, _ := client.TTL(ctx, sessionId).Result()
+
+	if err := json.Unmarshal([]byte(record), &sessionPayload); err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Can't unmarhal session payload", err.Error())
+	}
 
-	return &session.Payload.UserId, newSession, err
+	return &m.Session{ID: sessionId, Payload: sessionPayload, TTL: ttl}, nil
 }
@@ -0,0 +1,260 @@
+package sessiondata
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+	"warehouseai/auth/config"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+const parallelRequests = 50
+
+var testPolicy = config.SessionPolicyCfg{
+	IdleTTL:     time.Hour,
+	MaxLifetime: 24 * time.Hour,
+	RotateEvery: 15 * time.Minute,
+	GraceTTL:    30 * time.Second,
+}
+
+type touchResult struct {
+	userId  *string
+	session *m.Session
+	err     *e.DBError
+}
+
+func newTestDatabase(t *testing.T, policy config.SessionPolicyCfg) *Database {
+	client, _ := newTestClient(t)
+
+	return &Database{DB: client, Policy: policy}
+}
+
+// Сдвигает время в payload сессии в прошлое, TTL ключа сохраняется
+func backdate(t *testing.T, client *redis.Client, sessionId string, change func(payload *m.SessionPayload)) {
+	ctx := context.Background()
+
+	raw, err := client.Get(ctx, sessionId).Result()
+	require.NoError(t, err)
+
+	var payload m.SessionPayload
+	require.NoError(t, json.Unmarshal([]byte(raw), &payload))
+
+	change(&payload)
+
+	marshaled, _ := json.Marshal(payload)
+	require.NoError(t, client.Set(ctx, sessionId, marshaled, time.Minute).Err())
+}
+
+func touchInParallel(d *Database, sessionId string) []touchResult {
+	results := make([]touchResult, parallelRequests)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 0; i < parallelRequests; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			userId, session, err := d.Update(context.Background(), sessionId)
+			results[i] = touchResult{userId: userId, session: session, err: err}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	return results
+}
+
+func TestUpdateKeepsSessionId(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	session, err := d.Create(ctx, userId, m.SessionMeta{UserAgent: "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0", IP: "10.0.0.1"})
+	require.Nil(t, err)
+	require.Equal(t, "Chrome on Windows", session.Payload.Device)
+
+	for _, result := range touchInParallel(d, session.ID) {
+		require.Nil(t, result.err)
+		require.Equal(t, userId, *result.userId)
+		require.Equal(t, session.ID, result.session.ID)
+	}
+
+	sessions, err := d.List(ctx, userId)
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+}
+
+func TestUpdateSlidesExpiry(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	session, _ := d.Create(ctx, userId, m.SessionMeta{})
+	lastSeen := time.Now().Add(-10 * time.Minute)
+
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) { payload.LastSeenAt = lastSeen })
+
+	_, touched, err := d.Update(ctx, session.ID)
+	require.Nil(t, err)
+	require.Equal(t, session.ID, touched.ID)
+	require.True(t, touched.Payload.LastSeenAt.After(lastSeen))
+
+	ttl, _ := d.DB.TTL(ctx, session.ID).Result()
+	require.Greater(t, ttl, 59*time.Minute)
+}
+
+func TestUpdateRespectsMaxLifetime(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	session, _ := d.Create(ctx, userId, m.SessionMeta{})
+
+	// До конца жизни сессии 10 минут - TTL не может быть больше, чем IdleTTL
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) {
+		payload.CreatedAt = time.Now().Add(-testPolicy.MaxLifetime + 10*time.Minute)
+		payload.LastSeenAt = time.Now().Add(-time.Hour)
+		payload.RotatedAt = time.Now()
+	})
+
+	_, touched, err := d.Update(ctx, session.ID)
+	require.Nil(t, err)
+	require.LessOrEqual(t, touched.TTL, 10*time.Minute)
+
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) { payload.CreatedAt = time.Now().Add(-testPolicy.MaxLifetime) })
+
+	_, _, err = d.Update(ctx, session.ID)
+	require.NotNil(t, err)
+	require.Equal(t, e.DbNotFound, err.ErrorType)
+
+	sessions, _ := d.List(ctx, userId)
+	require.Empty(t, sessions)
+}
+
+func TestUpdateRotatesOnceUnderConcurrency(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	session, _ := d.Create(ctx, userId, m.SessionMeta{})
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) { payload.RotatedAt = time.Now().Add(-testPolicy.RotateEvery) })
+
+	results := touchInParallel(d, session.ID)
+	rotatedId := results[0].session.ID
+
+	// Все параллельные запросы получают один и тот же новый ID, ни один не получил 401
+	require.NotEqual(t, session.ID, rotatedId)
+
+	for _, result := range results {
+		require.Nil(t, result.err)
+		require.Equal(t, userId, *result.userId)
+		require.Equal(t, rotatedId, result.session.ID)
+	}
+
+	sessions, err := d.List(ctx, userId)
+	require.Nil(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, rotatedId, sessions[0].ID)
+
+	// Старый ID в grace-окне ведёт на новую сессию, а новая сессия сразу не ротируется
+	_, touched, err := d.Update(ctx, session.ID)
+	require.Nil(t, err)
+	require.Equal(t, rotatedId, touched.ID)
+
+	_, touched, err = d.Update(ctx, rotatedId)
+	require.Nil(t, err)
+	require.Equal(t, rotatedId, touched.ID)
+
+	ttl, _ := d.DB.TTL(ctx, session.ID).Result()
+	require.LessOrEqual(t, ttl, testPolicy.GraceTTL)
+}
+
+func TestUpdateAfterGraceWindow(t *testing.T) {
+	policy := testPolicy
+	policy.GraceTTL = 50 * time.Millisecond
+
+	d := newTestDatabase(t, policy)
+	ctx := context.Background()
+
+	session, _ := d.Create(ctx, uuid.Must(uuid.NewV4()).String(), m.SessionMeta{})
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) { payload.RotatedAt = time.Now().Add(-policy.RotateEvery) })
+
+	_, rotated, err := d.Update(ctx, session.ID)
+	require.Nil(t, err)
+	require.NotEqual(t, session.ID, rotated.ID)
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, _, err = d.Update(ctx, session.ID)
+	require.NotNil(t, err)
+	require.Equal(t, e.DbNotFound, err.ErrorType)
+
+	_, touched, err := d.Update(ctx, rotated.ID)
+	require.Nil(t, err)
+	require.Equal(t, rotated.ID, touched.ID)
+}
+
+func TestDeleteRotatedSession(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	session, _ := d.Create(ctx, userId, m.SessionMeta{})
+	backdate(t, d.DB, session.ID, func(payload *m.SessionPayload) { payload.RotatedAt = time.Now().Add(-testPolicy.RotateEvery) })
+
+	_, rotated, _ := d.Update(ctx, session.ID)
+
+	// Выход со старой cookie в grace-окне завершает и новую сессию
+	require.Nil(t, d.Delete(ctx, session.ID))
+
+	_, _, err := d.Update(ctx, rotated.ID)
+	require.NotNil(t, err)
+	require.Equal(t, e.DbNotFound, err.ErrorType)
+
+	sessions, _ := d.List(ctx, userId)
+	require.Empty(t, sessions)
+}
+
+func TestDeleteAllDuringRotation(t *testing.T) {
+	d := newTestDatabase(t, testPolicy)
+	ctx := context.Background()
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	current, _ := d.Create(ctx, userId, m.SessionMeta{})
+	other, _ := d.Create(ctx, userId, m.SessionMeta{})
+	backdate(t, d.DB, other.ID, func(payload *m.SessionPayload) { payload.RotatedAt = time.Now().Add(-testPolicy.RotateEvery) })
+
+	var wg sync.WaitGroup
+	var deleteErr *e.DBError
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		d.Update(ctx, other.ID)
+	}()
+
+	go func() {
+		defer wg.Done()
+		_, deleteErr = d.DeleteAll(ctx, userId, current.ID)
+	}()
+
+	wg.Wait()
+	require.Nil(t, deleteErr)
+
+	// Как бы ни легли операции, ротированная сессия не должна пережить DeleteAll
+	sessions, _ := d.List(ctx, userId)
+	require.Len(t, sessions, 1)
+	require.Equal(t, current.ID, sessions[0].ID)
+}
@@ -0,0 +1,406 @@
+package sessiondata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Минимальный Redis для тестов: строки, множества, TTL и WATCH/MULTI/EXEC.
+// Версия ключа растёт при каждой записи, EXEC отменяется, если наблюдаемый ключ изменился.
+type fakeRedis struct {
+	mu       sync.Mutex
+	strings  map[string]string
+	sets     map[string]map[string]struct{}
+	expires  map[string]time.Time
+	versions map[string]uint64
+}
+
+type fakeConn struct {
+	watched map[string]uint64
+	multi   bool
+	queued  [][]string
+}
+
+type reply interface{}
+
+type simpleString string
+type redisError string
+
+func newTestClient(t *testing.T) (*redis.Client, *fakeRedis) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &fakeRedis{
+		strings:  map[string]string{},
+		sets:     map[string]map[string]struct{}{},
+		expires:  map[string]time.Time{},
+		versions: map[string]uint64{},
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{
+		Addr:             listener.Addr().String(),
+		Protocol:         2,
+		DisableIndentity: true,
+		PoolSize:         64,
+	})
+
+	t.Cleanup(func() {
+		client.Close()
+		listener.Close()
+	})
+
+	return client, server
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	state := &fakeConn{}
+
+	for {
+		args, err := readCommand(reader)
+
+		if err != nil {
+			return
+		}
+
+		writeReply(writer, s.handle(state, args))
+
+		if writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) handle(state *fakeConn, args []string) reply {
+	name := strings.ToUpper(args[0])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch name {
+	case "MULTI":
+		state.multi = true
+		state.queued = nil
+		return simpleString("OK")
+	case "DISCARD":
+		state.multi = false
+		state.queued = nil
+		state.watched = nil
+		return simpleString("OK")
+	case "EXEC":
+		queued, watched := state.queued, state.watched
+		state.multi, state.queued, state.watched = false, nil, nil
+
+		for key, version := range watched {
+			if s.versions[key] != version {
+				return []reply(nil)
+			}
+		}
+
+		replies := make([]reply, 0, len(queued))
+
+		for _, command := range queued {
+			replies = append(replies, s.exec(command))
+		}
+
+		return replies
+	case "WATCH":
+		if state.watched == nil {
+			state.watched = map[string]uint64{}
+		}
+
+		for _, key := range args[1:] {
+			s.expire(key)
+			state.watched[key] = s.versions[key]
+		}
+
+		return simpleString("OK")
+	case "UNWATCH":
+		state.watched = nil
+		return simpleString("OK")
+	}
+
+	if state.multi {
+		state.queued = append(state.queued, args)
+		return simpleString("QUEUED")
+	}
+
+	return s.exec(args)
+}
+
+func (s *fakeRedis) exec(args []string) reply {
+	for _, key := range commandKeys(args) {
+		s.expire(key)
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return simpleString("PONG")
+	case "GET":
+		value, ok := s.strings[args[1]]
+
+		if !ok {
+			return nil
+		}
+
+		return value
+	case "SET":
+		key := args[1]
+		s.strings[key] = args[2]
+		delete(s.sets, key)
+		delete(s.expires, key)
+
+		for i := 3; i+1 < len(args); i += 2 {
+			amount, _ := strconv.ParseInt(args[i+1], 10, 64)
+
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				s.expires[key] = time.Now().Add(time.Duration(amount) * time.Second)
+			case "PX":
+				s.expires[key] = time.Now().Add(time.Duration(amount) * time.Millisecond)
+			}
+		}
+
+		s.versions[key]++
+		return simpleString("OK")
+	case "DEL":
+		var deleted int64
+
+		for _, key := range args[1:] {
+			if s.exists(key) {
+				deleted++
+			}
+
+			s.remove(key)
+		}
+
+		return deleted
+	case "TTL", "PTTL":
+		key := args[1]
+
+		if !s.exists(key) {
+			return int64(-2)
+		}
+
+		expiresAt, ok := s.expires[key]
+
+		if !ok {
+			return int64(-1)
+		}
+
+		if strings.ToUpper(args[0]) == "PTTL" {
+			return time.Until(expiresAt).Milliseconds()
+		}
+
+		return int64(time.Until(expiresAt).Round(time.Second) / time.Second)
+	case "EXPIRE", "PEXPIRE":
+		key := args[1]
+
+		if !s.exists(key) {
+			return int64(0)
+		}
+
+		amount, _ := strconv.ParseInt(args[2], 10, 64)
+		unit := time.Second
+
+		if strings.ToUpper(args[0]) == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+
+		s.expires[key] = time.Now().Add(time.Duration(amount) * unit)
+		s.versions[key]++
+		return int64(1)
+	case "SADD":
+		key := args[1]
+		var added int64
+
+		if s.sets[key] == nil {
+			s.sets[key] = map[string]struct{}{}
+		}
+
+		for _, member := range args[2:] {
+			if _, ok := s.sets[key][member]; !ok {
+				s.sets[key][member] = struct{}{}
+				added++
+			}
+		}
+
+		s.versions[key]++
+		return added
+	case "SREM":
+		key := args[1]
+		var removed int64
+
+		for _, member := range args[2:] {
+			if _, ok := s.sets[key][member]; ok {
+				delete(s.sets[key], member)
+				removed++
+			}
+		}
+
+		if len(s.sets[key]) == 0 {
+			s.remove(key)
+		}
+
+		s.versions[key]++
+		return removed
+	case "SMEMBERS":
+		members := make([]reply, 0, len(s.sets[args[1]]))
+
+		for member := range s.sets[args[1]] {
+			members = append(members, member)
+		}
+
+		sort.Slice(members, func(i, j int) bool { return members[i].(string) < members[j].(string) })
+		return members
+	}
+
+	return redisError("ERR unknown command '" + args[0] + "'")
+}
+
+func commandKeys(args []string) []string {
+	switch strings.ToUpper(args[0]) {
+	case "DEL":
+		return args[1:]
+	case "PING":
+		return nil
+	}
+
+	if len(args) > 1 {
+		return args[1:2]
+	}
+
+	return nil
+}
+
+func (s *fakeRedis) exists(key string) bool {
+	_, isString := s.strings[key]
+	_, isSet := s.sets[key]
+
+	return isString || isSet
+}
+
+func (s *fakeRedis) remove(key string) {
+	if s.exists(key) {
+		s.versions[key]++
+	}
+
+	delete(s.strings, key)
+	delete(s.sets, key)
+	delete(s.expires, key)
+}
+
+// Ленивое удаление протухших ключей, как при обращении к ключу в Redis
+func (s *fakeRedis) expire(key string) {
+	if expiresAt, ok := s.expires[key]; ok && !time.Now().Before(expiresAt) {
+		s.remove(key)
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+
+	for i := 0; i < count; i++ {
+		header, err := readLine(reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.Atoi(header[1:])
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeReply(writer *bufio.Writer, value reply) {
+	switch v := value.(type) {
+	case nil:
+		writer.WriteString("$-1\r\n")
+	case simpleString:
+		fmt.Fprintf(writer, "+%s\r\n", v)
+	case redisError:
+		fmt.Fprintf(writer, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(writer, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(v), v)
+	case []reply:
+		if v == nil {
+			writer.WriteString("*-1\r\n")
+			return
+		}
+
+		fmt.Fprintf(writer, "*%d\r\n", len(v))
+
+		for _, item := range v {
+			writeReply(writer, item)
+		}
+	}
+}
@@ -25,10 +25,33 @@ func (d *Database[T]) Create(token *T) *e.DBError {
 	return nil
 }
 
+// Заменяет токен пользователя новым. Старый токен удаляется в той же транзакции,
+// иначе повторный запрос упирается в unique по user_id.
+func (d *Database[T]) Replace(userId string, token *T) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(new(T)).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return e.NewDBError(e.DbExist, "Token with this key/keys already exists.", err.Error())
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+// Протухшие токены не находятся. Срок сравнивается в базе: expires_at хранится без часового пояса.
 func (d *Database[T]) Get(conditions map[string]interface{}) (*T, *e.DBError) {
 	var token T
 
-	result := d.DB.Where(conditions).First(&token)
+	result := d.DB.Where(conditions).Where("expires_at > now()").First(&token)
 
 	if result.Error != nil {
 		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -55,6 +78,18 @@ func (d *Database[T]) Delete(condition map[string]interface{}) *e.DBError {
 	return nil
 }
 
+func (d *Database[T]) DeleteExpired() (int64, *e.DBError) {
+	var token T
+
+	result := d.DB.Where("expires_at <= now()").Delete(&token)
+
+	if result.Error != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", result.Error.Error())
+	}
+
+	return result.RowsAffected, nil
+}
+
 func isDuplicateKeyError(err error) bool {
 	pgErr, ok := err.(*pgconn.PgError)
 	if ok {
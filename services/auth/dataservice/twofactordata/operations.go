@@ -0,0 +1,112 @@
+package twofactordata
+
+import (
+	"errors"
+	"time"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Get(userId string) (*m.TwoFactor, *e.DBError) {
+	var twoFactor m.TwoFactor
+
+	if err := d.DB.Where("user_id = ?", userId).First(&twoFactor).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &twoFactor, nil
+}
+
+func (d *Database) Save(twoFactor *m.TwoFactor) *e.DBError {
+	if err := d.DB.Save(twoFactor).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Включает 2FA и заменяет коды восстановления одной транзакцией
+func (d *Database) Enable(userId string, step int64, recoveryCodes []m.RecoveryCode) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&m.TwoFactor{}).Where("user_id = ?", userId).Updates(map[string]interface{}{"enabled": true, "last_used_step": step})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&m.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+
+	if err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Атомарно сдвигает последний принятый шаг. Если шаг уже использован - DbNotFound.
+func (d *Database) UseStep(userId string, step int64) *e.DBError {
+	result := d.DB.Model(&m.TwoFactor{}).Where("user_id = ? AND last_used_step < ?", userId, step).Update("last_used_step", step)
+
+	if result.Error != nil {
+		return errorHandle(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "Code was already used.", "")
+	}
+
+	return nil
+}
+
+func (d *Database) UseRecoveryCode(userId string, codeHash string) *e.DBError {
+	result := d.DB.Model(&m.RecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return errorHandle(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "Recovery code not found.", "")
+	}
+
+	return nil
+}
+
+func (d *Database) Delete(userId string) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&m.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userId).Delete(&m.TwoFactor{}).Error
+	})
+
+	if err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func errorHandle(err error) *e.DBError {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Entity not found.", err.Error())
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+}
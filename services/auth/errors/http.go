@@ -5,18 +5,28 @@ import (
 )
 
 const (
-	HttpInternalError int = fiber.StatusInternalServerError
-	HttpAlreadyExist  int = fiber.StatusConflict
-	HttpNotFound      int = fiber.StatusNotFound
-	HttpBadRequest    int = fiber.StatusBadRequest
-	HttpForbidden     int = fiber.StatusForbidden
-	HttpUnauthorized  int = fiber.StatusUnauthorized
+	HttpInternalError        int = fiber.StatusInternalServerError
+	HttpAlreadyExist         int = fiber.StatusConflict
+	HttpNotFound             int = fiber.StatusNotFound
+	HttpBadRequest           int = fiber.StatusBadRequest
+	HttpForbidden            int = fiber.StatusForbidden
+	HttpUnauthorized         int = fiber.StatusUnauthorized
+	HttpPayloadTooLarge      int = fiber.StatusRequestEntityTooLarge
+	HttpUnsupportedMediaType int = fiber.StatusUnsupportedMediaType
+	HttpTooManyRequests      int = fiber.StatusTooManyRequests
 )
 
 type (
 	ErrorResponse struct {
 		ErrorCode    int    `json:"error_code"`
 		ErrorMessage string `json:"err_msg"`
+		// Список нарушенных правил, например политики паролей
+		Violations []Violation `json:"violations,omitempty"`
+	}
+
+	Violation struct {
+		Rule    string `json:"rule"`
+		Message string `json:"message"`
 	}
 )
 
@@ -40,3 +50,11 @@ func NewErrorResponse(errorType int, message string) *ErrorResponse {
 		ErrorMessage: message,
 	}
 }
+
+func NewValidationErrorResponse(message string, violations []Violation) *ErrorResponse {
+	return &ErrorResponse{
+		ErrorCode:    HttpBadRequest,
+		ErrorMessage: message,
+		Violations:   violations,
+	}
+}
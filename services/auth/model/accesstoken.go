@@ -0,0 +1,29 @@
+package model
+
+type (
+	// Полезная нагрузка access-токена. ai, user и stat проверяют его сами, без запроса в auth.
+	AccessClaims struct {
+		Id          string   `json:"jti"`
+		Issuer      string   `json:"iss"`
+		Subject     string   `json:"sub"`
+		Roles       []string `json:"roles"`
+		Permissions []string `json:"perms"`
+		IsDeveloper bool     `json:"dev"`
+		IssuedAt    int64    `json:"iat"`
+		ExpiresAt   int64    `json:"exp"`
+	}
+
+	// Публичный ключ в формате JWK (RFC 8037, OKP/Ed25519)
+	Jwk struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+	}
+
+	Jwks struct {
+		Keys []Jwk `json:"keys"`
+	}
+)
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type AuditAction string
+type AuditResult string
+
+const (
+	AuditRegister          AuditAction = "register"
+	AuditLogin             AuditAction = "login"
+	AuditLoginTwoFactor    AuditAction = "login.2fa"
+	AuditLoginMagicLink    AuditAction = "login.magic_link"
+	AuditLoginGoogle       AuditAction = "login.google"
+	AuditLogout            AuditAction = "logout"
+	AuditPasswordResetSend AuditAction = "password.reset_request"
+	AuditPasswordReset     AuditAction = "password.reset"
+	AuditPasswordChange    AuditAction = "password.change"
+	AuditEmailChangeSend   AuditAction = "email.change_request"
+	AuditEmailChange       AuditAction = "email.change"
+	AuditTwoFactorEnable   AuditAction = "2fa.enable"
+	AuditTwoFactorDisable  AuditAction = "2fa.disable"
+	AuditSessionRevoke     AuditAction = "session.revoke"
+	AuditSessionRevokeAll  AuditAction = "session.revoke_others"
+	AuditRoleGrant         AuditAction = "role.grant"
+	AuditRoleRevoke        AuditAction = "role.revoke"
+	AuditDeveloperReview   AuditAction = "developer.review"
+	AuditAccountDeleteSend AuditAction = "account.delete_request"
+	AuditAccountDeleteConf AuditAction = "account.delete_confirm"
+	AuditAccountDeleteStop AuditAction = "account.delete_cancel"
+)
+
+const (
+	AuditSuccess AuditResult = "success"
+	AuditFailure AuditResult = "failure"
+)
+
+var auditActions = map[AuditAction]bool{
+	AuditRegister:          true,
+	AuditLogin:             true,
+	AuditLoginTwoFactor:    true,
+	AuditLoginMagicLink:    true,
+	AuditLoginGoogle:       true,
+	AuditLogout:            true,
+	AuditPasswordResetSend: true,
+	AuditPasswordReset:     true,
+	AuditPasswordChange:    true,
+	AuditEmailChangeSend:   true,
+	AuditEmailChange:       true,
+	AuditTwoFactorEnable:   true,
+	AuditTwoFactorDisable:  true,
+	AuditSessionRevoke:     true,
+	AuditSessionRevokeAll:  true,
+	AuditRoleGrant:         true,
+	AuditRoleRevoke:        true,
+	AuditDeveloperReview:   true,
+	AuditAccountDeleteSend: true,
+	AuditAccountDeleteConf: true,
+	AuditAccountDeleteStop: true,
+}
+
+func IsKnownAuditAction(action AuditAction) bool {
+	return auditActions[action]
+}
+
+// Запись журнала безопасности. Только добавляется, изменить или удалить её нельзя.
+// Actor - кто выполнил действие (пусто, если не вошёл), Target - над кем: id пользователя или почта.
+type AuditEvent struct {
+	ID        uuid.UUID     `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	ActorId   uuid.NullUUID `json:"actor_id" gorm:"type:uuid"`
+	Action    AuditAction   `json:"action" gorm:"type:string;not null"`
+	Target    string        `json:"target" gorm:"type:string;not null"`
+	IP        string        `json:"ip" gorm:"column:ip;type:string"`
+	UserAgent string        `json:"user_agent" gorm:"type:string"`
+	Result    AuditResult   `json:"result" gorm:"type:string;not null"`
+	CreatedAt time.Time     `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+type AuditFilter struct {
+	ActorId string
+	Target  string
+	Action  AuditAction
+	Result  AuditResult
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
@@ -0,0 +1,8 @@
+package model
+
+// Ссылка для входа по почте. Хранится под хешем токена, поэтому по содержимому
+// Redis войти нельзя. NonceHash привязывает ссылку к браузеру, который её запросил.
+type MagicLink struct {
+	UserId    string `json:"user_id"`
+	NonceHash string `json:"nonce_hash"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type (
+	// Привязка аккаунта к пользователю внешнего провайдера (Google)
+	OidcIdentity struct {
+		ID        uuid.UUID `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+		Issuer    string    `json:"issuer" gorm:"type:string;not null;uniqueIndex:idx_oidc_subject"`
+		Subject   string    `json:"subject" gorm:"type:string;not null;uniqueIndex:idx_oidc_subject"`
+		UserId    string    `json:"user_id" gorm:"type:uuid;not null"`
+		Email     string    `json:"email" gorm:"type:string;not null"`
+		CreatedAt time.Time `json:"created_at" gorm:"type:time;default: now();not null"`
+	}
+
+	// Данные, которые нужны на callback: nonce для ID token и code_verifier для PKCE
+	OidcState struct {
+		Nonce    string `json:"nonce"`
+		Verifier string `json:"verifier"`
+	}
+
+	// Ожидающая привязка Google к существующему аккаунту с паролем
+	OidcLink struct {
+		UserId  string `json:"user_id"`
+		Issuer  string `json:"issuer"`
+		Subject string `json:"subject"`
+		Email   string `json:"email"`
+	}
+
+	IdTokenClaims struct {
+		Issuer        string `json:"iss"`
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Nonce         string `json:"nonce"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Picture       string `json:"picture"`
+	}
+)
@@ -0,0 +1,8 @@
+package model
+
+// Права выдаются сервисом пользователей
+type Permission string
+
+const (
+	PermissionReadAudit Permission = "audit:read"
+)
@@ -4,4 +4,9 @@ type QueueName string
 
 const (
 	Reject QueueName = "token_rejected"
+
+	AccountDeleteAuth  QueueName = "account_delete_auth"
+	AccountDeleteReply QueueName = "account_delete_reply"
+
+	SecurityAlert QueueName = "security_alert"
 )
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Важное изменение аккаунта. Сервис пользователей показывает его владельцу как уведомление.
+type SecurityAlertEvent struct {
+	EventId   string      `json:"event_id"`
+	UserId    string      `json:"user_id"`
+	Action    AuditAction `json:"action"`
+	IP        string      `json:"ip"`
+	UserAgent string      `json:"user_agent"`
+	CreatedAt time.Time   `json:"created_at"`
+}
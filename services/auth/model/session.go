@@ -10,7 +10,20 @@ type (
 	}
 
 	SessionPayload struct {
-		UserId    string    `json:"user_id"`
-		CreatedAt time.Time `json:"created_at"`
+		UserId     string    `json:"user_id"`
+		CreatedAt  time.Time `json:"created_at"`
+		Device     string    `json:"device"`
+		UserAgent  string    `json:"user_agent"`
+		IP         string    `json:"ip"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		RotatedAt  time.Time `json:"rotated_at"`
+		// Заполнено у старого ID после ротации: запись живёт GraceTTL и ведёт на новый ID
+		ReplacedBy string `json:"replaced_by,omitempty"`
+	}
+
+	// Откуда пришёл запрос на вход, сохраняется в сессии для списка устройств
+	SessionMeta struct {
+		UserAgent string
+		IP        string
 	}
 )
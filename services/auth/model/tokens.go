@@ -18,14 +18,14 @@ type ResetToken struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
 	UserId    uuid.UUID `json:"-" gorm:"type:uuid;not null;unique"`
 	Token     string    `json:"-" gorm:"type:string;not null"`
-	ExpiresAt time.Time `json:"-" gorm:"type:time;not null;default: now() + INTERVAL '5 minutes'"`
-	CreatedAt time.Time `json:"-" gorm:"type:time;default: now();not null"`
+	ExpiresAt time.Time `json:"-" gorm:"type:timestamp;not null;default: now() + INTERVAL '10 minutes'"`
+	CreatedAt time.Time `json:"-" gorm:"type:timestamp;default: now();not null"`
 }
 
 type VerificationToken struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
 	UserId    string    `json:"-" gorm:"type:uuid;not null;unique"`
 	Token     string    `json:"-" gorm:"type:string;not null"`
-	ExpiresAt time.Time `json:"-" gorm:"type:time;not null;default: now() + INTERVAL '5 minutes'"`
-	CreatedAt time.Time `json:"-" gorm:"type:time;default: now();not null"`
+	ExpiresAt time.Time `json:"-" gorm:"type:timestamp;not null;default: now() + INTERVAL '10 minutes'"`
+	CreatedAt time.Time `json:"-" gorm:"type:timestamp;default: now();not null"`
 }
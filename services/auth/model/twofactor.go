@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type (
+	// Секрет TOTP пользователя. Пока Enabled == false, это незавершённое подключение.
+	// LastUsedStep - последний принятый шаг, повторно тот же код не принимается.
+	TwoFactor struct {
+		UserId       string    `json:"user_id" gorm:"type:uuid;primarykey"`
+		Secret       string    `json:"-" gorm:"type:string;not null"`
+		Enabled      bool      `json:"enabled" gorm:"default:false;not null"`
+		LastUsedStep int64     `json:"-" gorm:"default:0;not null"`
+		CreatedAt    time.Time `json:"created_at" gorm:"type:time"`
+		UpdatedAt    time.Time `json:"updated_at" gorm:"type:time"`
+	}
+
+	RecoveryCode struct {
+		ID        uuid.UUID  `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+		UserId    string     `json:"-" gorm:"type:uuid;not null"`
+		CodeHash  string     `json:"-" gorm:"type:string;not null"`
+		UsedAt    *time.Time `json:"used_at" gorm:"type:time"`
+		CreatedAt time.Time  `json:"created_at" gorm:"type:time;default: now();not null"`
+	}
+
+	// Вход, у которого проверен пароль, но ещё не введён второй фактор
+	PendingLogin struct {
+		UserId    string    `json:"user_id"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
@@ -1,16 +1,32 @@
 package handlers
 
 import (
+	"context"
+	"time"
 	"warehouseai/auth/adapter/broker"
 	"warehouseai/auth/adapter/grpc/client/user"
-	"warehouseai/auth/dataservice/picturedata"
+	"warehouseai/auth/adapter/oidc"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	"warehouseai/auth/dataservice/attemptdata"
+	"warehouseai/auth/dataservice/auditdata"
+	"warehouseai/auth/dataservice/identitydata"
+	"warehouseai/auth/dataservice/magiclinkdata"
+	"warehouseai/auth/dataservice/oidcdata"
+	"warehouseai/auth/dataservice/pendingdata"
 	"warehouseai/auth/dataservice/sessiondata"
 	"warehouseai/auth/dataservice/tokendata"
+	"warehouseai/auth/dataservice/twofactordata"
 	e "warehouseai/auth/errors"
 	"warehouseai/auth/model"
 	"warehouseai/auth/service"
+	"warehouseai/auth/service/accesstoken"
+	"warehouseai/auth/service/audit"
+	"warehouseai/auth/service/google"
 	"warehouseai/auth/service/login"
+	"warehouseai/auth/service/password"
 	"warehouseai/auth/service/register"
+	"warehouseai/auth/service/twofactor"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -20,10 +36,25 @@ type Handler struct {
 	ResetTokenDB        *tokendata.Database[model.ResetToken]
 	VerificationTokenDB *tokendata.Database[model.VerificationToken]
 	SessionDB           *sessiondata.Database
-	PictureStorage      *picturedata.Storage
+	OidcStateDB         *oidcdata.Database
+	IdentityDB          *identitydata.Database
+	TwoFactorDB         *twofactordata.Database
+	PendingLoginDB      *pendingdata.Database
+	AttemptDB           *attemptdata.Database
+	MagicLinkDB         *magiclinkdata.Database
+	AuditDB             *auditdata.Database
+	PictureStorage      dataservice.PictureInterface
 	Broker              *broker.Broker
 	Logger              *logrus.Logger
 	UserClient          *user.UserGrpcClient
+	OidcProvider        *oidc.Provider
+	OidcCfg             config.OidcCfg
+	TwoFactorCfg        config.TwoFactorCfg
+	LockoutCfg          config.LockoutCfg
+	MagicLinkCfg        config.MagicLinkCfg
+	CookieCfg           config.CookieCfg
+	Signer              *accesstoken.Signer
+	PasswordPolicy      *password.Policy
 }
 
 func (h *Handler) RegisterHandler(c *fiber.Ctx) error {
@@ -50,12 +81,20 @@ func (h *Handler) RegisterHandler(c *fiber.Ctx) error {
 	req.Email = form.Value["email"][0]
 	req.ViaGoogle = false
 
-	userId, svcErr := register.Register(&req, h.UserClient, h.VerificationTokenDB, h.Broker, h.Logger)
+	userId, svcErr := register.Register(&req, h.UserClient, h.VerificationTokenDB, h.Broker, h.PasswordPolicy, h.Logger)
 
 	if svcErr != nil {
+		h.audit(c, model.AuditRegister, "", req.Email, svcErr)
+		service.DeleteImage(req.Image, h.PictureStorage, h.Logger)
 		return c.Status(svcErr.ErrorCode).JSON(svcErr)
 	}
 
+	h.audit(c, model.AuditRegister, "", userId.UserId, nil)
+
+	if imageUrls, ok := c.Locals("imageUrls").(*service.ImageUrls); ok {
+		userId.Images = imageUrls
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(userId)
 }
 
@@ -67,22 +106,166 @@ func (h *Handler) LoginHandler(c *fiber.Ctx) error {
 		return c.Status(response.ErrorCode).JSON(response)
 	}
 
-	response, session, err := login.Login(&request, h.UserClient, h.SessionDB, h.Logger)
+	request.Meta = sessionMeta(c)
+
+	response, session, err := login.Login(&request, h.UserClient, h.SessionDB, h.TwoFactorDB, h.PendingLoginDB, h.AttemptDB, h.Broker, h.TwoFactorCfg, h.LockoutCfg, h.Logger)
+
+	if err != nil {
+		h.audit(c, model.AuditLogin, "", request.Email, err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	// При 2FA вход завершится на /login/2fa, там и запишется
+	if response.UserId != "" {
+		h.audit(c, model.AuditLogin, response.UserId, response.UserId, nil)
+	}
+
+	// При включённой 2FA сессии ещё нет, клиент должен вызвать /login/2fa
+	if session != nil {
+		h.setSessionCookies(c, session)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) MagicLinkHandler(c *fiber.Ctx) error {
+	var request login.MagicLinkRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	request.IP = c.IP()
+	request.CookieNonce = c.Cookies("magicNonce")
+
+	response, err := login.RequestMagicLink(request, h.UserClient, h.MagicLinkDB, h.AttemptDB, h.Broker, h.MagicLinkCfg, h.LockoutCfg, h.Logger)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
 	}
 
+	// Lax, т.к. ссылку открывают переходом из почтового клиента
 	c.Cookie(&fiber.Cookie{
-		Name:     "sessionId",
-		Value:    session.ID,
-		SameSite: fiber.CookieSameSiteNoneMode,
+		Name:     "magicNonce",
+		Value:    response.Nonce,
+		Expires:  time.Now().Add(h.MagicLinkCfg.TTL),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
 		Secure:   true,
 	})
 
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) MagicLinkConfirmHandler(c *fiber.Ctx) error {
+	request := login.MagicLinkConfirmRequest{
+		Token:       c.Query("token"),
+		CookieNonce: c.Cookies("magicNonce"),
+		Meta:        sessionMeta(c),
+	}
+
+	response, session, err := login.ConfirmMagicLink(request, h.MagicLinkDB, h.SessionDB, h.TwoFactorDB, h.PendingLoginDB, h.TwoFactorCfg, h.Logger)
+
+	if err != nil {
+		h.audit(c, model.AuditLoginMagicLink, "", "", err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	if response.UserId != "" {
+		h.audit(c, model.AuditLoginMagicLink, response.UserId, response.UserId, nil)
+	}
+
+	c.ClearCookie("magicNonce")
+
+	if session != nil {
+		h.setSessionCookies(c, session)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+func (h *Handler) LoginTwoFactorHandler(c *fiber.Ctx) error {
+	var request login.TwoFactorLoginRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	request.Meta = sessionMeta(c)
+
+	// После исчерпания попыток вход удаляется, поэтому пользователя узнаём заранее
+	target := ""
+
+	if pending, dbErr := h.PendingLoginDB.Get(context.Background(), request.PendingToken); dbErr == nil {
+		target = pending.UserId
+	}
+
+	response, session, err := login.LoginTwoFactor(&request, h.SessionDB, h.TwoFactorDB, h.PendingLoginDB, h.TwoFactorCfg, h.Logger)
+
+	if err != nil {
+		h.audit(c, model.AuditLoginTwoFactor, "", target, err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	h.audit(c, model.AuditLoginTwoFactor, response.UserId, response.UserId, nil)
+
+	h.setSessionCookies(c, session)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) TwoFactorEnrollHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+
+	response, err := twofactor.Enroll(userId, h.TwoFactorDB, h.UserClient, h.TwoFactorCfg, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) TwoFactorConfirmHandler(c *fiber.Ctx) error {
+	var request twofactor.ConfirmRequest
+	userId := c.Locals("userId").(string)
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	response, err := twofactor.Confirm(userId, &request, h.TwoFactorDB, h.TwoFactorCfg, h.Logger)
+	h.audit(c, model.AuditTwoFactorEnable, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) TwoFactorDisableHandler(c *fiber.Ctx) error {
+	var request twofactor.DisableRequest
+	userId := c.Locals("userId").(string)
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	err := twofactor.Disable(userId, &request, h.TwoFactorDB, h.UserClient, h.Logger)
+	h.audit(c, model.AuditTwoFactorDisable, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
 func (h *Handler) RegisterVerifyHandler(c *fiber.Ctx) error {
 	token := c.Query("token")
 	user := c.Query("user")
@@ -90,9 +273,10 @@ func (h *Handler) RegisterVerifyHandler(c *fiber.Ctx) error {
 	request := register.RegisterVerifyRequest{
 		UserId: user,
 		Token:  token,
+		IP:     c.IP(),
 	}
 
-	response, err := register.RegisterVerify(request, h.UserClient, h.VerificationTokenDB, h.Logger)
+	response, err := register.RegisterVerify(request, h.UserClient, h.VerificationTokenDB, h.AttemptDB, h.LockoutCfg, h.Logger)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err.ErrorMessage)
@@ -101,8 +285,26 @@ func (h *Handler) RegisterVerifyHandler(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+func (h *Handler) ResendVerificationHandler(c *fiber.Ctx) error {
+	var request register.ResendVerificationRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	request.IP = c.IP()
+
+	if err := register.ResendVerification(request, h.UserClient, h.VerificationTokenDB, h.AttemptDB, h.Broker, h.LockoutCfg, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
 func (h *Handler) PasswordReset(c *fiber.Ctx) error {
 	resetTokenId := c.Query("token_id")
+	verificationCode := c.Query("verification")
 	var request service.ResetConfirmRequest
 
 	if err := c.BodyParser(&request); err != nil {
@@ -110,12 +312,15 @@ func (h *Handler) PasswordReset(c *fiber.Ctx) error {
 		return c.Status(response.ErrorCode).JSON(response)
 	}
 
-	response, err := service.PasswordReset(&request, resetTokenId, h.UserClient, h.ResetTokenDB, h.Logger)
+	response, err := service.PasswordReset(&request, resetTokenId, verificationCode, c.IP(), h.UserClient, h.ResetTokenDB, h.SessionDB, h.AttemptDB, h.LockoutCfg, h.PasswordPolicy, h.Logger)
 
 	if err != nil {
+		h.audit(c, model.AuditPasswordReset, "", "", err)
 		return c.Status(err.ErrorCode).JSON(err)
 	}
 
+	h.audit(c, model.AuditPasswordReset, "", response.UserId, nil)
+
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
@@ -123,7 +328,7 @@ func (h *Handler) VerifyReset(c *fiber.Ctx) error {
 	verificationCode := c.Query("verification")
 	resetTokenId := c.Query("token_id")
 
-	resetToken, err := service.VerifyResetCode(verificationCode, resetTokenId, h.ResetTokenDB, h.Logger)
+	resetToken, err := service.VerifyResetCode(verificationCode, resetTokenId, c.IP(), h.ResetTokenDB, h.AttemptDB, h.LockoutCfg, h.Logger)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
@@ -141,6 +346,7 @@ func (h *Handler) SendResetHandler(c *fiber.Ctx) error {
 	}
 
 	resetToken, err := service.SendResetEmail(request, h.ResetTokenDB, h.UserClient, h.Broker, h.Logger)
+	h.audit(c, model.AuditPasswordResetSend, "", request.Email, err)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
@@ -157,10 +363,18 @@ func (h *Handler) LogoutHandler(c *fiber.Ctx) error {
 		return c.Status(response.ErrorCode).JSON(response)
 	}
 
+	userId := ""
+
+	if session, dbErr := h.SessionDB.Get(context.Background(), sessionId); dbErr == nil {
+		userId = session.Payload.UserId
+	}
+
 	if err := service.Logout(sessionId, h.SessionDB, h.Logger); err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
 	}
-	c.ClearCookie("sessionId")
+	c.ClearCookie("sessionId", "accessToken", "csrfToken")
+
+	h.audit(c, model.AuditLogout, userId, userId, nil)
 
 	return c.SendStatus(fiber.StatusOK)
 }
@@ -179,12 +393,232 @@ func (h *Handler) WhoAmIHandler(c *fiber.Ctx) error {
 		return c.Status(err.ErrorCode).JSON(err)
 	}
 
+	// whoami вызывается фронтендом при загрузке, заодно обновляем access-токен
+	h.setSessionCookies(c, newSession)
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) ListSessionsHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	sessionId := c.Locals("sessionId").(string)
+
+	response, err := service.ListSessions(userId, sessionId, h.SessionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) RevokeSessionHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	publicId := c.Query("id")
+
+	if publicId == "" {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Empty session id")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	err := service.RevokeSession(userId, publicId, h.SessionDB, h.Logger)
+	h.audit(c, model.AuditSessionRevoke, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) RevokeOtherSessionsHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	sessionId := c.Locals("sessionId").(string)
+
+	response, err := service.RevokeSessions(userId, sessionId, h.SessionDB, h.Logger)
+	h.audit(c, model.AuditSessionRevokeAll, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) GoogleLoginHandler(c *fiber.Ctx) error {
+	response, err := google.Login(h.OidcProvider, h.OidcStateDB, h.OidcCfg, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	// Lax, т.к. callback приходит редиректом с домена Google
+	c.Cookie(&fiber.Cookie{
+		Name:     "oidcState",
+		Value:    response.State,
+		Expires:  time.Now().Add(h.OidcCfg.StateTTL),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+		Secure:   true,
+	})
+
+	return c.Redirect(response.Url, fiber.StatusFound)
+}
+
+func (h *Handler) GoogleCallbackHandler(c *fiber.Ctx) error {
+	if providerErr := c.Query("error"); providerErr != "" {
+		response := e.NewErrorResponse(e.HttpBadRequest, providerErr)
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	request := google.CallbackRequest{
+		Code:        c.Query("code"),
+		State:       c.Query("state"),
+		CookieState: c.Cookies("oidcState"),
+		Meta:        sessionMeta(c),
+	}
+
+	c.ClearCookie("oidcState")
+
+	response, session, err := google.Callback(&request, h.OidcProvider, h.OidcStateDB, h.IdentityDB, h.SessionDB, h.TwoFactorDB, h.PendingLoginDB, h.UserClient, h.Broker, h.OidcCfg, h.TwoFactorCfg, h.Logger)
+
+	if err != nil {
+		h.audit(c, model.AuditLoginGoogle, "", "", err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	// Без UserId ждём привязки аккаунта или второго фактора, входа ещё не было
+	if response.UserId != "" {
+		h.audit(c, model.AuditLoginGoogle, response.UserId, response.UserId, nil)
+	}
+
+	if session != nil {
+		h.setSessionCookies(c, session)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) GoogleLinkHandler(c *fiber.Ctx) error {
+	var request google.LinkRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid request body")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	request.Meta = sessionMeta(c)
+
+	response, session, err := google.Link(&request, h.OidcStateDB, h.IdentityDB, h.SessionDB, h.TwoFactorDB, h.PendingLoginDB, h.UserClient, h.TwoFactorCfg, h.Logger)
+
+	if err != nil {
+		h.audit(c, model.AuditLoginGoogle, "", "", err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	if response.UserId != "" {
+		h.audit(c, model.AuditLoginGoogle, response.UserId, response.UserId, nil)
+	}
+
+	if session != nil {
+		h.setSessionCookies(c, session)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) SecurityActivityHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+
+	existUser, err := h.UserClient.GetById(context.Background(), userId)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	events, err := audit.GetRecentActivity(userId, existUser.Email, h.AuditDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(events)
+}
+
+func (h *Handler) AuditQueryHandler(c *fiber.Ctx) error {
+	var request audit.QueryRequest
+
+	if err := c.QueryParser(&request); err != nil {
+		response := e.NewErrorResponse(e.HttpBadRequest, "Invalid query parameters")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	events, err := audit.Query(request, h.AuditDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(events)
+}
+
+// Токен для заголовка X-CSRF-Token. Фронтенд на другом домене не может прочитать cookie, поэтому он ещё и в ответе.
+func (h *Handler) CsrfHandler(c *fiber.Ctx) error {
+	response, err := service.IssueCsrfToken(c.Cookies("csrfToken"), h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "csrfToken",
+		Value:    response.Token,
+		HTTPOnly: true,
+		SameSite: h.CookieCfg.SameSite,
+		Secure:   true,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) JwksHandler(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	return c.Status(fiber.StatusOK).JSON(h.Signer.Jwks())
+}
+
+// Cookie сессии и короткоживущий access-токен, который ai и user проверяют локально
+func (h *Handler) setSessionCookies(c *fiber.Ctx, session *model.Session) {
 	c.Cookie(&fiber.Cookie{
 		Name:     "sessionId",
-		Value:    newSession.ID,
-		SameSite: fiber.CookieSameSiteNoneMode,
+		Value:    session.ID,
+		SameSite: h.CookieCfg.SameSite,
 		Secure:   true,
 	})
 
-	return c.SendStatus(fiber.StatusOK)
+	// Без токена сервисы проверят сессию через gRPC, поэтому ошибку только логируем
+	accessToken, _, err := accesstoken.Issue(session.Payload.UserId, h.Signer, h.UserClient, h.Logger)
+
+	if err != nil {
+		return
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "accessToken",
+		Value:    accessToken,
+		MaxAge:   int(h.Signer.TTL().Seconds()),
+		HTTPOnly: true,
+		SameSite: h.CookieCfg.SameSite,
+		Secure:   true,
+	})
+}
+
+func sessionMeta(c *fiber.Ctx) model.SessionMeta {
+	return model.SessionMeta{UserAgent: c.Get(fiber.HeaderUserAgent), IP: c.IP()}
+}
+
+func (h *Handler) audit(c *fiber.Ctx, action model.AuditAction, actorId string, target string, err *e.ErrorResponse) {
+	event := audit.NewEvent(action, actorId, target, sessionMeta(c), err)
+	audit.Record(event, h.AuditDB, h.Logger)
+	audit.Alert(event, h.Broker, h.Logger)
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"warehouseai/auth/config"
 	"warehouseai/auth/dataservice"
 	"warehouseai/auth/service"
 
@@ -8,7 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func Image(logger *logrus.Logger, picture dataservice.PictureInterface) func(c *fiber.Ctx) error {
+func Image(logger *logrus.Logger, picture dataservice.PictureInterface, cfg config.ImageCfg) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		pic, err := c.FormFile("image")
 
@@ -16,13 +17,14 @@ func Image(logger *logrus.Logger, picture dataservice.PictureInterface) func(c *
 			return c.Next()
 		}
 
-		url, svcErr := service.UploadImage(pic, picture, logger)
+		urls, svcErr := service.UploadImage(pic, picture, cfg, logger)
 
 		if svcErr != nil {
 			return c.Status(svcErr.ErrorCode).JSON(svcErr)
 		}
 
-		c.Locals("imageUrl", url)
+		c.Locals("imageUrl", urls.Full)
+		c.Locals("imageUrls", urls)
 		return c.Next()
 	}
 }
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"warehouseai/auth/adapter"
+	e "warehouseai/auth/errors"
+	"warehouseai/auth/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Ставится после SessionStrict. Своих access-токенов auth не проверяет, права берёт у сервиса пользователей.
+func RequirePermission(permission model.Permission, user adapter.UserGrpcInterface) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		existUser, err := user.GetById(context.Background(), c.Locals("userId").(string))
+
+		if err != nil {
+			return c.Status(err.ErrorCode).JSON(err)
+		}
+
+		for _, granted := range existUser.Permissions {
+			if granted == string(permission) {
+				return c.Next()
+			}
+		}
+
+		return c.Status(e.HttpForbidden).JSON(e.NewErrorResponse(e.HttpForbidden, "Not enough permissions"))
+	}
+}
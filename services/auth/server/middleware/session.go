@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	"warehouseai/auth/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func SessionStrict(logger *logrus.Logger, session dataservice.SessionInterface, cookie config.CookieCfg) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		sessionId := c.Cookies("sessionId")
+
+		if sessionId == "" {
+			response := e.NewErrorResponse(e.HttpUnauthorized, "Empty session key")
+			return c.Status(response.ErrorCode).JSON(response)
+		}
+
+		userId, newSession, err := service.Authenticate(sessionId, session, logger)
+
+		if err != nil {
+			return c.Status(err.ErrorCode).JSON(err)
+		}
+
+		// Cookie обновляем только после ротации ID
+		if newSession.ID != sessionId {
+			c.Cookie(&fiber.Cookie{
+				Name:     "sessionId",
+				Value:    newSession.ID,
+				SameSite: cookie.SameSite,
+				Secure:   true,
+			})
+		}
+
+		c.Locals("userId", *userId)
+		c.Locals("sessionId", newSession.ID)
+		return c.Next()
+	}
+}
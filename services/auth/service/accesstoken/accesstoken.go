@@ -0,0 +1,32 @@
+package accesstoken
+
+import (
+	"context"
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/adapter/grpc/gen"
+	e "warehouseai/auth/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Выпускает access-токен для пользователя сессии. Роли, права и флаг разработчика берутся
+// из сервиса пользователей на момент выпуска и обновляются не реже раза в TTL.
+// Вместе с токеном возвращается пользователь, для которого он выпущен.
+func Issue(userId string, signer *Signer, user adapter.UserGrpcInterface, logger *logrus.Logger) (string, *gen.User, *e.ErrorResponse) {
+	existUser, gwErr := user.GetById(context.Background(), userId)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Issue access token")
+		return "", nil, gwErr
+	}
+
+	token, err := signer.Sign(existUser.Id, existUser.Roles, existUser.Permissions, existUser.IsDeveloper, time.Now())
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Issue access token")
+		return "", existUser, e.NewErrorResponse(e.HttpInternalError, "Failed to issue access token")
+	}
+
+	return token, existUser, nil
+}
@@ -0,0 +1,114 @@
+package accesstoken
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"warehouseai/auth/adapter/grpc/gen"
+	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testSeed = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func decodeToken(t *testing.T, token string, publicKey ed25519.PublicKey) (header, m.AccessClaims) {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature))
+
+	var h header
+	var claims m.AccessClaims
+
+	rawHeader, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	rawClaims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, json.Unmarshal(rawHeader, &h))
+	require.NoError(t, json.Unmarshal(rawClaims, &claims))
+
+	return h, claims
+}
+
+func TestNewSigner(t *testing.T) {
+	first, err := NewSigner(config.AccessTokenCfg{SigningKey: testSeed, TTL: 5 * time.Minute})
+	require.NoError(t, err)
+
+	second, _ := NewSigner(config.AccessTokenCfg{SigningKey: testSeed, TTL: 5 * time.Minute})
+	require.Equal(t, first.KeyId(), second.KeyId())
+
+	jwks := first.Jwks()
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, "OKP", jwks.Keys[0].Kty)
+	require.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	require.Equal(t, first.KeyId(), jwks.Keys[0].Kid)
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(first.PublicKey()), jwks.Keys[0].X)
+
+	_, err = NewSigner(config.AccessTokenCfg{SigningKey: base64.StdEncoding.EncodeToString([]byte("short"))})
+	require.Error(t, err)
+
+	ephemeral, err := NewSigner(config.AccessTokenCfg{})
+	require.NoError(t, err)
+	require.NotEqual(t, first.KeyId(), ephemeral.KeyId())
+}
+
+func TestIssue(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	signer, _ := NewSigner(config.AccessTokenCfg{SigningKey: testSeed, TTL: 5 * time.Minute})
+	existUser := &gen.User{
+		Id:          uuid.Must(uuid.NewV4()).String(),
+		IsDeveloper: true,
+		Roles:       []string{"USER", "DEVELOPER"},
+		Permissions: []string{"ai:publish"},
+	}
+
+	userMock.EXPECT().GetById(context.Background(), existUser.Id).Return(existUser, nil).Times(1)
+
+	token, issuedFor, err := Issue(existUser.Id, signer, userMock, logger)
+	require.Nil(t, err)
+	require.Equal(t, existUser, issuedFor)
+
+	h, claims := decodeToken(t, token, signer.PublicKey())
+
+	require.Equal(t, header{Alg: Algorithm, Typ: "JWT", Kid: signer.KeyId()}, h)
+	require.Equal(t, Issuer, claims.Issuer)
+	require.Equal(t, existUser.Id, claims.Subject)
+	require.Equal(t, existUser.Roles, claims.Roles)
+	require.Equal(t, existUser.Permissions, claims.Permissions)
+	require.True(t, claims.IsDeveloper)
+	require.Equal(t, int64(5*60), claims.ExpiresAt-claims.IssuedAt)
+	require.NotEmpty(t, claims.Id)
+}
+
+func TestIssueUserNotFound(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	signer, _ := NewSigner(config.AccessTokenCfg{SigningKey: testSeed, TTL: 5 * time.Minute})
+	userId := uuid.Must(uuid.NewV4()).String()
+	notFound := e.NewErrorResponse(e.HttpNotFound, "User not found")
+
+	userMock.EXPECT().GetById(context.Background(), userId).Return(nil, notFound).Times(1)
+
+	token, _, err := Issue(userId, signer, userMock, logger)
+
+	require.Empty(t, token)
+	require.Equal(t, notFound, err)
+}
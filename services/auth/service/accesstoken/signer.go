@@ -0,0 +1,116 @@
+package accesstoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+	"warehouseai/auth/config"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	Issuer    = "warehouseai-auth"
+	Algorithm = "EdDSA"
+)
+
+type Signer struct {
+	key ed25519.PrivateKey
+	kid string
+	ttl time.Duration
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+func NewSigner(cfg config.AccessTokenCfg) (*Signer, error) {
+	var key ed25519.PrivateKey
+
+	if cfg.SigningKey == "" {
+		_, generated, err := ed25519.GenerateKey(rand.Reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key = generated
+	} else {
+		seed, err := base64.StdEncoding.DecodeString(cfg.SigningKey)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(seed) != ed25519.SeedSize {
+			return nil, errors.New("signing key must be a base64 encoded 32 byte Ed25519 seed")
+		}
+
+		key = ed25519.NewKeyFromSeed(seed)
+	}
+
+	// kid - отпечаток публичного ключа, при смене ключа клиенты перечитают JWKS
+	digest := sha256.Sum256(key.Public().(ed25519.PublicKey))
+
+	return &Signer{key: key, kid: base64.RawURLEncoding.EncodeToString(digest[:12]), ttl: cfg.TTL}, nil
+}
+
+func (s *Signer) Sign(userId string, roles []string, permissions []string, isDeveloper bool, now time.Time) (string, error) {
+	claims := m.AccessClaims{
+		Id:          uuid.Must(uuid.NewV4()).String(),
+		Issuer:      Issuer,
+		Subject:     userId,
+		Roles:       roles,
+		Permissions: permissions,
+		IsDeveloper: isDeveloper,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(s.ttl).Unix(),
+	}
+
+	encodedHeader, err := json.Marshal(header{Alg: Algorithm, Typ: "JWT", Kid: s.kid})
+
+	if err != nil {
+		return "", err
+	}
+
+	encodedClaims, err := json.Marshal(claims)
+
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+	signature := ed25519.Sign(s.key, []byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (s *Signer) KeyId() string {
+	return s.kid
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+func (s *Signer) Jwks() m.Jwks {
+	return m.Jwks{Keys: []m.Jwk{{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(s.PublicKey()),
+		Kid: s.kid,
+		Alg: Algorithm,
+		Use: "sig",
+	}}}
+}
@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	recentActivityLimit = 20
+	exportLimit         = 10000
+	defaultQueryLimit   = 50
+	maxQueryLimit       = 200
+)
+
+// Успешные действия, о которых владелец аккаунта получает оповещение
+var alertActions = map[m.AuditAction]bool{
+	m.AuditPasswordChange:   true,
+	m.AuditPasswordReset:    true,
+	m.AuditEmailChange:      true,
+	m.AuditTwoFactorEnable:  true,
+	m.AuditTwoFactorDisable: true,
+	m.AuditSessionRevokeAll: true,
+}
+
+type QueryRequest struct {
+	ActorId string `query:"actor_id"`
+	Target  string `query:"target"`
+	Action  string `query:"action"`
+	Result  string `query:"result"`
+	From    string `query:"from"`
+	To      string `query:"to"`
+	Limit   int    `query:"limit"`
+}
+
+// Результат события берётся из ошибки сценария: nil - успех
+func NewEvent(action m.AuditAction, actorId string, target string, meta m.SessionMeta, err *e.ErrorResponse) m.AuditEvent {
+	event := m.AuditEvent{
+		Action:    action,
+		Target:    target,
+		IP:        meta.IP,
+		UserAgent: meta.UserAgent,
+		Result:    m.AuditSuccess,
+	}
+
+	if actor, uuidErr := uuid.FromString(actorId); uuidErr == nil {
+		event.ActorId = uuid.NullUUID{UUID: actor, Valid: true}
+	}
+
+	if err != nil {
+		event.Result = m.AuditFailure
+	}
+
+	return event
+}
+
+// Журнал не должен ломать основной сценарий, поэтому ошибка записи только логируется
+func Record(event m.AuditEvent, audit dataservice.AuditInterface, logger *logrus.Logger) {
+	if err := audit.Create(&event); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload, "action": event.Action}).Info("Record audit event")
+	}
+}
+
+// Оповещает владельца аккаунта о важном изменении. Как и запись в журнал, не ломает основной сценарий.
+func Alert(event m.AuditEvent, producer adapter.SecurityAlertProducerInterface, logger *logrus.Logger) {
+	if event.Result != m.AuditSuccess || !alertActions[event.Action] {
+		return
+	}
+
+	// Target может быть почтой, оповещение уходит только по id пользователя
+	if _, err := uuid.FromString(event.Target); err != nil {
+		return
+	}
+
+	alert := m.SecurityAlertEvent{
+		EventId:   uuid.Must(uuid.NewV4()).String(),
+		UserId:    event.Target,
+		Action:    event.Action,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		CreatedAt: time.Now(),
+	}
+
+	if err := producer.SendSecurityAlert(alert); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error(), "action": event.Action}).Info("Send security alert")
+	}
+}
+
+// События других сервисов приходят по gRPC, их проверяем строже
+func RecordExternal(event m.AuditEvent, audit dataservice.AuditInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if !m.IsKnownAuditAction(event.Action) {
+		return e.NewErrorResponse(e.HttpBadRequest, "Unknown audit action")
+	}
+
+	if event.Result != m.AuditSuccess && event.Result != m.AuditFailure {
+		return e.NewErrorResponse(e.HttpBadRequest, "Unknown audit result")
+	}
+
+	if err := audit.Create(&event); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload, "action": event.Action}).Info("Record audit event")
+		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return nil
+}
+
+func GetRecentActivity(userId string, email string, audit dataservice.AuditInterface, logger *logrus.Logger) (*[]m.AuditEvent, *e.ErrorResponse) {
+	events, err := audit.GetByUser(userId, email, recentActivityLimit)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get security activity")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return events, nil
+}
+
+// Для выгрузки персональных данных нужна вся история пользователя, а не последние события
+func GetUserEvents(userId string, email string, audit dataservice.AuditInterface, logger *logrus.Logger) (*[]m.AuditEvent, *e.ErrorResponse) {
+	events, err := audit.GetByUser(userId, email, exportLimit)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get user audit events")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return events, nil
+}
+
+func Query(request QueryRequest, audit dataservice.AuditInterface, logger *logrus.Logger) (*[]m.AuditEvent, *e.ErrorResponse) {
+	filter, err := parseQueryRequest(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	events, dbErr := audit.GetMany(*filter)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Query audit events")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return events, nil
+}
+
+func parseQueryRequest(request QueryRequest) (*m.AuditFilter, *e.ErrorResponse) {
+	filter := m.AuditFilter{
+		Target: request.Target,
+		Action: m.AuditAction(request.Action),
+		Result: m.AuditResult(request.Result),
+		Limit:  request.Limit,
+	}
+
+	if request.ActorId != "" {
+		if _, err := uuid.FromString(request.ActorId); err != nil {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid actor_id value")
+		}
+
+		filter.ActorId = request.ActorId
+	}
+
+	if filter.Action != "" && !m.IsKnownAuditAction(filter.Action) {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Unknown action")
+	}
+
+	if filter.Result != "" && filter.Result != m.AuditSuccess && filter.Result != m.AuditFailure {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Result must be success or failure")
+	}
+
+	var parseErr error
+
+	if request.From != "" {
+		if filter.From, parseErr = time.Parse(time.RFC3339, request.From); parseErr != nil {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid from value, use RFC 3339")
+		}
+	}
+
+	if request.To != "" {
+		if filter.To, parseErr = time.Parse(time.RFC3339, request.To); parseErr != nil {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid to value, use RFC 3339")
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "from must be before to")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultQueryLimit
+	}
+
+	if filter.Limit > maxQueryLimit {
+		filter.Limit = maxQueryLimit
+	}
+
+	return &filter, nil
+}
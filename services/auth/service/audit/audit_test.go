@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"testing"
+	"time"
+	aMock "warehouseai/auth/adapter/mocks"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewEvent(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	meta := m.SessionMeta{IP: "10.0.0.1", UserAgent: "Mozilla/5.0"}
+
+	event := NewEvent(m.AuditLogin, userId, userId, meta, nil)
+
+	require.Equal(t, m.AuditSuccess, event.Result)
+	require.True(t, event.ActorId.Valid)
+	require.Equal(t, userId, event.ActorId.UUID.String())
+	require.Equal(t, meta.IP, event.IP)
+
+	// Неудачный вход: пользователь не вошёл, цель - почта
+	event = NewEvent(m.AuditLogin, "", "validemail@mail.com", meta, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials"))
+
+	require.Equal(t, m.AuditFailure, event.Result)
+	require.False(t, event.ActorId.Valid)
+	require.Equal(t, "validemail@mail.com", event.Target)
+}
+
+func TestAlert(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	alertMock := aMock.NewMockSecurityAlertProducerInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	meta := m.SessionMeta{IP: "10.0.0.1", UserAgent: "Mozilla/5.0"}
+
+	alertMock.EXPECT().SendSecurityAlert(gomock.Any()).DoAndReturn(func(alert m.SecurityAlertEvent) error {
+		require.Equal(t, userId, alert.UserId)
+		require.Equal(t, m.AuditTwoFactorDisable, alert.Action)
+		require.Equal(t, meta.IP, alert.IP)
+		require.NotEmpty(t, alert.EventId)
+		return nil
+	}).Times(1)
+
+	Alert(NewEvent(m.AuditTwoFactorDisable, userId, userId, meta, nil), alertMock, logger)
+
+	// Без оповещения: неудачное действие, обычный вход и цель-почта
+	Alert(NewEvent(m.AuditPasswordChange, userId, userId, meta, e.NewErrorResponse(e.HttpBadRequest, "Invalid password")), alertMock, logger)
+	Alert(NewEvent(m.AuditLogin, userId, userId, meta, nil), alertMock, logger)
+	Alert(NewEvent(m.AuditPasswordReset, "", "validemail@mail.com", meta, nil), alertMock, logger)
+}
+
+func TestRecordExternalValidateError(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	err := RecordExternal(m.AuditEvent{Action: "account.hack", Result: m.AuditSuccess}, auditMock, logger)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Unknown audit action"), err)
+
+	err = RecordExternal(m.AuditEvent{Action: m.AuditEmailChange, Result: "maybe"}, auditMock, logger)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Unknown audit result"), err)
+}
+
+func TestQueryValidateError(t *testing.T) {
+	cases := []struct {
+		name          string
+		request       QueryRequest
+		expectedError *e.ErrorResponse
+	}{
+		{
+			name:          "Invalid actor id",
+			request:       QueryRequest{ActorId: "actor"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid actor_id value"),
+		},
+		{
+			name:          "Unknown action",
+			request:       QueryRequest{Action: "account.hack"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Unknown action"),
+		},
+		{
+			name:          "Unknown result",
+			request:       QueryRequest{Result: "maybe"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Result must be success or failure"),
+		},
+		{
+			name:          "Invalid from",
+			request:       QueryRequest{From: "yesterday"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "Invalid from value, use RFC 3339"),
+		},
+		{
+			name:          "Reversed range",
+			request:       QueryRequest{From: "2024-02-01T00:00:00Z", To: "2024-01-01T00:00:00Z"},
+			expectedError: e.NewErrorResponse(e.HttpBadRequest, "from must be before to"),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			_, err := parseQueryRequest(tCase.request)
+
+			require.NotNil(t, err)
+			require.Equal(t, tCase.expectedError, err)
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	request := QueryRequest{Action: string(m.AuditLogin), Result: string(m.AuditFailure), From: "2024-01-01T00:00:00Z", Limit: 1000}
+	expFilter := m.AuditFilter{
+		Action: m.AuditLogin,
+		Result: m.AuditFailure,
+		From:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:  maxQueryLimit,
+	}
+	expEvents := &[]m.AuditEvent{{Action: m.AuditLogin, Target: "validemail@mail.com", Result: m.AuditFailure}}
+
+	auditMock.EXPECT().GetMany(expFilter).Return(expEvents, nil).Times(1)
+
+	events, err := Query(request, auditMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, expEvents, events)
+}
+
+func TestGetUserEvents(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	auditMock := dMock.NewMockAuditInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	expEvents := &[]m.AuditEvent{{Action: m.AuditLogin, Target: userId, Result: m.AuditSuccess}}
+
+	auditMock.EXPECT().GetByUser(userId, "validemail@mail.com", exportLimit).Return(expEvents, nil).Times(1)
+
+	events, err := GetUserEvents(userId, "validemail@mail.com", auditMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, expEvents, events)
+}
@@ -20,3 +20,20 @@ func Authenticate(sessionId string, session dataservice.SessionInterface, logger
 
 	return userId, newSession, nil
 }
+
+// Только читает сессию: ни продления, ни ротации, поэтому cookie клиента не устаревает.
+// Старый ID в grace-окне ведёт на новую сессию.
+func CheckSession(sessionId string, session dataservice.SessionInterface, logger *logrus.Logger) (*m.Session, *e.ErrorResponse) {
+	existSession, err := session.Get(context.Background(), sessionId)
+
+	if err == nil && existSession.Payload.ReplacedBy != "" {
+		existSession, err = session.Get(context.Background(), existSession.Payload.ReplacedBy)
+	}
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Check session")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return existSession, nil
+}
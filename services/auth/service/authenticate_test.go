@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckSession(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	current := &m.Session{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId}, TTL: time.Hour}
+	rotated := &m.Session{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId, ReplacedBy: current.ID}, TTL: time.Minute}
+
+	cases := []struct {
+		name      string
+		sessionId string
+		setup     func(sessionMock *dMock.MockSessionInterface)
+		expected  *m.Session
+		errCode   int
+	}{
+		{
+			name:      "current session",
+			sessionId: current.ID,
+			setup: func(sessionMock *dMock.MockSessionInterface) {
+				sessionMock.EXPECT().Get(context.Background(), current.ID).Return(current, nil).Times(1)
+			},
+			expected: current,
+		},
+		{
+			name:      "rotated session",
+			sessionId: rotated.ID,
+			setup: func(sessionMock *dMock.MockSessionInterface) {
+				sessionMock.EXPECT().Get(context.Background(), rotated.ID).Return(rotated, nil).Times(1)
+				sessionMock.EXPECT().Get(context.Background(), current.ID).Return(current, nil).Times(1)
+			},
+			expected: current,
+		},
+		{
+			name:      "revoked session",
+			sessionId: current.ID,
+			setup: func(sessionMock *dMock.MockSessionInterface) {
+				sessionMock.EXPECT().Get(context.Background(), current.ID).Return(nil, e.NewDBError(e.DbNotFound, "Session not found.", "redis: nil")).Times(1)
+			},
+			errCode: e.HttpNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			sessionMock := dMock.NewMockSessionInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(sessionMock)
+
+			session, err := CheckSession(tc.sessionId, sessionMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, session)
+		})
+	}
+}
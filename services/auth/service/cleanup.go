@@ -0,0 +1,59 @@
+package service
+
+import (
+	"time"
+	"warehouseai/auth/dataservice"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PurgeResult struct {
+	ResetTokens        int64
+	VerificationTokens int64
+}
+
+// Удаляет протухшие токены. Ошибка по одной таблице не мешает чистить другую.
+func PurgeExpiredTokens(resetToken dataservice.ResetTokenInterface, verificationToken dataservice.VerificationTokenInterface, logger *logrus.Logger) PurgeResult {
+	var result PurgeResult
+
+	if deleted, err := resetToken.DeleteExpired(); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge expired reset tokens")
+	} else {
+		result.ResetTokens = deleted
+	}
+
+	if deleted, err := verificationToken.DeleteExpired(); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge expired verification tokens")
+	} else {
+		result.VerificationTokens = deleted
+	}
+
+	logger.WithFields(logrus.Fields{
+		"time":                time.Now(),
+		"reset_tokens":        result.ResetTokens,
+		"verification_tokens": result.VerificationTokens,
+	}).Info("Purge expired tokens")
+
+	return result
+}
+
+// Фоновая чистка токенов, работает до закрытия stop
+func StartTokenCleanup(interval time.Duration, resetToken dataservice.ResetTokenInterface, verificationToken dataservice.VerificationTokenInterface, logger *logrus.Logger, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	PurgeExpiredTokens(resetToken, verificationToken, logger)
+
+	for {
+		select {
+		case <-ticker.C:
+			PurgeExpiredTokens(resetToken, verificationToken, logger)
+		case <-stop:
+			return
+		}
+	}
+}
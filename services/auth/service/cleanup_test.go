@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurgeExpiredTokens(t *testing.T) {
+	ctl := gomock.NewController(t)
+	resetMock := dMock.NewMockResetTokenInterface(ctl)
+	verificationMock := dMock.NewMockVerificationTokenInterface(ctl)
+
+	resetMock.EXPECT().DeleteExpired().Return(int64(3), nil).Times(1)
+	verificationMock.EXPECT().DeleteExpired().Return(int64(5), nil).Times(1)
+
+	result := PurgeExpiredTokens(resetMock, verificationMock, logrus.New())
+
+	require.Equal(t, PurgeResult{ResetTokens: 3, VerificationTokens: 5}, result)
+}
+
+func TestPurgeExpiredTokensPartialError(t *testing.T) {
+	ctl := gomock.NewController(t)
+	resetMock := dMock.NewMockResetTokenInterface(ctl)
+	verificationMock := dMock.NewMockVerificationTokenInterface(ctl)
+
+	resetMock.EXPECT().DeleteExpired().Return(int64(0), e.NewDBError(e.DbSystem, "Something went wrong.", "connection refused")).Times(1)
+	verificationMock.EXPECT().DeleteExpired().Return(int64(2), nil).Times(1)
+
+	result := PurgeExpiredTokens(resetMock, verificationMock, logrus.New())
+
+	require.Equal(t, PurgeResult{VerificationTokens: 2}, result)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+	e "warehouseai/auth/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+const csrfTokenBytes = 32
+
+type CsrfResponse struct {
+	Token string `json:"csrf_token"`
+}
+
+// Уже выданный токен возвращается как есть, чтобы вкладки не перетирали cookie друг другу
+func IssueCsrfToken(current string, logger *logrus.Logger) (*CsrfResponse, *e.ErrorResponse) {
+	if len(current) == base64.RawURLEncoding.EncodedLen(csrfTokenBytes) {
+		return &CsrfResponse{Token: current}, nil
+	}
+
+	randomBytes := make([]byte, csrfTokenBytes)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Issue CSRF token")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to issue CSRF token")
+	}
+
+	return &CsrfResponse{Token: base64.RawURLEncoding.EncodeToString(randomBytes)}, nil
+}
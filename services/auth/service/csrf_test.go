@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueCsrfToken(t *testing.T) {
+	logger := logrus.New()
+
+	first, err := IssueCsrfToken("", logger)
+
+	require.Nil(t, err)
+	require.Len(t, first.Token, 43)
+
+	// Повторный запрос с той же cookie не меняет токен
+	again, err := IssueCsrfToken(first.Token, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, first.Token, again.Token)
+
+	// Подложенное короткое значение заменяется новым
+	replaced, err := IssueCsrfToken("attacker", logger)
+
+	require.Nil(t, err)
+	require.NotEqual(t, "attacker", replaced.Token)
+	require.NotEqual(t, first.Token, replaced.Token)
+}
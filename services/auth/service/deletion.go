@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Выполняет шаг саги удаления аккаунта. Возвращает nil, если ответ оркестратору не нужен.
+func HandleAccountDeletion(command m.AccountDeletionCommand, session dataservice.SessionInterface, resetToken dataservice.ResetTokenInterface, verificationToken dataservice.VerificationTokenInterface, identity dataservice.IdentityInterface, twoFactor dataservice.TwoFactorInterface, picture dataservice.PictureInterface, logger *logrus.Logger) *m.AccountDeletionReply {
+	reply := &m.AccountDeletionReply{DeletionId: command.DeletionId, Step: command.Step, Action: command.Action, Success: true}
+
+	var err error
+
+	switch {
+	case command.Action == m.DeletionPurge:
+		purgeAccount(command.UserId, session, identity, twoFactor, logger)
+		return nil
+	case command.Action == m.DeletionCompensate:
+		// Сессии и токены не восстанавливаем: пользователь просто войдёт заново.
+		// Файлы аватара уже не вернуть, ссылку на него очищает сервис пользователей.
+		return reply
+	case command.Action != m.DeletionExecute:
+		err = fmt.Errorf("unknown action %s", command.Action)
+	case command.Step == m.DeletionStepAuth:
+		err = revokeAccountAccess(command.UserId, session, resetToken, verificationToken)
+	case command.Step == m.DeletionStepAvatar:
+		err = DeleteAvatar(command.Picture, picture)
+	default:
+		err = fmt.Errorf("unknown step %s", command.Step)
+	}
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error(), "deletion_id": command.DeletionId, "step": command.Step}).Info("Account deletion")
+		reply.Success = false
+		reply.Error = err.Error()
+	}
+
+	return reply
+}
+
+func revokeAccountAccess(userId string, session dataservice.SessionInterface, resetToken dataservice.ResetTokenInterface, verificationToken dataservice.VerificationTokenInterface) error {
+	if _, err := session.DeleteAll(context.Background(), userId, ""); err != nil {
+		return fmt.Errorf("delete sessions: %s", err.Payload)
+	}
+
+	if err := resetToken.Delete(map[string]interface{}{"user_id": userId}); err != nil && err.ErrorType != e.DbNotFound {
+		return fmt.Errorf("delete reset tokens: %s", err.Payload)
+	}
+
+	if err := verificationToken.Delete(map[string]interface{}{"user_id": userId}); err != nil && err.ErrorType != e.DbNotFound {
+		return fmt.Errorf("delete verification tokens: %s", err.Payload)
+	}
+
+	return nil
+}
+
+// 2FA и привязки Google удаляем только после удаления пользователя, чтобы компенсация не требовала их восстанавливать.
+// Сессии удаляем повторно: пользователь мог войти, пока шла сага.
+func purgeAccount(userId string, session dataservice.SessionInterface, identity dataservice.IdentityInterface, twoFactor dataservice.TwoFactorInterface, logger *logrus.Logger) {
+	if _, err := session.DeleteAll(context.Background(), userId, ""); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge sessions")
+	}
+
+	if err := twoFactor.Delete(userId); err != nil && err.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge two factor")
+	}
+
+	if err := identity.Delete(map[string]interface{}{"user_id": userId}); err != nil && err.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge identities")
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleAccountDeletionAuthStep(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	resetTokenMock := dMock.NewMockResetTokenInterface(ctl)
+	verificationTokenMock := dMock.NewMockVerificationTokenInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	command := m.AccountDeletionCommand{DeletionId: uuid.Must(uuid.NewV4()).String(), UserId: userId, Step: m.DeletionStepAuth, Action: m.DeletionExecute}
+
+	sessionMock.EXPECT().DeleteAll(context.Background(), userId, "").Return(int64(2), nil).Times(1)
+	resetTokenMock.EXPECT().Delete(map[string]interface{}{"user_id": userId}).Return(nil).Times(1)
+	verificationTokenMock.EXPECT().Delete(map[string]interface{}{"user_id": userId}).Return(e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+
+	reply := HandleAccountDeletion(command, sessionMock, resetTokenMock, verificationTokenMock, nil, nil, nil, logger)
+
+	require.Equal(t, &m.AccountDeletionReply{DeletionId: command.DeletionId, Step: m.DeletionStepAuth, Action: m.DeletionExecute, Success: true}, reply)
+}
+
+func TestHandleAccountDeletionAvatarStep(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	pictureMock := dMock.NewMockPictureInterface(ctl)
+	logger := logrus.New()
+
+	imageId := uuid.Must(uuid.NewV4()).String()
+	command := m.AccountDeletionCommand{
+		DeletionId: uuid.Must(uuid.NewV4()).String(),
+		Step:       m.DeletionStepAvatar,
+		Action:     m.DeletionExecute,
+		Picture:    "https://cdn.warehouse-ai.com/" + imageFileName(imageId, "full"),
+	}
+
+	pictureMock.EXPECT().DeleteImage(imageFileName(imageId, "thumbnail")).Return(nil).Times(1)
+	pictureMock.EXPECT().DeleteImage(imageFileName(imageId, "card")).Return(errors.New("storage is unavailable")).Times(1)
+	pictureMock.EXPECT().DeleteImage(imageFileName(imageId, "full")).Return(nil).Times(1)
+
+	reply := HandleAccountDeletion(command, nil, nil, nil, nil, nil, pictureMock, logger)
+
+	require.False(t, reply.Success)
+	require.Equal(t, "storage is unavailable", reply.Error)
+
+	// Аватар из Google не наш, удалять нечего
+	command.Picture = "https://lh3.googleusercontent.com/a/picture"
+	reply = HandleAccountDeletion(command, nil, nil, nil, nil, nil, pictureMock, logger)
+
+	require.True(t, reply.Success)
+}
+
+func TestHandleAccountDeletionPurge(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	command := m.AccountDeletionCommand{DeletionId: uuid.Must(uuid.NewV4()).String(), UserId: userId, Action: m.DeletionPurge}
+
+	sessionMock.EXPECT().DeleteAll(context.Background(), userId, "").Return(int64(0), nil).Times(1)
+	twoFactorMock.EXPECT().Delete(userId).Return(nil).Times(1)
+	identityMock.EXPECT().Delete(map[string]interface{}{"user_id": userId}).Return(nil).Times(1)
+
+	require.Nil(t, HandleAccountDeletion(command, sessionMock, nil, nil, identityMock, twoFactorMock, nil, logger))
+}
@@ -0,0 +1,400 @@
+package google
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/adapter/grpc/gen"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+	"warehouseai/auth/service/login"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type LoginResponse struct {
+	Url   string `json:"url"`
+	State string `json:"-"`
+}
+
+type CallbackRequest struct {
+	Code        string
+	State       string
+	CookieState string
+	Meta        m.SessionMeta
+}
+
+type CallbackResponse struct {
+	UserId            string `json:"user_id,omitempty"`
+	LinkRequired      bool   `json:"link_required"`
+	LinkToken         string `json:"link_token,omitempty"`
+	Email             string `json:"email,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	PendingToken      string `json:"pending_token,omitempty"`
+}
+
+type LinkRequest struct {
+	LinkToken string        `json:"link_token"`
+	Password  string        `json:"password"`
+	Meta      m.SessionMeta `json:"-"`
+}
+
+type LinkResponse struct {
+	UserId            string `json:"user_id,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	PendingToken      string `json:"pending_token,omitempty"`
+}
+
+func randomString(length int) (string, error) {
+	randomBytes := make([]byte, length)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// PKCE S256: BASE64URL(SHA256(code_verifier))
+func codeChallenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+func Login(
+	provider adapter.OidcProviderInterface,
+	stateRepository dataservice.OidcStateInterface,
+	cfg config.OidcCfg,
+	logger *logrus.Logger,
+) (*LoginResponse, *e.ErrorResponse) {
+	state, stateErr := randomString(32)
+	nonce, nonceErr := randomString(32)
+	verifier, verifierErr := randomString(48)
+
+	if stateErr != nil || nonceErr != nil || verifierErr != nil {
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to start Google sign in")
+	}
+
+	if err := stateRepository.SaveState(context.Background(), state, m.OidcState{Nonce: nonce, Verifier: verifier}, cfg.StateTTL); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Google login")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	url, err := provider.AuthCodeURL(context.Background(), state, nonce, codeChallenge(verifier))
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Google login")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Identity provider is unavailable")
+	}
+
+	return &LoginResponse{Url: url, State: state}, nil
+}
+
+// Завершает вход через Google. Если email уже занят аккаунтом с паролем, сессия не создаётся:
+// возвращается link_token, привязка подтверждается паролем через Link.
+func Callback(
+	req *CallbackRequest,
+	provider adapter.OidcProviderInterface,
+	stateRepository dataservice.OidcStateInterface,
+	identityRepository dataservice.IdentityInterface,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	user adapter.UserGrpcInterface,
+	broker adapter.BrokerInterface,
+	cfg config.OidcCfg,
+	twoFactorCfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*CallbackResponse, *m.Session, *e.ErrorResponse) {
+	if req.Code == "" || req.State == "" {
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Missing code or state")
+	}
+
+	// state из query должен совпадать с тем, что выдан этому браузеру, иначе это чужой логин
+	if subtle.ConstantTimeCompare([]byte(req.State), []byte(req.CookieState)) != 1 {
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid state")
+	}
+
+	state, dbErr := stateRepository.TakeState(context.Background(), req.State)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Google callback")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Sign in attempt expired, try again")
+		}
+
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	rawIdToken, err := provider.Exchange(context.Background(), req.Code, state.Verifier)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Google callback")
+		return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Failed to exchange authorization code")
+	}
+
+	claims, err := provider.Verify(context.Background(), rawIdToken)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Google callback")
+		return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(state.Nonce)) != 1 {
+		return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token")
+	}
+
+	if !claims.EmailVerified {
+		return nil, nil, e.NewErrorResponse(e.HttpForbidden, "Google account email is not verified")
+	}
+
+	if _, err := mail.ParseAddress(claims.Email); err != nil {
+		return nil, nil, e.NewErrorResponse(e.HttpForbidden, "Google account has no valid email")
+	}
+
+	identity, dbErr := identityRepository.Get(map[string]interface{}{"issuer": claims.Issuer, "subject": claims.Subject})
+
+	if dbErr == nil {
+		return createSession(identity.UserId, req.Meta, session, twoFactorRepository, pending, twoFactorCfg, logger)
+	}
+
+	if dbErr.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Google callback")
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	existUser, gwErr := user.GetByEmail(context.Background(), claims.Email)
+
+	if gwErr != nil && gwErr.ErrorCode != e.HttpNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Google callback")
+		return nil, nil, gwErr
+	}
+
+	if gwErr != nil {
+		return createUser(claims, req.Meta, identityRepository, session, twoFactorRepository, pending, user, broker, twoFactorCfg, logger)
+	}
+
+	if existUser.ViaGoogle {
+		if err := linkIdentity(existUser.Id, claims.Issuer, claims.Subject, claims.Email, identityRepository, logger); err != nil {
+			return nil, nil, err
+		}
+
+		return createSession(existUser.Id, req.Meta, session, twoFactorRepository, pending, twoFactorCfg, logger)
+	}
+
+	// Аккаунт с паролем привязываем только после повторного входа по паролю
+	linkToken, err := randomString(32)
+
+	if err != nil {
+		return nil, nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create link token")
+	}
+
+	link := m.OidcLink{UserId: existUser.Id, Issuer: claims.Issuer, Subject: claims.Subject, Email: claims.Email}
+
+	if dbErr := stateRepository.SaveLink(context.Background(), linkToken, link, cfg.LinkTTL); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Google callback")
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return &CallbackResponse{LinkRequired: true, LinkToken: linkToken, Email: claims.Email}, nil, nil
+}
+
+// Подтверждает привязку Google к существующему аккаунту паролем. link_token одноразовый,
+// при неверном пароле вход через Google нужно пройти заново.
+func Link(
+	req *LinkRequest,
+	stateRepository dataservice.OidcStateInterface,
+	identityRepository dataservice.IdentityInterface,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	user adapter.UserGrpcInterface,
+	twoFactorCfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*LinkResponse, *m.Session, *e.ErrorResponse) {
+	if req.LinkToken == "" {
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Missing link token")
+	}
+
+	link, dbErr := stateRepository.TakeLink(context.Background(), req.LinkToken)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Google link")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Link request expired, sign in with Google again")
+		}
+
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	existUser, gwErr := user.GetById(context.Background(), link.UserId)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Google link")
+		return nil, nil, gwErr
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(req.Password)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Google link")
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials")
+	}
+
+	if err := linkIdentity(existUser.Id, link.Issuer, link.Subject, link.Email, identityRepository, logger); err != nil {
+		return nil, nil, err
+	}
+
+	// Google уже подтвердил владение почтой
+	if !existUser.Verified {
+		if _, gwErr := user.UpdateVerificationStatus(context.Background(), existUser.Id); gwErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Google link")
+			return nil, nil, gwErr
+		}
+	}
+
+	response, newSession, err := createSession(existUser.Id, req.Meta, session, twoFactorRepository, pending, twoFactorCfg, logger)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &LinkResponse{UserId: response.UserId, TwoFactorRequired: response.TwoFactorRequired, PendingToken: response.PendingToken}, newSession, nil
+}
+
+func createUser(
+	claims *m.IdTokenClaims,
+	meta m.SessionMeta,
+	identityRepository dataservice.IdentityInterface,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	user adapter.UserGrpcInterface,
+	broker adapter.BrokerInterface,
+	twoFactorCfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*CallbackResponse, *m.Session, *e.ErrorResponse) {
+	username, err := generateUsername(claims.Email)
+
+	if err != nil {
+		return nil, nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create user")
+	}
+
+	// Пароль у Google-аккаунта случайный и никому не известен, войти по нему можно только после сброса
+	password, err := randomString(32)
+
+	if err != nil {
+		return nil, nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create user")
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+
+	if err != nil {
+		return nil, nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create user")
+	}
+
+	firstname := claims.GivenName
+
+	if firstname == "" {
+		firstname = username
+	}
+
+	userId, gwErr := user.Create(context.Background(), &gen.CreateUserMsg{
+		Firstname: firstname,
+		Lastname:  claims.FamilyName,
+		Username:  username,
+		Password:  string(passwordHash),
+		Picture:   claims.Picture,
+		Email:     claims.Email,
+		ViaGoogle: true,
+		Verified:  true,
+	})
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Google register")
+		return nil, nil, gwErr
+	}
+
+	if err := linkIdentity(userId, claims.Issuer, claims.Subject, claims.Email, identityRepository, logger); err != nil {
+		if err := broker.SendUserReject(userId); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Google register")
+		}
+
+		return nil, nil, err
+	}
+
+	return createSession(userId, meta, session, twoFactorRepository, pending, twoFactorCfg, logger)
+}
+
+func linkIdentity(userId string, issuer string, subject string, email string, identityRepository dataservice.IdentityInterface, logger *logrus.Logger) *e.ErrorResponse {
+	identity := m.OidcIdentity{UserId: userId, Issuer: issuer, Subject: subject, Email: email}
+
+	if err := identityRepository.Create(&identity); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Google link")
+		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return nil
+}
+
+// Вход через Google тоже требует второй фактор, если он включён
+func createSession(
+	userId string,
+	meta m.SessionMeta,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	twoFactorCfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*CallbackResponse, *m.Session, *e.ErrorResponse) {
+	response, newSession, err := login.StartSession(userId, meta, session, twoFactorRepository, pending, twoFactorCfg, logger)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &CallbackResponse{UserId: response.UserId, TwoFactorRequired: response.TwoFactorRequired, PendingToken: response.PendingToken}, newSession, nil
+}
+
+// Имя пользователя из локальной части почты + случайный суффикс, т.к. username уникален
+func generateUsername(email string) (string, error) {
+	local := strings.SplitN(email, "@", 2)[0]
+
+	var builder strings.Builder
+
+	for _, r := range local {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.') {
+			builder.WriteRune(r)
+		}
+	}
+
+	base := builder.String()
+
+	if len(base) > 20 {
+		base = base[:20]
+	}
+
+	if base == "" {
+		base = "user"
+	}
+
+	suffix := make([]byte, 3)
+
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return base + "_" + hex.EncodeToString(suffix), nil
+}
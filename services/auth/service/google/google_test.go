@@ -0,0 +1,475 @@
+package google
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"warehouseai/auth/adapter/grpc/gen"
+	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/adapter/oidc"
+	"warehouseai/auth/config"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testTwoFactorCfg = config.TwoFactorCfg{Issuer: "WarehouseAI", PendingTTL: 5 * time.Minute, MaxAttempts: 5, RecoveryCodes: 10}
+
+const (
+	testClientId = "warehouse-client"
+	testCode     = "authorization-code"
+	testNonce    = "test-nonce"
+	testVerifier = "test-code-verifier"
+)
+
+// Локальный IdP: discovery, JWKS и token endpoint, который проверяет PKCE и отдаёт заранее подписанный ID token
+type stubIdP struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	idToken string
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		if r.PostForm.Get("code") != testCode || r.PostForm.Get("code_verifier") != testVerifier {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idp.idToken, "token_type": "Bearer"})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *stubIdP) cfg() config.OidcCfg {
+	return config.OidcCfg{
+		Issuer:      idp.server.URL,
+		ClientId:    testClientId,
+		RedirectUrl: "http://localhost/api/auth/google/callback",
+		StateTTL:    10 * time.Minute,
+		LinkTTL:     10 * time.Minute,
+	}
+}
+
+func (idp *stubIdP) claims(email string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":            idp.server.URL,
+		"aud":            testClientId,
+		"sub":            "google-subject",
+		"email":          email,
+		"email_verified": true,
+		"nonce":          testNonce,
+		"given_name":     "Ivan",
+		"family_name":    "Petrov",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func twoFactorDisabled(twoFactorMock *dMock.MockTwoFactorInterface, userId string) {
+	twoFactorMock.EXPECT().Get(userId).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+}
+
+func newSessionFor(userId string) *m.Session {
+	return &m.Session{
+		ID:      uuid.Must(uuid.NewV4()).String(),
+		Payload: m.SessionPayload{UserId: userId, CreatedAt: time.Now()},
+		TTL:     24 * time.Hour,
+	}
+}
+
+func callbackRequest() *CallbackRequest {
+	return &CallbackRequest{Code: testCode, State: "state", CookieState: "state"}
+}
+
+func TestGoogleLogin(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	logger := logrus.New()
+	idp := newStubIdP(t)
+
+	var saved m.OidcState
+
+	stateMock.EXPECT().SaveState(context.Background(), gomock.Any(), gomock.Any(), 10*time.Minute).DoAndReturn(
+		func(_ context.Context, _ string, payload m.OidcState, _ time.Duration) *e.DBError {
+			saved = payload
+			return nil
+		},
+	).Times(1)
+
+	response, err := Login(oidc.NewProvider(idp.cfg()), stateMock, idp.cfg(), logger)
+
+	require.Nil(t, err)
+
+	authUrl, parseErr := url.Parse(response.Url)
+	require.NoError(t, parseErr)
+
+	query := authUrl.Query()
+	require.Equal(t, idp.server.URL+"/authorize", authUrl.Scheme+"://"+authUrl.Host+authUrl.Path)
+	require.Equal(t, response.State, query.Get("state"))
+	require.Equal(t, saved.Nonce, query.Get("nonce"))
+	require.Equal(t, codeChallenge(saved.Verifier), query.Get("code_challenge"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.Equal(t, testClientId, query.Get("client_id"))
+}
+
+func TestGoogleCallbackNewUser(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	idp := newStubIdP(t)
+	idp.idToken = signToken(t, idp.key, idp.claims("ivan.petrov@gmail.com"))
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	expSession := newSessionFor(userId)
+
+	stateMock.EXPECT().TakeState(context.Background(), "state").Return(&m.OidcState{Nonce: testNonce, Verifier: testVerifier}, nil).Times(1)
+	identityMock.EXPECT().Get(map[string]interface{}{"issuer": idp.server.URL, "subject": "google-subject"}).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	userMock.EXPECT().GetByEmail(context.Background(), "ivan.petrov@gmail.com").Return(nil, e.NewErrorResponse(e.HttpNotFound, "User not found")).Times(1)
+	userMock.EXPECT().Create(context.Background(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msg *gen.CreateUserMsg) (string, *e.ErrorResponse) {
+			require.True(t, msg.ViaGoogle)
+			require.True(t, msg.Verified)
+			require.Equal(t, "ivan.petrov@gmail.com", msg.Email)
+			require.Equal(t, "Ivan", msg.Firstname)
+			require.Equal(t, "Petrov", msg.Lastname)
+			require.Regexp(t, `^ivan\.petrov_[0-9a-f]{6}$`, msg.Username)
+			require.NotEmpty(t, msg.Password)
+			return userId, nil
+		},
+	).Times(1)
+	identityMock.EXPECT().Create(&m.OidcIdentity{UserId: userId, Issuer: idp.server.URL, Subject: "google-subject", Email: "ivan.petrov@gmail.com"}).Return(nil).Times(1)
+	twoFactorDisabled(twoFactorMock, userId)
+	sessionMock.EXPECT().Create(context.Background(), userId, m.SessionMeta{}).Return(expSession, nil).Times(1)
+
+	response, session, err := Callback(callbackRequest(), oidc.NewProvider(idp.cfg()), stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, brokerMock, idp.cfg(), testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &CallbackResponse{UserId: userId}, response)
+	require.Equal(t, expSession, session)
+}
+
+func TestGoogleCallbackLinkedIdentity(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	idp := newStubIdP(t)
+	idp.idToken = signToken(t, idp.key, idp.claims("ivan.petrov@gmail.com"))
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	expSession := newSessionFor(userId)
+
+	stateMock.EXPECT().TakeState(context.Background(), "state").Return(&m.OidcState{Nonce: testNonce, Verifier: testVerifier}, nil).Times(1)
+	identityMock.EXPECT().Get(gomock.Any()).Return(&m.OidcIdentity{UserId: userId}, nil).Times(1)
+	twoFactorDisabled(twoFactorMock, userId)
+	sessionMock.EXPECT().Create(context.Background(), userId, m.SessionMeta{}).Return(expSession, nil).Times(1)
+
+	response, session, err := Callback(callbackRequest(), oidc.NewProvider(idp.cfg()), stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, brokerMock, idp.cfg(), testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &CallbackResponse{UserId: userId}, response)
+	require.Equal(t, expSession, session)
+}
+
+func TestGoogleCallbackTwoFactorRequired(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	idp := newStubIdP(t)
+	idp.idToken = signToken(t, idp.key, idp.claims("ivan.petrov@gmail.com"))
+
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	stateMock.EXPECT().TakeState(context.Background(), "state").Return(&m.OidcState{Nonce: testNonce, Verifier: testVerifier}, nil).Times(1)
+	identityMock.EXPECT().Get(gomock.Any()).Return(&m.OidcIdentity{UserId: userId}, nil).Times(1)
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Enabled: true}, nil).Times(1)
+	pendingMock.EXPECT().Create(context.Background(), userId, testTwoFactorCfg.PendingTTL).Return("pending-token", nil).Times(1)
+
+	response, session, err := Callback(callbackRequest(), oidc.NewProvider(idp.cfg()), stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, brokerMock, idp.cfg(), testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Nil(t, session)
+	require.Equal(t, &CallbackResponse{TwoFactorRequired: true, PendingToken: "pending-token"}, response)
+}
+
+func TestGoogleCallbackPasswordAccount(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	idp := newStubIdP(t)
+	idp.idToken = signToken(t, idp.key, idp.claims("ivan.petrov@gmail.com"))
+
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Email: "ivan.petrov@gmail.com", Verified: true}
+	var linkToken string
+
+	stateMock.EXPECT().TakeState(context.Background(), "state").Return(&m.OidcState{Nonce: testNonce, Verifier: testVerifier}, nil).Times(1)
+	identityMock.EXPECT().Get(gomock.Any()).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	userMock.EXPECT().GetByEmail(context.Background(), existUser.Email).Return(existUser, nil).Times(1)
+	stateMock.EXPECT().SaveLink(context.Background(), gomock.Any(), m.OidcLink{UserId: existUser.Id, Issuer: idp.server.URL, Subject: "google-subject", Email: existUser.Email}, 10*time.Minute).DoAndReturn(
+		func(_ context.Context, token string, _ m.OidcLink, _ time.Duration) *e.DBError {
+			linkToken = token
+			return nil
+		},
+	).Times(1)
+
+	response, session, err := Callback(callbackRequest(), oidc.NewProvider(idp.cfg()), stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, brokerMock, idp.cfg(), testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Nil(t, session)
+	require.Equal(t, &CallbackResponse{LinkRequired: true, LinkToken: linkToken, Email: existUser.Email}, response)
+}
+
+func TestGoogleCallbackError(t *testing.T) {
+	otherKey, keyErr := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, keyErr)
+
+	cases := []struct {
+		name      string
+		request   *CallbackRequest
+		token     func(idp *stubIdP) string
+		takeState bool
+		expErr    *e.ErrorResponse
+	}{
+		{
+			name:    "state_mismatch",
+			request: &CallbackRequest{Code: testCode, State: "state", CookieState: "other"},
+			expErr:  e.NewErrorResponse(e.HttpBadRequest, "Invalid state"),
+		},
+		{
+			name:      "nonce_mismatch",
+			request:   callbackRequest(),
+			takeState: true,
+			token: func(idp *stubIdP) string {
+				claims := idp.claims("ivan.petrov@gmail.com")
+				claims["nonce"] = "replayed"
+				return signToken(t, idp.key, claims)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token"),
+		},
+		{
+			name:      "foreign_signature",
+			request:   callbackRequest(),
+			takeState: true,
+			token: func(idp *stubIdP) string {
+				return signToken(t, otherKey, idp.claims("ivan.petrov@gmail.com"))
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token"),
+		},
+		{
+			name:      "wrong_audience",
+			request:   callbackRequest(),
+			takeState: true,
+			token: func(idp *stubIdP) string {
+				claims := idp.claims("ivan.petrov@gmail.com")
+				claims["aud"] = "another-client"
+				return signToken(t, idp.key, claims)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token"),
+		},
+		{
+			name:      "expired",
+			request:   callbackRequest(),
+			takeState: true,
+			token: func(idp *stubIdP) string {
+				claims := idp.claims("ivan.petrov@gmail.com")
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return signToken(t, idp.key, claims)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Invalid ID token"),
+		},
+		{
+			name:      "email_not_verified",
+			request:   callbackRequest(),
+			takeState: true,
+			token: func(idp *stubIdP) string {
+				claims := idp.claims("ivan.petrov@gmail.com")
+				claims["email_verified"] = false
+				return signToken(t, idp.key, claims)
+			},
+			expErr: e.NewErrorResponse(e.HttpForbidden, "Google account email is not verified"),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			stateMock := dMock.NewMockOidcStateInterface(ctl)
+			identityMock := dMock.NewMockIdentityInterface(ctl)
+			sessionMock := dMock.NewMockSessionInterface(ctl)
+			twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+			pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+			userMock := aMock.NewMockUserGrpcInterface(ctl)
+			brokerMock := aMock.NewMockBrokerInterface(ctl)
+			logger := logrus.New()
+
+			idp := newStubIdP(t)
+
+			if tCase.token != nil {
+				idp.idToken = tCase.token(idp)
+			}
+
+			if tCase.takeState {
+				stateMock.EXPECT().TakeState(context.Background(), "state").Return(&m.OidcState{Nonce: testNonce, Verifier: testVerifier}, nil).Times(1)
+			}
+
+			response, session, err := Callback(tCase.request, oidc.NewProvider(idp.cfg()), stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, brokerMock, idp.cfg(), testTwoFactorCfg, logger)
+
+			require.Nil(t, response)
+			require.Nil(t, session)
+			require.Equal(t, tCase.expErr, err)
+		})
+	}
+}
+
+func TestGoogleLink(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("12345678"), 12)
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Email: "ivan.petrov@gmail.com", Password: string(hash), Verified: false}
+	link := &m.OidcLink{UserId: existUser.Id, Issuer: "https://accounts.google.com", Subject: "google-subject", Email: existUser.Email}
+	expSession := newSessionFor(existUser.Id)
+
+	stateMock.EXPECT().TakeLink(context.Background(), "link-token").Return(link, nil).Times(1)
+	userMock.EXPECT().GetById(context.Background(), existUser.Id).Return(existUser, nil).Times(1)
+	identityMock.EXPECT().Create(&m.OidcIdentity{UserId: existUser.Id, Issuer: link.Issuer, Subject: link.Subject, Email: link.Email}).Return(nil).Times(1)
+	userMock.EXPECT().UpdateVerificationStatus(context.Background(), existUser.Id).Return(true, nil).Times(1)
+	twoFactorDisabled(twoFactorMock, existUser.Id)
+	sessionMock.EXPECT().Create(context.Background(), existUser.Id, m.SessionMeta{}).Return(expSession, nil).Times(1)
+
+	response, session, err := Link(&LinkRequest{LinkToken: "link-token", Password: "12345678"}, stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &LinkResponse{UserId: existUser.Id}, response)
+	require.Equal(t, expSession, session)
+}
+
+func TestGoogleLinkInvalidPassword(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	stateMock := dMock.NewMockOidcStateInterface(ctl)
+	identityMock := dMock.NewMockIdentityInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("12345678"), 12)
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Password: string(hash), Verified: true}
+
+	stateMock.EXPECT().TakeLink(context.Background(), "link-token").Return(&m.OidcLink{UserId: existUser.Id}, nil).Times(1)
+	userMock.EXPECT().GetById(context.Background(), existUser.Id).Return(existUser, nil).Times(1)
+
+	response, session, err := Link(&LinkRequest{LinkToken: "link-token", Password: "wrong-password"}, stateMock, identityMock, sessionMock, twoFactorMock, pendingMock, userMock, testTwoFactorCfg, logger)
+
+	require.Nil(t, response)
+	require.Nil(t, session)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials"), err)
+}
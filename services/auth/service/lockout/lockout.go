@@ -0,0 +1,140 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	"warehouseai/auth/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Ключ, по которому считаются неудачные попытки: email, IP, ID токена.
+// Limit - после стольких неудач ключ блокируется на LockoutTTL.
+// Account - ключ привязан к аккаунту, его блокировка означает блокировку аккаунта.
+type Key struct {
+	Name    string
+	Limit   int
+	Account bool
+}
+
+// Возвращает 429, если хотя бы один из ключей сейчас заблокирован или ждёт задержку
+func Check(keys []Key, attempts dataservice.AttemptInterface, logger *logrus.Logger) *e.ErrorResponse {
+	for _, key := range keys {
+		blockedFor, dbErr := attempts.BlockedFor(context.Background(), key.Name)
+
+		if dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Check attempts")
+			return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+		}
+
+		if blockedFor > 0 {
+			return tooManyAttempts(blockedFor)
+		}
+	}
+
+	return nil
+}
+
+// Учитывает неудачную попытку по всем ключам и выставляет задержку.
+// Возвращает ключи, которые этой попыткой дошли до Limit и заблокированы.
+func Fail(keys []Key, attempts dataservice.AttemptInterface, cfg config.LockoutCfg, logger *logrus.Logger) []Key {
+	locked := make([]Key, 0)
+
+	for _, key := range keys {
+		failures, dbErr := attempts.Fail(context.Background(), key.Name, cfg.Window)
+
+		if dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Fail attempt")
+			continue
+		}
+
+		delay := Delay(failures, key.Limit, cfg)
+
+		if delay == 0 {
+			continue
+		}
+
+		if dbErr := attempts.Block(context.Background(), key.Name, delay); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Fail attempt")
+			continue
+		}
+
+		// Ровно на пороге, чтобы письмо о блокировке ушло один раз
+		if key.Limit > 0 && failures == int64(key.Limit) {
+			locked = append(locked, key)
+		}
+	}
+
+	return locked
+}
+
+// Успешная попытка сбрасывает счётчики аккаунта. Счётчик IP не сбрасываем:
+// иначе перебор по многим аккаунтам с одного адреса можно разбавлять своим.
+func Succeed(keys []Key, attempts dataservice.AttemptInterface, logger *logrus.Logger) {
+	for _, key := range keys {
+		if !key.Account {
+			continue
+		}
+
+		if dbErr := attempts.Reset(context.Background(), key.Name); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reset attempts")
+		}
+	}
+}
+
+// Задержка после failures неудач: первые FreeAttempts бесплатно, дальше
+// BaseDelay, 2*BaseDelay, 4*BaseDelay... не больше MaxDelay, с limit - блокировка.
+func Delay(failures int64, limit int, cfg config.LockoutCfg) time.Duration {
+	if limit > 0 && failures >= int64(limit) {
+		return cfg.LockoutTTL
+	}
+
+	if failures <= int64(cfg.FreeAttempts) {
+		return 0
+	}
+
+	delay := cfg.BaseDelay
+
+	for i := int64(cfg.FreeAttempts) + 1; i < failures && delay < cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+
+	return delay
+}
+
+func tooManyAttempts(blockedFor time.Duration) *e.ErrorResponse {
+	seconds := int((blockedFor + time.Second - 1) / time.Second)
+
+	return e.NewErrorResponse(e.HttpTooManyRequests, fmt.Sprintf("Too many attempts, try again in %d seconds", seconds))
+}
+
+// Письмо владельцу аккаунта: кто-то подбирает пароль или код
+func NotifyLocked(to string, firstname string, cfg config.LockoutCfg, mail adapter.BrokerInterface, logger *logrus.Logger) {
+	message := model.Email{
+		To:      to,
+		Subject: "Вход в аккаунт временно заблокирован",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+      
+      Мы зафиксировали несколько неудачных попыток входа в аккаунт, связанный с почтой %s.
+      Вход временно заблокирован на %d минут.
+      
+      Если это были не вы - рекомендуем сменить пароль после разблокировки.
+      
+      WarehouseAI Team
+      `, firstname, to, int(cfg.LockoutTTL/time.Minute)),
+	}
+
+	if err := mail.SendEmail(message); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+}
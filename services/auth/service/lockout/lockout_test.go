@@ -0,0 +1,74 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+	"warehouseai/auth/config"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testCfg = config.LockoutCfg{
+	FreeAttempts: 3,
+	MaxFailures:  10,
+	BaseDelay:    time.Second,
+	MaxDelay:     time.Minute,
+	Window:       time.Hour,
+	LockoutTTL:   15 * time.Minute,
+}
+
+func TestDelay(t *testing.T) {
+	cases := []struct {
+		failures int64
+		expDelay time.Duration
+	}{
+		{failures: 1, expDelay: 0},
+		{failures: 3, expDelay: 0},
+		{failures: 4, expDelay: time.Second},
+		{failures: 5, expDelay: 2 * time.Second},
+		{failures: 9, expDelay: 32 * time.Second},
+		{failures: 10, expDelay: testCfg.LockoutTTL},
+		{failures: 50, expDelay: testCfg.LockoutTTL},
+	}
+
+	for _, tCase := range cases {
+		require.Equal(t, tCase.expDelay, Delay(tCase.failures, testCfg.MaxFailures, testCfg), "failures: %d", tCase.failures)
+	}
+
+	// Без лимита задержка растёт только до MaxDelay
+	require.Equal(t, testCfg.MaxDelay, Delay(100, 0, testCfg))
+}
+
+func TestFailReturnsLockedKeys(t *testing.T) {
+	ctl := gomock.NewController(t)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+
+	keys := []Key{{Name: "email", Limit: 10, Account: true}, {Name: "ip", Limit: 100}}
+
+	attemptMock.EXPECT().Fail(context.Background(), "email", testCfg.Window).Return(int64(10), nil).Times(1)
+	attemptMock.EXPECT().Block(context.Background(), "email", testCfg.LockoutTTL).Return(nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "ip", testCfg.Window).Return(int64(2), nil).Times(1)
+
+	locked := Fail(keys, attemptMock, testCfg, logrus.New())
+
+	require.Equal(t, []Key{keys[0]}, locked)
+}
+
+func TestCheck(t *testing.T) {
+	ctl := gomock.NewController(t)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+
+	keys := []Key{{Name: "email"}, {Name: "ip"}}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), "email").Return(time.Duration(0), nil).Times(1)
+	attemptMock.EXPECT().BlockedFor(context.Background(), "ip").Return(1500*time.Millisecond, nil).Times(1)
+
+	err := Check(keys, attemptMock, logrus.New())
+
+	require.Equal(t, e.NewErrorResponse(e.HttpTooManyRequests, "Too many attempts, try again in 2 seconds"), err)
+}
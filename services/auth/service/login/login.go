@@ -2,25 +2,42 @@ package login
 
 import (
 	"context"
-	"fmt"
 	"net/mail"
+	"strings"
 	"time"
 	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
 	"warehouseai/auth/model"
+	"warehouseai/auth/service/lockout"
+	"warehouseai/auth/service/twofactor"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// bcrypt-хеш случайного пароля той же стоимости, что и у пользователей
+const dummyHash = "$2a$12$er95aDtr8bo6MRbycJjg5enjEgwCoRm3pHL71jWYCQDdTTN6KMGoe"
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// Заполняется хендлером из заголовков запроса
+	Meta model.SessionMeta `json:"-"`
 }
 
 type LoginResponse struct {
-	UserId string `json:"user_id"`
+	UserId            string `json:"user_id,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	PendingToken      string `json:"pending_token,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	PendingToken string            `json:"pending_token"`
+	Code         string            `json:"code"`
+	RecoveryCode string            `json:"recovery_code"`
+	Meta         model.SessionMeta `json:"-"`
 }
 
 func validateLoginRequest(req *LoginRequest) *e.ErrorResponse {
@@ -31,34 +48,157 @@ func validateLoginRequest(req *LoginRequest) *e.ErrorResponse {
 	return nil
 }
 
-func Login(req *LoginRequest, user adapter.UserGrpcInterface, session dataservice.SessionInterface, logger *logrus.Logger) (*LoginResponse, *model.Session, *e.ErrorResponse) {
+func Login(
+	req *LoginRequest,
+	user adapter.UserGrpcInterface,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	attempts dataservice.AttemptInterface,
+	broker adapter.BrokerInterface,
+	cfg config.TwoFactorCfg,
+	lockoutCfg config.LockoutCfg,
+	logger *logrus.Logger,
+) (*LoginResponse, *model.Session, *e.ErrorResponse) {
 	if err := validateLoginRequest(req); err != nil {
 		return nil, nil, err
 	}
 
+	keys := []lockout.Key{
+		{Name: "login:email:" + strings.ToLower(strings.TrimSpace(req.Email)), Limit: lockoutCfg.MaxFailures, Account: true},
+		{Name: "login:ip:" + req.Meta.IP, Limit: lockoutCfg.IpMaxFailures},
+	}
+
+	if err := lockout.Check(keys, attempts, logger); err != nil {
+		return nil, nil, err
+	}
+
 	existUser, gwErr := user.GetByEmail(context.Background(), req.Email)
 
-	if gwErr != nil {
+	if gwErr != nil && gwErr.ErrorCode != e.HttpNotFound {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Login user")
 		return nil, nil, gwErr
 	}
 
+	// Для несуществующей почты тоже сравниваем хеш: ответ и время ответа не должны её выдавать
+	hash := []byte(dummyHash)
+
+	if gwErr == nil {
+		hash = []byte(existUser.Password)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": "invalid credentials"}).Info("Login user")
+
+		for _, key := range lockout.Fail(keys, attempts, lockoutCfg, logger) {
+			if key.Account && gwErr == nil {
+				lockout.NotifyLocked(existUser.Email, existUser.Firstname, lockoutCfg, broker, logger)
+			}
+		}
+
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials")
+	}
+
+	lockout.Succeed(keys, attempts, logger)
+
 	if !existUser.Verified {
 		return nil, nil, e.NewErrorResponse(e.HttpForbidden, "Verify your email first")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(req.Password)); err != nil {
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Login user")
-		fmt.Println(err.Error())
-		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials")
+	return StartSession(existUser.Id, req.Meta, session, twoFactorRepository, pending, cfg, logger)
+}
+
+// Создаёт сессию после проверки первого фактора. Если у пользователя включена 2FA,
+// вместо сессии выдаётся короткоживущий pending_token для LoginTwoFactor.
+func StartSession(
+	userId string,
+	meta model.SessionMeta,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	cfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*LoginResponse, *model.Session, *e.ErrorResponse) {
+	enabled, err := twofactor.IsEnabled(userId, twoFactorRepository, logger)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
-	newSession, sessErr := session.Create(context.Background(), existUser.Id)
+	if enabled {
+		pendingToken, dbErr := pending.Create(context.Background(), userId, cfg.PendingTTL)
+
+		if dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Login user")
+			return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+		}
+
+		return &LoginResponse{TwoFactorRequired: true, PendingToken: pendingToken}, nil, nil
+	}
+
+	return createSession(userId, meta, session, logger)
+}
+
+func LoginTwoFactor(
+	req *TwoFactorLoginRequest,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	cfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*LoginResponse, *model.Session, *e.ErrorResponse) {
+	if req.PendingToken == "" || (req.Code == "" && req.RecoveryCode == "") {
+		return nil, nil, e.NewErrorResponse(e.HttpBadRequest, "Provide pending token and code")
+	}
+
+	pendingLogin, dbErr := pending.Get(context.Background(), req.PendingToken)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Login 2FA")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Login attempt expired, sign in again")
+		}
+
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	attempts, dbErr := pending.Attempt(context.Background(), req.PendingToken)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Login 2FA")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Login attempt expired, sign in again")
+		}
+
+		return nil, nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	// Перебор кодов: после MaxAttempts неудач нужно заново вводить пароль
+	if attempts > int64(cfg.MaxAttempts) {
+		pending.Delete(context.Background(), req.PendingToken)
+		return nil, nil, e.NewErrorResponse(e.HttpUnauthorized, "Too many attempts, sign in again")
+	}
+
+	if err := twofactor.CheckCode(pendingLogin.UserId, req.Code, req.RecoveryCode, twoFactorRepository, logger); err != nil {
+		return nil, nil, err
+	}
+
+	if dbErr := pending.Delete(context.Background(), req.PendingToken); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Login 2FA")
+	}
+
+	return createSession(pendingLogin.UserId, req.Meta, session, logger)
+}
+
+func createSession(userId string, meta model.SessionMeta, session dataservice.SessionInterface, logger *logrus.Logger) (*LoginResponse, *model.Session, *e.ErrorResponse) {
+	newSession, sessErr := session.Create(context.Background(), userId, meta)
 
 	if sessErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": sessErr}).Info("Login user")
 		return nil, nil, e.NewErrorResponseFromDBError(sessErr.ErrorType, sessErr.Message)
 	}
 
-	return &LoginResponse{UserId: existUser.Id}, newSession, nil
+	return &LoginResponse{UserId: userId}, newSession, nil
 }
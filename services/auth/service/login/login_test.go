@@ -6,6 +6,7 @@ import (
 	"time"
 	"warehouseai/auth/adapter/grpc/gen"
 	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
 	dMock "warehouseai/auth/dataservice/mocks"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
@@ -17,6 +18,18 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var testTwoFactorCfg = config.TwoFactorCfg{Issuer: "WarehouseAI", PendingTTL: 5 * time.Minute, MaxAttempts: 5, RecoveryCodes: 10}
+
+var testLockoutCfg = config.LockoutCfg{
+	FreeAttempts:  3,
+	MaxFailures:   10,
+	IpMaxFailures: 100,
+	BaseDelay:     time.Second,
+	MaxDelay:      time.Minute,
+	Window:        time.Hour,
+	LockoutTTL:    15 * time.Minute,
+}
+
 func TestValidateLogin(t *testing.T) {
 	req := &LoginRequest{
 		Email:    "validemail@mail.com",
@@ -59,11 +72,16 @@ func TestLogin(t *testing.T) {
 
 	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
 	dbMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
 	logger := logrus.New()
 
 	request := &LoginRequest{
 		Email:    "validemail@mail.com",
 		Password: "12345678",
+		Meta:     m.SessionMeta{UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0", IP: "10.0.0.1"},
 	}
 
 	hash, _ := bcrypt.GenerateFromPassword([]byte("12345678"), 12)
@@ -92,10 +110,13 @@ func TestLogin(t *testing.T) {
 		TTL:     24 * time.Hour,
 	}
 
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
 	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(expUser, nil).Times(1)
-	dbMock.EXPECT().Create(context.Background(), expUser.Id).Return(expSession, nil).Times(1)
+	attemptMock.EXPECT().Reset(context.Background(), "login:email:"+request.Email).Return(nil).Times(1)
+	twoFactorMock.EXPECT().Get(expUser.Id).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	dbMock.EXPECT().Create(context.Background(), expUser.Id, request.Meta).Return(expSession, nil).Times(1)
 
-	resp, session, err := Login(request, grpcMock, dbMock, logger)
+	resp, session, err := Login(request, grpcMock, dbMock, twoFactorMock, pendingMock, attemptMock, brokerMock, testTwoFactorCfg, testLockoutCfg, logger)
 
 	require.NotNil(t, resp)
 	require.NotNil(t, session)
@@ -127,13 +148,18 @@ func TestLoginError(t *testing.T) {
 
 	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
 	dbMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
 	logger := logrus.New()
 
 	for _, tCase := range cases {
 		t.Run(tCase.name, func(t *testing.T) {
+			attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
 			grpcMock.EXPECT().GetByEmail(context.Background(), tCase.req.Email).Return(nil, &e.ErrorResponse{ErrorCode: e.HttpBadRequest, ErrorMessage: "User is not exist"}).Times(1)
 
-			resp, session, err := Login(tCase.req, grpcMock, dbMock, logger)
+			resp, session, err := Login(tCase.req, grpcMock, dbMock, twoFactorMock, pendingMock, attemptMock, brokerMock, testTwoFactorCfg, testLockoutCfg, logger)
 
 			require.Nil(t, resp)
 			require.Nil(t, session)
@@ -142,3 +168,176 @@ func TestLoginError(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginTwoFactorRequired(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	dbMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	request := &LoginRequest{Email: "validemail@mail.com", Password: "12345678"}
+	hash, _ := bcrypt.GenerateFromPassword([]byte(request.Password), 12)
+	expUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Email: request.Email, Password: string(hash), Verified: true}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(expUser, nil).Times(1)
+	attemptMock.EXPECT().Reset(context.Background(), gomock.Any()).Return(nil).Times(1)
+	twoFactorMock.EXPECT().Get(expUser.Id).Return(&m.TwoFactor{UserId: expUser.Id, Enabled: true}, nil).Times(1)
+	pendingMock.EXPECT().Create(context.Background(), expUser.Id, testTwoFactorCfg.PendingTTL).Return("pending-token", nil).Times(1)
+
+	resp, session, err := Login(request, grpcMock, dbMock, twoFactorMock, pendingMock, attemptMock, brokerMock, testTwoFactorCfg, testLockoutCfg, logger)
+
+	require.Nil(t, err)
+	require.Nil(t, session)
+	require.Equal(t, &LoginResponse{TwoFactorRequired: true, PendingToken: "pending-token"}, resp)
+}
+
+func TestLoginUnknownEmail(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	logger := logrus.New()
+
+	request := &LoginRequest{Email: "notexistemail@mail.com", Password: "12345678", Meta: m.SessionMeta{IP: "10.0.0.1"}}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(nil, e.NewErrorResponse(e.HttpNotFound, "User not found")).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "login:email:"+request.Email, testLockoutCfg.Window).Return(int64(1), nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "login:ip:10.0.0.1", testLockoutCfg.Window).Return(int64(1), nil).Times(1)
+
+	resp, session, err := Login(request, grpcMock, nil, nil, nil, attemptMock, nil, testTwoFactorCfg, testLockoutCfg, logger)
+
+	// Ответ такой же, как на неверный пароль
+	require.Nil(t, resp)
+	require.Nil(t, session)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials"), err)
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	request := &LoginRequest{Email: "ValidEmail@mail.com", Password: "wrong-password", Meta: m.SessionMeta{IP: "10.0.0.1"}}
+	hash, _ := bcrypt.GenerateFromPassword([]byte("12345678"), 12)
+	expUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Firstname: "Firstname", Email: "validemail@mail.com", Password: string(hash), Verified: true}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(expUser, nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "login:email:validemail@mail.com", testLockoutCfg.Window).Return(int64(testLockoutCfg.MaxFailures), nil).Times(1)
+	attemptMock.EXPECT().Block(context.Background(), "login:email:validemail@mail.com", testLockoutCfg.LockoutTTL).Return(nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "login:ip:10.0.0.1", testLockoutCfg.Window).Return(int64(2), nil).Times(1)
+	brokerMock.EXPECT().SendEmail(gomock.Any()).DoAndReturn(func(email m.Email) error {
+		require.Equal(t, expUser.Email, email.To)
+		return nil
+	}).Times(1)
+
+	_, _, err := Login(request, grpcMock, nil, nil, nil, attemptMock, brokerMock, testTwoFactorCfg, testLockoutCfg, logger)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials"), err)
+
+	// Пока ключ заблокирован, даже верный пароль не проверяется
+	request.Password = "12345678"
+	attemptMock.EXPECT().BlockedFor(context.Background(), "login:email:validemail@mail.com").Return(10*time.Minute, nil).Times(1)
+
+	_, _, err = Login(request, grpcMock, nil, nil, nil, attemptMock, brokerMock, testTwoFactorCfg, testLockoutCfg, logger)
+	require.Equal(t, e.NewErrorResponse(e.HttpTooManyRequests, "Too many attempts, try again in 600 seconds"), err)
+}
+
+func TestLoginTwoFactorRecoveryCode(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	dbMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	expSession := &m.Session{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId, CreatedAt: time.Now()}, TTL: 24 * time.Hour}
+	request := &TwoFactorLoginRequest{PendingToken: "pending-token", RecoveryCode: "abcd-efgh"}
+
+	pendingMock.EXPECT().Get(context.Background(), request.PendingToken).Return(&m.PendingLogin{UserId: userId}, nil).Times(1)
+	pendingMock.EXPECT().Attempt(context.Background(), request.PendingToken).Return(int64(1), nil).Times(1)
+	twoFactorMock.EXPECT().UseRecoveryCode(userId, gomock.Any()).Return(nil).Times(1)
+	pendingMock.EXPECT().Delete(context.Background(), request.PendingToken).Return(nil).Times(1)
+	dbMock.EXPECT().Create(context.Background(), userId, request.Meta).Return(expSession, nil).Times(1)
+
+	resp, session, err := LoginTwoFactor(request, dbMock, twoFactorMock, pendingMock, testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &LoginResponse{UserId: userId}, resp)
+	require.Equal(t, expSession, session)
+}
+
+func TestLoginTwoFactorError(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	cases := []struct {
+		name    string
+		request *TwoFactorLoginRequest
+		prepare func(twoFactorMock *dMock.MockTwoFactorInterface, pendingMock *dMock.MockPendingLoginInterface)
+		expErr  *e.ErrorResponse
+	}{
+		{
+			name:    "empty_code",
+			request: &TwoFactorLoginRequest{PendingToken: "pending-token"},
+			prepare: func(*dMock.MockTwoFactorInterface, *dMock.MockPendingLoginInterface) {},
+			expErr:  e.NewErrorResponse(e.HttpBadRequest, "Provide pending token and code"),
+		},
+		{
+			name:    "expired",
+			request: &TwoFactorLoginRequest{PendingToken: "pending-token", Code: "123456"},
+			prepare: func(_ *dMock.MockTwoFactorInterface, pendingMock *dMock.MockPendingLoginInterface) {
+				pendingMock.EXPECT().Get(context.Background(), "pending-token").Return(nil, e.NewDBError(e.DbNotFound, "Pending login not found.", "")).Times(1)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Login attempt expired, sign in again"),
+		},
+		{
+			name:    "too_many_attempts",
+			request: &TwoFactorLoginRequest{PendingToken: "pending-token", Code: "123456"},
+			prepare: func(_ *dMock.MockTwoFactorInterface, pendingMock *dMock.MockPendingLoginInterface) {
+				pendingMock.EXPECT().Get(context.Background(), "pending-token").Return(&m.PendingLogin{UserId: userId}, nil).Times(1)
+				pendingMock.EXPECT().Attempt(context.Background(), "pending-token").Return(int64(6), nil).Times(1)
+				pendingMock.EXPECT().Delete(context.Background(), "pending-token").Return(nil).Times(1)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Too many attempts, sign in again"),
+		},
+		{
+			name:    "used_recovery_code",
+			request: &TwoFactorLoginRequest{PendingToken: "pending-token", RecoveryCode: "abcd-efgh"},
+			prepare: func(twoFactorMock *dMock.MockTwoFactorInterface, pendingMock *dMock.MockPendingLoginInterface) {
+				pendingMock.EXPECT().Get(context.Background(), "pending-token").Return(&m.PendingLogin{UserId: userId}, nil).Times(1)
+				pendingMock.EXPECT().Attempt(context.Background(), "pending-token").Return(int64(1), nil).Times(1)
+				twoFactorMock.EXPECT().UseRecoveryCode(userId, gomock.Any()).Return(e.NewDBError(e.DbNotFound, "Recovery code not found.", "")).Times(1)
+			},
+			expErr: e.NewErrorResponse(e.HttpUnauthorized, "Invalid code"),
+		},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			dbMock := dMock.NewMockSessionInterface(ctl)
+			twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+			pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+			logger := logrus.New()
+
+			tCase.prepare(twoFactorMock, pendingMock)
+
+			resp, session, err := LoginTwoFactor(tCase.request, dbMock, twoFactorMock, pendingMock, testTwoFactorCfg, logger)
+
+			require.Nil(t, resp)
+			require.Nil(t, session)
+			require.Equal(t, tCase.expErr, err)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package login
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	"warehouseai/auth/model"
+	"warehouseai/auth/service/lockout"
+
+	"github.com/sirupsen/logrus"
+)
+
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+	// Заполняются хендлером: IP и nonce из cookie, если браузер уже запрашивал ссылку
+	IP          string `json:"-"`
+	CookieNonce string `json:"-"`
+}
+
+type MagicLinkResponse struct {
+	Nonce string
+}
+
+type MagicLinkConfirmRequest struct {
+	Token       string
+	CookieNonce string
+	Meta        model.SessionMeta
+}
+
+// Отправляет ссылку для входа. Nonce возвращается всегда, даже для неизвестной почты,
+// чтобы ответ не выдавал, есть ли такой аккаунт. Каждый запрос считается попыткой.
+func RequestMagicLink(
+	req MagicLinkRequest,
+	user adapter.UserGrpcInterface,
+	links dataservice.MagicLinkInterface,
+	attempts dataservice.AttemptInterface,
+	broker adapter.BrokerInterface,
+	cfg config.MagicLinkCfg,
+	lockoutCfg config.LockoutCfg,
+	logger *logrus.Logger,
+) (*MagicLinkResponse, *e.ErrorResponse) {
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid email address")
+	}
+
+	keys := []lockout.Key{
+		{Name: "magic:email:" + strings.ToLower(strings.TrimSpace(req.Email)), Limit: lockoutCfg.ResendLimit},
+		{Name: "magic:ip:" + req.IP, Limit: lockoutCfg.IpMaxFailures},
+	}
+
+	if err := lockout.Check(keys, attempts, logger); err != nil {
+		return nil, err
+	}
+
+	lockout.Fail(keys, attempts, lockoutCfg, logger)
+
+	// Повторный запрос из того же браузера не должен ломать уже отправленные ссылки
+	nonce := req.CookieNonce
+
+	if nonce == "" {
+		var err error
+
+		if nonce, err = randomToken(32); err != nil {
+			return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create the sign in link")
+		}
+	}
+
+	existUser, gwErr := user.GetByEmail(context.Background(), req.Email)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Magic link")
+
+		if gwErr.ErrorCode == e.HttpNotFound {
+			return &MagicLinkResponse{Nonce: nonce}, nil
+		}
+
+		return nil, gwErr
+	}
+
+	if !existUser.Verified {
+		return &MagicLinkResponse{Nonce: nonce}, nil
+	}
+
+	token, err := randomToken(32)
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to create the sign in link")
+	}
+
+	if dbErr := links.Save(context.Background(), hashToken(token), model.MagicLink{UserId: existUser.Id, NonceHash: hashToken(nonce)}, cfg.TTL); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Magic link")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if err := broker.SendEmail(magicLinkEmail(existUser.Firstname, existUser.Email, token, cfg)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to send email.")
+	}
+
+	return &MagicLinkResponse{Nonce: nonce}, nil
+}
+
+// Входит по ссылке из письма. Ссылка тратится только в браузере с тем же nonce,
+// поэтому пересланное письмо или сканер ссылок не могут ни войти, ни сжечь её.
+func ConfirmMagicLink(
+	req MagicLinkConfirmRequest,
+	links dataservice.MagicLinkInterface,
+	session dataservice.SessionInterface,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	pending dataservice.PendingLoginInterface,
+	cfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*LoginResponse, *model.Session, *e.ErrorResponse) {
+	if req.Token == "" {
+		return nil, nil, invalidMagicLink()
+	}
+
+	if req.CookieNonce == "" {
+		return nil, nil, e.NewErrorResponse(e.HttpForbidden, "Open the link in the browser where you requested it")
+	}
+
+	tokenHash := hashToken(req.Token)
+	link, dbErr := links.Get(context.Background(), tokenHash)
+
+	if dbErr != nil {
+		return nil, nil, magicLinkDBError(dbErr, logger)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(link.NonceHash), []byte(hashToken(req.CookieNonce))) != 1 {
+		return nil, nil, e.NewErrorResponse(e.HttpForbidden, "Open the link in the browser where you requested it")
+	}
+
+	if _, dbErr := links.Take(context.Background(), tokenHash); dbErr != nil {
+		return nil, nil, magicLinkDBError(dbErr, logger)
+	}
+
+	return StartSession(link.UserId, req.Meta, session, twoFactorRepository, pending, cfg, logger)
+}
+
+func magicLinkDBError(dbErr *e.DBError, logger *logrus.Logger) *e.ErrorResponse {
+	logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Magic link")
+
+	if dbErr.ErrorType == e.DbNotFound {
+		return invalidMagicLink()
+	}
+
+	return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+}
+
+func invalidMagicLink() *e.ErrorResponse {
+	return e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired sign in link")
+}
+
+func magicLinkEmail(firstname string, email string, token string, cfg config.MagicLinkCfg) model.Email {
+	return model.Email{
+		To:      email,
+		Subject: "Вход в WarehouseAI",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Для входа в аккаунт перейдите по ссылке:
+      %s
+
+      Ссылка одноразовая и действует %d минут. Открыть её можно только в том браузере, где вы запросили вход.
+
+      Если вы не запрашивали вход - проигнорируйте данное письмо.
+
+      WarehouseAI Team
+      `, firstname, fmt.Sprintf("%s/api/auth/magic/confirm?token=%s", os.Getenv("DOMAIN"), token), int(cfg.TTL/time.Minute)),
+	}
+}
+
+func randomToken(length int) (string, error) {
+	randomBytes := make([]byte, length)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+func hashToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,130 @@
+package login
+
+import (
+	"context"
+	"testing"
+	"time"
+	"warehouseai/auth/adapter/grpc/gen"
+	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testMagicLinkCfg = config.MagicLinkCfg{TTL: 15 * time.Minute}
+
+func TestRequestMagicLink(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	linkMock := dMock.NewMockMagicLinkInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	request := MagicLinkRequest{Email: "validemail@mail.com", IP: "10.0.0.1"}
+	expUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Firstname: "Firstname", Email: request.Email, Verified: true}
+
+	var saved m.MagicLink
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	attemptMock.EXPECT().Fail(context.Background(), "magic:email:"+request.Email, testLockoutCfg.Window).Return(int64(1), nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "magic:ip:"+request.IP, testLockoutCfg.Window).Return(int64(1), nil).Times(1)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(expUser, nil).Times(1)
+	linkMock.EXPECT().Save(context.Background(), gomock.Any(), gomock.Any(), testMagicLinkCfg.TTL).DoAndReturn(
+		func(_ context.Context, tokenHash string, payload m.MagicLink, _ time.Duration) *e.DBError {
+			saved = payload
+			return nil
+		},
+	).Times(1)
+	brokerMock.EXPECT().SendEmail(gomock.AssignableToTypeOf(m.Email{})).Return(nil).Times(1)
+
+	resp, err := RequestMagicLink(request, grpcMock, linkMock, attemptMock, brokerMock, testMagicLinkCfg, testLockoutCfg, logger)
+
+	require.Nil(t, err)
+	require.NotEmpty(t, resp.Nonce)
+	require.Equal(t, expUser.Id, saved.UserId)
+	// В Redis лежит только хеш nonce
+	require.Equal(t, hashToken(resp.Nonce), saved.NonceHash)
+}
+
+func TestRequestMagicLinkUnknownEmail(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	linkMock := dMock.NewMockMagicLinkInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	logger := logrus.New()
+
+	request := MagicLinkRequest{Email: "unknown@mail.com", IP: "10.0.0.1", CookieNonce: "nonce"}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	attemptMock.EXPECT().Fail(context.Background(), gomock.Any(), testLockoutCfg.Window).Return(int64(1), nil).Times(2)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(nil, e.NewErrorResponse(e.HttpNotFound, "User not found")).Times(1)
+
+	resp, err := RequestMagicLink(request, grpcMock, linkMock, attemptMock, brokerMock, testMagicLinkCfg, testLockoutCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &MagicLinkResponse{Nonce: "nonce"}, resp)
+}
+
+func TestConfirmMagicLink(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	linkMock := dMock.NewMockMagicLinkInterface(ctl)
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	pendingMock := dMock.NewMockPendingLoginInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	request := MagicLinkConfirmRequest{
+		Token:       "token",
+		CookieNonce: "nonce",
+		Meta:        m.SessionMeta{UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0", IP: "10.0.0.1"},
+	}
+	link := &m.MagicLink{UserId: userId, NonceHash: hashToken("nonce")}
+	expSession := &m.Session{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId}}
+
+	linkMock.EXPECT().Get(context.Background(), hashToken("token")).Return(link, nil).Times(1)
+	linkMock.EXPECT().Take(context.Background(), hashToken("token")).Return(link, nil).Times(1)
+	twoFactorMock.EXPECT().Get(userId).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	sessionMock.EXPECT().Create(context.Background(), userId, request.Meta).Return(expSession, nil).Times(1)
+
+	resp, session, err := ConfirmMagicLink(request, linkMock, sessionMock, twoFactorMock, pendingMock, testTwoFactorCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &LoginResponse{UserId: userId}, resp)
+	require.Equal(t, expSession, session)
+}
+
+func TestConfirmMagicLinkError(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	linkMock := dMock.NewMockMagicLinkInterface(ctl)
+	logger := logrus.New()
+
+	link := &m.MagicLink{UserId: uuid.Must(uuid.NewV4()).String(), NonceHash: hashToken("nonce")}
+	otherBrowser := e.NewErrorResponse(e.HttpForbidden, "Open the link in the browser where you requested it")
+
+	// Пересланная ссылка не тратится: Take не вызывается
+	linkMock.EXPECT().Get(context.Background(), hashToken("token")).Return(link, nil).Times(1)
+
+	_, _, err := ConfirmMagicLink(MagicLinkConfirmRequest{Token: "token", CookieNonce: "other"}, linkMock, nil, nil, nil, testTwoFactorCfg, logger)
+	require.Equal(t, otherBrowser, err)
+
+	_, _, err = ConfirmMagicLink(MagicLinkConfirmRequest{Token: "token"}, linkMock, nil, nil, nil, testTwoFactorCfg, logger)
+	require.Equal(t, otherBrowser, err)
+
+	linkMock.EXPECT().Get(context.Background(), hashToken("used")).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+
+	_, _, err = ConfirmMagicLink(MagicLinkConfirmRequest{Token: "used", CookieNonce: "nonce"}, linkMock, nil, nil, nil, testTwoFactorCfg, logger)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired sign in link"), err)
+}
@@ -0,0 +1,122 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Длина префикса SHA1, по которому разбит список, как в k-anonymity API Have I Been Pwned
+const prefixLength = 5
+
+// Локальный список утёкших паролей. Хеши разложены по префиксам: при проверке
+// берётся диапазон по первым символам SHA1 и в нём ищется остаток хеша.
+type Breached struct {
+	ranges map[string]map[string]struct{}
+}
+
+func LoadBreached(path string) (*Breached, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadBreached(file)
+}
+
+// Принимает как полный формат "<SHA1>:<count>", так и ответы range API,
+// склеенные через заголовок с префиксом: строка "<prefix>" и за ней "<suffix>:<count>".
+func ReadBreached(reader io.Reader) (*Breached, error) {
+	breached := &Breached{ranges: map[string]map[string]struct{}{}}
+	scanner := bufio.NewScanner(reader)
+	prefix := ""
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		record := strings.TrimSpace(scanner.Text())
+
+		if record == "" || strings.HasPrefix(record, "#") {
+			continue
+		}
+
+		hash := strings.ToUpper(strings.SplitN(record, ":", 2)[0])
+
+		if !isHex(hash) {
+			return nil, fmt.Errorf("line %d: invalid hash %q", line, hash)
+		}
+
+		switch len(hash) {
+		case prefixLength:
+			prefix = hash
+		case sha1.Size*2 - prefixLength:
+			if prefix == "" {
+				return nil, fmt.Errorf("line %d: hash suffix without prefix", line)
+			}
+
+			breached.add(prefix, hash)
+		case sha1.Size * 2:
+			breached.add(hash[:prefixLength], hash[prefixLength:])
+		default:
+			return nil, fmt.Errorf("line %d: invalid hash length %d", line, len(hash))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return breached, nil
+}
+
+func (b *Breached) add(prefix string, suffix string) {
+	if b.ranges[prefix] == nil {
+		b.ranges[prefix] = map[string]struct{}{}
+	}
+
+	b.ranges[prefix][suffix] = struct{}{}
+}
+
+func (b *Breached) Contains(password string) bool {
+	if b == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := b.ranges[hash[:prefixLength]][hash[prefixLength:]]
+
+	return found
+}
+
+func (b *Breached) Len() int {
+	if b == nil {
+		return 0
+	}
+
+	count := 0
+
+	for _, suffixes := range b.ranges {
+		count += len(suffixes)
+	}
+
+	return count
+}
+
+func isHex(value string) bool {
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789ABCDEF", r) {
+			return false
+		}
+	}
+
+	return value != ""
+}
@@ -0,0 +1,83 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"warehouseai/auth/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = config.PasswordPolicyCfg{MinLength: 10, MaxLength: 72, MinClasses: 3}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func rules(policy *Policy, password string, username string, email string) []string {
+	result := make([]string, 0)
+
+	for _, violation := range policy.Validate(password, username, email) {
+		result = append(result, violation.Rule)
+	}
+
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	policy := &Policy{Cfg: testCfg}
+
+	cases := []struct {
+		name     string
+		password string
+		expRules []string
+	}{
+		{name: "valid", password: "Corr3ct-Horse", expRules: []string{}},
+		{name: "short", password: "Ab1-", expRules: []string{RuleMinLength}},
+		{name: "long", password: strings.Repeat("Ab1-", 19), expRules: []string{RuleMaxLength}},
+		{name: "classes", password: "onlylowercaseletters", expRules: []string{RuleCharacterClasses}},
+		{name: "username", password: "Xx-SkyWalker-99", expRules: []string{RuleContainsUsername}},
+		{name: "email_local_part", password: "Luke.Sky-2024!", expRules: []string{RuleContainsEmail}},
+		{name: "everything", password: "skywalker", expRules: []string{RuleMinLength, RuleCharacterClasses, RuleContainsUsername}},
+	}
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			require.Equal(t, tCase.expRules, rules(policy, tCase.password, "skywalker", "luke.sky@mail.com"))
+		})
+	}
+}
+
+func TestValidateBreached(t *testing.T) {
+	hash := sha1Hex("Passw0rd-2024")
+
+	// Формат range API: префикс отдельной строкой, за ним остатки хешей
+	breached, err := ReadBreached(strings.NewReader("# top passwords\n" + hash[:5] + "\n" + hash[5:] + ":1532\n" + sha1Hex("Other-Passw0rd") + ":12\n"))
+	require.NoError(t, err)
+	require.Equal(t, 2, breached.Len())
+
+	policy := &Policy{Cfg: testCfg, Breached: breached}
+
+	require.Equal(t, []string{RuleBreached}, rules(policy, "Passw0rd-2024", "", ""))
+	require.Equal(t, []string{RuleBreached}, rules(policy, "Other-Passw0rd", "", ""))
+	require.Empty(t, rules(policy, "Corr3ct-Horse", "", ""))
+}
+
+func TestReadBreachedErrors(t *testing.T) {
+	cases := map[string]string{
+		"not_hex":           "ZZZZZ\n",
+		"suffix_no_prefix":  sha1Hex("x")[5:] + ":1\n",
+		"wrong_hash_length": "ABCDEF:1\n",
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadBreached(strings.NewReader(input))
+			require.Error(t, err)
+		})
+	}
+}
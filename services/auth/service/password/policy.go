@@ -0,0 +1,130 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"warehouseai/auth/config"
+	e "warehouseai/auth/errors"
+)
+
+const (
+	RuleMinLength        = "min_length"
+	RuleMaxLength        = "max_length"
+	RuleCharacterClasses = "character_classes"
+	RuleContainsUsername = "contains_username"
+	RuleContainsEmail    = "contains_email"
+	RuleBreached         = "breached"
+)
+
+// Короче этого имя и почту в пароле не ищем - иначе мешают случайные совпадения
+const minIdentityLength = 3
+
+// Общая политика паролей для регистрации, сброса и смены пароля
+type Policy struct {
+	Cfg      config.PasswordPolicyCfg
+	Breached *Breached
+}
+
+func NewPolicy(cfg config.PasswordPolicyCfg) (*Policy, error) {
+	policy := &Policy{Cfg: cfg}
+
+	if cfg.BreachedFile == "" {
+		return policy, nil
+	}
+
+	breached, err := LoadBreached(cfg.BreachedFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Breached = breached
+	return policy, nil
+}
+
+// Возвращает все нарушенные правила, а не только первое: так пользователь исправит пароль за раз
+func (p *Policy) Validate(password string, username string, email string) []e.Violation {
+	violations := make([]e.Violation, 0)
+
+	if length := utf8.RuneCountInString(password); length < p.Cfg.MinLength {
+		violations = append(violations, e.Violation{Rule: RuleMinLength, Message: fmt.Sprintf("Password must be at least %d characters long", p.Cfg.MinLength)})
+	}
+
+	if p.Cfg.MaxLength > 0 && len(password) > p.Cfg.MaxLength {
+		violations = append(violations, e.Violation{Rule: RuleMaxLength, Message: fmt.Sprintf("Password must be at most %d bytes long", p.Cfg.MaxLength)})
+	}
+
+	if classes := characterClasses(password); classes < p.Cfg.MinClasses {
+		violations = append(violations, e.Violation{Rule: RuleCharacterClasses, Message: fmt.Sprintf("Password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.Cfg.MinClasses)})
+	}
+
+	lowered := strings.ToLower(password)
+
+	if username = strings.ToLower(strings.TrimSpace(username)); len(username) >= minIdentityLength && strings.Contains(lowered, username) {
+		violations = append(violations, e.Violation{Rule: RuleContainsUsername, Message: "Password must not contain the username"})
+	}
+
+	if containsEmail(lowered, email) {
+		violations = append(violations, e.Violation{Rule: RuleContainsEmail, Message: "Password must not contain the email"})
+	}
+
+	if p.Breached.Contains(password) {
+		violations = append(violations, e.Violation{Rule: RuleBreached, Message: "Password has appeared in a data breach, choose another one"})
+	}
+
+	return violations
+}
+
+func (p *Policy) Check(password string, username string, email string) *e.ErrorResponse {
+	if violations := p.Validate(password, username, email); len(violations) > 0 {
+		return e.NewValidationErrorResponse("Password does not meet the requirements", violations)
+	}
+
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			classes++
+		}
+	}
+
+	return classes
+}
+
+// Проверяется и почта целиком, и её локальная часть до @
+func containsEmail(lowered string, email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if email == "" {
+		return false
+	}
+
+	if strings.Contains(lowered, email) {
+		return true
+	}
+
+	local := strings.SplitN(email, "@", 2)[0]
+
+	return len(local) >= minIdentityLength && strings.Contains(lowered, local)
+}
@@ -1,10 +1,13 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
 	"mime/multipart"
-	"path/filepath"
+	"path"
+	"strings"
 	"time"
+	"warehouseai/auth/config"
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
 
@@ -12,22 +15,121 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func UploadImage(pic *multipart.FileHeader, picture dataservice.PictureInterface, logger *logrus.Logger) (string, *e.ErrorResponse) {
+// Имена файлов в хранилище: <prefix><id>_<size>.jpg
+const imageFilePrefix = "avatar."
+
+func UploadImage(pic *multipart.FileHeader, picture dataservice.PictureInterface, cfg config.ImageCfg, logger *logrus.Logger) (*ImageUrls, *e.ErrorResponse) {
+	if pic.Size > cfg.MaxSize {
+		return nil, e.NewErrorResponse(e.HttpPayloadTooLarge, "Image is too large.")
+	}
+
 	picPayload, err := pic.Open()
 
 	if err != nil {
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Register user")
-		return "", e.NewErrorResponse(e.HttpInternalError, "Can't read provided avatar.")
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Upload image")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Can't read provided image.")
 	}
 
-	url, fileErr := picture.UploadFile(picPayload, fmt.Sprintf("avatar.%s%s", uuid.Must(uuid.NewV4()).String(), filepath.Ext(pic.Filename)))
+	defer picPayload.Close()
+
+	variants, svcErr := ProcessImage(picPayload, cfg)
 
-	if fileErr != nil {
-		fmt.Println(fileErr)
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": fileErr.Error()}).Info("Register user")
-		return "", e.NewErrorResponse(e.HttpInternalError, "Can't upload user avatar.")
+	if svcErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": svcErr.ErrorMessage}).Info("Upload image")
+		return nil, svcErr
 	}
 
-	defer picPayload.Close()
-	return url, nil
+	imageId := uuid.Must(uuid.NewV4()).String()
+	uploaded := map[string]string{}
+
+	for _, size := range imageSizes {
+		url, fileErr := picture.UploadFile(bytes.NewReader(variants[size.Name]), imageFileName(imageId, size.Name))
+
+		if fileErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": fileErr.Error()}).Info("Upload image")
+
+			for _, uploadedUrl := range uploaded {
+				DeleteImage(uploadedUrl, picture, logger)
+			}
+
+			return nil, e.NewErrorResponse(e.HttpInternalError, "Can't upload image.")
+		}
+
+		uploaded[size.Name] = url
+	}
+
+	return &ImageUrls{
+		Thumbnail: uploaded["thumbnail"],
+		Card:      uploaded["card"],
+		Full:      uploaded["full"],
+	}, nil
+}
+
+// Восстанавливает ссылки на все размеры по ссылке на full, которая хранится в базе.
+// Для картинок, загруженных до появления размеров, все ссылки совпадают.
+func ImageUrlsFrom(url string) ImageUrls {
+	names := imageFileNames(path.Base(url))
+	base := strings.TrimSuffix(url, path.Base(url))
+
+	if len(names) == 1 {
+		return ImageUrls{Thumbnail: url, Card: url, Full: url}
+	}
+
+	return ImageUrls{Thumbnail: base + names[0], Card: base + names[1], Full: base + names[2]}
+}
+
+// Удаляет картинку (все её размеры) из хранилища по публичной ссылке. Ошибка только логируется.
+func DeleteImage(url string, picture dataservice.PictureInterface, logger *logrus.Logger) {
+	if url == "" {
+		return
+	}
+
+	for _, fileName := range imageFileNames(path.Base(url)) {
+		if err := picture.DeleteImage(fileName); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Delete image")
+		}
+	}
+}
+
+// Удаляет все размеры аватара и возвращает первую ошибку: шагу удаления аккаунта нужен честный результат.
+// Чужие ссылки (например, аватар из Google) пропускаются.
+func DeleteAvatar(url string, picture dataservice.PictureInterface) error {
+	fileName := path.Base(url)
+
+	if url == "" || !strings.HasPrefix(fileName, imageFilePrefix) {
+		return nil
+	}
+
+	var firstErr error
+
+	for _, name := range imageFileNames(fileName) {
+		if err := picture.DeleteImage(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func imageFileName(imageId string, size string) string {
+	return fmt.Sprintf("%s%s_%s.jpg", imageFilePrefix, imageId, size)
+}
+
+func imageFileNames(fileName string) []string {
+	for _, size := range imageSizes {
+		suffix := fmt.Sprintf("_%s.jpg", size.Name)
+
+		if strings.HasPrefix(fileName, imageFilePrefix) && strings.HasSuffix(fileName, suffix) {
+			imageId := strings.TrimSuffix(strings.TrimPrefix(fileName, imageFilePrefix), suffix)
+			names := make([]string, 0, len(imageSizes))
+
+			for _, variant := range imageSizes {
+				names = append(names, imageFileName(imageId, variant.Name))
+			}
+
+			return names
+		}
+	}
+
+	return []string{fileName}
 }
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"warehouseai/auth/dataservice/picturedata"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileHeader(t *testing.T, payload []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("image", "avatar.png")
+	require.NoError(t, err)
+
+	_, err = part.Write(payload)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	request := httptest.NewRequest("POST", "/", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	require.NoError(t, request.ParseMultipartForm(int64(body.Len())))
+
+	return request.MultipartForm.File["image"][0]
+}
+
+func TestUploadAndDeleteImage(t *testing.T) {
+	storage := picturedata.NewMemoryStorage("http://localhost/api/auth/static")
+	logger := logrus.New()
+
+	urls, err := UploadImage(newTestFileHeader(t, encodePNG(t, newTestImage(800, 400))), storage, testImageCfg, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, ImageUrlsFrom(urls.Full), *urls)
+
+	for _, url := range []string{urls.Thumbnail, urls.Card, urls.Full} {
+		_, ok := storage.Get(path.Base(url))
+		require.True(t, ok)
+	}
+
+	DeleteImage(urls.Full, storage, logger)
+
+	for _, url := range []string{urls.Thumbnail, urls.Card, urls.Full} {
+		_, ok := storage.Get(path.Base(url))
+		require.False(t, ok)
+	}
+}
+
+func TestDeleteAvatar(t *testing.T) {
+	storage := picturedata.NewMemoryStorage("http://localhost/api/auth/static")
+
+	urls, err := UploadImage(newTestFileHeader(t, encodePNG(t, newTestImage(300, 300))), storage, testImageCfg, logrus.New())
+	require.Nil(t, err)
+
+	// Аватар из Google лежит не у нас, удалять нечего
+	require.NoError(t, DeleteAvatar("https://lh3.googleusercontent.com/a/photo.jpg", storage))
+	require.NoError(t, DeleteAvatar(urls.Full, storage))
+
+	for _, url := range []string{urls.Thumbnail, urls.Card, urls.Full} {
+		_, ok := storage.Get(path.Base(url))
+		require.False(t, ok)
+	}
+}
@@ -2,9 +2,9 @@ package register
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"math/rand"
 	"net/mail"
 	"os"
 	"time"
@@ -13,6 +13,8 @@ import (
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
+	"warehouseai/auth/service"
+	"warehouseai/auth/service/password"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
@@ -29,7 +31,8 @@ type RegisterRequest struct {
 }
 
 type RegisterResponse struct {
-	UserId string `json:"user_id"`
+	UserId string             `json:"user_id"`
+	Images *service.ImageUrls `json:"images,omitempty"`
 }
 
 func generateToken(length int) (string, error) {
@@ -45,26 +48,35 @@ func generateToken(length int) (string, error) {
 	return key, nil
 }
 
+func verificationEmail(firstname string, email string, userId string, token string) m.Email {
+	return m.Email{
+		To:      email,
+		Subject: "Подтверждение электронной почты",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+      
+      Для завершения регистрации перейдите, пожалуйста, по ссылке:
+      %s
+      
+      Если вы не указывали эту электронную почту - проигнорируйте данное письмо.
+      
+      WarehouseAI Team
+      `, firstname, fmt.Sprintf("%s/register/confirm?user=%s&token=%s", os.Getenv("DOMAIN"), userId, token)),
+	}
+}
+
 func hashPassword(password string) string {
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), 12)
 
 	return string(hash)
 }
 
-func validateRegisterRequest(req *RegisterRequest) *e.ErrorResponse {
-	if len(req.Password) > 72 {
-		return e.NewErrorResponse(e.HttpBadRequest, "Password is too long")
-	}
-
-	if len(req.Password) < 8 {
-		return e.NewErrorResponse(e.HttpBadRequest, "Password is too short")
-	}
-
+func validateRegisterRequest(req *RegisterRequest, policy *password.Policy) *e.ErrorResponse {
 	if _, err := mail.ParseAddress(req.Email); err != nil {
 		return e.NewErrorResponse(e.HttpBadRequest, "The provided string is not email")
 	}
 
-	return nil
+	return policy.Check(req.Password, req.Username, req.Email)
 }
 
 func Register(
@@ -72,9 +84,10 @@ func Register(
 	user adapter.UserGrpcInterface,
 	tokenRepository dataservice.VerificationTokenInterface,
 	broker adapter.BrokerInterface,
+	policy *password.Policy,
 	logger *logrus.Logger,
 ) (*RegisterResponse, *e.ErrorResponse) {
-	if err := validateRegisterRequest(req); err != nil {
+	if err := validateRegisterRequest(req, policy); err != nil {
 		return nil, err
 	}
 
@@ -106,7 +119,7 @@ func Register(
 		Token:  string(tokenHash),
 	}
 
-	if err := tokenRepository.Create(&verificationTokenItem); err != nil {
+	if err := tokenRepository.Replace(userId, &verificationTokenItem); err != nil {
 		if err := broker.SendUserReject(userId); err != nil {
 			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Register user")
 			return nil, e.NewErrorResponse(e.HttpInternalError, err.Error())
@@ -116,20 +129,7 @@ func Register(
 		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
 
-	message := m.Email{
-		To:      req.Email,
-		Subject: "Подтверждение электронной почты",
-		Message: fmt.Sprintf(`
-      Здравствуйте, %s!
-      
-      Для завершения регистрации перейдите, пожалуйста, по ссылке:
-      %s
-      
-      Если вы не указывали эту электронную почту - проигнорируйте данное письмо.
-      
-      WarehouseAI Team
-      `, req.Firstname, fmt.Sprintf("%s/register/confirm?user=%s&token=%s", os.Getenv("DOMAIN"), userId, token)),
-	}
+	message := verificationEmail(req.Firstname, req.Email, userId, token)
 
 	if err := broker.SendEmail(message); err != nil {
 		if err := broker.SendUserReject(userId); err != nil {
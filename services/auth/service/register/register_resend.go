@@ -0,0 +1,93 @@
+package register
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+	"warehouseai/auth/service/lockout"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+	// Заполняется хендлером
+	IP string `json:"-"`
+}
+
+// Повторно отправляет письмо подтверждения, старая ссылка перестаёт работать.
+// Каждый запрос считается попыткой, поэтому частые запросы упираются в задержку.
+// Для неизвестной и уже подтверждённой почты ответ тот же, письмо просто не уходит.
+func ResendVerification(
+	request ResendVerificationRequest,
+	user adapter.UserGrpcInterface,
+	tokenRepository dataservice.VerificationTokenInterface,
+	attempts dataservice.AttemptInterface,
+	broker adapter.BrokerInterface,
+	lockoutCfg config.LockoutCfg,
+	logger *logrus.Logger,
+) *e.ErrorResponse {
+	if _, err := mail.ParseAddress(request.Email); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "The provided string is not email")
+	}
+
+	keys := []lockout.Key{
+		{Name: "resend:email:" + strings.ToLower(strings.TrimSpace(request.Email)), Limit: lockoutCfg.ResendLimit},
+		{Name: "resend:ip:" + request.IP, Limit: lockoutCfg.IpMaxFailures},
+	}
+
+	if err := lockout.Check(keys, attempts, logger); err != nil {
+		return err
+	}
+
+	lockout.Fail(keys, attempts, lockoutCfg, logger)
+
+	existUser, gwErr := user.GetByEmail(context.Background(), request.Email)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Resend verification")
+
+		if gwErr.ErrorCode == e.HttpNotFound {
+			return nil
+		}
+
+		return gwErr
+	}
+
+	if existUser.Verified {
+		return nil
+	}
+
+	token, err := generateToken(12)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Resend verification")
+		return e.NewErrorResponse(e.HttpInternalError, "Failed to create the verification code")
+	}
+
+	tokenHash, err := bcrypt.GenerateFromPassword([]byte(token), 12)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Resend verification")
+		return e.NewErrorResponse(e.HttpInternalError, "Failed to encrypt the verification code")
+	}
+
+	if err := tokenRepository.Replace(existUser.Id, &m.VerificationToken{UserId: existUser.Id, Token: string(tokenHash)}); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Resend verification")
+		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if err := broker.SendEmail(verificationEmail(existUser.Firstname, existUser.Email, existUser.Id, token)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+		return e.NewErrorResponse(e.HttpInternalError, "Failed to send email.")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package register
+
+import (
+	"context"
+	"testing"
+	"time"
+	"warehouseai/auth/adapter/grpc/gen"
+	aMock "warehouseai/auth/adapter/mocks"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResendVerification(t *testing.T) {
+	ctl := gomock.NewController(t)
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	repositoryMock := dMock.NewMockVerificationTokenInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	brokerMock := aMock.NewMockBrokerInterface(ctl)
+	log := logrus.New()
+
+	request := ResendVerificationRequest{Email: "validemail@mail.com", IP: "10.0.0.1"}
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Firstname: "Firstname", Email: request.Email}
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+	attemptMock.EXPECT().Fail(context.Background(), gomock.Any(), testLockoutCfg.Window).Return(int64(1), nil).Times(2)
+	grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(existUser, nil).Times(1)
+	repositoryMock.EXPECT().Replace(existUser.Id, gomock.AssignableToTypeOf(&m.VerificationToken{})).Return(nil).Times(1)
+	brokerMock.EXPECT().SendEmail(gomock.AssignableToTypeOf(m.Email{})).DoAndReturn(func(email m.Email) error {
+		require.Equal(t, existUser.Email, email.To)
+		return nil
+	}).Times(1)
+
+	err := ResendVerification(request, grpcMock, repositoryMock, attemptMock, brokerMock, testLockoutCfg, log)
+
+	require.Nil(t, err)
+}
+
+func TestResendVerificationSilent(t *testing.T) {
+	cases := []struct {
+		name    string
+		user    *gen.User
+		userErr *e.ErrorResponse
+	}{
+		{name: "unknown_email", userErr: e.NewErrorResponse(e.HttpNotFound, "User not found")},
+		{name: "already_verified", user: &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Verified: true}},
+	}
+
+	ctl := gomock.NewController(t)
+	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	log := logrus.New()
+
+	for _, tCase := range cases {
+		t.Run(tCase.name, func(t *testing.T) {
+			request := ResendVerificationRequest{Email: "validemail@mail.com"}
+
+			attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
+			attemptMock.EXPECT().Fail(context.Background(), gomock.Any(), testLockoutCfg.Window).Return(int64(1), nil).Times(2)
+			grpcMock.EXPECT().GetByEmail(context.Background(), request.Email).Return(tCase.user, tCase.userErr).Times(1)
+
+			// Ни токен, ни письмо не создаются, но ответ такой же, как для настоящей отправки
+			err := ResendVerification(request, grpcMock, nil, attemptMock, nil, testLockoutCfg, log)
+
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestResendVerificationRateLimited(t *testing.T) {
+	ctl := gomock.NewController(t)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), "resend:email:validemail@mail.com").Return(30*time.Second, nil).Times(1)
+
+	err := ResendVerification(ResendVerificationRequest{Email: "ValidEmail@mail.com"}, nil, nil, attemptMock, nil, testLockoutCfg, logrus.New())
+
+	require.Equal(t, e.NewErrorResponse(e.HttpTooManyRequests, "Too many attempts, try again in 30 seconds"), err)
+}
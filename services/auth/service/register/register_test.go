@@ -5,9 +5,11 @@ import (
 	"testing"
 	"warehouseai/auth/adapter/grpc/gen"
 	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
 	dMock "warehouseai/auth/dataservice/mocks"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
+	"warehouseai/auth/service/password"
 
 	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,8 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+var testPolicy = &password.Policy{Cfg: config.PasswordPolicyCfg{MinLength: 10, MaxLength: 72, MinClasses: 3}}
+
 // Valid request
 func TestRegisterValidate(t *testing.T) {
 	request := &RegisterRequest{
@@ -22,12 +26,12 @@ func TestRegisterValidate(t *testing.T) {
 		Lastname:  "Lastname",
 		Email:     "validmail@mail.com",
 		Username:  "Username",
-		Password:  "12345678",
+		Password:  "Corr3ct-Horse",
 		Image:     "",
 		ViaGoogle: false,
 	}
 
-	err := validateRegisterRequest(request)
+	err := validateRegisterRequest(request, testPolicy)
 	require.Nil(t, err)
 }
 
@@ -48,7 +52,9 @@ func TestValidateError(t *testing.T) {
 				Image:     "",
 				ViaGoogle: false,
 			},
-			expectedError: &e.ErrorResponse{ErrorCode: e.HttpBadRequest, ErrorMessage: "Password is too long"},
+			expectedError: e.NewValidationErrorResponse("Password does not meet the requirements", []e.Violation{
+				{Rule: password.RuleMaxLength, Message: "Password must be at most 72 bytes long"},
+			}),
 		},
 		{
 			name: "short_password",
@@ -61,7 +67,10 @@ func TestValidateError(t *testing.T) {
 				Image:     "",
 				ViaGoogle: false,
 			},
-			expectedError: &e.ErrorResponse{ErrorCode: e.HttpBadRequest, ErrorMessage: "Password is too short"},
+			expectedError: e.NewValidationErrorResponse("Password does not meet the requirements", []e.Violation{
+				{Rule: password.RuleMinLength, Message: "Password must be at least 10 characters long"},
+				{Rule: password.RuleCharacterClasses, Message: "Password must contain at least 3 of: lowercase letters, uppercase letters, digits, symbols"},
+			}),
 		},
 		{
 			name: "bad_email",
@@ -70,7 +79,7 @@ func TestValidateError(t *testing.T) {
 				Lastname:  "Lastname",
 				Email:     "validmail",
 				Username:  "Username",
-				Password:  "12345678",
+				Password:  "Corr3ct-Horse",
 				Image:     "",
 				ViaGoogle: false,
 			},
@@ -80,7 +89,7 @@ func TestValidateError(t *testing.T) {
 
 	for _, tCase := range cases {
 		t.Run(tCase.name, func(t *testing.T) {
-			err := validateRegisterRequest(tCase.request)
+			err := validateRegisterRequest(tCase.request, testPolicy)
 
 			require.NotNil(t, err)
 			require.Equal(t, tCase.expectedError, err)
@@ -100,7 +109,7 @@ func TestRegister(t *testing.T) {
 		Firstname: "Firstname",
 		Lastname:  "Lastname",
 		Username:  "Username",
-		Password:  "12345678",
+		Password:  "Corr3ct-Horse",
 		Image:     "",
 		Email:     "validmail@mail.com",
 		ViaGoogle: false,
@@ -109,10 +118,10 @@ func TestRegister(t *testing.T) {
 	userId := uuid.Must(uuid.NewV4()).String()
 
 	grpcMock.EXPECT().Create(context.Background(), gomock.AssignableToTypeOf(&gen.CreateUserMsg{})).Return(userId, nil).Times(1)
-	dbMock.EXPECT().Create(gomock.AssignableToTypeOf(&m.VerificationToken{})).Return(nil).Times(1)
+	dbMock.EXPECT().Replace(userId, gomock.AssignableToTypeOf(&m.VerificationToken{})).Return(nil).Times(1)
 	brokerMock.EXPECT().SendEmail(gomock.AssignableToTypeOf(m.Email{})).Return(nil).Times(1)
 
-	resp, err := Register(request, grpcMock, dbMock, brokerMock, logger)
+	resp, err := Register(request, grpcMock, dbMock, brokerMock, testPolicy, logger)
 
 	require.Nil(t, err)
 	require.Equal(t, &RegisterResponse{UserId: userId}, resp)
@@ -130,7 +139,7 @@ func TestRegisterGrpcError(t *testing.T) {
 				Firstname: "Firstname",
 				Lastname:  "Lastname",
 				Username:  "Username",
-				Password:  "12345678",
+				Password:  "Corr3ct-Horse",
 				Image:     "",
 				Email:     "validmail@mail.com",
 				ViaGoogle: false,
@@ -146,7 +155,7 @@ func TestRegisterGrpcError(t *testing.T) {
 				Firstname: "Firstname",
 				Lastname:  "Lastname",
 				Username:  "Username",
-				Password:  "12345678",
+				Password:  "Corr3ct-Horse",
 				Image:     "",
 				Email:     "validmail@mail.com",
 				ViaGoogle: false,
@@ -169,7 +178,7 @@ func TestRegisterGrpcError(t *testing.T) {
 		t.Run(tCase.name, func(t *testing.T) {
 			grpcMock.EXPECT().Create(context.Background(), gomock.AssignableToTypeOf(&gen.CreateUserMsg{})).Return("", tCase.expErr).Times(1)
 
-			resp, err := Register(tCase.req, grpcMock, dbMock, brokerMock, logger)
+			resp, err := Register(tCase.req, grpcMock, dbMock, brokerMock, testPolicy, logger)
 
 			require.NotNil(t, err)
 			require.Equal(t, tCase.expErr, err)
@@ -190,7 +199,7 @@ func TestRegisterDbError(t *testing.T) {
 				Firstname: "Firstname",
 				Lastname:  "Lastname",
 				Username:  "Username",
-				Password:  "12345678",
+				Password:  "Corr3ct-Horse",
 				Image:     "",
 				Email:     "validmail@mail.com",
 				ViaGoogle: false,
@@ -207,7 +216,7 @@ func TestRegisterDbError(t *testing.T) {
 				Firstname: "Firstname",
 				Lastname:  "Lastname",
 				Username:  "Username",
-				Password:  "12345678",
+				Password:  "Corr3ct-Horse",
 				Image:     "",
 				Email:     "validmail@mail.com",
 				ViaGoogle: false,
@@ -230,10 +239,10 @@ func TestRegisterDbError(t *testing.T) {
 	for _, tCase := range cases {
 		t.Run(tCase.name, func(t *testing.T) {
 			grpcMock.EXPECT().Create(context.Background(), gomock.AssignableToTypeOf(&gen.CreateUserMsg{})).Return("id", nil).Times(1)
-			dbMock.EXPECT().Create(gomock.AssignableToTypeOf(&m.VerificationToken{})).Return(tCase.expErr).Times(1)
+			dbMock.EXPECT().Replace("id", gomock.AssignableToTypeOf(&m.VerificationToken{})).Return(tCase.expErr).Times(1)
 			brokerMock.EXPECT().SendUserReject("id").Return(nil).Times(1)
 
-			resp, err := Register(tCase.req, grpcMock, dbMock, brokerMock, logger)
+			resp, err := Register(tCase.req, grpcMock, dbMock, brokerMock, testPolicy, logger)
 
 			require.NotNil(t, err)
 			require.Equal(t, e.NewErrorResponseFromDBError(tCase.expErr.ErrorType, tCase.expErr.Message), err)
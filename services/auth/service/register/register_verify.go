@@ -2,11 +2,12 @@ package register
 
 import (
 	"context"
-	"fmt"
 	"time"
 	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
+	"warehouseai/auth/service/lockout"
 
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
@@ -15,6 +16,8 @@ import (
 type RegisterVerifyRequest struct {
 	Token  string `json:"token"`
 	UserId string `json:"user_id"`
+	// Заполняется хендлером
+	IP string `json:"-"`
 }
 
 type RegisterVerifyResponse struct {
@@ -29,37 +32,53 @@ func validateVerifyRequest(request RegisterVerifyRequest) *e.ErrorResponse {
 	return nil
 }
 
+func invalidVerificationToken() *e.ErrorResponse {
+	return e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired verification token.")
+}
+
 func RegisterVerify(
 	request RegisterVerifyRequest,
 	user adapter.UserGrpcInterface,
 	verificationToken dataservice.VerificationTokenInterface,
+	attempts dataservice.AttemptInterface,
+	lockoutCfg config.LockoutCfg,
 	logger *logrus.Logger,
 ) (*RegisterVerifyResponse, *e.ErrorResponse) {
 	if err := validateVerifyRequest(request); err != nil {
 		return nil, err
 	}
 
+	keys := []lockout.Key{
+		{Name: "verify:user:" + request.UserId, Limit: lockoutCfg.MaxFailures, Account: true},
+		{Name: "verify:ip:" + request.IP, Limit: lockoutCfg.IpMaxFailures},
+	}
+
+	if err := lockout.Check(keys, attempts, logger); err != nil {
+		return nil, err
+	}
+
 	existVerificationToken, dbErr := verificationToken.Get(map[string]interface{}{"user_id": request.UserId})
 
-	if dbErr != nil {
+	if dbErr != nil && dbErr.ErrorType != e.DbNotFound {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Register verify user")
 		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
-	fmt.Println(time.Now())
-	fmt.Println(existVerificationToken.ExpiresAt)
-
-	// Удаляем токен, если он протух. Пользователю нужно отправлять запрос еще раз.
-	if time.Now().After(existVerificationToken.ExpiresAt) {
-		verificationToken.Delete(map[string]interface{}{"id": existVerificationToken.ID})
-		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired verification token.")
+	// Протухший токен не находится. Пользователю нужно отправлять запрос еще раз.
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Register verify user")
+		lockout.Fail(keys, attempts, lockoutCfg, logger)
+		return nil, invalidVerificationToken()
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(existVerificationToken.Token), []byte(request.Token)); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Register verify user")
-		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid register verification key")
+		lockout.Fail(keys, attempts, lockoutCfg, logger)
+		return nil, invalidVerificationToken()
 	}
 
+	lockout.Succeed(keys, attempts, logger)
+
 	verified, gwErr := user.UpdateVerificationStatus(context.Background(), request.UserId)
 
 	if gwErr != nil {
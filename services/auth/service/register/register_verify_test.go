@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
 	dMock "warehouseai/auth/dataservice/mocks"
 	e "warehouseai/auth/errors"
 	m "warehouseai/auth/model"
@@ -16,6 +17,16 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var testLockoutCfg = config.LockoutCfg{
+	FreeAttempts:  3,
+	MaxFailures:   10,
+	IpMaxFailures: 100,
+	BaseDelay:     time.Second,
+	MaxDelay:      time.Minute,
+	Window:        time.Hour,
+	LockoutTTL:    15 * time.Minute,
+}
+
 func TestVerifyValidate(t *testing.T) {
 	request := RegisterVerifyRequest{
 		Token:  "someToken",
@@ -46,6 +57,7 @@ func TestRegisterVerify(t *testing.T) {
 	ctl := gomock.NewController(t)
 	repositoryMock := dMock.NewMockVerificationTokenInterface(ctl)
 	grpcMock := aMock.NewMockUserGrpcInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
 	log := logrus.New()
 
 	plainTokenPayload := "some-token"
@@ -64,12 +76,49 @@ func TestRegisterVerify(t *testing.T) {
 		UserId: existToken.UserId,
 	}
 
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(2)
 	repositoryMock.EXPECT().Get(map[string]interface{}{"user_id": existToken.UserId}).Return(existToken, nil).Times(1)
+	attemptMock.EXPECT().Reset(context.Background(), "verify:user:"+existToken.UserId).Return(nil).Times(1)
 	grpcMock.EXPECT().UpdateVerificationStatus(context.Background(), request.UserId).Return(true, nil).Times(1)
 	repositoryMock.EXPECT().Delete(map[string]interface{}{"id": existToken.ID}).Return(nil).Times(1)
 
-	resp, err := RegisterVerify(request, grpcMock, repositoryMock, log)
+	resp, err := RegisterVerify(request, grpcMock, repositoryMock, attemptMock, testLockoutCfg, log)
 
 	require.Nil(t, err)
 	require.Equal(t, &RegisterVerifyResponse{Verified: true}, resp)
 }
+
+func TestRegisterVerifyWrongToken(t *testing.T) {
+	ctl := gomock.NewController(t)
+	repositoryMock := dMock.NewMockVerificationTokenInterface(ctl)
+	attemptMock := dMock.NewMockAttemptInterface(ctl)
+	log := logrus.New()
+
+	hashTokenPayload, _ := bcrypt.GenerateFromPassword([]byte("some-token"), 12)
+	existToken := &m.VerificationToken{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserId:    uuid.Must(uuid.NewV4()).String(),
+		Token:     string(hashTokenPayload),
+		ExpiresAt: time.Now().Add(time.Minute * 10),
+	}
+
+	request := RegisterVerifyRequest{Token: "wrong-token", UserId: existToken.UserId, IP: "10.0.0.1"}
+	expErr := e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired verification token.")
+
+	attemptMock.EXPECT().BlockedFor(context.Background(), gomock.Any()).Return(time.Duration(0), nil).Times(4)
+	repositoryMock.EXPECT().Get(map[string]interface{}{"user_id": existToken.UserId}).Return(existToken, nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "verify:user:"+existToken.UserId, testLockoutCfg.Window).Return(int64(4), nil).Times(1)
+	attemptMock.EXPECT().Block(context.Background(), "verify:user:"+existToken.UserId, testLockoutCfg.BaseDelay).Return(nil).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), "verify:ip:10.0.0.1", testLockoutCfg.Window).Return(int64(4), nil).Times(1)
+	attemptMock.EXPECT().Block(context.Background(), "verify:ip:10.0.0.1", testLockoutCfg.BaseDelay).Return(nil).Times(1)
+
+	_, err := RegisterVerify(request, nil, repositoryMock, attemptMock, testLockoutCfg, log)
+	require.Equal(t, expErr, err)
+
+	// Для пользователя без токена ответ тот же
+	repositoryMock.EXPECT().Get(gomock.Any()).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	attemptMock.EXPECT().Fail(context.Background(), gomock.Any(), testLockoutCfg.Window).Return(int64(1), nil).Times(2)
+
+	_, err = RegisterVerify(request, nil, repositoryMock, attemptMock, testLockoutCfg, log)
+	require.Equal(t, expErr, err)
+}
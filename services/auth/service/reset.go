@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"time"
 	"warehouseai/auth/adapter"
 	"warehouseai/auth/adapter/grpc/gen"
+	"warehouseai/auth/config"
 	"warehouseai/auth/dataservice"
 	e "warehouseai/auth/errors"
 	"warehouseai/auth/model"
+	"warehouseai/auth/service/lockout"
+	"warehouseai/auth/service/password"
 
 	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
@@ -25,7 +29,6 @@ type ResetAttemptResponse struct {
 }
 
 type ResetConfirmRequest struct {
-	UserId   string `json:"user_id"`
 	Password string `json:"password"`
 }
 
@@ -37,12 +40,40 @@ type ResetVerifyResponse struct {
 	UserId string `json:"user_id"`
 }
 
-func PasswordReset(request *ResetConfirmRequest, resetTokenId string, user adapter.UserGrpcInterface, resetToken dataservice.ResetTokenInterface, logger *logrus.Logger) (*ResetConfirmResponse, *e.ErrorResponse) {
-	existResetToken, dbErr := resetToken.Get(map[string]interface{}{"id": resetTokenId})
+// Один ответ на несуществующий, протухший и неверный код: перебор не должен отличать их друг от друга
+func invalidResetCode() *e.ErrorResponse {
+	return e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired verification code")
+}
 
-	if dbErr != nil {
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reset password")
-		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+func PasswordReset(
+	request *ResetConfirmRequest,
+	resetTokenId string,
+	verificationCode string,
+	ip string,
+	user adapter.UserGrpcInterface,
+	resetToken dataservice.ResetTokenInterface,
+	session dataservice.SessionInterface,
+	attempts dataservice.AttemptInterface,
+	lockoutCfg config.LockoutCfg,
+	policy *password.Policy,
+	logger *logrus.Logger,
+) (*ResetConfirmResponse, *e.ErrorResponse) {
+	existResetToken, err := checkResetCode(verificationCode, resetTokenId, ip, resetToken, attempts, lockoutCfg, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	existUser, gwErr := user.GetById(context.Background(), existResetToken.UserId.String())
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Reset password")
+		return nil, gwErr
+	}
+
+	// Токен не удаляем: пользователь может сразу прислать другой пароль с тем же кодом
+	if err := policy.Check(request.Password, existUser.Username, existUser.Email); err != nil {
+		return nil, err
 	}
 
 	if err := resetToken.Delete(map[string]interface{}{"id": existResetToken.ID}); err != nil {
@@ -57,40 +88,108 @@ func PasswordReset(request *ResetConfirmRequest, resetTokenId string, user adapt
 		return nil, e.NewErrorResponse(e.HttpInternalError, "Error while hashing new password.")
 	}
 
-	resp, gwErr := user.ResetPassword(context.Background(), &gen.ResetPasswordRequest{UserId: request.UserId, Password: string(hash)})
+	resp, gwErr := user.ResetPassword(context.Background(), &gen.ResetPasswordRequest{UserId: existResetToken.UserId.String(), Password: string(hash)})
 
 	if gwErr != nil {
 		return nil, gwErr
 	}
 
+	// Пароль мог утечь вместе с активными сессиями, завершаем все
+	if _, err := RevokeSessions(resp, "", session, logger); err != nil {
+		return nil, err
+	}
+
 	return &ResetConfirmResponse{UserId: resp}, nil
 }
 
-func VerifyResetCode(verificationCode string, resetTokenId string, resetToken dataservice.ResetTokenInterface, logger *logrus.Logger) (*ResetVerifyResponse, *e.ErrorResponse) {
-	existResetToken, err := resetToken.Get(map[string]interface{}{"id": resetTokenId})
+func VerifyResetCode(
+	verificationCode string,
+	resetTokenId string,
+	ip string,
+	resetToken dataservice.ResetTokenInterface,
+	attempts dataservice.AttemptInterface,
+	lockoutCfg config.LockoutCfg,
+	logger *logrus.Logger,
+) (*ResetVerifyResponse, *e.ErrorResponse) {
+	existResetToken, err := checkResetCode(verificationCode, resetTokenId, ip, resetToken, attempts, lockoutCfg, logger)
 
 	if err != nil {
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Verify reset token")
-		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		return nil, err
+	}
+
+	return &ResetVerifyResponse{UserId: existResetToken.UserId.String()}, nil
+}
+
+// Проверяет код сброса с учётом попыток по токену и по IP.
+// После ResetCodeAttempts неверных кодов токен удаляется, нужно запрашивать новый.
+func checkResetCode(
+	verificationCode string,
+	resetTokenId string,
+	ip string,
+	resetToken dataservice.ResetTokenInterface,
+	attempts dataservice.AttemptInterface,
+	lockoutCfg config.LockoutCfg,
+	logger *logrus.Logger,
+) (*model.ResetToken, *e.ErrorResponse) {
+	if verificationCode == "" || resetTokenId == "" {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "One of the parameters is empty.")
+	}
+
+	tokenKey := lockout.Key{Name: "reset:token:" + resetTokenId, Limit: lockoutCfg.ResetCodeAttempts}
+	keys := []lockout.Key{tokenKey, {Name: "reset:ip:" + ip, Limit: lockoutCfg.IpMaxFailures}}
+
+	if err := lockout.Check(keys, attempts, logger); err != nil {
+		return nil, err
+	}
+
+	existResetToken, dbErr := resetToken.Get(map[string]interface{}{"id": resetTokenId})
+
+	if dbErr != nil && dbErr.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Verify reset token")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Verify reset token")
+		lockout.Fail(keys, attempts, lockoutCfg, logger)
+		return nil, invalidResetCode()
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(existResetToken.Token), []byte(verificationCode)); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Verify reset token")
-		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid verification code.")
+
+		for _, key := range lockout.Fail(keys, attempts, lockoutCfg, logger) {
+			if key.Name == tokenKey.Name {
+				resetToken.Delete(map[string]interface{}{"id": existResetToken.ID})
+			}
+		}
+
+		return nil, invalidResetCode()
 	}
 
-	return &ResetVerifyResponse{UserId: existResetToken.UserId.String()}, nil
+	return existResetToken, nil
 }
 
 func SendResetEmail(req ResetAttemptRequest, resetToken dataservice.ResetTokenInterface, user adapter.UserGrpcInterface, mail adapter.BrokerInterface, logger *logrus.Logger) (*ResetAttemptResponse, *e.ErrorResponse) {
 	existUser, gwErr := user.GetByEmail(context.Background(), req.Email)
 
+	// На неизвестную почту отвечаем так же, как на известную, только письмо не уходит
+	if gwErr != nil && gwErr.ErrorCode == e.HttpNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Send reset token")
+		return &ResetAttemptResponse{TokenId: uuid.Must(uuid.NewV4()).String()}, nil
+	}
+
 	if gwErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Send reset token")
 		return nil, gwErr
 	}
 
-	verificationCode := generateCode(8)
+	verificationCode, codeErr := generateCode(8)
+
+	if codeErr != nil {
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Verification code generation error")
+	}
+
 	hash, bcryptErr := bcrypt.GenerateFromPassword([]byte(verificationCode), 12)
 
 	if bcryptErr != nil {
@@ -102,7 +201,8 @@ func SendResetEmail(req ResetAttemptRequest, resetToken dataservice.ResetTokenIn
 		Token:  string(hash),
 	}
 
-	if err := resetToken.Create(newResetToken); err != nil {
+	// Новый код заменяет предыдущий
+	if err := resetToken.Replace(existUser.Id, newResetToken); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Send reset token")
 		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
@@ -130,14 +230,20 @@ func SendResetEmail(req ResetAttemptRequest, resetToken dataservice.ResetTokenIn
 	return &ResetAttemptResponse{TokenId: newResetToken.ID.String()}, nil
 }
 
-func generateCode(length int) string {
+func generateCode(length int) (string, error) {
 	charset := []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 
 	batch := make([]byte, length)
 
 	for i := range batch {
-		batch[i] = charset[rand.Intn(len(charset))]
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+
+		if err != nil {
+			return "", err
+		}
+
+		batch[i] = charset[n.Int64()]
 	}
 
-	return string(batch)
+	return string(batch), nil
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+type SessionInfo struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// ID сессии - это секрет из cookie, наружу отдаём только его хэш
+func publicSessionId(sessionId string) string {
+	digest := sha256.Sum256([]byte(sessionId))
+
+	return hex.EncodeToString(digest[:16])
+}
+
+func ListSessions(userId string, currentSessionId string, session dataservice.SessionInterface, logger *logrus.Logger) ([]SessionInfo, *e.ErrorResponse) {
+	sessions, err := session.List(context.Background(), userId)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("List sessions")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	result := make([]SessionInfo, 0, len(sessions))
+
+	for _, s := range sessions {
+		result = append(result, SessionInfo{
+			Id:         publicSessionId(s.ID),
+			Device:     s.Payload.Device,
+			UserAgent:  s.Payload.UserAgent,
+			IP:         s.Payload.IP,
+			CreatedAt:  s.Payload.CreatedAt,
+			LastSeenAt: s.Payload.LastSeenAt,
+			Current:    s.ID == currentSessionId,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].LastSeenAt.After(result[j].LastSeenAt) })
+
+	return result, nil
+}
+
+func RevokeSession(userId string, publicId string, session dataservice.SessionInterface, logger *logrus.Logger) *e.ErrorResponse {
+	sessions, err := session.List(context.Background(), userId)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Revoke session")
+		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	for _, s := range sessions {
+		if publicSessionId(s.ID) != publicId {
+			continue
+		}
+
+		if err := session.Delete(context.Background(), s.ID); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Revoke session")
+			return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		}
+
+		return nil
+	}
+
+	return e.NewErrorResponse(e.HttpNotFound, "Session not found")
+}
+
+// Завершает все сессии пользователя, кроме exceptSessionId. Пустой exceptSessionId - завершить все.
+func RevokeSessions(userId string, exceptSessionId string, session dataservice.SessionInterface, logger *logrus.Logger) (*RevokeSessionsResponse, *e.ErrorResponse) {
+	revoked, err := session.DeleteAll(context.Background(), userId, exceptSessionId)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Revoke sessions")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return &RevokeSessionsResponse{Revoked: revoked}, nil
+}
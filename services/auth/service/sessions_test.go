@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func testSessions(userId string) []m.Session {
+	now := time.Now()
+
+	return []m.Session{
+		{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId, Device: "Chrome on Windows", LastSeenAt: now.Add(-time.Hour)}},
+		{ID: uuid.Must(uuid.NewV4()).String(), Payload: m.SessionPayload{UserId: userId, Device: "Safari on iPhone", LastSeenAt: now}},
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	sessions := testSessions(userId)
+
+	sessionMock.EXPECT().List(context.Background(), userId).Return(sessions, nil).Times(1)
+
+	response, err := ListSessions(userId, sessions[0].ID, sessionMock, logger)
+
+	require.Nil(t, err)
+	require.Len(t, response, 2)
+
+	// Сначала последняя активная, ID сессии наружу не отдаётся
+	require.Equal(t, "Safari on iPhone", response[0].Device)
+	require.False(t, response[0].Current)
+	require.True(t, response[1].Current)
+	require.Equal(t, publicSessionId(sessions[0].ID), response[1].Id)
+	require.NotEqual(t, sessions[0].ID, response[1].Id)
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	sessions := testSessions(userId)
+
+	sessionMock.EXPECT().List(context.Background(), userId).Return(sessions, nil).Times(2)
+	sessionMock.EXPECT().Delete(context.Background(), sessions[1].ID).Return(nil).Times(1)
+
+	require.Nil(t, RevokeSession(userId, publicSessionId(sessions[1].ID), sessionMock, logger))
+
+	// Чужую или несуществующую сессию удалить нельзя
+	require.Equal(t, e.NewErrorResponse(e.HttpNotFound, "Session not found"), RevokeSession(userId, publicSessionId(uuid.Must(uuid.NewV4()).String()), sessionMock, logger))
+}
+
+func TestRevokeSessions(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	sessionMock := dMock.NewMockSessionInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	currentId := uuid.Must(uuid.NewV4()).String()
+
+	sessionMock.EXPECT().DeleteAll(context.Background(), userId, currentId).Return(int64(3), nil).Times(1)
+	sessionMock.EXPECT().DeleteAll(context.Background(), userId, "").Return(int64(0), e.NewDBError(e.DbSystem, "Something went wrong.", "")).Times(1)
+
+	response, err := RevokeSessions(userId, currentId, sessionMock, logger)
+
+	require.Nil(t, err)
+	require.Equal(t, &RevokeSessionsResponse{Revoked: 3}, response)
+
+	response, err = RevokeSessions(userId, "", sessionMock, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponseFromDBError(e.DbSystem, "Something went wrong."), err)
+}
@@ -0,0 +1,111 @@
+package twofactor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	m "warehouseai/auth/model"
+)
+
+// Параметры RFC 6238 по умолчанию - их понимают все приложения-аутентификаторы
+const (
+	period = 30
+	digits = 6
+	// Сколько соседних шагов принимаем из-за расхождения часов
+	skew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 20)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+func otpauthURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(period))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
+
+// HOTP (RFC 4226) для шага времени
+func generateCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0F
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7FFFFFFF
+
+	return fmt.Sprintf("%0*d", digits, value%1_000_000)
+}
+
+// Возвращает шаг, которому соответствует код, с учётом skew
+func matchStep(secret string, code string, now time.Time) (int64, bool) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil || len(code) != digits {
+		return 0, false
+	}
+
+	current := now.Unix() / period
+
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// Коды восстановления имеют высокую энтропию, поэтому для хранения достаточно SHA-256
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	digest := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(digest[:])
+}
+
+func generateRecoveryCodes(userId string, count int) ([]string, []m.RecoveryCode, error) {
+	codes := make([]string, 0, count)
+	hashed := make([]m.RecoveryCode, 0, count)
+
+	for i := 0; i < count; i++ {
+		randomBytes := make([]byte, 5)
+
+		if _, err := rand.Read(randomBytes); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(secretEncoding.EncodeToString(randomBytes))
+		code := raw[:4] + "-" + raw[4:]
+
+		codes = append(codes, code)
+		hashed = append(hashed, m.RecoveryCode{UserId: userId, CodeHash: hashRecoveryCode(code)})
+	}
+
+	return codes, hashed, nil
+}
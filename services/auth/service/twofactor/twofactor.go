@@ -0,0 +1,210 @@
+package twofactor
+
+import (
+	"context"
+	"time"
+	"warehouseai/auth/adapter"
+	"warehouseai/auth/config"
+	"warehouseai/auth/dataservice"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type EnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauth_uri"`
+}
+
+type ConfirmRequest struct {
+	Code string `json:"code"`
+}
+
+type ConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type DisableRequest struct {
+	Password string `json:"password"`
+}
+
+// Начинает подключение 2FA. Повторный вызов до подтверждения выдаёт новый секрет.
+func Enroll(
+	userId string,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	user adapter.UserGrpcInterface,
+	cfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*EnrollResponse, *e.ErrorResponse) {
+	existTwoFactor, dbErr := twoFactorRepository.Get(userId)
+
+	if dbErr != nil && dbErr.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Enroll 2FA")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if existTwoFactor != nil && existTwoFactor.Enabled {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Two-factor authentication is already enabled")
+	}
+
+	existUser, gwErr := user.GetById(context.Background(), userId)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Enroll 2FA")
+		return nil, gwErr
+	}
+
+	secret, err := generateSecret()
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Enroll 2FA")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to generate secret")
+	}
+
+	if dbErr := twoFactorRepository.Save(&m.TwoFactor{UserId: userId, Secret: secret}); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Enroll 2FA")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return &EnrollResponse{Secret: secret, OtpauthUri: otpauthURI(cfg.Issuer, existUser.Email, secret)}, nil
+}
+
+// Подтверждает подключение первым кодом из приложения и выдаёт коды восстановления.
+// Коды показываются один раз, храним только хэши.
+func Confirm(
+	userId string,
+	req *ConfirmRequest,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	cfg config.TwoFactorCfg,
+	logger *logrus.Logger,
+) (*ConfirmResponse, *e.ErrorResponse) {
+	existTwoFactor, dbErr := twoFactorRepository.Get(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Confirm 2FA")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Start two-factor enrollment first")
+		}
+
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if existTwoFactor.Enabled {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Two-factor authentication is already enabled")
+	}
+
+	step, ok := matchStep(existTwoFactor.Secret, req.Code, time.Now())
+
+	if !ok {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid code")
+	}
+
+	codes, hashed, err := generateRecoveryCodes(userId, cfg.RecoveryCodes)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Confirm 2FA")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to generate recovery codes")
+	}
+
+	if dbErr := twoFactorRepository.Enable(userId, step, hashed); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Confirm 2FA")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return &ConfirmResponse{RecoveryCodes: codes}, nil
+}
+
+func Disable(
+	userId string,
+	req *DisableRequest,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	user adapter.UserGrpcInterface,
+	logger *logrus.Logger,
+) *e.ErrorResponse {
+	existUser, gwErr := user.GetById(context.Background(), userId)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Disable 2FA")
+		return gwErr
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(req.Password)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Disable 2FA")
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials")
+	}
+
+	if dbErr := twoFactorRepository.Delete(userId); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Disable 2FA")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Проверяет второй фактор при входе: код из приложения или одноразовый код восстановления
+func CheckCode(
+	userId string,
+	code string,
+	recoveryCode string,
+	twoFactorRepository dataservice.TwoFactorInterface,
+	logger *logrus.Logger,
+) *e.ErrorResponse {
+	if recoveryCode != "" {
+		if dbErr := twoFactorRepository.UseRecoveryCode(userId, hashRecoveryCode(recoveryCode)); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Check 2FA")
+
+			if dbErr.ErrorType == e.DbNotFound {
+				return e.NewErrorResponse(e.HttpUnauthorized, "Invalid code")
+			}
+
+			return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+		}
+
+		return nil
+	}
+
+	existTwoFactor, dbErr := twoFactorRepository.Get(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Check 2FA")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	step, ok := matchStep(existTwoFactor.Secret, code, time.Now())
+
+	if !ok || step <= existTwoFactor.LastUsedStep {
+		return e.NewErrorResponse(e.HttpUnauthorized, "Invalid code")
+	}
+
+	// Защита от повторного использования: шаг сдвигается атомарно, параллельный вход с тем же кодом не пройдёт
+	if dbErr := twoFactorRepository.UseStep(userId, step); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Check 2FA")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return e.NewErrorResponse(e.HttpUnauthorized, "Invalid code")
+		}
+
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Включена ли 2FA у пользователя. Незавершённое подключение не считается.
+func IsEnabled(userId string, twoFactorRepository dataservice.TwoFactorInterface, logger *logrus.Logger) (bool, *e.ErrorResponse) {
+	existTwoFactor, dbErr := twoFactorRepository.Get(userId)
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return false, nil
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Check 2FA")
+		return false, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return existTwoFactor.Enabled, nil
+}
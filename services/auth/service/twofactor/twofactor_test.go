@@ -0,0 +1,203 @@
+package twofactor
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"warehouseai/auth/adapter/grpc/gen"
+	aMock "warehouseai/auth/adapter/mocks"
+	"warehouseai/auth/config"
+	dMock "warehouseai/auth/dataservice/mocks"
+	e "warehouseai/auth/errors"
+	m "warehouseai/auth/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testCfg = config.TwoFactorCfg{Issuer: "WarehouseAI", PendingTTL: 5 * time.Minute, MaxAttempts: 5, RecoveryCodes: 10}
+
+func codeAt(t *testing.T, secret string, step int64) string {
+	key, err := secretEncoding.DecodeString(secret)
+	require.NoError(t, err)
+
+	return generateCode(key, step)
+}
+
+// Тестовые векторы RFC 6238 (SHA1), последние 6 цифр
+func TestGenerateCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range cases {
+		require.Equal(t, expected, generateCode(key, unix/period))
+	}
+}
+
+func TestMatchStep(t *testing.T) {
+	secret, err := generateSecret()
+	require.NoError(t, err)
+
+	key, _ := secretEncoding.DecodeString(secret)
+	now := time.Now()
+	current := now.Unix() / period
+
+	step, ok := matchStep(secret, generateCode(key, current-1), now)
+	require.True(t, ok)
+	require.Equal(t, current-1, step)
+
+	_, ok = matchStep(secret, generateCode(key, current-3), now)
+	require.False(t, ok)
+
+	_, ok = matchStep(secret, "12345", now)
+	require.False(t, ok)
+}
+
+func TestEnroll(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Email: "user@mail.com"}
+	var saved *m.TwoFactor
+
+	twoFactorMock.EXPECT().Get(existUser.Id).Return(nil, e.NewDBError(e.DbNotFound, "Entity not found.", "")).Times(1)
+	userMock.EXPECT().GetById(context.Background(), existUser.Id).Return(existUser, nil).Times(1)
+	twoFactorMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(twoFactor *m.TwoFactor) *e.DBError {
+		saved = twoFactor
+		return nil
+	}).Times(1)
+
+	response, err := Enroll(existUser.Id, twoFactorMock, userMock, testCfg, logger)
+
+	require.Nil(t, err)
+	require.False(t, saved.Enabled)
+	require.Equal(t, saved.Secret, response.Secret)
+
+	uri, parseErr := url.Parse(response.OtpauthUri)
+	require.NoError(t, parseErr)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/WarehouseAI:user@mail.com", uri.Path)
+	require.Equal(t, response.Secret, uri.Query().Get("secret"))
+	require.Equal(t, "WarehouseAI", uri.Query().Get("issuer"))
+}
+
+func TestEnrollAlreadyEnabled(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Enabled: true}, nil).Times(1)
+
+	response, err := Enroll(userId, twoFactorMock, userMock, testCfg, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpAlreadyExist, "Two-factor authentication is already enabled"), err)
+}
+
+func TestConfirm(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	secret, _ := generateSecret()
+	step := time.Now().Unix() / period
+	var storedCodes []m.RecoveryCode
+
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Secret: secret}, nil).Times(1)
+	twoFactorMock.EXPECT().Enable(userId, step, gomock.Any()).DoAndReturn(func(_ string, _ int64, codes []m.RecoveryCode) *e.DBError {
+		storedCodes = codes
+		return nil
+	}).Times(1)
+
+	response, err := Confirm(userId, &ConfirmRequest{Code: codeAt(t, secret, step)}, twoFactorMock, testCfg, logger)
+
+	require.Nil(t, err)
+	require.Len(t, response.RecoveryCodes, testCfg.RecoveryCodes)
+	require.Len(t, storedCodes, testCfg.RecoveryCodes)
+
+	for i, code := range response.RecoveryCodes {
+		require.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		require.Equal(t, hashRecoveryCode(code), storedCodes[i].CodeHash)
+	}
+
+	// Код восстановления принимается в любом регистре и без дефиса
+	require.Equal(t, hashRecoveryCode(response.RecoveryCodes[0]), hashRecoveryCode(strings.ToUpper(strings.ReplaceAll(response.RecoveryCodes[0], "-", ""))))
+}
+
+func TestConfirmInvalidCode(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	secret, _ := generateSecret()
+
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Secret: secret}, nil).Times(1)
+
+	response, err := Confirm(userId, &ConfirmRequest{Code: "000000x"}, twoFactorMock, testCfg, logger)
+
+	require.Nil(t, response)
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid code"), err)
+}
+
+func TestCheckCode(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	secret, _ := generateSecret()
+	step := time.Now().Unix() / period
+	code := codeAt(t, secret, step)
+
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Secret: secret, Enabled: true}, nil).Times(1)
+	twoFactorMock.EXPECT().UseStep(userId, step).Return(nil).Times(1)
+
+	require.Nil(t, CheckCode(userId, code, "", twoFactorMock, logger))
+
+	// Тот же код второй раз не принимается
+	twoFactorMock.EXPECT().Get(userId).Return(&m.TwoFactor{UserId: userId, Secret: secret, Enabled: true, LastUsedStep: step}, nil).Times(1)
+
+	require.Equal(t, e.NewErrorResponse(e.HttpUnauthorized, "Invalid code"), CheckCode(userId, code, "", twoFactorMock, logger))
+}
+
+func TestDisable(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	twoFactorMock := dMock.NewMockTwoFactorInterface(ctl)
+	userMock := aMock.NewMockUserGrpcInterface(ctl)
+	logger := logrus.New()
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("12345678"), 12)
+	existUser := &gen.User{Id: uuid.Must(uuid.NewV4()).String(), Password: string(hash)}
+
+	userMock.EXPECT().GetById(context.Background(), existUser.Id).Return(existUser, nil).Times(2)
+	twoFactorMock.EXPECT().Delete(existUser.Id).Return(nil).Times(1)
+
+	require.Equal(t, e.NewErrorResponse(e.HttpBadRequest, "Invalid credentials"), Disable(existUser.Id, &DisableRequest{Password: "wrong-password"}, twoFactorMock, userMock, logger))
+	require.Nil(t, Disable(existUser.Id, &DisableRequest{Password: "12345678"}, twoFactorMock, userMock, logger))
+}
@@ -0,0 +1,12 @@
+package adapter
+
+import (
+	"warehouseai/stat/adapter/grpc/gen"
+	e "warehouseai/stat/errors"
+	m "warehouseai/stat/model"
+)
+
+type AuthGrpcInterface interface {
+	Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.ErrorResponse)
+	VerifyAccessToken(token string) (*m.AccessClaims, *e.ErrorResponse)
+}
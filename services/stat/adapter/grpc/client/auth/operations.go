@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+	"time"
+	"warehouseai/stat/adapter/grpc/gen"
+	e "warehouseai/stat/errors"
+	m "warehouseai/stat/model"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type AuthGrpcClient struct {
+	conn *grpc.ClientConn
+
+	// Публичные ключи auth для локальной проверки access-токенов
+	keysMu        sync.Mutex
+	keys          map[string]ed25519.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewAuthGrpcClient(grpcUrl string) *AuthGrpcClient {
+	conn, err := grpc.Dial(grpcUrl, grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &AuthGrpcClient{
+		conn: conn,
+	}
+}
+
+func (c *AuthGrpcClient) Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.ErrorResponse) {
+	client := gen.NewAuthServiceClient(c.conn)
+	resp, err := client.Authenticate(context.Background(), &gen.AuthenticationRequest{SessionId: sessionId})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+
+		if s.Code() == codes.Aborted {
+			return nil, e.NewErrorResponse(e.HttpUnauthorized, s.Message())
+		}
+
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return resp, nil
+}
+
+// Проверяет access-токен без обращения к auth (кроме редкого обновления ключей)
+func (c *AuthGrpcClient) VerifyAccessToken(token string) (*m.AccessClaims, *e.ErrorResponse) {
+	claims, err := verifyAccessToken(token, c.publicKey, time.Now())
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpUnauthorized, err.Error())
+	}
+
+	return claims, nil
+}
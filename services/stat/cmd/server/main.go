@@ -1,8 +1,12 @@
 package server
 
 import (
+	"warehouseai/stat/adapter/grpc/client/auth"
+	"warehouseai/stat/config"
 	"warehouseai/stat/dataservice/statdata"
+	"warehouseai/stat/model"
 	h "warehouseai/stat/server/handlers"
+	"warehouseai/stat/server/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -13,31 +17,36 @@ func StartServer(port string, statDb *statdata.Database, logger *logrus.Logger)
 	handler := newHttpHandler(statDb, logger)
 	app := fiber.New()
 	app.Use(setupCORS())
+	app.Use(middleware.Csrf(middleware.AccessTokenVerifier(handler.AuthClient)))
 
 	//Todo: сделать ручки для принятия проведённого времени на сайте конкретным порльзователем
 	//Todo: сделать ручку для изменения флага дневной активности пользователя
 	//Todo: сделать ручку увеличения количества просмотров каждой нейронки
 	//Todo: сделать ручку для трекинга количестваа активной аудитории в данный момент времени
 	//Todo: перенести логику трекинга количества использований каждой нейронки сюда
+	sessionMw := middleware.Session(logger, handler.AuthClient, config.NewCookieCfg())
+	readStatMw := middleware.RequirePermission(model.PermissionReadStat)
+
 	route := app.Group("/stat")
-	route.Get("user/num", handler.GetNumOfUsersHandler)
-	route.Get("developer/num", handler.GetNumOfDevelopersHandler)
-	route.Get("ai/uses", handler.GetNumOfAiUsesHandler)
+	route.Get("user/num", sessionMw, readStatMw, handler.GetNumOfUsersHandler)
+	route.Get("developer/num", sessionMw, readStatMw, handler.GetNumOfDevelopersHandler)
+	route.Get("ai/uses", sessionMw, readStatMw, handler.GetNumOfAiUsesHandler)
 
 	return app.Listen(port)
 }
 
 func newHttpHandler(db *statdata.Database, logger *logrus.Logger) *h.Handler {
 	return &h.Handler{
-		DB:     db,
-		Logger: logger,
+		DB:         db,
+		Logger:     logger,
+		AuthClient: auth.NewAuthGrpcClient("auth:8041"),
 	}
 }
 
 // TODO: Подумать над тем, что может нужно что-то поменять
 func setupCORS() func(*fiber.Ctx) error {
 	return cors.New(cors.Config{
-		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,X-CSRF-Token",
 		AllowOrigins:     "http://localhost:3000, https://warehouse-ai-frontend.vercel.app",
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
@@ -30,7 +30,7 @@ func (d *Database) GetNumOfUsers() (uint, *e.DBError) {
 func (d *Database) GetNumOfDevelopers() (uint, *e.DBError) {
 	var num uint
 
-	if err := d.DB.Model(m.User{}).Select("COUNT(*)").Where("role = ?", "Developer").Find(&num).Error; err != nil {
+	if err := d.DB.Model(m.User{}).Select("COUNT(*)").Where("is_developer = ?", true).Scan(&num).Error; err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return 0, e.NewDBError(e.DbSystem, "Something went wrong", err.Error())
 		}
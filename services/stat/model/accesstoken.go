@@ -0,0 +1,13 @@
+package model
+
+// Полезная нагрузка access-токена, выпущенного сервисом auth
+type AccessClaims struct {
+	Id          string   `json:"jti"`
+	Issuer      string   `json:"iss"`
+	Subject     string   `json:"sub"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"perms"`
+	IsDeveloper bool     `json:"dev"`
+	IssuedAt    int64    `json:"iat"`
+	ExpiresAt   int64    `json:"exp"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// Права выдаются сервисом пользователей и приходят в access-токене
+type Permission string
+
+const (
+	PermissionReadStat Permission = "stat:read"
+)
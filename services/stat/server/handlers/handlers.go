@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"warehouseai/stat/adapter/grpc/client/auth"
 	"warehouseai/stat/dataservice/statdata"
 	"warehouseai/stat/service"
 
@@ -10,8 +11,9 @@ import (
 )
 
 type Handler struct {
-	DB     *statdata.Database
-	Logger *logrus.Logger
+	DB         *statdata.Database
+	Logger     *logrus.Logger
+	AuthClient *auth.AuthGrpcClient
 }
 
 func (h *Handler) GetNumOfUsersHandler(c *fiber.Ctx) error {
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"time"
+	"warehouseai/stat/adapter"
+	"warehouseai/stat/config"
+	e "warehouseai/stat/errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func Session(logger *logrus.Logger, auth adapter.AuthGrpcInterface, cookie config.CookieCfg) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		token := BearerToken(c)
+
+		if token == "" {
+			token = c.Cookies("accessToken")
+		}
+
+		// Access-токен (из Authorization или cookie) проверяется локально. Если его нет или он истёк, идём в auth по gRPC.
+		if claims, err := auth.VerifyAccessToken(token); err == nil {
+			c.Locals("userId", claims.Subject)
+			c.Locals("roles", claims.Roles)
+			c.Locals("permissions", claims.Permissions)
+			return c.Next()
+		}
+
+		sessionId := c.Cookies("sessionId")
+
+		if sessionId == "" {
+			return c.Status(e.HttpUnauthorized).JSON(e.NewErrorResponse(e.HttpUnauthorized, "Empty session key."))
+		}
+
+		resp, authErr := auth.Authenticate(sessionId)
+
+		if authErr != nil {
+			return c.Status(authErr.ErrorCode).JSON(authErr)
+		}
+
+		// Сессия продлевается на стороне auth, cookie меняется только после ротации ID
+		if resp.SessionId != sessionId {
+			c.Cookie(&fiber.Cookie{
+				Name:     "sessionId",
+				Value:    resp.SessionId,
+				SameSite: cookie.SameSite,
+				Secure:   true,
+			})
+		}
+
+		c.Locals("userId", resp.UserId)
+		c.Locals("roles", resp.Roles)
+		c.Locals("permissions", resp.Permissions)
+
+		if claims, err := auth.VerifyAccessToken(resp.AccessToken); err == nil {
+			c.Cookie(&fiber.Cookie{
+				Name:     "accessToken",
+				Value:    resp.AccessToken,
+				Expires:  time.Unix(claims.ExpiresAt, 0),
+				HTTPOnly: true,
+				SameSite: cookie.SameSite,
+				Secure:   true,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// Для Csrf: Bearer-токен засчитывается, только если его подпись и срок действительны
+func AccessTokenVerifier(auth adapter.AuthGrpcInterface) func(token string) bool {
+	return func(token string) bool {
+		_, err := auth.VerifyAccessToken(token)
+		return err == nil
+	}
+}
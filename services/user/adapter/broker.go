@@ -5,3 +5,8 @@ import "warehouseai/user/model"
 type MailProducerInterface interface {
 	SendEmail(email model.Email) error
 }
+
+// Команды саги удаления аккаунта
+type DeletionProducerInterface interface {
+	SendDeletionCommand(command model.AccountDeletionCommand) error
+}
@@ -3,7 +3,9 @@ package broker
 import (
 	"context"
 	"encoding/json"
+	"time"
 	"warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
 	m "warehouseai/user/model"
 	"warehouseai/user/service"
 
@@ -44,6 +46,167 @@ func (b Broker) SendEmail(email m.Email) error {
 	return nil
 }
 
+// auth отвечает и за сессии, и за хранилище аватаров
+var deletionQueues = map[m.DeletionStep]m.QueueName{
+	m.DeletionStepAuth:   m.AccountDeleteAuth,
+	m.DeletionStepAvatar: m.AccountDeleteAuth,
+	m.DeletionStepAi:     m.AccountDeleteAi,
+}
+
+func (b Broker) SendDeletionCommand(command m.AccountDeletionCommand) error {
+	messageStr, err := json.Marshal(command)
+
+	if err != nil {
+		return err
+	}
+
+	if err := b.Channel.PublishWithContext(
+		context.Background(),
+		"",
+		string(deletionQueues[command.Step]),
+		false,
+		false,
+		rmq.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: rmq.Persistent,
+			Body:         []byte(messageStr),
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Ответы сервисов на шаги саги удаления аккаунта
+func (b Broker) ReceiveDeletionReply(userRepository dataservice.UserInterface, deletionRepository dataservice.DeletionInterface, logger *logrus.Logger) {
+	messages, err := b.Channel.Consume(
+		string(m.AccountDeleteReply),
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for message := range messages {
+		var reply m.AccountDeletionReply
+
+		if err := json.Unmarshal(message.Body, &reply); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Account deletion reply")
+			message.Nack(false, false)
+			continue
+		}
+
+		service.HandleDeletionReply(reply, userRepository, deletionRepository, b, b, logger)
+		message.Ack(false)
+	}
+}
+
+// Ошибка базы возвращает событие в очередь, некорректные события отбрасываются
+func (b Broker) ReceiveOwnershipEvent(ownedRepository dataservice.OwnedInterface, logger *logrus.Logger) {
+	messages, err := b.Channel.Consume(
+		string(m.AiOwnership),
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for message := range messages {
+		var event m.AiOwnershipEvent
+
+		if err := json.Unmarshal(message.Body, &event); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("AI ownership event")
+			message.Nack(false, false)
+			continue
+		}
+
+		if err := service.HandleOwnershipEvent(event, ownedRepository, logger); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("AI ownership event")
+			message.Nack(false, err.ErrorCode == e.HttpInternalError)
+			continue
+		}
+
+		message.Ack(false)
+	}
+}
+
+// Рассылает подписчикам уведомления о публикациях разработчиков. Повторная доставка не создаёт дублей.
+func (b Broker) ReceiveReleaseEvent(followRepository dataservice.FollowInterface, notificationRepository dataservice.NotificationInterface, hub *service.NotificationHub, logger *logrus.Logger) {
+	b.consumeNotificationEvents(m.AiRelease, "AI release event", logger, func(body []byte) *e.ErrorResponse {
+		var event m.AiReleaseEvent
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return e.NewErrorResponse(e.HttpBadRequest, err.Error())
+		}
+
+		return service.HandleReleaseEvent(event, followRepository, notificationRepository, hub, logger)
+	})
+}
+
+func (b Broker) ReceiveRatingEvent(notificationRepository dataservice.NotificationInterface, hub *service.NotificationHub, logger *logrus.Logger) {
+	b.consumeNotificationEvents(m.AiRating, "AI rating event", logger, func(body []byte) *e.ErrorResponse {
+		var event m.AiRatingEvent
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return e.NewErrorResponse(e.HttpBadRequest, err.Error())
+		}
+
+		return service.HandleRatingEvent(event, notificationRepository, hub, logger)
+	})
+}
+
+func (b Broker) ReceiveSecurityAlert(notificationRepository dataservice.NotificationInterface, hub *service.NotificationHub, logger *logrus.Logger) {
+	b.consumeNotificationEvents(m.SecurityAlert, "Security alert", logger, func(body []byte) *e.ErrorResponse {
+		var event m.SecurityAlertEvent
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return e.NewErrorResponse(e.HttpBadRequest, err.Error())
+		}
+
+		return service.HandleSecurityAlert(event, notificationRepository, hub, logger)
+	})
+}
+
+// Ошибка базы возвращает событие в очередь, некорректные события и события удалённых пользователей отбрасываются
+func (b Broker) consumeNotificationEvents(queue m.QueueName, operation string, logger *logrus.Logger, handle func(body []byte) *e.ErrorResponse) {
+	messages, err := b.Channel.Consume(
+		string(queue),
+		"",
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for message := range messages {
+		if err := handle(message.Body); err != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info(operation)
+			message.Nack(false, err.ErrorCode == e.HttpInternalError)
+			continue
+		}
+
+		message.Ack(false)
+	}
+}
+
 func (b Broker) ReceiveTokenReject(userRepository dataservice.UserInterface, logger *logrus.Logger) {
 	messages, err := b.Channel.Consume(
 		b.SagaQueues[m.Reject].Name,
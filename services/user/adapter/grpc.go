@@ -1,11 +1,23 @@
 package adapter
 
-import e "warehouseai/user/errors"
+import (
+	"warehouseai/user/adapter/grpc/gen"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+)
 
 type AuthGrpcInterface interface {
-	Authenticate(sessionId string) (string, string, *e.ErrorResponse)
+	Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.ErrorResponse)
+	VerifyAccessToken(token string) (*m.AccessClaims, *e.ErrorResponse)
+	CheckSession(sessionId string) (*m.SessionState, *e.ErrorResponse)
+	RevokeSessions(userId string, exceptSessionId string) *e.ErrorResponse
+	ValidatePassword(password string, username string, email string) *e.ErrorResponse
+	RecordAuditEvent(event m.AuditEvent) *e.ErrorResponse
+	GetUserAuditEvents(userId string, email string) ([]m.ExportAuditEvent, *e.ErrorResponse)
 }
 
 type AiGrpcInterface interface {
 	GetById(aiId string) (string, *e.ErrorResponse)
+	GetUserData(userId string) (*m.AiUserData, *e.ErrorResponse)
+	GetPublishedAis(ids []string) ([]m.PublicAi, *e.ErrorResponse)
 }
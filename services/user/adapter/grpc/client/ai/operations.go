@@ -4,6 +4,7 @@ import (
 	"context"
 	"warehouseai/user/adapter/grpc/gen"
 	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -43,3 +44,60 @@ func (c *AiGrpcClient) GetById(aiId string) (string, *e.ErrorResponse) {
 
 	return resp.Id, nil
 }
+
+func (c *AiGrpcClient) GetUserData(userId string) (*m.AiUserData, *e.ErrorResponse) {
+	client := gen.NewAiServiceClient(c.conn)
+	resp, err := client.GetUserData(context.Background(), &gen.UserDataRequest{UserId: userId})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	data := &m.AiUserData{
+		Owned:      make([]m.ExportOwnedAi, 0, len(resp.Owned)),
+		Ratings:    make([]m.ExportRating, 0, len(resp.Ratings)),
+		Executions: make([]m.ExportExecution, 0, len(resp.Executions)),
+	}
+
+	for _, ai := range resp.Owned {
+		data.Owned = append(data.Owned, m.ExportOwnedAi{Id: ai.Id, Name: ai.Name, Description: ai.Description, Used: ai.Used, CreatedAt: ai.CreatedAt})
+	}
+
+	for _, rate := range resp.Ratings {
+		data.Ratings = append(data.Ratings, m.ExportRating{AiId: rate.AiId, Rate: rate.Rate, VerifiedUsage: rate.VerifiedUsage, CreatedAt: rate.CreatedAt})
+	}
+
+	for _, execution := range resp.Executions {
+		data.Executions = append(data.Executions, m.ExportExecution{Id: execution.Id, AiId: execution.AiId, CommandId: execution.CommandId, CreatedAt: execution.CreatedAt})
+	}
+
+	return data, nil
+}
+
+func (c *AiGrpcClient) GetPublishedAis(ids []string) ([]m.PublicAi, *e.ErrorResponse) {
+	client := gen.NewAiServiceClient(c.conn)
+	resp, err := client.GetPublishedAis(context.Background(), &gen.PublishedAisRequest{Ids: ids})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	ais := make([]m.PublicAi, 0, len(resp.Ais))
+
+	for _, ai := range resp.Ais {
+		ais = append(ais, m.PublicAi{
+			Id:            ai.Id,
+			Name:          ai.Name,
+			Description:   ai.Description,
+			BackgroundUrl: ai.BackgroundUrl,
+			Used:          ai.Used,
+			AverageRating: ai.AvgRating,
+			RatingCount:   ai.CountRating,
+			CreatedAt:     ai.CreatedAt,
+		})
+	}
+
+	return ais, nil
+}
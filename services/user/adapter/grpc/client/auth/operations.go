@@ -2,8 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"sync"
+	"time"
 	"warehouseai/user/adapter/grpc/gen"
 	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -13,6 +17,11 @@ import (
 
 type AuthGrpcClient struct {
 	conn *grpc.ClientConn
+
+	// Публичные ключи auth для локальной проверки access-токенов
+	keysMu        sync.Mutex
+	keys          map[string]ed25519.PublicKey
+	keysFetchedAt time.Time
 }
 
 func NewAuthGrpcClient(grpcUrl string) *AuthGrpcClient {
@@ -27,7 +36,7 @@ func NewAuthGrpcClient(grpcUrl string) *AuthGrpcClient {
 	}
 }
 
-func (c *AuthGrpcClient) Authenticate(sessionId string) (string, string, *e.ErrorResponse) {
+func (c *AuthGrpcClient) Authenticate(sessionId string) (*gen.AuthenticationResponse, *e.ErrorResponse) {
 	client := gen.NewAuthServiceClient(c.conn)
 	resp, err := client.Authenticate(context.Background(), &gen.AuthenticationRequest{SessionId: sessionId})
 
@@ -35,11 +44,126 @@ func (c *AuthGrpcClient) Authenticate(sessionId string) (string, string, *e.Erro
 		s, _ := status.FromError(err)
 
 		if s.Code() == codes.Aborted {
-			return "", "", e.NewErrorResponse(e.HttpUnauthorized, s.Message())
+			return nil, e.NewErrorResponse(e.HttpUnauthorized, s.Message())
+		}
+
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return resp, nil
+}
+
+// Проверяет сессию, не продлевая её: потоку нельзя менять cookie, поэтому Authenticate ему не подходит
+func (c *AuthGrpcClient) CheckSession(sessionId string) (*m.SessionState, *e.ErrorResponse) {
+	client := gen.NewAuthServiceClient(c.conn)
+	resp, err := client.CheckSession(context.Background(), &gen.CheckSessionRequest{SessionId: sessionId})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+
+		if s.Code() == codes.Aborted {
+			return nil, e.NewErrorResponse(e.HttpUnauthorized, s.Message())
 		}
 
-		return "", "", e.NewErrorResponse(e.HttpInternalError, s.Message())
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return &m.SessionState{UserId: resp.UserId, SessionId: resp.SessionId, ExpiresAt: time.Unix(resp.ExpiresAt, 0)}, nil
+}
+
+// Проверяет access-токен без обращения к auth (кроме редкого обновления ключей)
+func (c *AuthGrpcClient) VerifyAccessToken(token string) (*m.AccessClaims, *e.ErrorResponse) {
+	claims, err := verifyAccessToken(token, c.publicKey, time.Now())
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpUnauthorized, err.Error())
+	}
+
+	return claims, nil
+}
+
+// Завершает сессии пользователя, кроме exceptSessionId
+func (c *AuthGrpcClient) RevokeSessions(userId string, exceptSessionId string) *e.ErrorResponse {
+	client := gen.NewAuthServiceClient(c.conn)
+	_, err := client.RevokeUserSessions(context.Background(), &gen.RevokeUserSessionsRequest{UserId: userId, ExceptSessionId: exceptSessionId})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return nil
+}
+
+// Политика паролей живёт в auth. Нарушения возвращаются списком в ошибке 400.
+func (c *AuthGrpcClient) ValidatePassword(password string, username string, email string) *e.ErrorResponse {
+	client := gen.NewAuthServiceClient(c.conn)
+	resp, err := client.ValidatePassword(context.Background(), &gen.ValidatePasswordRequest{Password: password, Username: username, Email: email})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	if len(resp.Violations) == 0 {
+		return nil
+	}
+
+	violations := make([]e.Violation, 0, len(resp.Violations))
+
+	for _, violation := range resp.Violations {
+		violations = append(violations, e.Violation{Rule: violation.Rule, Message: violation.Message})
+	}
+
+	return e.NewValidationErrorResponse("Password does not meet the requirements", violations)
+}
+
+func (c *AuthGrpcClient) RecordAuditEvent(event m.AuditEvent) *e.ErrorResponse {
+	result := "failure"
+
+	if event.Success {
+		result = "success"
+	}
+
+	client := gen.NewAuthServiceClient(c.conn)
+	_, err := client.RecordAuditEvent(context.Background(), &gen.AuditEventRequest{
+		ActorId:   event.ActorId,
+		Action:    string(event.Action),
+		Target:    event.Target,
+		Ip:        event.IP,
+		UserAgent: event.UserAgent,
+		Result:    result,
+	})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	return nil
+}
+
+func (c *AuthGrpcClient) GetUserAuditEvents(userId string, email string) ([]m.ExportAuditEvent, *e.ErrorResponse) {
+	client := gen.NewAuthServiceClient(c.conn)
+	resp, err := client.GetUserAuditEvents(context.Background(), &gen.UserAuditEventsRequest{UserId: userId, Email: email})
+
+	if err != nil {
+		s, _ := status.FromError(err)
+		return nil, e.NewErrorResponse(e.HttpInternalError, s.Message())
+	}
+
+	events := make([]m.ExportAuditEvent, 0, len(resp.Events))
+
+	for _, event := range resp.Events {
+		events = append(events, m.ExportAuditEvent{
+			Action:    event.Action,
+			Target:    event.Target,
+			IP:        event.Ip,
+			UserAgent: event.UserAgent,
+			Result:    event.Result,
+			CreatedAt: event.CreatedAt,
+		})
 	}
 
-	return resp.UserId, resp.SessionId, nil
+	return events, nil
 }
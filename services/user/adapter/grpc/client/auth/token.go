@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"warehouseai/user/adapter/grpc/gen"
+	m "warehouseai/user/model"
+)
+
+const (
+	accessTokenIssuer    = "warehouseai-auth"
+	accessTokenAlgorithm = "EdDSA"
+	clockSkew            = 30 * time.Second
+	// Неизвестный kid - повод перечитать ключи, но не чаще раза в минуту
+	keysRefreshInterval = time.Minute
+)
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Ключ для kid из кэша, при промахе ключи перечитываются из auth
+func (c *AuthGrpcClient) publicKey(kid string) (ed25519.PublicKey, error) {
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(c.keysFetchedAt) < keysRefreshInterval {
+		return nil, errors.New("unknown key id")
+	}
+
+	c.keysFetchedAt = time.Now()
+	client := gen.NewAuthServiceClient(c.conn)
+	resp, err := client.GetPublicKeys(context.Background(), &gen.GetPublicKeysRequest{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(resp.Keys))
+
+	for _, key := range resp.Keys {
+		if key.Alg == accessTokenAlgorithm && len(key.Key) == ed25519.PublicKeySize {
+			keys[key.Kid] = ed25519.PublicKey(key.Key)
+		}
+	}
+
+	c.keys = keys
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, errors.New("unknown key id")
+}
+
+func verifyAccessToken(token string, publicKey func(kid string) (ed25519.PublicKey, error), now time.Time) (*m.AccessClaims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+
+	if err != nil {
+		return nil, errors.New("malformed token header")
+	}
+
+	var header tokenHeader
+
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, errors.New("malformed token header")
+	}
+
+	// Алгоритм фиксирован, значение из заголовка только сверяем
+	if header.Alg != accessTokenAlgorithm {
+		return nil, errors.New("unexpected signing algorithm")
+	}
+
+	key, err := publicKey(header.Kid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil || !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+
+	if err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+
+	var claims m.AccessClaims
+
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+
+	if claims.Issuer != accessTokenIssuer || claims.Subject == "" {
+		return nil, errors.New("invalid token claims")
+	}
+
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return nil, errors.New("token expired")
+	}
+
+	if now.Add(clockSkew).Before(time.Unix(claims.IssuedAt, 0)) {
+		return nil, errors.New("token issued in the future")
+	}
+
+	return &claims, nil
+}
@@ -19,7 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AiService_GetAiById_FullMethodName = "/AiService/GetAiById"
+	AiService_GetAiById_FullMethodName       = "/AiService/GetAiById"
+	AiService_GetUserData_FullMethodName     = "/AiService/GetUserData"
+	AiService_GetPublishedAis_FullMethodName = "/AiService/GetPublishedAis"
 )
 
 // AiServiceClient is the client API for AiService service.
@@ -27,6 +29,8 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AiServiceClient interface {
 	GetAiById(ctx context.Context, in *GetAiByIdMsg, opts ...grpc.CallOption) (*AI, error)
+	GetUserData(ctx context.Context, in *UserDataRequest, opts ...grpc.CallOption) (*UserDataResponse, error)
+	GetPublishedAis(ctx context.Context, in *PublishedAisRequest, opts ...grpc.CallOption) (*PublishedAisResponse, error)
 }
 
 type aiServiceClient struct {
@@ -46,11 +50,31 @@ func (c *aiServiceClient) GetAiById(ctx context.Context, in *GetAiByIdMsg, opts
 	return out, nil
 }
 
+func (c *aiServiceClient) GetUserData(ctx context.Context, in *UserDataRequest, opts ...grpc.CallOption) (*UserDataResponse, error) {
+	out := new(UserDataResponse)
+	err := c.cc.Invoke(ctx, AiService_GetUserData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aiServiceClient) GetPublishedAis(ctx context.Context, in *PublishedAisRequest, opts ...grpc.CallOption) (*PublishedAisResponse, error) {
+	out := new(PublishedAisResponse)
+	err := c.cc.Invoke(ctx, AiService_GetPublishedAis_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AiServiceServer is the server API for AiService service.
 // All implementations must embed UnimplementedAiServiceServer
 // for forward compatibility
 type AiServiceServer interface {
 	GetAiById(context.Context, *GetAiByIdMsg) (*AI, error)
+	GetUserData(context.Context, *UserDataRequest) (*UserDataResponse, error)
+	GetPublishedAis(context.Context, *PublishedAisRequest) (*PublishedAisResponse, error)
 	mustEmbedUnimplementedAiServiceServer()
 }
 
@@ -61,6 +85,12 @@ type UnimplementedAiServiceServer struct {
 func (UnimplementedAiServiceServer) GetAiById(context.Context, *GetAiByIdMsg) (*AI, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAiById not implemented")
 }
+func (UnimplementedAiServiceServer) GetUserData(context.Context, *UserDataRequest) (*UserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserData not implemented")
+}
+func (UnimplementedAiServiceServer) GetPublishedAis(context.Context, *PublishedAisRequest) (*PublishedAisResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublishedAis not implemented")
+}
 func (UnimplementedAiServiceServer) mustEmbedUnimplementedAiServiceServer() {}
 
 // UnsafeAiServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +122,42 @@ func _AiService_GetAiById_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AiService_GetUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).GetUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_GetUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).GetUserData(ctx, req.(*UserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AiService_GetPublishedAis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishedAisRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AiServiceServer).GetPublishedAis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AiService_GetPublishedAis_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AiServiceServer).GetPublishedAis(ctx, req.(*PublishedAisRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AiService_ServiceDesc is the grpc.ServiceDesc for AiService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +169,14 @@ var AiService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAiById",
 			Handler:    _AiService_GetAiById_Handler,
 		},
+		{
+			MethodName: "GetUserData",
+			Handler:    _AiService_GetUserData_Handler,
+		},
+		{
+			MethodName: "GetPublishedAis",
+			Handler:    _AiService_GetPublishedAis_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ai.proto",
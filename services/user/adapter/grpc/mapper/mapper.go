@@ -9,6 +9,7 @@ import (
 )
 
 func UserToProto(m *model.User) *gen.User {
+	roles := m.RoleNames()
 
 	return &gen.User{
 		Id:          m.ID.String(),
@@ -21,8 +22,10 @@ func UserToProto(m *model.User) *gen.User {
 		Verified:    m.Verified,
 		IsDeveloper: m.IsDeveloper,
 		ViaGoogle:   m.ViaGoogle,
-		CreatedAt:   m.CreatedAt.String(),
-		UpdatedAt:   m.UpdatedAt.String(),
+		CreatedAt:   m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   m.UpdatedAt.Format(time.RFC3339),
+		Roles:       roles,
+		Permissions: model.Permissions(roles),
 	}
 }
 
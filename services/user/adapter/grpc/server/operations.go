@@ -33,6 +33,8 @@ func (s *UserGrpcServer) CreateUser(ctx context.Context, req *gen.CreateUserMsg)
 		Password:  req.Password,
 		Picture:   req.Picture,
 		Email:     req.Email,
+		ViaGoogle: req.ViaGoogle,
+		Verified:  req.Verified,
 	}
 
 	userId, err := service.Create(newUser, s.UserDB, s.Logger)
@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/user/adapter/broker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/user/adapter/broker.go -destination=services/user/adapter/mocks/mock_broker.go
+//
+// Package mock_adapter is a generated GoMock package.
+package mock_adapter
+
+import (
+	reflect "reflect"
+	model "warehouseai/user/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMailProducerInterface is a mock of MailProducerInterface interface.
+type MockMailProducerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailProducerInterfaceMockRecorder
+}
+
+// MockMailProducerInterfaceMockRecorder is the mock recorder for MockMailProducerInterface.
+type MockMailProducerInterfaceMockRecorder struct {
+	mock *MockMailProducerInterface
+}
+
+// NewMockMailProducerInterface creates a new mock instance.
+func NewMockMailProducerInterface(ctrl *gomock.Controller) *MockMailProducerInterface {
+	mock := &MockMailProducerInterface{ctrl: ctrl}
+	mock.recorder = &MockMailProducerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailProducerInterface) EXPECT() *MockMailProducerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SendEmail mocks base method.
+func (m *MockMailProducerInterface) SendEmail(email model.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendEmail", email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendEmail indicates an expected call of SendEmail.
+func (mr *MockMailProducerInterfaceMockRecorder) SendEmail(email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockMailProducerInterface)(nil).SendEmail), email)
+}
+
+// MockDeletionProducerInterface is a mock of DeletionProducerInterface interface.
+type MockDeletionProducerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletionProducerInterfaceMockRecorder
+}
+
+// MockDeletionProducerInterfaceMockRecorder is the mock recorder for MockDeletionProducerInterface.
+type MockDeletionProducerInterfaceMockRecorder struct {
+	mock *MockDeletionProducerInterface
+}
+
+// NewMockDeletionProducerInterface creates a new mock instance.
+func NewMockDeletionProducerInterface(ctrl *gomock.Controller) *MockDeletionProducerInterface {
+	mock := &MockDeletionProducerInterface{ctrl: ctrl}
+	mock.recorder = &MockDeletionProducerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletionProducerInterface) EXPECT() *MockDeletionProducerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SendDeletionCommand mocks base method.
+func (m *MockDeletionProducerInterface) SendDeletionCommand(command model.AccountDeletionCommand) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDeletionCommand", command)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendDeletionCommand indicates an expected call of SendDeletionCommand.
+func (mr *MockDeletionProducerInterfaceMockRecorder) SendDeletionCommand(command any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDeletionCommand", reflect.TypeOf((*MockDeletionProducerInterface)(nil).SendDeletionCommand), command)
+}
@@ -0,0 +1,211 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/user/adapter/grpc.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/user/adapter/grpc.go -destination=services/user/adapter/mocks/mock_grpc.go
+//
+// Package mock_adapter is a generated GoMock package.
+package mock_adapter
+
+import (
+	reflect "reflect"
+	gen "warehouseai/user/adapter/grpc/gen"
+	errors "warehouseai/user/errors"
+	model "warehouseai/user/model"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthGrpcInterface is a mock of AuthGrpcInterface interface.
+type MockAuthGrpcInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthGrpcInterfaceMockRecorder
+}
+
+// MockAuthGrpcInterfaceMockRecorder is the mock recorder for MockAuthGrpcInterface.
+type MockAuthGrpcInterfaceMockRecorder struct {
+	mock *MockAuthGrpcInterface
+}
+
+// NewMockAuthGrpcInterface creates a new mock instance.
+func NewMockAuthGrpcInterface(ctrl *gomock.Controller) *MockAuthGrpcInterface {
+	mock := &MockAuthGrpcInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthGrpcInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthGrpcInterface) EXPECT() *MockAuthGrpcInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthGrpcInterface) Authenticate(sessionId string) (*gen.AuthenticationResponse, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", sessionId)
+	ret0, _ := ret[0].(*gen.AuthenticationResponse)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthGrpcInterfaceMockRecorder) Authenticate(sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthGrpcInterface)(nil).Authenticate), sessionId)
+}
+
+// CheckSession mocks base method.
+func (m *MockAuthGrpcInterface) CheckSession(sessionId string) (*model.SessionState, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSession", sessionId)
+	ret0, _ := ret[0].(*model.SessionState)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// CheckSession indicates an expected call of CheckSession.
+func (mr *MockAuthGrpcInterfaceMockRecorder) CheckSession(sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSession", reflect.TypeOf((*MockAuthGrpcInterface)(nil).CheckSession), sessionId)
+}
+
+// GetUserAuditEvents mocks base method.
+func (m *MockAuthGrpcInterface) GetUserAuditEvents(userId, email string) ([]model.ExportAuditEvent, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAuditEvents", userId, email)
+	ret0, _ := ret[0].([]model.ExportAuditEvent)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// GetUserAuditEvents indicates an expected call of GetUserAuditEvents.
+func (mr *MockAuthGrpcInterfaceMockRecorder) GetUserAuditEvents(userId, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAuditEvents", reflect.TypeOf((*MockAuthGrpcInterface)(nil).GetUserAuditEvents), userId, email)
+}
+
+// RecordAuditEvent mocks base method.
+func (m *MockAuthGrpcInterface) RecordAuditEvent(event model.AuditEvent) *errors.ErrorResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAuditEvent", event)
+	ret0, _ := ret[0].(*errors.ErrorResponse)
+	return ret0
+}
+
+// RecordAuditEvent indicates an expected call of RecordAuditEvent.
+func (mr *MockAuthGrpcInterfaceMockRecorder) RecordAuditEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuditEvent", reflect.TypeOf((*MockAuthGrpcInterface)(nil).RecordAuditEvent), event)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockAuthGrpcInterface) RevokeSessions(userId, exceptSessionId string) *errors.ErrorResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", userId, exceptSessionId)
+	ret0, _ := ret[0].(*errors.ErrorResponse)
+	return ret0
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockAuthGrpcInterfaceMockRecorder) RevokeSessions(userId, exceptSessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockAuthGrpcInterface)(nil).RevokeSessions), userId, exceptSessionId)
+}
+
+// ValidatePassword mocks base method.
+func (m *MockAuthGrpcInterface) ValidatePassword(password, username, email string) *errors.ErrorResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePassword", password, username, email)
+	ret0, _ := ret[0].(*errors.ErrorResponse)
+	return ret0
+}
+
+// ValidatePassword indicates an expected call of ValidatePassword.
+func (mr *MockAuthGrpcInterfaceMockRecorder) ValidatePassword(password, username, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePassword", reflect.TypeOf((*MockAuthGrpcInterface)(nil).ValidatePassword), password, username, email)
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockAuthGrpcInterface) VerifyAccessToken(token string) (*model.AccessClaims, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", token)
+	ret0, _ := ret[0].(*model.AccessClaims)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockAuthGrpcInterfaceMockRecorder) VerifyAccessToken(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockAuthGrpcInterface)(nil).VerifyAccessToken), token)
+}
+
+// MockAiGrpcInterface is a mock of AiGrpcInterface interface.
+type MockAiGrpcInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAiGrpcInterfaceMockRecorder
+}
+
+// MockAiGrpcInterfaceMockRecorder is the mock recorder for MockAiGrpcInterface.
+type MockAiGrpcInterfaceMockRecorder struct {
+	mock *MockAiGrpcInterface
+}
+
+// NewMockAiGrpcInterface creates a new mock instance.
+func NewMockAiGrpcInterface(ctrl *gomock.Controller) *MockAiGrpcInterface {
+	mock := &MockAiGrpcInterface{ctrl: ctrl}
+	mock.recorder = &MockAiGrpcInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAiGrpcInterface) EXPECT() *MockAiGrpcInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetById mocks base method.
+func (m *MockAiGrpcInterface) GetById(aiId string) (string, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", aiId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockAiGrpcInterfaceMockRecorder) GetById(aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAiGrpcInterface)(nil).GetById), aiId)
+}
+
+// GetPublishedAis mocks base method.
+func (m *MockAiGrpcInterface) GetPublishedAis(ids []string) ([]model.PublicAi, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedAis", ids)
+	ret0, _ := ret[0].([]model.PublicAi)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// GetPublishedAis indicates an expected call of GetPublishedAis.
+func (mr *MockAiGrpcInterfaceMockRecorder) GetPublishedAis(ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedAis", reflect.TypeOf((*MockAiGrpcInterface)(nil).GetPublishedAis), ids)
+}
+
+// GetUserData mocks base method.
+func (m *MockAiGrpcInterface) GetUserData(userId string) (*model.AiUserData, *errors.ErrorResponse) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserData", userId)
+	ret0, _ := ret[0].(*model.AiUserData)
+	ret1, _ := ret[1].(*errors.ErrorResponse)
+	return ret0, ret1
+}
+
+// GetUserData indicates an expected call of GetUserData.
+func (mr *MockAiGrpcInterfaceMockRecorder) GetUserData(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserData", reflect.TypeOf((*MockAiGrpcInterface)(nil).GetUserData), userId)
+}
@@ -42,6 +42,20 @@ func NewBroker() *broker.Broker {
 		sagaQueues[key] = queue
 	}
 
+	// Очереди саги удаления аккаунта и событий для уведомлений переживают перезапуск RabbitMQ
+	for _, name := range []m.QueueName{m.AccountDeleteAuth, m.AccountDeleteAi, m.AccountDeleteReply, m.AiOwnership, m.AiRelease, m.AiRating, m.SecurityAlert} {
+		if _, err := ch.QueueDeclare(
+			string(name),
+			true,
+			false,
+			false,
+			false,
+			nil,
+		); err != nil {
+			panic(fmt.Sprintf("Unable to create queue in the channel; %s", err))
+		}
+	}
+
 	mailQueue, err := ch.QueueDeclare(
 		"mail",
 		false,
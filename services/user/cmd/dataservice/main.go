@@ -3,9 +3,27 @@ package dataservice
 import (
 	"fmt"
 	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	"warehouseai/user/dataservice/collectiondata"
+	"warehouseai/user/dataservice/deletiondata"
+	"warehouseai/user/dataservice/developerdata"
+	"warehouseai/user/dataservice/emailchangedata"
+	"warehouseai/user/dataservice/exportdata"
 	"warehouseai/user/dataservice/favoritesdata"
+	"warehouseai/user/dataservice/followdata"
+	fsexport "warehouseai/user/dataservice/fs/exportdata"
+	memexport "warehouseai/user/dataservice/memory/exportdata"
+	"warehouseai/user/dataservice/notificationdata"
+	"warehouseai/user/dataservice/owneddata"
+	"warehouseai/user/dataservice/roledata"
+	s3export "warehouseai/user/dataservice/s3/exportdata"
+	"warehouseai/user/dataservice/signedlink"
 	"warehouseai/user/dataservice/userdata"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -35,3 +53,166 @@ func NewFavoritesDatabase() *favoritesdata.Database {
 
 	return &favoritesdata.Database{DB: db}
 }
+
+func NewCollectionDatabase() *collectiondata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &collectiondata.Database{DB: db}
+}
+
+func NewFollowDatabase() *followdata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &followdata.Database{DB: db}
+}
+
+func NewNotificationDatabase() *notificationdata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &notificationdata.Database{DB: db}
+}
+
+func NewOwnedDatabase() *owneddata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &owneddata.Database{DB: db}
+}
+
+func NewEmailChangeDatabase() *emailchangedata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &emailchangedata.Database{DB: db}
+}
+
+func NewRoleDatabase() *roledata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &roledata.Database{DB: db}
+}
+
+func NewDeveloperDatabase() *developerdata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &developerdata.Database{DB: db}
+}
+
+func NewDeletionDatabase() *deletiondata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &deletiondata.Database{DB: db}
+}
+
+func NewExportDatabase() *exportdata.Database {
+	cfg := config.NewUserDatabaseCfg()
+	DSN := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port)
+
+	db, err := gorm.Open(postgres.Open(DSN), &gorm.Config{})
+	if err != nil {
+		fmt.Println("❌Failed to connect to the database.")
+		panic(err)
+	}
+
+	return &exportdata.Database{DB: db}
+}
+
+func NewExportStorage() d.ExportStorageInterface {
+	cfg := config.NewStorageCfg()
+
+	// С пустым ключом HMAC подписи ссылок скачивания может подделать кто угодно
+	if (cfg.Driver == config.FileStorage || cfg.Driver == config.MemoryStorage) && cfg.SigningKey == "" {
+		panic("❌STORAGE_SIGNING_KEY is required for the fs and memory storage drivers.")
+	}
+
+	signer := signedlink.Signer{Link: cfg.DownloadLink, Key: cfg.SigningKey}
+
+	switch cfg.Driver {
+	case config.FileStorage:
+		return &fsexport.Storage{Root: cfg.Root, Signer: signer}
+
+	case config.MemoryStorage:
+		return memexport.NewStorage(signer)
+
+	case config.S3Storage:
+		return &s3export.Storage{Bucket: cfg.Bucket, Session: newS3Session(cfg)}
+	}
+
+	panic(fmt.Sprintf("❌Unknown storage driver %q.", cfg.Driver))
+}
+
+func newS3Session(config config.StorageCfg) *session.Session {
+	sess, err := session.NewSession(
+		&aws.Config{
+			Endpoint:            aws.String(config.Endpoint),
+			Region:              aws.String(config.Region),
+			STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
+			Credentials: credentials.NewStaticCredentials(
+				config.AccessKey,
+				config.SecretKey,
+				"",
+			),
+		},
+	)
+
+	if err != nil {
+		fmt.Println("❌Failed to connect to the S3 storage.")
+		panic(err)
+	}
+
+	return sess
+}
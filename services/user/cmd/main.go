@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"os"
 	"time"
+	"warehouseai/user/adapter/grpc/client/ai"
+	"warehouseai/user/adapter/grpc/client/auth"
 	"warehouseai/user/cmd/adapter/broker"
 	"warehouseai/user/cmd/adapter/grpc"
 	"warehouseai/user/cmd/dataservice"
 	"warehouseai/user/cmd/server"
+	"warehouseai/user/config"
+	"warehouseai/user/service"
 
 	"github.com/sirupsen/logrus"
 )
@@ -26,16 +30,49 @@ func main() {
 
 	userDB := dataservice.NewUserDatabase()
 	favoritesDB := dataservice.NewFavoritesDatabase()
+	collectionDB := dataservice.NewCollectionDatabase()
+	ownedDB := dataservice.NewOwnedDatabase()
+	followDB := dataservice.NewFollowDatabase()
+	notificationDB := dataservice.NewNotificationDatabase()
+	emailChangeDB := dataservice.NewEmailChangeDatabase()
+	roleDB := dataservice.NewRoleDatabase()
+	developerDB := dataservice.NewDeveloperDatabase()
+	deletionDB := dataservice.NewDeletionDatabase()
+	exportDB := dataservice.NewExportDatabase()
+	exportStorage := dataservice.NewExportStorage()
 	broker := broker.NewBroker()
+	hub := service.NewNotificationHub()
 	fmt.Println("✅Database successfully connected.")
 
 	grpcServer := grpc.Start("user:8001", userDB, favoritesDB, log)
 	go grpcServer()
 	go broker.ReceiveTokenReject(userDB, log)
+	go broker.ReceiveDeletionReply(userDB, deletionDB, log)
+	go broker.ReceiveOwnershipEvent(ownedDB, log)
+	go broker.ReceiveReleaseEvent(followDB, notificationDB, hub, log)
+	go broker.ReceiveRatingEvent(notificationDB, hub, log)
+	go broker.ReceiveSecurityAlert(notificationDB, hub, log)
 
-	if err := server.StartServer(":8000", userDB, favoritesDB, broker, log); err != nil {
+	stopDeletions := make(chan struct{})
+	go service.StartDeletionScheduler(config.NewDeletionCfg(), userDB, deletionDB, broker, broker, log, stopDeletions)
+
+	stopExports := make(chan struct{})
+	go service.StartExportWorker(config.NewExportCfg(), userDB, favoritesDB, collectionDB, followDB, exportDB, exportStorage, ai.NewAiGrpcClient("ai:8021"), auth.NewAuthGrpcClient("auth:8041"), broker, notificationDB, hub, log, stopExports)
+
+	stopDigests := make(chan struct{})
+	go service.StartDigestWorker(config.NewNotificationCfg(), userDB, notificationDB, broker, log, stopDigests)
+
+	if err := server.StartServer(":8000", userDB, favoritesDB, collectionDB, ownedDB, followDB, notificationDB, emailChangeDB, roleDB, developerDB, deletionDB, exportDB, exportStorage, hub, broker, log); err != nil {
 		fmt.Println("❌Failed to start the HTTP Handler.")
 		log.WithFields(logrus.Fields{"time": time.Now().String(), "error": err.Error()}).Info("User Microservice")
 		panic(err)
 	}
+
+	defer func() {
+		close(stopDeletions)
+		close(stopExports)
+		close(stopDigests)
+		broker.Channel.Close()
+		broker.Connection.Close()
+	}()
 }
@@ -4,53 +4,120 @@ import (
 	"warehouseai/user/adapter/broker"
 	"warehouseai/user/adapter/grpc/client/ai"
 	"warehouseai/user/adapter/grpc/client/auth"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	"warehouseai/user/dataservice/collectiondata"
+	"warehouseai/user/dataservice/deletiondata"
+	"warehouseai/user/dataservice/developerdata"
+	"warehouseai/user/dataservice/emailchangedata"
+	"warehouseai/user/dataservice/exportdata"
 	"warehouseai/user/dataservice/favoritesdata"
+	"warehouseai/user/dataservice/followdata"
+	"warehouseai/user/dataservice/notificationdata"
+	"warehouseai/user/dataservice/owneddata"
+	"warehouseai/user/dataservice/roledata"
 	"warehouseai/user/dataservice/userdata"
+	"warehouseai/user/model"
 	h "warehouseai/user/server/handlers"
 	"warehouseai/user/server/middleware"
+	"warehouseai/user/service"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/sirupsen/logrus"
 )
 
-func StartServer(port string, userDb *userdata.Database, favoritesDb *favoritesdata.Database, brk *broker.Broker, logger *logrus.Logger) error {
-	handler := newHttpHandler(userDb, favoritesDb, brk, logger)
+func StartServer(port string, userDb *userdata.Database, favoritesDb *favoritesdata.Database, collectionDb *collectiondata.Database, ownedDb *owneddata.Database, followDb *followdata.Database, notificationDb *notificationdata.Database, emailDb *emailchangedata.Database, roleDb *roledata.Database, developerDb *developerdata.Database, deletionDb *deletiondata.Database, exportDb *exportdata.Database, exportStorage d.ExportStorageInterface, hub *service.NotificationHub, brk *broker.Broker, logger *logrus.Logger) error {
+	handler := newHttpHandler(userDb, favoritesDb, collectionDb, ownedDb, followDb, notificationDb, emailDb, roleDb, developerDb, deletionDb, exportDb, exportStorage, hub, brk, logger)
 	app := fiber.New()
 	app.Use(setupCORS())
+	app.Use(middleware.Csrf(middleware.AccessTokenVerifier(handler.AuthClient)))
 
-	sessionMw := middleware.Session(logger, handler.AuthClient)
+	sessionMw := middleware.Session(logger, handler.AuthClient, config.NewCookieCfg())
 	userMw := middleware.User(logger, handler.UserDB)
+	manageRolesMw := middleware.RequirePermission(model.PermissionManageRoles)
+	reviewDevelopersMw := middleware.RequirePermission(model.PermissionReviewDevelopers)
 
 	route := app.Group("/user")
 	route.Patch("/update", sessionMw, handler.UpdatePersonalDataHandler)
 	route.Patch("/update/email", sessionMw, handler.UpdateEmailHandler)
+	route.Get("/email/confirm", handler.ConfirmEmailHandler)
 	route.Patch("/update/password", sessionMw, userMw, handler.UpdatePasswordHandler)
+	route.Post("/delete", sessionMw, userMw, handler.DeleteAccountHandler)
+	route.Get("/delete/confirm", handler.ConfirmAccountDeletionHandler)
+	route.Post("/delete/cancel", sessionMw, handler.CancelAccountDeletionHandler)
+	route.Get("/delete/status", sessionMw, handler.GetAccountDeletionHandler)
+	route.Post("/export", sessionMw, handler.RequestExportHandler)
+	route.Get("/export/status", sessionMw, handler.GetExportHandler)
+
+	if _, ok := exportStorage.(d.LocalExportStorageInterface); ok {
+		route.Get("/export/download", handler.LocalDownloadHandler)
+	}
 	route.Patch("/favorites/add", sessionMw, handler.AddFavoriteHandler)
 	route.Delete("/favorites/delete", sessionMw, handler.RemoveFavoriteHandler)
 	route.Get("/favorites", sessionMw, handler.GetFavoritesHandler)
+	route.Get("/collections", sessionMw, handler.GetCollectionsHandler)
+	route.Post("/collections/create", sessionMw, handler.CreateCollectionHandler)
+	route.Patch("/collections/update", sessionMw, handler.UpdateCollectionHandler)
+	route.Delete("/collections/delete", sessionMw, handler.DeleteCollectionHandler)
+	route.Patch("/collections/reorder", sessionMw, handler.ReorderCollectionsHandler)
+	route.Get("/collections/items", sessionMw, handler.GetCollectionItemsHandler)
+	route.Patch("/collections/items/add", sessionMw, handler.AddCollectionItemHandler)
+	route.Delete("/collections/items/delete", sessionMw, handler.RemoveCollectionItemHandler)
+	route.Patch("/collections/items/reorder", sessionMw, handler.ReorderCollectionItemsHandler)
+	route.Get("/collections/shared", handler.GetSharedCollectionHandler)
 	route.Get("/get", handler.GetUserById)
+	route.Get("/me", sessionMw, handler.GetMeHandler)
+	route.Get("/profile", handler.GetProfileHandler)
+	route.Post("/follow", sessionMw, handler.FollowHandler)
+	route.Delete("/unfollow", sessionMw, handler.UnfollowHandler)
+	route.Get("/notifications", sessionMw, handler.GetNotificationsHandler)
+	route.Get("/notifications/stream", sessionMw, handler.StreamNotificationsHandler)
+	route.Patch("/notifications/read", sessionMw, handler.MarkNotificationsReadHandler)
+	route.Patch("/notifications/settings", sessionMw, handler.UpdateNotificationSettingsHandler)
+	route.Get("/roles", sessionMw, manageRolesMw, handler.GetRolesHandler)
+	route.Post("/roles/grant", sessionMw, manageRolesMw, handler.GrantRoleHandler)
+	route.Post("/roles/revoke", sessionMw, manageRolesMw, handler.RevokeRoleHandler)
+	route.Post("/developer/apply", sessionMw, handler.DeveloperApplyHandler)
+	route.Get("/developer/application", sessionMw, handler.GetDeveloperApplicationHandler)
+	route.Get("/developer/applications", sessionMw, reviewDevelopersMw, handler.GetPendingApplicationsHandler)
+	route.Post("/developer/applications/review", sessionMw, reviewDevelopersMw, handler.ReviewApplicationHandler)
 
 	return app.Listen(port)
 }
 
-func newHttpHandler(userDb *userdata.Database, favoritesDB *favoritesdata.Database, brk *broker.Broker, logger *logrus.Logger) *h.Handler {
+func newHttpHandler(userDb *userdata.Database, favoritesDB *favoritesdata.Database, collectionDB *collectiondata.Database, ownedDB *owneddata.Database, followDB *followdata.Database, notificationDB *notificationdata.Database, emailDB *emailchangedata.Database, roleDB *roledata.Database, developerDB *developerdata.Database, deletionDB *deletiondata.Database, exportDB *exportdata.Database, exportStorage d.ExportStorageInterface, hub *service.NotificationHub, brk *broker.Broker, logger *logrus.Logger) *h.Handler {
 	authClient := auth.NewAuthGrpcClient("auth:8041")
 	aiClient := ai.NewAiGrpcClient("ai:8021")
 
 	return &h.Handler{
-		UserDB:      userDb,
-		FavoritesDB: favoritesDB,
-		Logger:      logger,
-		Broker:      brk,
-		AiClient:    aiClient,
-		AuthClient:  authClient,
+		UserDB:        userDb,
+		FavoritesDB:   favoritesDB,
+		CollectionDB:  collectionDB,
+		OwnedDB:       ownedDB,
+		FollowDB:      followDB,
+		NotifyDB:      notificationDB,
+		EmailDB:       emailDB,
+		RoleDB:        roleDB,
+		DeveloperDB:   developerDB,
+		DeletionDB:    deletionDB,
+		ExportDB:      exportDB,
+		ExportStorage: exportStorage,
+		DeveloperCfg:  config.NewDeveloperCfg(),
+		DeletionCfg:   config.NewDeletionCfg(),
+		ExportCfg:     config.NewExportCfg(),
+		NotifyCfg:     config.NewNotificationCfg(),
+		Hub:           hub,
+		Logger:        logger,
+		Broker:        brk,
+		AiClient:      aiClient,
+		AuthClient:    authClient,
 	}
 }
 
 func setupCORS() func(*fiber.Ctx) error {
 	return cors.New(cors.Config{
-		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,X-CSRF-Token",
 		AllowOrigins:     "http://localhost:3000, https://warehouse-ai-frontend.vercel.app, https://warehousai.com",
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SameSite для cookie сессии, access-токена и CSRF-токена. None нужен, пока фронтенд живёт на другом домене.
+type CookieCfg struct {
+	SameSite string
+}
+
+func NewCookieCfg() CookieCfg {
+	sameSite := strings.ToLower(os.Getenv("SESSION_COOKIE_SAMESITE"))
+
+	switch sameSite {
+	case fiber.CookieSameSiteLaxMode, fiber.CookieSameSiteStrictMode, fiber.CookieSameSiteNoneMode:
+		return CookieCfg{SameSite: sameSite}
+	}
+
+	return CookieCfg{SameSite: fiber.CookieSameSiteNoneMode}
+}
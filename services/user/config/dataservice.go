@@ -4,6 +4,14 @@ import (
 	"os"
 )
 
+type StorageDriver string
+
+const (
+	S3Storage     StorageDriver = "s3"
+	FileStorage   StorageDriver = "fs"
+	MemoryStorage StorageDriver = "memory"
+)
+
 type DatabaseCfg struct {
 	Host     string
 	Name     string
@@ -21,3 +29,42 @@ func NewUserDatabaseCfg() DatabaseCfg {
 		Port:     "5432",
 	}
 }
+
+type StorageCfg struct {
+	Driver    StorageDriver
+	Root      string
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Region    string
+	Bucket    string
+	// Только для fs и memory: адрес роута скачивания и ключ подписи ссылок на него
+	DownloadLink string
+	SigningKey   string
+}
+
+func NewStorageCfg() StorageCfg {
+	driver := StorageDriver(os.Getenv("STORAGE_DRIVER"))
+
+	if driver == "" {
+		driver = S3Storage
+	}
+
+	root := os.Getenv("STORAGE_ROOT")
+
+	if root == "" {
+		root = "./storage"
+	}
+
+	return StorageCfg{
+		Driver:       driver,
+		Root:         root,
+		Endpoint:     os.Getenv("S3_HOST"),
+		AccessKey:    os.Getenv("S3_ACCESSKEY"),
+		SecretKey:    os.Getenv("S3_SECRETKEY"),
+		Bucket:       os.Getenv("S3_BUCKET"),
+		Region:       os.Getenv("S3_REGION"),
+		DownloadLink: os.Getenv("STORAGE_DOWNLOAD_LINK"),
+		SigningKey:   os.Getenv("STORAGE_SIGNING_KEY"),
+	}
+}
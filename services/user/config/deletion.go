@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type DeletionCfg struct {
+	// Сколько ждать перед удалением аккаунта, пока пользователь может передумать
+	GracePeriod time.Duration
+	// Как часто запускать удаления, у которых закончился период ожидания, 0 - не запускать
+	Interval time.Duration
+	// Сколько сага может ждать ответа на шаг, после этого шаг отправляется ещё раз
+	StepTimeout time.Duration
+	// Сколько действует ссылка подтверждения удаления без пароля
+	ConfirmTTL time.Duration
+}
+
+func NewDeletionCfg() DeletionCfg {
+	return DeletionCfg{
+		GracePeriod: durationFromEnv("ACCOUNT_DELETION_GRACE_PERIOD", 7*24*time.Hour),
+		Interval:    durationFromEnv("ACCOUNT_DELETION_INTERVAL", time.Minute),
+		StepTimeout: durationFromEnv("ACCOUNT_DELETION_STEP_TIMEOUT", 15*time.Minute),
+		ConfirmTTL:  durationFromEnv("ACCOUNT_DELETION_CONFIRM_TTL", time.Hour),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,14 @@
+package config
+
+import "os"
+
+type DeveloperCfg struct {
+	// Одобрять заявки сразу, без администратора
+	AutoApprove bool
+}
+
+func NewDeveloperCfg() DeveloperCfg {
+	return DeveloperCfg{
+		AutoApprove: os.Getenv("DEVELOPER_AUTO_APPROVE") == "true",
+	}
+}
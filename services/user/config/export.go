@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type ExportCfg struct {
+	// Сколько действует ссылка на архив. После этого архив удаляется из хранилища.
+	LinkTTL time.Duration
+	// Новую выгрузку можно запросить не чаще, чем раз в Cooldown
+	Cooldown time.Duration
+	// Как часто собирать запрошенные выгрузки и удалять истёкшие архивы, 0 - не собирать
+	Interval time.Duration
+	// Выгрузка, которая собирается дольше, считается брошенной (экземпляр сервиса упал) и помечается failed
+	ProcessingTimeout time.Duration
+}
+
+func NewExportCfg() ExportCfg {
+	return ExportCfg{
+		LinkTTL:           durationFromEnv("EXPORT_LINK_TTL", 48*time.Hour),
+		Cooldown:          durationFromEnv("EXPORT_COOLDOWN", 24*time.Hour),
+		Interval:          durationFromEnv("EXPORT_INTERVAL", 30*time.Second),
+		ProcessingTimeout: durationFromEnv("EXPORT_PROCESSING_TIMEOUT", 30*time.Minute),
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+type NotificationCfg struct {
+	// Как часто отправлять дайджест непрочитанных уведомлений на почту, 0 - не отправлять
+	DigestInterval time.Duration
+	// Как часто поток уведомлений пишет keep-alive и проверяет уведомления других экземпляров сервиса
+	StreamHeartbeat time.Duration
+}
+
+func NewNotificationCfg() NotificationCfg {
+	return NotificationCfg{
+		DigestInterval:  durationFromEnv("NOTIFICATION_DIGEST_INTERVAL", 24*time.Hour),
+		StreamHeartbeat: durationFromEnv("NOTIFICATION_STREAM_HEARTBEAT", 25*time.Second),
+	}
+}
@@ -0,0 +1,198 @@
+package collectiondata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Create(collection *m.FavoriteCollection) *e.DBError {
+	if err := d.DB.Create(collection).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) Get(conditions map[string]interface{}) (*m.FavoriteCollection, *e.DBError) {
+	var collection m.FavoriteCollection
+
+	if err := d.DB.Where(conditions).First(&collection).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &collection, nil
+}
+
+func (d *Database) GetMany(userId string) (*[]m.FavoriteCollection, *e.DBError) {
+	var collections []m.FavoriteCollection
+
+	if err := d.DB.Where(map[string]interface{}{"user_id": userId}).Order("position, created_at").Find(&collections).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &collections, nil
+}
+
+func (d *Database) Count(userId string) (int64, *e.DBError) {
+	var count int64
+
+	if err := d.DB.Model(&m.FavoriteCollection{}).Where(map[string]interface{}{"user_id": userId}).Count(&count).Error; err != nil {
+		return 0, errorHandle(err)
+	}
+
+	return count, nil
+}
+
+// Позиция после последней коллекции. Count не подходит: после удаления он совпадает с занятой позицией.
+func (d *Database) NextPosition(userId string) (int, *e.DBError) {
+	var position int
+
+	if err := d.DB.Model(&m.FavoriteCollection{}).Select("COALESCE(MAX(position) + 1, 0)").Where("user_id = ?", userId).Scan(&position).Error; err != nil {
+		return 0, errorHandle(err)
+	}
+
+	return position, nil
+}
+
+func (d *Database) Update(collectionId string, updatedFields map[string]interface{}) *e.DBError {
+	updatedFields["updated_at"] = gorm.Expr("now()")
+
+	if err := d.DB.Model(&m.FavoriteCollection{}).Where("id = ?", collectionId).Updates(updatedFields).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Записи коллекции удаляются каскадом
+func (d *Database) Delete(collectionId string) *e.DBError {
+	if err := d.DB.Where("id = ?", collectionId).Delete(&m.FavoriteCollection{}).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Позиция коллекции - её индекс в collectionIds. Чужие коллекции не затрагиваются.
+func (d *Database) Reorder(userId string, collectionIds []string) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		for position, collectionId := range collectionIds {
+			if err := tx.Model(&m.FavoriteCollection{}).Where("id = ? AND user_id = ?", collectionId, userId).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) AddItem(item *m.CollectionItem) *e.DBError {
+	if err := d.DB.Create(item).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) NextItemPosition(collectionId string) (int, *e.DBError) {
+	var position int
+
+	if err := d.DB.Model(&m.CollectionItem{}).Select("COALESCE(MAX(position) + 1, 0)").Where("collection_id = ?", collectionId).Scan(&position).Error; err != nil {
+		return 0, errorHandle(err)
+	}
+
+	return position, nil
+}
+
+func (d *Database) GetItemIds(collectionId string) ([]string, *e.DBError) {
+	var aiIds []string
+
+	if err := d.DB.Model(&m.CollectionItem{}).Where("collection_id = ?", collectionId).Order("position, id").Pluck("ai_id", &aiIds).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return aiIds, nil
+}
+
+func (d *Database) GetItems(collectionId string, after *m.Cursor, limit int) (*[]m.CollectionItem, *e.DBError) {
+	var items []m.CollectionItem
+
+	query := d.DB.Where("collection_id = ?", collectionId)
+
+	if after != nil {
+		query = query.Where("(position, id) > (?, ?)", after.Position, after.ID)
+	}
+
+	if err := query.Order("position, id").Limit(limit).Find(&items).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &items, nil
+}
+
+func (d *Database) DeleteItem(collectionId string, aiId string) *e.DBError {
+	if err := d.DB.Where("collection_id = ? AND ai_id = ?", collectionId, aiId).Delete(&m.CollectionItem{}).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Убирает ИИ из всех коллекций пользователя, когда его удаляют из избранного
+func (d *Database) DeleteUserItems(userId string, aiId string) *e.DBError {
+	collections := d.DB.Model(&m.FavoriteCollection{}).Select("id").Where("user_id = ?", userId)
+
+	if err := d.DB.Where("ai_id = ? AND collection_id IN (?)", aiId, collections).Delete(&m.CollectionItem{}).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Позиция записи - индекс её ИИ в aiIds
+func (d *Database) ReorderItems(collectionId string, aiIds []string) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		for position, aiId := range aiIds {
+			if err := tx.Model(&m.CollectionItem{}).Where("collection_id = ? AND ai_id = ?", collectionId, aiId).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func errorHandle(err error) *e.DBError {
+	var pgErr *pgconn.PgError
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Collection not found.", err.Error())
+	}
+
+	// unique_violation = 23505
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return e.NewDBError(e.DbExist, "Entity with this key/keys already exists.", err.Error())
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+}
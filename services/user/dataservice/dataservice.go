@@ -1,8 +1,12 @@
 package dataservice
 
 import (
+	"io"
+	"time"
 	e "warehouseai/user/errors"
 	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
 )
 
 type UserInterface interface {
@@ -17,6 +21,101 @@ type UserInterface interface {
 type FavoritesInterface interface {
 	Add(favorite *m.UserFavorite) *e.DBError
 	GetUserFavorites(userId string) (*[]m.UserFavorite, *e.DBError)
+	GetUserFavoritesPage(userId string, after *m.Cursor, limit int) (*[]m.UserFavorite, *e.DBError)
 	GetFavorite(userId string, aiId string) (*m.UserFavorite, *e.DBError)
 	Delete(userId string, aiId string) *e.DBError
 }
+
+type CollectionInterface interface {
+	Create(collection *m.FavoriteCollection) *e.DBError
+	Get(conditions map[string]interface{}) (*m.FavoriteCollection, *e.DBError)
+	GetMany(userId string) (*[]m.FavoriteCollection, *e.DBError)
+	Count(userId string) (int64, *e.DBError)
+	NextPosition(userId string) (int, *e.DBError)
+	Update(collectionId string, updatedFields map[string]interface{}) *e.DBError
+	Delete(collectionId string) *e.DBError
+	Reorder(userId string, collectionIds []string) *e.DBError
+	AddItem(item *m.CollectionItem) *e.DBError
+	NextItemPosition(collectionId string) (int, *e.DBError)
+	GetItemIds(collectionId string) ([]string, *e.DBError)
+	GetItems(collectionId string, after *m.Cursor, limit int) (*[]m.CollectionItem, *e.DBError)
+	DeleteItem(collectionId string, aiId string) *e.DBError
+	DeleteUserItems(userId string, aiId string) *e.DBError
+	ReorderItems(collectionId string, aiIds []string) *e.DBError
+}
+
+type OwnedInterface interface {
+	Add(own *m.UserOwn) *e.DBError
+	GetUserOwned(userId string) (*[]m.UserOwn, *e.DBError)
+	Delete(userId string, aiId string) *e.DBError
+}
+
+type FollowInterface interface {
+	Add(follow *m.UserFollow) *e.DBError
+	Delete(followerId string, developerId string) *e.DBError
+	CountFollowers(developerId string) (int64, *e.DBError)
+	GetFollowing(followerId string) (*[]m.UserFollow, *e.DBError)
+	GetFollowerIds(developerId string) ([]uuid.UUID, *e.DBError)
+}
+
+type NotificationInterface interface {
+	CreateMany(notifications []m.Notification) *e.DBError
+	GetPage(userId string, after *m.Cursor, limit int, unreadOnly bool) (*[]m.Notification, *e.DBError)
+	GetAll(userId string) (*[]m.Notification, *e.DBError)
+	GetAfter(userId string, afterId uint, limit int) (*[]m.Notification, *e.DBError)
+	GetLastId(userId string) (uint, *e.DBError)
+	CountUnread(userId string) (int64, *e.DBError)
+	MarkRead(userId string, ids []uint) *e.DBError
+	GetDigestPending(limit int) (*[]m.Notification, *e.DBError)
+	MarkEmailed(ids []uint) *e.DBError
+}
+
+type EmailChangeInterface interface {
+	Replace(change *m.EmailChange) *e.DBError
+	GetByToken(tokenHash string) (*m.EmailChange, *e.DBError)
+	Delete(condition map[string]interface{}) *e.DBError
+}
+
+type RoleInterface interface {
+	Grant(grant *m.RoleGrant) *e.DBError
+	Revoke(userId string, role m.UserRole) *e.DBError
+}
+
+type DeveloperInterface interface {
+	Replace(application *m.DeveloperApplication) *e.DBError
+	Get(conditions map[string]interface{}) (*m.DeveloperApplication, *e.DBError)
+	GetMany(conditions map[string]interface{}, limit int) (*[]m.DeveloperApplication, *e.DBError)
+	Review(applicationId string, status m.ApplicationStatus, reviewedBy uuid.NullUUID, comment string) (*m.DeveloperApplication, *e.DBError)
+}
+
+type DeletionInterface interface {
+	Create(deletion *m.AccountDeletion) *e.DBError
+	Get(conditions map[string]interface{}) (*m.AccountDeletion, *e.DBError)
+	GetDue(now time.Time, limit int) (*[]m.AccountDeletion, *e.DBError)
+	GetStale(before time.Time, limit int) (*[]m.AccountDeletion, *e.DBError)
+	Transition(deletionId string, from m.DeletionStatus, updatedFields map[string]interface{}) *e.DBError
+	UpdateStep(deletionId string, step m.DeletionStep, status m.DeletionStepStatus, stepError string) *e.DBError
+}
+
+type ExportInterface interface {
+	Create(export *m.DataExport) *e.DBError
+	Get(conditions map[string]interface{}) (*m.DataExport, *e.DBError)
+	GetMany(conditions map[string]interface{}, limit int) (*[]m.DataExport, *e.DBError)
+	GetExpired(now time.Time, limit int) (*[]m.DataExport, *e.DBError)
+	GetStale(before time.Time, limit int) (*[]m.DataExport, *e.DBError)
+	Transition(exportId string, from m.ExportStatus, updatedFields map[string]interface{}) *e.DBError
+}
+
+// Архивы выгрузки персональных данных. Скачать их можно только по ссылке с ограниченным сроком.
+type ExportStorageInterface interface {
+	Save(key string, file io.Reader) error
+	PresignDownload(key string, expires time.Duration) (string, error)
+	Delete(key string) error
+}
+
+// Драйверы без своих ссылок (fs, memory): архив по подписанной ссылке отдаёт сам сервис
+type LocalExportStorageInterface interface {
+	ExportStorageInterface
+	Verify(key string, expires string, signature string) error
+	Open(key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,101 @@
+package deletiondata
+
+import (
+	"errors"
+	"time"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+// Создаёт удаление вместе со всеми шагами. Активное удаление у пользователя одно, иначе DbExist.
+func (d *Database) Create(deletion *m.AccountDeletion) *e.DBError {
+	if err := d.DB.Create(deletion).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+func (d *Database) Get(conditions map[string]interface{}) (*m.AccountDeletion, *e.DBError) {
+	var deletion m.AccountDeletion
+
+	if err := d.DB.Where(conditions).Preload("Steps").Order("created_at DESC").First(&deletion).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &deletion, nil
+}
+
+func (d *Database) GetDue(now time.Time, limit int) (*[]m.AccountDeletion, *e.DBError) {
+	var deletions []m.AccountDeletion
+
+	if err := d.DB.Where("status = ? AND scheduled_for <= ?", m.DeletionScheduled, now).Preload("Steps").Order("scheduled_for").Limit(limit).Find(&deletions).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &deletions, nil
+}
+
+// Идущие удаления, которые не менялись с before
+func (d *Database) GetStale(before time.Time, limit int) (*[]m.AccountDeletion, *e.DBError) {
+	var deletions []m.AccountDeletion
+
+	if err := d.DB.Where("status IN ? AND updated_at <= ?", []m.DeletionStatus{m.DeletionInProgress, m.DeletionCompensating}, before).Preload("Steps").Order("updated_at").Limit(limit).Find(&deletions).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &deletions, nil
+}
+
+// Меняет удаление, только если оно в статусе from, иначе DbNotFound.
+// Так отмена и запуск по расписанию не могут сработать одновременно.
+func (d *Database) Transition(deletionId string, from m.DeletionStatus, updatedFields map[string]interface{}) *e.DBError {
+	updatedFields["updated_at"] = gorm.Expr("now()")
+
+	result := d.DB.Model(&m.AccountDeletion{}).Where("id = ? AND status = ?", deletionId, from).Updates(updatedFields)
+
+	if result.Error != nil {
+		return errorHandle(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "Account deletion not found.", "")
+	}
+
+	return nil
+}
+
+func (d *Database) UpdateStep(deletionId string, step m.DeletionStep, status m.DeletionStepStatus, stepError string) *e.DBError {
+	result := d.DB.Model(&m.AccountDeletionStep{}).
+		Where("deletion_id = ? AND step = ?", deletionId, step).
+		Updates(map[string]interface{}{"status": status, "error": stepError, "updated_at": gorm.Expr("now()")})
+
+	if result.Error != nil {
+		return errorHandle(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "Account deletion step not found.", "")
+	}
+
+	return nil
+}
+
+func errorHandle(err error) *e.DBError {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Account deletion not found.", err.Error())
+	}
+
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return e.NewDBError(e.DbExist, "Account deletion is already requested.", err.Error())
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+}
@@ -0,0 +1,110 @@
+package developerdata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+// Новая заявка заменяет предыдущую
+func (d *Database) Replace(application *m.DeveloperApplication) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", application.UserId).Delete(&m.DeveloperApplication{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(application).Error
+	})
+
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return e.NewDBError(e.DbExist, "Entity with this key/keys already exists.", err.Error())
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) Get(conditions map[string]interface{}) (*m.DeveloperApplication, *e.DBError) {
+	var application m.DeveloperApplication
+
+	if err := d.DB.Where(conditions).First(&application).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+		}
+
+		return nil, e.NewDBError(e.DbNotFound, "Application not found.", err.Error())
+	}
+
+	return &application, nil
+}
+
+func (d *Database) GetMany(conditions map[string]interface{}, limit int) (*[]m.DeveloperApplication, *e.DBError) {
+	var applications []m.DeveloperApplication
+
+	if err := d.DB.Where(conditions).Order("created_at").Limit(limit).Find(&applications).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &applications, nil
+}
+
+// Решение по заявке и флаг is_developer меняются вместе. Решение принимается
+// только по заявке в статусе pending, иначе DbNotFound.
+func (d *Database) Review(applicationId string, status m.ApplicationStatus, reviewedBy uuid.NullUUID, comment string) (*m.DeveloperApplication, *e.DBError) {
+	var application m.DeveloperApplication
+
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&application).
+			Where("id = ? AND status = ?", applicationId, m.ApplicationPending).
+			Updates(map[string]interface{}{"status": status, "reviewed_by": reviewedBy, "comment": comment, "updated_at": gorm.Expr("now()")})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("id = ?", applicationId).First(&application).Error; err != nil {
+			return err
+		}
+
+		if status != m.ApplicationApproved {
+			return nil
+		}
+
+		return tx.Model(&m.User{}).Where("id = ?", application.UserId).Update("is_developer", true).Error
+	})
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, e.NewDBError(e.DbNotFound, "Pending application not found.", err.Error())
+		}
+
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &application, nil
+}
+
+func isDuplicateKeyError(err error) bool {
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		// unique_violation = 23505
+		return pgErr.Code == "23505"
+
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package emailchangedata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+// Новый запрос на смену почты заменяет предыдущий
+func (d *Database) Replace(change *m.EmailChange) *e.DBError {
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", change.UserId).Delete(&m.EmailChange{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(change).Error
+	})
+
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return e.NewDBError(e.DbExist, "Entity with this key/keys already exists.", err.Error())
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+// Протухшие запросы не находятся, срок сравнивается в базе
+func (d *Database) GetByToken(tokenHash string) (*m.EmailChange, *e.DBError) {
+	var change m.EmailChange
+
+	if err := d.DB.Where("token_hash = ? AND expires_at > now()", tokenHash).First(&change).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+		}
+
+		return nil, e.NewDBError(e.DbNotFound, "Entity not found.", err.Error())
+	}
+
+	return &change, nil
+}
+
+func (d *Database) Delete(condition map[string]interface{}) *e.DBError {
+	if err := d.DB.Where(condition).Delete(&m.EmailChange{}).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func isDuplicateKeyError(err error) bool {
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		// unique_violation = 23505
+		return pgErr.Code == "23505"
+
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package exportdata
+
+import (
+	"errors"
+	"time"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Create(export *m.DataExport) *e.DBError {
+	if err := d.DB.Create(export).Error; err != nil {
+		return errorHandle(err)
+	}
+
+	return nil
+}
+
+// Последняя выгрузка по условию
+func (d *Database) Get(conditions map[string]interface{}) (*m.DataExport, *e.DBError) {
+	var export m.DataExport
+
+	if err := d.DB.Where(conditions).Order("created_at DESC").First(&export).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &export, nil
+}
+
+func (d *Database) GetMany(conditions map[string]interface{}, limit int) (*[]m.DataExport, *e.DBError) {
+	var exports []m.DataExport
+
+	if err := d.DB.Where(conditions).Order("created_at").Limit(limit).Find(&exports).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &exports, nil
+}
+
+func (d *Database) GetExpired(now time.Time, limit int) (*[]m.DataExport, *e.DBError) {
+	var exports []m.DataExport
+
+	if err := d.DB.Where("status = ? AND expires_at <= ?", m.ExportReady, now).Order("expires_at").Limit(limit).Find(&exports).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &exports, nil
+}
+
+// Выгрузки в сборке, которые не менялись с before
+func (d *Database) GetStale(before time.Time, limit int) (*[]m.DataExport, *e.DBError) {
+	var exports []m.DataExport
+
+	if err := d.DB.Where("status = ? AND updated_at <= ?", m.ExportProcessing, before).Order("updated_at").Limit(limit).Find(&exports).Error; err != nil {
+		return nil, errorHandle(err)
+	}
+
+	return &exports, nil
+}
+
+// Меняет выгрузку, только если она в статусе from, иначе DbNotFound.
+// Так одну выгрузку не соберут два экземпляра сервиса.
+func (d *Database) Transition(exportId string, from m.ExportStatus, updatedFields map[string]interface{}) *e.DBError {
+	updatedFields["updated_at"] = gorm.Expr("now()")
+
+	result := d.DB.Model(&m.DataExport{}).Where("id = ? AND status = ?", exportId, from).Updates(updatedFields)
+
+	if result.Error != nil {
+		return errorHandle(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "Data export not found.", "")
+	}
+
+	return nil
+}
+
+func errorHandle(err error) *e.DBError {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return e.NewDBError(e.DbNotFound, "Data export not found.", err.Error())
+	}
+
+	return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+}
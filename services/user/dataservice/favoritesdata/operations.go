@@ -39,6 +39,23 @@ func (d *Database) GetUserFavorites(userId string) (*[]m.UserFavorite, *e.DBErro
 	return &favorites, nil
 }
 
+// Страница избранного, новые сверху
+func (d *Database) GetUserFavoritesPage(userId string, after *m.Cursor, limit int) (*[]m.UserFavorite, *e.DBError) {
+	var favorites []m.UserFavorite
+
+	query := d.DB.Where(map[string]interface{}{"user_id": userId})
+
+	if after != nil {
+		query = query.Where("id < ?", after.ID)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Find(&favorites).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &favorites, nil
+}
+
 func (d *Database) GetFavorite(userId string, aiId string) (*m.UserFavorite, *e.DBError) {
 	var favorite m.UserFavorite
 
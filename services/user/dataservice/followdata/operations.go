@@ -0,0 +1,81 @@
+package followdata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Add(follow *m.UserFollow) *e.DBError {
+	if err := d.DB.Create(follow).Error; err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			// unique_violation = 23505
+			case "23505":
+				return e.NewDBError(e.DbExist, "You already follow this developer.", err.Error())
+			// foreign_key_violation = 23503
+			case "23503":
+				return e.NewDBError(e.DbNotFound, "User not found.", err.Error())
+			}
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) Delete(followerId string, developerId string) *e.DBError {
+	result := d.DB.Where(map[string]interface{}{"follower_id": followerId, "developer_id": developerId}).Delete(&m.UserFollow{})
+
+	if result.Error != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", result.Error.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return e.NewDBError(e.DbNotFound, "You do not follow this developer.", "follow not found")
+	}
+
+	return nil
+}
+
+func (d *Database) CountFollowers(developerId string) (int64, *e.DBError) {
+	var count int64
+
+	if err := d.DB.Model(&m.UserFollow{}).Where(map[string]interface{}{"developer_id": developerId}).Count(&count).Error; err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return count, nil
+}
+
+// Подписки самого пользователя - для выгрузки данных
+func (d *Database) GetFollowing(followerId string) (*[]m.UserFollow, *e.DBError) {
+	var follows []m.UserFollow
+
+	if err := d.DB.Where(map[string]interface{}{"follower_id": followerId}).Order("id").Find(&follows).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &follows, nil
+}
+
+func (d *Database) GetFollowerIds(developerId string) ([]uuid.UUID, *e.DBError) {
+	var followerIds []uuid.UUID
+
+	if err := d.DB.Model(&m.UserFollow{}).Where(map[string]interface{}{"developer_id": developerId}).Order("id").Pluck("follower_id", &followerIds).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return followerIds, nil
+}
@@ -0,0 +1,98 @@
+package exportdata
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"warehouseai/user/dataservice/signedlink"
+)
+
+// Все ключи архивов лежат под этим префиксом, другие ключи драйвер не принимает
+const keyPrefix = "exports/"
+
+// Хранит архивы выгрузки на диске. Вместо подписи S3 ссылка подписывается HMAC'ом
+// и ведёт на роут скачивания самого сервиса (Signer.Link).
+type Storage struct {
+	Root   string
+	Signer signedlink.Signer
+}
+
+func (s *Storage) Save(key string, file io.Reader) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) PresignDownload(key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	return s.Signer.Presign(key, expires), nil
+}
+
+func (s *Storage) Verify(key string, expires string, signature string) error {
+	if _, err := s.path(key); err != nil {
+		return err
+	}
+
+	return s.Signer.Verify(key, expires, signature)
+}
+
+func (s *Storage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *Storage) Delete(key string) error {
+	path, err := s.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) path(key string) (string, error) {
+	cleanKey := filepath.Clean(key)
+
+	if key == "" || filepath.IsAbs(cleanKey) || !strings.HasPrefix(cleanKey, keyPrefix) {
+		return "", errors.New("invalid file key")
+	}
+
+	return filepath.Join(s.Root, cleanKey), nil
+}
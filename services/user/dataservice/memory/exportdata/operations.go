@@ -0,0 +1,64 @@
+package exportdata
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"time"
+	"warehouseai/user/dataservice/signedlink"
+)
+
+// Хранит архивы выгрузки в памяти процесса. Ссылка такая же, как у fs: подписанная, на роут скачивания сервиса.
+type Storage struct {
+	Signer signedlink.Signer
+	mu     sync.RWMutex
+	files  map[string][]byte
+}
+
+func NewStorage(signer signedlink.Signer) *Storage {
+	return &Storage{Signer: signer, files: map[string][]byte{}}
+}
+
+func (s *Storage) Save(key string, file io.Reader) error {
+	payload, err := io.ReadAll(file)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.files[key] = payload
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Storage) PresignDownload(key string, expires time.Duration) (string, error) {
+	return s.Signer.Presign(key, expires), nil
+}
+
+func (s *Storage) Verify(key string, expires string, signature string) error {
+	return s.Signer.Verify(key, expires, signature)
+}
+
+func (s *Storage) Open(key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	payload, ok := s.files[key]
+
+	if !ok {
+		return nil, errors.New("file not found")
+	}
+
+	return io.NopCloser(bytes.NewReader(payload)), nil
+}
+
+func (s *Storage) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.files, key)
+	s.mu.Unlock()
+
+	return nil
+}
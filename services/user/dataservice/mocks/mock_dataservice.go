@@ -0,0 +1,1364 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/user/dataservice/dataservice.go
+//
+// Generated by this command:
+//
+//	mockgen -source=services/user/dataservice/dataservice.go -destination=services/user/dataservice/mocks/mock_dataservice.go -package=mock_dataservice
+//
+// Package mock_dataservice is a generated GoMock package.
+package mock_dataservice
+
+import (
+	io "io"
+	reflect "reflect"
+	time "time"
+	errors "warehouseai/user/errors"
+	model "warehouseai/user/model"
+
+	uuid "github.com/gofrs/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserInterface is a mock of UserInterface interface.
+type MockUserInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserInterfaceMockRecorder
+}
+
+// MockUserInterfaceMockRecorder is the mock recorder for MockUserInterface.
+type MockUserInterfaceMockRecorder struct {
+	mock *MockUserInterface
+}
+
+// NewMockUserInterface creates a new mock instance.
+func NewMockUserInterface(ctrl *gomock.Controller) *MockUserInterface {
+	mock := &MockUserInterface{ctrl: ctrl}
+	mock.recorder = &MockUserInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserInterface) EXPECT() *MockUserInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserInterface) Create(user *model.User) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", user)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserInterfaceMockRecorder) Create(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserInterface)(nil).Create), user)
+}
+
+// Delete mocks base method.
+func (m *MockUserInterface) Delete(condition map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", condition)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserInterfaceMockRecorder) Delete(condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserInterface)(nil).Delete), condition)
+}
+
+// GetOneBy mocks base method.
+func (m *MockUserInterface) GetOneBy(conditions map[string]any) (*model.User, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOneBy", conditions)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetOneBy indicates an expected call of GetOneBy.
+func (mr *MockUserInterfaceMockRecorder) GetOneBy(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneBy", reflect.TypeOf((*MockUserInterface)(nil).GetOneBy), conditions)
+}
+
+// GetOneByPreload mocks base method.
+func (m *MockUserInterface) GetOneByPreload(conditions map[string]any, preload string) (*model.User, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOneByPreload", conditions, preload)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetOneByPreload indicates an expected call of GetOneByPreload.
+func (mr *MockUserInterfaceMockRecorder) GetOneByPreload(conditions, preload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneByPreload", reflect.TypeOf((*MockUserInterface)(nil).GetOneByPreload), conditions, preload)
+}
+
+// RawUpdate mocks base method.
+func (m *MockUserInterface) RawUpdate(userId string, updatedFields any) (*model.User, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RawUpdate", userId, updatedFields)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// RawUpdate indicates an expected call of RawUpdate.
+func (mr *MockUserInterfaceMockRecorder) RawUpdate(userId, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RawUpdate", reflect.TypeOf((*MockUserInterface)(nil).RawUpdate), userId, updatedFields)
+}
+
+// Update mocks base method.
+func (m *MockUserInterface) Update(userId string, newValues map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, newValues)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockUserInterfaceMockRecorder) Update(userId, newValues any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserInterface)(nil).Update), userId, newValues)
+}
+
+// MockFavoritesInterface is a mock of FavoritesInterface interface.
+type MockFavoritesInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFavoritesInterfaceMockRecorder
+}
+
+// MockFavoritesInterfaceMockRecorder is the mock recorder for MockFavoritesInterface.
+type MockFavoritesInterfaceMockRecorder struct {
+	mock *MockFavoritesInterface
+}
+
+// NewMockFavoritesInterface creates a new mock instance.
+func NewMockFavoritesInterface(ctrl *gomock.Controller) *MockFavoritesInterface {
+	mock := &MockFavoritesInterface{ctrl: ctrl}
+	mock.recorder = &MockFavoritesInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFavoritesInterface) EXPECT() *MockFavoritesInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockFavoritesInterface) Add(favorite *model.UserFavorite) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", favorite)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockFavoritesInterfaceMockRecorder) Add(favorite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFavoritesInterface)(nil).Add), favorite)
+}
+
+// Delete mocks base method.
+func (m *MockFavoritesInterface) Delete(userId, aiId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, aiId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFavoritesInterfaceMockRecorder) Delete(userId, aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFavoritesInterface)(nil).Delete), userId, aiId)
+}
+
+// GetFavorite mocks base method.
+func (m *MockFavoritesInterface) GetFavorite(userId, aiId string) (*model.UserFavorite, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFavorite", userId, aiId)
+	ret0, _ := ret[0].(*model.UserFavorite)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetFavorite indicates an expected call of GetFavorite.
+func (mr *MockFavoritesInterfaceMockRecorder) GetFavorite(userId, aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavorite", reflect.TypeOf((*MockFavoritesInterface)(nil).GetFavorite), userId, aiId)
+}
+
+// GetUserFavorites mocks base method.
+func (m *MockFavoritesInterface) GetUserFavorites(userId string) (*[]model.UserFavorite, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserFavorites", userId)
+	ret0, _ := ret[0].(*[]model.UserFavorite)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetUserFavorites indicates an expected call of GetUserFavorites.
+func (mr *MockFavoritesInterfaceMockRecorder) GetUserFavorites(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserFavorites", reflect.TypeOf((*MockFavoritesInterface)(nil).GetUserFavorites), userId)
+}
+
+// GetUserFavoritesPage mocks base method.
+func (m *MockFavoritesInterface) GetUserFavoritesPage(userId string, after *model.Cursor, limit int) (*[]model.UserFavorite, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserFavoritesPage", userId, after, limit)
+	ret0, _ := ret[0].(*[]model.UserFavorite)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetUserFavoritesPage indicates an expected call of GetUserFavoritesPage.
+func (mr *MockFavoritesInterfaceMockRecorder) GetUserFavoritesPage(userId, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserFavoritesPage", reflect.TypeOf((*MockFavoritesInterface)(nil).GetUserFavoritesPage), userId, after, limit)
+}
+
+// MockCollectionInterface is a mock of CollectionInterface interface.
+type MockCollectionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionInterfaceMockRecorder
+}
+
+// MockCollectionInterfaceMockRecorder is the mock recorder for MockCollectionInterface.
+type MockCollectionInterfaceMockRecorder struct {
+	mock *MockCollectionInterface
+}
+
+// NewMockCollectionInterface creates a new mock instance.
+func NewMockCollectionInterface(ctrl *gomock.Controller) *MockCollectionInterface {
+	mock := &MockCollectionInterface{ctrl: ctrl}
+	mock.recorder = &MockCollectionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionInterface) EXPECT() *MockCollectionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockCollectionInterface) AddItem(item *model.CollectionItem) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", item)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockCollectionInterfaceMockRecorder) AddItem(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockCollectionInterface)(nil).AddItem), item)
+}
+
+// Count mocks base method.
+func (m *MockCollectionInterface) Count(userId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockCollectionInterfaceMockRecorder) Count(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockCollectionInterface)(nil).Count), userId)
+}
+
+// Create mocks base method.
+func (m *MockCollectionInterface) Create(collection *model.FavoriteCollection) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", collection)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCollectionInterfaceMockRecorder) Create(collection any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCollectionInterface)(nil).Create), collection)
+}
+
+// Delete mocks base method.
+func (m *MockCollectionInterface) Delete(collectionId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", collectionId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCollectionInterfaceMockRecorder) Delete(collectionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCollectionInterface)(nil).Delete), collectionId)
+}
+
+// DeleteItem mocks base method.
+func (m *MockCollectionInterface) DeleteItem(collectionId, aiId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", collectionId, aiId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockCollectionInterfaceMockRecorder) DeleteItem(collectionId, aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockCollectionInterface)(nil).DeleteItem), collectionId, aiId)
+}
+
+// DeleteUserItems mocks base method.
+func (m *MockCollectionInterface) DeleteUserItems(userId, aiId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserItems", userId, aiId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// DeleteUserItems indicates an expected call of DeleteUserItems.
+func (mr *MockCollectionInterfaceMockRecorder) DeleteUserItems(userId, aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserItems", reflect.TypeOf((*MockCollectionInterface)(nil).DeleteUserItems), userId, aiId)
+}
+
+// Get mocks base method.
+func (m *MockCollectionInterface) Get(conditions map[string]any) (*model.FavoriteCollection, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.FavoriteCollection)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCollectionInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCollectionInterface)(nil).Get), conditions)
+}
+
+// GetItemIds mocks base method.
+func (m *MockCollectionInterface) GetItemIds(collectionId string) ([]string, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemIds", collectionId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetItemIds indicates an expected call of GetItemIds.
+func (mr *MockCollectionInterfaceMockRecorder) GetItemIds(collectionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemIds", reflect.TypeOf((*MockCollectionInterface)(nil).GetItemIds), collectionId)
+}
+
+// GetItems mocks base method.
+func (m *MockCollectionInterface) GetItems(collectionId string, after *model.Cursor, limit int) (*[]model.CollectionItem, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", collectionId, after, limit)
+	ret0, _ := ret[0].(*[]model.CollectionItem)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockCollectionInterfaceMockRecorder) GetItems(collectionId, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockCollectionInterface)(nil).GetItems), collectionId, after, limit)
+}
+
+// GetMany mocks base method.
+func (m *MockCollectionInterface) GetMany(userId string) (*[]model.FavoriteCollection, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", userId)
+	ret0, _ := ret[0].(*[]model.FavoriteCollection)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockCollectionInterfaceMockRecorder) GetMany(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockCollectionInterface)(nil).GetMany), userId)
+}
+
+// NextItemPosition mocks base method.
+func (m *MockCollectionInterface) NextItemPosition(collectionId string) (int, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextItemPosition", collectionId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// NextItemPosition indicates an expected call of NextItemPosition.
+func (mr *MockCollectionInterfaceMockRecorder) NextItemPosition(collectionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextItemPosition", reflect.TypeOf((*MockCollectionInterface)(nil).NextItemPosition), collectionId)
+}
+
+// NextPosition mocks base method.
+func (m *MockCollectionInterface) NextPosition(userId string) (int, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextPosition", userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// NextPosition indicates an expected call of NextPosition.
+func (mr *MockCollectionInterfaceMockRecorder) NextPosition(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextPosition", reflect.TypeOf((*MockCollectionInterface)(nil).NextPosition), userId)
+}
+
+// Reorder mocks base method.
+func (m *MockCollectionInterface) Reorder(userId string, collectionIds []string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", userId, collectionIds)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockCollectionInterfaceMockRecorder) Reorder(userId, collectionIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockCollectionInterface)(nil).Reorder), userId, collectionIds)
+}
+
+// ReorderItems mocks base method.
+func (m *MockCollectionInterface) ReorderItems(collectionId string, aiIds []string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderItems", collectionId, aiIds)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// ReorderItems indicates an expected call of ReorderItems.
+func (mr *MockCollectionInterfaceMockRecorder) ReorderItems(collectionId, aiIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderItems", reflect.TypeOf((*MockCollectionInterface)(nil).ReorderItems), collectionId, aiIds)
+}
+
+// Update mocks base method.
+func (m *MockCollectionInterface) Update(collectionId string, updatedFields map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", collectionId, updatedFields)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCollectionInterfaceMockRecorder) Update(collectionId, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCollectionInterface)(nil).Update), collectionId, updatedFields)
+}
+
+// MockOwnedInterface is a mock of OwnedInterface interface.
+type MockOwnedInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnedInterfaceMockRecorder
+}
+
+// MockOwnedInterfaceMockRecorder is the mock recorder for MockOwnedInterface.
+type MockOwnedInterfaceMockRecorder struct {
+	mock *MockOwnedInterface
+}
+
+// NewMockOwnedInterface creates a new mock instance.
+func NewMockOwnedInterface(ctrl *gomock.Controller) *MockOwnedInterface {
+	mock := &MockOwnedInterface{ctrl: ctrl}
+	mock.recorder = &MockOwnedInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOwnedInterface) EXPECT() *MockOwnedInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockOwnedInterface) Add(own *model.UserOwn) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", own)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOwnedInterfaceMockRecorder) Add(own any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOwnedInterface)(nil).Add), own)
+}
+
+// Delete mocks base method.
+func (m *MockOwnedInterface) Delete(userId, aiId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, aiId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOwnedInterfaceMockRecorder) Delete(userId, aiId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOwnedInterface)(nil).Delete), userId, aiId)
+}
+
+// GetUserOwned mocks base method.
+func (m *MockOwnedInterface) GetUserOwned(userId string) (*[]model.UserOwn, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOwned", userId)
+	ret0, _ := ret[0].(*[]model.UserOwn)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetUserOwned indicates an expected call of GetUserOwned.
+func (mr *MockOwnedInterfaceMockRecorder) GetUserOwned(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOwned", reflect.TypeOf((*MockOwnedInterface)(nil).GetUserOwned), userId)
+}
+
+// MockFollowInterface is a mock of FollowInterface interface.
+type MockFollowInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowInterfaceMockRecorder
+}
+
+// MockFollowInterfaceMockRecorder is the mock recorder for MockFollowInterface.
+type MockFollowInterfaceMockRecorder struct {
+	mock *MockFollowInterface
+}
+
+// NewMockFollowInterface creates a new mock instance.
+func NewMockFollowInterface(ctrl *gomock.Controller) *MockFollowInterface {
+	mock := &MockFollowInterface{ctrl: ctrl}
+	mock.recorder = &MockFollowInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowInterface) EXPECT() *MockFollowInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockFollowInterface) Add(follow *model.UserFollow) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", follow)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockFollowInterfaceMockRecorder) Add(follow any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockFollowInterface)(nil).Add), follow)
+}
+
+// CountFollowers mocks base method.
+func (m *MockFollowInterface) CountFollowers(developerId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFollowers", developerId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// CountFollowers indicates an expected call of CountFollowers.
+func (mr *MockFollowInterfaceMockRecorder) CountFollowers(developerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFollowers", reflect.TypeOf((*MockFollowInterface)(nil).CountFollowers), developerId)
+}
+
+// Delete mocks base method.
+func (m *MockFollowInterface) Delete(followerId, developerId string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", followerId, developerId)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFollowInterfaceMockRecorder) Delete(followerId, developerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFollowInterface)(nil).Delete), followerId, developerId)
+}
+
+// GetFollowerIds mocks base method.
+func (m *MockFollowInterface) GetFollowerIds(developerId string) ([]uuid.UUID, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowerIds", developerId)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetFollowerIds indicates an expected call of GetFollowerIds.
+func (mr *MockFollowInterfaceMockRecorder) GetFollowerIds(developerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowerIds", reflect.TypeOf((*MockFollowInterface)(nil).GetFollowerIds), developerId)
+}
+
+// GetFollowing mocks base method.
+func (m *MockFollowInterface) GetFollowing(followerId string) (*[]model.UserFollow, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowing", followerId)
+	ret0, _ := ret[0].(*[]model.UserFollow)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetFollowing indicates an expected call of GetFollowing.
+func (mr *MockFollowInterfaceMockRecorder) GetFollowing(followerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowing", reflect.TypeOf((*MockFollowInterface)(nil).GetFollowing), followerId)
+}
+
+// MockNotificationInterface is a mock of NotificationInterface interface.
+type MockNotificationInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationInterfaceMockRecorder
+}
+
+// MockNotificationInterfaceMockRecorder is the mock recorder for MockNotificationInterface.
+type MockNotificationInterfaceMockRecorder struct {
+	mock *MockNotificationInterface
+}
+
+// NewMockNotificationInterface creates a new mock instance.
+func NewMockNotificationInterface(ctrl *gomock.Controller) *MockNotificationInterface {
+	mock := &MockNotificationInterface{ctrl: ctrl}
+	mock.recorder = &MockNotificationInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationInterface) EXPECT() *MockNotificationInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountUnread mocks base method.
+func (m *MockNotificationInterface) CountUnread(userId string) (int64, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockNotificationInterfaceMockRecorder) CountUnread(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockNotificationInterface)(nil).CountUnread), userId)
+}
+
+// CreateMany mocks base method.
+func (m *MockNotificationInterface) CreateMany(notifications []model.Notification) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", notifications)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockNotificationInterfaceMockRecorder) CreateMany(notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockNotificationInterface)(nil).CreateMany), notifications)
+}
+
+// GetAfter mocks base method.
+func (m *MockNotificationInterface) GetAfter(userId string, afterId uint, limit int) (*[]model.Notification, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAfter", userId, afterId, limit)
+	ret0, _ := ret[0].(*[]model.Notification)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetAfter indicates an expected call of GetAfter.
+func (mr *MockNotificationInterfaceMockRecorder) GetAfter(userId, afterId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAfter", reflect.TypeOf((*MockNotificationInterface)(nil).GetAfter), userId, afterId, limit)
+}
+
+// GetAll mocks base method.
+func (m *MockNotificationInterface) GetAll(userId string) (*[]model.Notification, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].(*[]model.Notification)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockNotificationInterfaceMockRecorder) GetAll(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockNotificationInterface)(nil).GetAll), userId)
+}
+
+// GetDigestPending mocks base method.
+func (m *MockNotificationInterface) GetDigestPending(limit int) (*[]model.Notification, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigestPending", limit)
+	ret0, _ := ret[0].(*[]model.Notification)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetDigestPending indicates an expected call of GetDigestPending.
+func (mr *MockNotificationInterfaceMockRecorder) GetDigestPending(limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestPending", reflect.TypeOf((*MockNotificationInterface)(nil).GetDigestPending), limit)
+}
+
+// GetLastId mocks base method.
+func (m *MockNotificationInterface) GetLastId(userId string) (uint, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastId", userId)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetLastId indicates an expected call of GetLastId.
+func (mr *MockNotificationInterfaceMockRecorder) GetLastId(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastId", reflect.TypeOf((*MockNotificationInterface)(nil).GetLastId), userId)
+}
+
+// GetPage mocks base method.
+func (m *MockNotificationInterface) GetPage(userId string, after *model.Cursor, limit int, unreadOnly bool) (*[]model.Notification, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPage", userId, after, limit, unreadOnly)
+	ret0, _ := ret[0].(*[]model.Notification)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetPage indicates an expected call of GetPage.
+func (mr *MockNotificationInterfaceMockRecorder) GetPage(userId, after, limit, unreadOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPage", reflect.TypeOf((*MockNotificationInterface)(nil).GetPage), userId, after, limit, unreadOnly)
+}
+
+// MarkEmailed mocks base method.
+func (m *MockNotificationInterface) MarkEmailed(ids []uint) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailed", ids)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// MarkEmailed indicates an expected call of MarkEmailed.
+func (mr *MockNotificationInterfaceMockRecorder) MarkEmailed(ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailed", reflect.TypeOf((*MockNotificationInterface)(nil).MarkEmailed), ids)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotificationInterface) MarkRead(userId string, ids []uint) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", userId, ids)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationInterfaceMockRecorder) MarkRead(userId, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotificationInterface)(nil).MarkRead), userId, ids)
+}
+
+// MockEmailChangeInterface is a mock of EmailChangeInterface interface.
+type MockEmailChangeInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailChangeInterfaceMockRecorder
+}
+
+// MockEmailChangeInterfaceMockRecorder is the mock recorder for MockEmailChangeInterface.
+type MockEmailChangeInterfaceMockRecorder struct {
+	mock *MockEmailChangeInterface
+}
+
+// NewMockEmailChangeInterface creates a new mock instance.
+func NewMockEmailChangeInterface(ctrl *gomock.Controller) *MockEmailChangeInterface {
+	mock := &MockEmailChangeInterface{ctrl: ctrl}
+	mock.recorder = &MockEmailChangeInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailChangeInterface) EXPECT() *MockEmailChangeInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockEmailChangeInterface) Delete(condition map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", condition)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockEmailChangeInterfaceMockRecorder) Delete(condition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEmailChangeInterface)(nil).Delete), condition)
+}
+
+// GetByToken mocks base method.
+func (m *MockEmailChangeInterface) GetByToken(tokenHash string) (*model.EmailChange, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByToken", tokenHash)
+	ret0, _ := ret[0].(*model.EmailChange)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetByToken indicates an expected call of GetByToken.
+func (mr *MockEmailChangeInterfaceMockRecorder) GetByToken(tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByToken", reflect.TypeOf((*MockEmailChangeInterface)(nil).GetByToken), tokenHash)
+}
+
+// Replace mocks base method.
+func (m *MockEmailChangeInterface) Replace(change *model.EmailChange) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", change)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockEmailChangeInterfaceMockRecorder) Replace(change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockEmailChangeInterface)(nil).Replace), change)
+}
+
+// MockRoleInterface is a mock of RoleInterface interface.
+type MockRoleInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleInterfaceMockRecorder
+}
+
+// MockRoleInterfaceMockRecorder is the mock recorder for MockRoleInterface.
+type MockRoleInterfaceMockRecorder struct {
+	mock *MockRoleInterface
+}
+
+// NewMockRoleInterface creates a new mock instance.
+func NewMockRoleInterface(ctrl *gomock.Controller) *MockRoleInterface {
+	mock := &MockRoleInterface{ctrl: ctrl}
+	mock.recorder = &MockRoleInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleInterface) EXPECT() *MockRoleInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Grant mocks base method.
+func (m *MockRoleInterface) Grant(grant *model.RoleGrant) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grant", grant)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Grant indicates an expected call of Grant.
+func (mr *MockRoleInterfaceMockRecorder) Grant(grant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MockRoleInterface)(nil).Grant), grant)
+}
+
+// Revoke mocks base method.
+func (m *MockRoleInterface) Revoke(userId string, role model.UserRole) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", userId, role)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRoleInterfaceMockRecorder) Revoke(userId, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRoleInterface)(nil).Revoke), userId, role)
+}
+
+// MockDeveloperInterface is a mock of DeveloperInterface interface.
+type MockDeveloperInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeveloperInterfaceMockRecorder
+}
+
+// MockDeveloperInterfaceMockRecorder is the mock recorder for MockDeveloperInterface.
+type MockDeveloperInterfaceMockRecorder struct {
+	mock *MockDeveloperInterface
+}
+
+// NewMockDeveloperInterface creates a new mock instance.
+func NewMockDeveloperInterface(ctrl *gomock.Controller) *MockDeveloperInterface {
+	mock := &MockDeveloperInterface{ctrl: ctrl}
+	mock.recorder = &MockDeveloperInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeveloperInterface) EXPECT() *MockDeveloperInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockDeveloperInterface) Get(conditions map[string]any) (*model.DeveloperApplication, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.DeveloperApplication)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeveloperInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeveloperInterface)(nil).Get), conditions)
+}
+
+// GetMany mocks base method.
+func (m *MockDeveloperInterface) GetMany(conditions map[string]any, limit int) (*[]model.DeveloperApplication, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", conditions, limit)
+	ret0, _ := ret[0].(*[]model.DeveloperApplication)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockDeveloperInterfaceMockRecorder) GetMany(conditions, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockDeveloperInterface)(nil).GetMany), conditions, limit)
+}
+
+// Replace mocks base method.
+func (m *MockDeveloperInterface) Replace(application *model.DeveloperApplication) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", application)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockDeveloperInterfaceMockRecorder) Replace(application any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockDeveloperInterface)(nil).Replace), application)
+}
+
+// Review mocks base method.
+func (m *MockDeveloperInterface) Review(applicationId string, status model.ApplicationStatus, reviewedBy uuid.NullUUID, comment string) (*model.DeveloperApplication, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", applicationId, status, reviewedBy, comment)
+	ret0, _ := ret[0].(*model.DeveloperApplication)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockDeveloperInterfaceMockRecorder) Review(applicationId, status, reviewedBy, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockDeveloperInterface)(nil).Review), applicationId, status, reviewedBy, comment)
+}
+
+// MockDeletionInterface is a mock of DeletionInterface interface.
+type MockDeletionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeletionInterfaceMockRecorder
+}
+
+// MockDeletionInterfaceMockRecorder is the mock recorder for MockDeletionInterface.
+type MockDeletionInterfaceMockRecorder struct {
+	mock *MockDeletionInterface
+}
+
+// NewMockDeletionInterface creates a new mock instance.
+func NewMockDeletionInterface(ctrl *gomock.Controller) *MockDeletionInterface {
+	mock := &MockDeletionInterface{ctrl: ctrl}
+	mock.recorder = &MockDeletionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeletionInterface) EXPECT() *MockDeletionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeletionInterface) Create(deletion *model.AccountDeletion) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", deletion)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeletionInterfaceMockRecorder) Create(deletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeletionInterface)(nil).Create), deletion)
+}
+
+// Get mocks base method.
+func (m *MockDeletionInterface) Get(conditions map[string]any) (*model.AccountDeletion, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeletionInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeletionInterface)(nil).Get), conditions)
+}
+
+// GetDue mocks base method.
+func (m *MockDeletionInterface) GetDue(now time.Time, limit int) (*[]model.AccountDeletion, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDue", now, limit)
+	ret0, _ := ret[0].(*[]model.AccountDeletion)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetDue indicates an expected call of GetDue.
+func (mr *MockDeletionInterfaceMockRecorder) GetDue(now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDue", reflect.TypeOf((*MockDeletionInterface)(nil).GetDue), now, limit)
+}
+
+// GetStale mocks base method.
+func (m *MockDeletionInterface) GetStale(before time.Time, limit int) (*[]model.AccountDeletion, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", before, limit)
+	ret0, _ := ret[0].(*[]model.AccountDeletion)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockDeletionInterfaceMockRecorder) GetStale(before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockDeletionInterface)(nil).GetStale), before, limit)
+}
+
+// Transition mocks base method.
+func (m *MockDeletionInterface) Transition(deletionId string, from model.DeletionStatus, updatedFields map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", deletionId, from, updatedFields)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockDeletionInterfaceMockRecorder) Transition(deletionId, from, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockDeletionInterface)(nil).Transition), deletionId, from, updatedFields)
+}
+
+// UpdateStep mocks base method.
+func (m *MockDeletionInterface) UpdateStep(deletionId string, step model.DeletionStep, status model.DeletionStepStatus, stepError string) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStep", deletionId, step, status, stepError)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// UpdateStep indicates an expected call of UpdateStep.
+func (mr *MockDeletionInterfaceMockRecorder) UpdateStep(deletionId, step, status, stepError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStep", reflect.TypeOf((*MockDeletionInterface)(nil).UpdateStep), deletionId, step, status, stepError)
+}
+
+// MockExportInterface is a mock of ExportInterface interface.
+type MockExportInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportInterfaceMockRecorder
+}
+
+// MockExportInterfaceMockRecorder is the mock recorder for MockExportInterface.
+type MockExportInterfaceMockRecorder struct {
+	mock *MockExportInterface
+}
+
+// NewMockExportInterface creates a new mock instance.
+func NewMockExportInterface(ctrl *gomock.Controller) *MockExportInterface {
+	mock := &MockExportInterface{ctrl: ctrl}
+	mock.recorder = &MockExportInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportInterface) EXPECT() *MockExportInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockExportInterface) Create(export *model.DataExport) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", export)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockExportInterfaceMockRecorder) Create(export any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExportInterface)(nil).Create), export)
+}
+
+// Get mocks base method.
+func (m *MockExportInterface) Get(conditions map[string]any) (*model.DataExport, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", conditions)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExportInterfaceMockRecorder) Get(conditions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExportInterface)(nil).Get), conditions)
+}
+
+// GetExpired mocks base method.
+func (m *MockExportInterface) GetExpired(now time.Time, limit int) (*[]model.DataExport, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", now, limit)
+	ret0, _ := ret[0].(*[]model.DataExport)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockExportInterfaceMockRecorder) GetExpired(now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockExportInterface)(nil).GetExpired), now, limit)
+}
+
+// GetMany mocks base method.
+func (m *MockExportInterface) GetMany(conditions map[string]any, limit int) (*[]model.DataExport, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", conditions, limit)
+	ret0, _ := ret[0].(*[]model.DataExport)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockExportInterfaceMockRecorder) GetMany(conditions, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockExportInterface)(nil).GetMany), conditions, limit)
+}
+
+// GetStale mocks base method.
+func (m *MockExportInterface) GetStale(before time.Time, limit int) (*[]model.DataExport, *errors.DBError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStale", before, limit)
+	ret0, _ := ret[0].(*[]model.DataExport)
+	ret1, _ := ret[1].(*errors.DBError)
+	return ret0, ret1
+}
+
+// GetStale indicates an expected call of GetStale.
+func (mr *MockExportInterfaceMockRecorder) GetStale(before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStale", reflect.TypeOf((*MockExportInterface)(nil).GetStale), before, limit)
+}
+
+// Transition mocks base method.
+func (m *MockExportInterface) Transition(exportId string, from model.ExportStatus, updatedFields map[string]any) *errors.DBError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", exportId, from, updatedFields)
+	ret0, _ := ret[0].(*errors.DBError)
+	return ret0
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockExportInterfaceMockRecorder) Transition(exportId, from, updatedFields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockExportInterface)(nil).Transition), exportId, from, updatedFields)
+}
+
+// MockExportStorageInterface is a mock of ExportStorageInterface interface.
+type MockExportStorageInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportStorageInterfaceMockRecorder
+}
+
+// MockExportStorageInterfaceMockRecorder is the mock recorder for MockExportStorageInterface.
+type MockExportStorageInterfaceMockRecorder struct {
+	mock *MockExportStorageInterface
+}
+
+// NewMockExportStorageInterface creates a new mock instance.
+func NewMockExportStorageInterface(ctrl *gomock.Controller) *MockExportStorageInterface {
+	mock := &MockExportStorageInterface{ctrl: ctrl}
+	mock.recorder = &MockExportStorageInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportStorageInterface) EXPECT() *MockExportStorageInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockExportStorageInterface) Delete(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockExportStorageInterfaceMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockExportStorageInterface)(nil).Delete), key)
+}
+
+// PresignDownload mocks base method.
+func (m *MockExportStorageInterface) PresignDownload(key string, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignDownload", key, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignDownload indicates an expected call of PresignDownload.
+func (mr *MockExportStorageInterfaceMockRecorder) PresignDownload(key, expires any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignDownload", reflect.TypeOf((*MockExportStorageInterface)(nil).PresignDownload), key, expires)
+}
+
+// Save mocks base method.
+func (m *MockExportStorageInterface) Save(key string, file io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", key, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockExportStorageInterfaceMockRecorder) Save(key, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockExportStorageInterface)(nil).Save), key, file)
+}
+
+// MockLocalExportStorageInterface is a mock of LocalExportStorageInterface interface.
+type MockLocalExportStorageInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalExportStorageInterfaceMockRecorder
+}
+
+// MockLocalExportStorageInterfaceMockRecorder is the mock recorder for MockLocalExportStorageInterface.
+type MockLocalExportStorageInterfaceMockRecorder struct {
+	mock *MockLocalExportStorageInterface
+}
+
+// NewMockLocalExportStorageInterface creates a new mock instance.
+func NewMockLocalExportStorageInterface(ctrl *gomock.Controller) *MockLocalExportStorageInterface {
+	mock := &MockLocalExportStorageInterface{ctrl: ctrl}
+	mock.recorder = &MockLocalExportStorageInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalExportStorageInterface) EXPECT() *MockLocalExportStorageInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockLocalExportStorageInterface) Delete(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLocalExportStorageInterfaceMockRecorder) Delete(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLocalExportStorageInterface)(nil).Delete), key)
+}
+
+// Open mocks base method.
+func (m *MockLocalExportStorageInterface) Open(key string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", key)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockLocalExportStorageInterfaceMockRecorder) Open(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockLocalExportStorageInterface)(nil).Open), key)
+}
+
+// PresignDownload mocks base method.
+func (m *MockLocalExportStorageInterface) PresignDownload(key string, expires time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PresignDownload", key, expires)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PresignDownload indicates an expected call of PresignDownload.
+func (mr *MockLocalExportStorageInterfaceMockRecorder) PresignDownload(key, expires any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PresignDownload", reflect.TypeOf((*MockLocalExportStorageInterface)(nil).PresignDownload), key, expires)
+}
+
+// Save mocks base method.
+func (m *MockLocalExportStorageInterface) Save(key string, file io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", key, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockLocalExportStorageInterfaceMockRecorder) Save(key, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLocalExportStorageInterface)(nil).Save), key, file)
+}
+
+// Verify mocks base method.
+func (m *MockLocalExportStorageInterface) Verify(key, expires, signature string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", key, expires, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockLocalExportStorageInterfaceMockRecorder) Verify(key, expires, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockLocalExportStorageInterface)(nil).Verify), key, expires, signature)
+}
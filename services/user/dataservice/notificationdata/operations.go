@@ -0,0 +1,139 @@
+package notificationdata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const batchSize = 500
+
+type Database struct {
+	DB *gorm.DB
+}
+
+// Уведомление по уже обработанному событию пропускается, поэтому повторная доставка безопасна
+func (d *Database) CreateMany(notifications []m.Notification) *e.DBError {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	if err := d.DB.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(notifications, batchSize).Error; err != nil {
+		var pgErr *pgconn.PgError
+
+		// foreign_key_violation = 23503, получатель уже удалён
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return e.NewDBError(e.DbNotFound, "User not found.", err.Error())
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) GetPage(userId string, after *m.Cursor, limit int, unreadOnly bool) (*[]m.Notification, *e.DBError) {
+	var notifications []m.Notification
+
+	query := d.DB.Where(map[string]interface{}{"user_id": userId})
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if after != nil {
+		query = query.Where("id < ?", after.ID)
+	}
+
+	if err := query.Order("id DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &notifications, nil
+}
+
+// Все уведомления пользователя - для выгрузки данных
+func (d *Database) GetAll(userId string) (*[]m.Notification, *e.DBError) {
+	var notifications []m.Notification
+
+	if err := d.DB.Where(map[string]interface{}{"user_id": userId}).Order("id").Find(&notifications).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &notifications, nil
+}
+
+// Уведомления после afterId по возрастанию - для потока и повтора пропущенного
+func (d *Database) GetAfter(userId string, afterId uint, limit int) (*[]m.Notification, *e.DBError) {
+	var notifications []m.Notification
+
+	if err := d.DB.Where("user_id = ? AND id > ?", userId, afterId).Order("id").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &notifications, nil
+}
+
+func (d *Database) GetLastId(userId string) (uint, *e.DBError) {
+	var lastId uint
+
+	if err := d.DB.Model(&m.Notification{}).Where("user_id = ?", userId).Select("COALESCE(MAX(id), 0)").Scan(&lastId).Error; err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return lastId, nil
+}
+
+func (d *Database) CountUnread(userId string) (int64, *e.DBError) {
+	var count int64
+
+	if err := d.DB.Model(&m.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error; err != nil {
+		return 0, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return count, nil
+}
+
+// Без ids отмечает прочитанными все уведомления пользователя
+func (d *Database) MarkRead(userId string, ids []uint) *e.DBError {
+	query := d.DB.Model(&m.Notification{}).Where("user_id = ? AND read_at IS NULL", userId)
+
+	if len(ids) != 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	if err := query.Update("read_at", gorm.Expr("now()")).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+// Непрочитанные публикации для пользователей, включивших дайджест, которые ещё не уходили на почту
+func (d *Database) GetDigestPending(limit int) (*[]m.Notification, *e.DBError) {
+	var notifications []m.Notification
+
+	digestUsers := d.DB.Model(&m.User{}).Select("id").Where("email_digest")
+
+	if err := d.DB.Where("user_id IN (?) AND type IN ? AND emailed_at IS NULL AND read_at IS NULL", digestUsers, m.DigestTypes).Order("user_id, id").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &notifications, nil
+}
+
+func (d *Database) MarkEmailed(ids []uint) *e.DBError {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := d.DB.Model(&m.Notification{}).Where("id IN ?", ids).Update("emailed_at", gorm.Expr("now()")).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
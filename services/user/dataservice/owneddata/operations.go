@@ -0,0 +1,53 @@
+package owneddata
+
+import (
+	"errors"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Add(own *m.UserOwn) *e.DBError {
+	if err := d.DB.Create(own).Error; err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			// unique_violation = 23505
+			case "23505":
+				return e.NewDBError(e.DbExist, "Entity with this key/keys already exists.", err.Error())
+			// foreign_key_violation = 23503, пользователь уже удалён
+			case "23503":
+				return e.NewDBError(e.DbNotFound, "User not found.", err.Error())
+			}
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) GetUserOwned(userId string) (*[]m.UserOwn, *e.DBError) {
+	var owned []m.UserOwn
+
+	if err := d.DB.Where(map[string]interface{}{"user_id": userId}).Order("id").Find(&owned).Error; err != nil {
+		return nil, e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return &owned, nil
+}
+
+func (d *Database) Delete(userId string, aiId string) *e.DBError {
+	if err := d.DB.Where(map[string]interface{}{"user_id": userId, "ai_id": aiId}).Delete(&m.UserOwn{}).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package roledata
+
+import (
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type Database struct {
+	DB *gorm.DB
+}
+
+func (d *Database) Grant(grant *m.RoleGrant) *e.DBError {
+	if err := d.DB.Create(grant).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return e.NewDBError(e.DbExist, "Role is already granted.", err.Error())
+		}
+
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func (d *Database) Revoke(userId string, role m.UserRole) *e.DBError {
+	if err := d.DB.Where("user_id = ? AND role = ?", userId, role).Delete(&m.RoleGrant{}).Error; err != nil {
+		return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
+	}
+
+	return nil
+}
+
+func isDuplicateKeyError(err error) bool {
+	pgErr, ok := err.(*pgconn.PgError)
+	if ok {
+		// unique_violation = 23505
+		return pgErr.Code == "23505"
+
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package exportdata
+
+import (
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// Архивы приватные: скачать можно только по подписанной ссылке
+type Storage struct {
+	Bucket  string
+	Session *session.Session
+}
+
+func (s *Storage) Save(key string, file io.Reader) error {
+	uploader := s3manager.NewUploader(s.Session)
+
+	_, err := uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(s.Bucket),
+		ACL:         aws.String("private"),
+		Key:         aws.String(key),
+		ContentType: aws.String("application/zip"),
+		Body:        file,
+	})
+
+	return err
+}
+
+func (s *Storage) PresignDownload(key string, expires time.Duration) (string, error) {
+	svc := s3.New(s.Session)
+	req, _ := svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+
+	return req.Presign(expires)
+}
+
+func (s *Storage) Delete(key string) error {
+	svc := s3.New(s.Session)
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
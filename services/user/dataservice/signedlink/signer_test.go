@@ -0,0 +1,94 @@
+package signedlink
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerVerify(t *testing.T) {
+	signer := Signer{Link: "http://localhost:8000/user/export/download", Key: "secret"}
+	key := "exports/user/export.zip"
+
+	link, err := url.Parse(signer.Presign(key, time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "/user/export/download", link.Path)
+
+	query := link.Query()
+	require.Equal(t, key, query.Get("key"))
+
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	cases := []struct {
+		name      string
+		signer    Signer
+		key       string
+		expires   string
+		signature string
+		err       string
+	}{
+		{
+			name:      "valid link",
+			signer:    signer,
+			key:       key,
+			expires:   query.Get("expires"),
+			signature: query.Get("signature"),
+		},
+		{
+			name:      "another key",
+			signer:    signer,
+			key:       "exports/another/export.zip",
+			expires:   query.Get("expires"),
+			signature: query.Get("signature"),
+			err:       "invalid signature",
+		},
+		{
+			name:      "extended expiry",
+			signer:    signer,
+			key:       key,
+			expires:   strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10),
+			signature: query.Get("signature"),
+			err:       "invalid signature",
+		},
+		{
+			name:      "another signing key",
+			signer:    Signer{Link: signer.Link, Key: "another"},
+			key:       key,
+			expires:   query.Get("expires"),
+			signature: query.Get("signature"),
+			err:       "invalid signature",
+		},
+		{
+			name:      "expired link",
+			signer:    signer,
+			key:       key,
+			expires:   expired,
+			signature: signer.sign(key, time.Now().Add(-time.Minute).Unix()),
+			err:       "download link expired",
+		},
+		{
+			name:      "invalid expires",
+			signer:    signer,
+			key:       key,
+			expires:   "tomorrow",
+			signature: query.Get("signature"),
+			err:       "invalid expires",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.signer.Verify(tc.key, tc.expires, tc.signature)
+
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
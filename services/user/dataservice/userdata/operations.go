@@ -87,6 +87,10 @@ func (d *Database) RawUpdate(userId string, updatedFields interface{}) (*m.User,
 func (d *Database) Update(userId string, newValues map[string]interface{}) *e.DBError {
 	// TODO: Добавить ошибку, что такого поля из newValues не существует, если её нет в модели пользователя
 	if err := d.DB.Model(&m.User{}).Where(map[string]interface{}{"id": userId}).Updates(newValues).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return e.NewDBError(e.DbExist, "Entity with this key/keys already exists.", err.Error())
+		}
+
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return e.NewDBError(e.DbSystem, "Something went wrong.", err.Error())
 		}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Полезная нагрузка access-токена, выпущенного сервисом auth
+type AccessClaims struct {
+	Id          string   `json:"jti"`
+	Issuer      string   `json:"iss"`
+	Subject     string   `json:"sub"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"perms"`
+	IsDeveloper bool     `json:"dev"`
+	IssuedAt    int64    `json:"iat"`
+	ExpiresAt   int64    `json:"exp"`
+}
+
+// Сессия в auth на момент проверки. SessionId может отличаться от проверяемого, если тот уже ротирован.
+type SessionState struct {
+	UserId    string
+	SessionId string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,26 @@
+package model
+
+type AuditAction string
+
+// Журнал безопасности хранится в auth, здесь только действия сервиса пользователей
+const (
+	AuditPasswordChange  AuditAction = "password.change"
+	AuditEmailChangeSend AuditAction = "email.change_request"
+	AuditEmailChange     AuditAction = "email.change"
+	AuditRoleGrant       AuditAction = "role.grant"
+	AuditRoleRevoke      AuditAction = "role.revoke"
+	AuditDeveloperReview AuditAction = "developer.review"
+	// Само удаление идёт сагой, в журнал попадают запрос, подтверждение по почте и отмена
+	AuditAccountDeleteSend AuditAction = "account.delete_request"
+	AuditAccountDeleteConf AuditAction = "account.delete_confirm"
+	AuditAccountDeleteStop AuditAction = "account.delete_cancel"
+)
+
+type AuditEvent struct {
+	ActorId   string
+	Action    AuditAction
+	Target    string
+	IP        string
+	UserAgent string
+	Success   bool
+}
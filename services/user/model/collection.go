@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Папка избранных ИИ. Публичную коллекцию можно открыть по ссылке без входа.
+type FavoriteCollection struct {
+	ID         uuid.UUID        `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	UserId     uuid.UUID        `json:"-" gorm:"type:uuid;not null"`
+	Name       string           `json:"name" gorm:"type:string;not null"`
+	Position   int              `json:"position" gorm:"type:int;default:0;not null"`
+	IsPublic   bool             `json:"is_public" gorm:"default:false;not null"`
+	ShareToken *string          `json:"-" gorm:"type:string;unique"`
+	ShareLink  string           `json:"share_link,omitempty" gorm:"-"`
+	Items      []CollectionItem `json:"-" gorm:"foreignKey:CollectionId"`
+	CreatedAt  time.Time        `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+	UpdatedAt  time.Time        `json:"updated_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+type CollectionItem struct {
+	ID           uint      `json:"-" gorm:"primarykey"`
+	CollectionId uuid.UUID `json:"collection_id" gorm:"type:uuid;not null"`
+	AiId         uuid.UUID `json:"ai_id" gorm:"type:uuid;not null"`
+	Position     int       `json:"position" gorm:"type:int;default:0;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+func (CollectionItem) TableName() string {
+	return "favorite_collection_items"
+}
+
+// Позиция последней записи страницы. Избранное сортируется только по ID, коллекции - по позиции и ID.
+type Cursor struct {
+	Position int
+	ID       uint
+}
+
+type AiPage struct {
+	Items      []PublicAi `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type SharedCollection struct {
+	Name string `json:"name"`
+	AiPage
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type (
+	DeletionStatus     string
+	DeletionStep       string
+	DeletionStepStatus string
+	DeletionAction     string
+)
+
+const (
+	// Запрошено без пароля и ждёт подтверждения по ссылке из письма, в расписание ещё не попало
+	DeletionUnconfirmed DeletionStatus = "unconfirmed"
+	// Период ожидания: пользователь ещё может отменить удаление
+	DeletionScheduled    DeletionStatus = "scheduled"
+	DeletionCancelled    DeletionStatus = "cancelled"
+	DeletionInProgress   DeletionStatus = "in_progress"
+	DeletionCompensating DeletionStatus = "compensating"
+	DeletionCompleted    DeletionStatus = "completed"
+	// Шаг не выполнился, выполненные шаги откачены (или откат тоже не удался, см. Error)
+	DeletionFailed DeletionStatus = "failed"
+)
+
+const (
+	DeletionStepAuth   DeletionStep = "auth"
+	DeletionStepAi     DeletionStep = "ai"
+	DeletionStepAvatar DeletionStep = "avatar"
+	DeletionStepUser   DeletionStep = "user"
+)
+
+// Порядок шагов саги. Пользователь удаляется последним, чтобы до этого любой шаг можно было откатить.
+var DeletionSteps = []DeletionStep{DeletionStepAuth, DeletionStepAi, DeletionStepAvatar, DeletionStepUser}
+
+const (
+	StepPending     DeletionStepStatus = "pending"
+	StepDone        DeletionStepStatus = "done"
+	StepFailed      DeletionStepStatus = "failed"
+	StepCompensated DeletionStepStatus = "compensated"
+	StepSkipped     DeletionStepStatus = "skipped"
+)
+
+const (
+	DeletionExecute    DeletionAction = "execute"
+	DeletionCompensate DeletionAction = "compensate"
+	// Необратимая зачистка после завершения саги, ответ на неё не отправляется
+	DeletionPurge DeletionAction = "purge"
+)
+
+// Запись переживает пользователя, поэтому внешнего ключа на users нет
+type AccountDeletion struct {
+	ID           uuid.UUID             `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	UserId       uuid.UUID             `json:"-" gorm:"type:uuid;not null"`
+	Status       DeletionStatus        `json:"status" gorm:"type:string;default:scheduled;not null"`
+	Step         DeletionStep          `json:"step,omitempty" gorm:"type:string"`
+	Error        string                `json:"error,omitempty" gorm:"type:string"`
+	Steps        []AccountDeletionStep `json:"steps" gorm:"foreignKey:DeletionId"`
+	ScheduledFor time.Time             `json:"scheduled_for" gorm:"type:timestamp;not null"`
+	ConfirmToken *string               `json:"-" gorm:"column:confirm_token_hash;type:string;unique"`
+	CreatedAt    time.Time             `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+	UpdatedAt    time.Time             `json:"updated_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+type AccountDeletionStep struct {
+	ID         uuid.UUID          `json:"-" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	DeletionId uuid.UUID          `json:"-" gorm:"type:uuid;not null"`
+	Step       DeletionStep       `json:"step" gorm:"type:string;not null"`
+	Status     DeletionStepStatus `json:"status" gorm:"type:string;default:pending;not null"`
+	Error      string             `json:"error,omitempty" gorm:"type:string"`
+	UpdatedAt  time.Time          `json:"updated_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+type (
+	AccountDeletionCommand struct {
+		DeletionId string         `json:"deletion_id"`
+		UserId     string         `json:"user_id"`
+		Step       DeletionStep   `json:"step"`
+		Action     DeletionAction `json:"action"`
+		Picture    string         `json:"picture,omitempty"`
+	}
+
+	AccountDeletionReply struct {
+		DeletionId string         `json:"deletion_id"`
+		Step       DeletionStep   `json:"step"`
+		Action     DeletionAction `json:"action"`
+		Success    bool           `json:"success"`
+		Error      string         `json:"error,omitempty"`
+	}
+)
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type ApplicationStatus string
+
+const (
+	ApplicationPending  ApplicationStatus = "pending"
+	ApplicationApproved ApplicationStatus = "approved"
+	ApplicationRejected ApplicationStatus = "rejected"
+)
+
+// Заявка на статус разработчика. На пользователя одна заявка, повторная подача заменяет отклонённую.
+type DeveloperApplication struct {
+	ID              uuid.UUID         `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	UserId          uuid.UUID         `json:"user_id" gorm:"type:uuid;not null;unique"`
+	DisplayName     string            `json:"display_name" gorm:"type:string;not null"`
+	Website         string            `json:"website" gorm:"type:string"`
+	TermsAcceptedAt time.Time         `json:"terms_accepted_at" gorm:"type:timestamp;not null"`
+	Status          ApplicationStatus `json:"status" gorm:"type:string;default:pending;not null"`
+	// Пусто при автоматическом одобрении
+	ReviewedBy uuid.NullUUID `json:"reviewed_by" gorm:"type:uuid"`
+	Comment    string        `json:"comment" gorm:"type:string"`
+	CreatedAt  time.Time     `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+	UpdatedAt  time.Time     `json:"updated_at" gorm:"type:timestamp;default: now();not null"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Новая почта ждёт подтверждения по ссылке, в users она попадает только после него
+type EmailChange struct {
+	ID        uuid.UUID `json:"-" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	UserId    uuid.UUID `json:"-" gorm:"type:uuid;not null;unique"`
+	NewEmail  string    `json:"-" gorm:"type:string;not null"`
+	TokenHash string    `json:"-" gorm:"type:string;not null;unique"`
+	ExpiresAt time.Time `json:"-" gorm:"type:timestamp;not null;default: now() + INTERVAL '1 hour'"`
+	CreatedAt time.Time `json:"-" gorm:"type:timestamp;default: now();not null"`
+}
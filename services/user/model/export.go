@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending    ExportStatus = "pending"
+	ExportProcessing ExportStatus = "processing"
+	ExportReady      ExportStatus = "ready"
+	ExportFailed     ExportStatus = "failed"
+	// Ссылка истекла, архив удалён из хранилища
+	ExportExpired ExportStatus = "expired"
+)
+
+// Выгрузка персональных данных. Без внешнего ключа на users: архив нужно удалить из хранилища,
+// даже если пользователь удалил аккаунт раньше, чем истекла ссылка.
+type DataExport struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primarykey;default:uuid_generate_v4()"`
+	UserId    uuid.UUID    `json:"-" gorm:"type:uuid;not null"`
+	Status    ExportStatus `json:"status" gorm:"type:string;default:pending;not null"`
+	FileKey   string       `json:"-" gorm:"type:string"`
+	Error     string       `json:"error,omitempty" gorm:"type:string"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty" gorm:"type:timestamp"`
+	CreatedAt time.Time    `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+	UpdatedAt time.Time    `json:"updated_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+// Содержимое архива выгрузки: data.json целиком и по CSV на каждую таблицу
+type (
+	ExportData struct {
+		ExportedAt    time.Time            `json:"exported_at"`
+		Profile       ExportProfile        `json:"profile"`
+		Favorites     []string             `json:"favorites"`
+		Collections   []ExportCollection   `json:"collections"`
+		Follows       []ExportFollow       `json:"follows"`
+		Notifications []ExportNotification `json:"notifications"`
+		Owned         []ExportOwnedAi      `json:"owned_ai"`
+		Ratings       []ExportRating       `json:"ratings"`
+		Executions    []ExportExecution    `json:"executions"`
+		AuditEvents   []ExportAuditEvent   `json:"audit_events"`
+	}
+
+	ExportProfile struct {
+		Id          string    `json:"id"`
+		Firstname   string    `json:"firstname"`
+		Lastname    string    `json:"lastname"`
+		Username    string    `json:"username"`
+		Email       string    `json:"email"`
+		Picture     string    `json:"picture"`
+		Bio         string    `json:"bio"`
+		ViaGoogle   bool      `json:"via_google"`
+		Verified    bool      `json:"verified"`
+		IsDeveloper bool      `json:"is_developer"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	ExportCollection struct {
+		Id        string    `json:"id"`
+		Name      string    `json:"name"`
+		Position  int       `json:"position"`
+		IsPublic  bool      `json:"is_public"`
+		AiIds     []string  `json:"ai_ids"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	ExportFollow struct {
+		DeveloperId string    `json:"developer_id"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	ExportNotification struct {
+		Type      NotificationType       `json:"type"`
+		Data      map[string]interface{} `json:"data"`
+		ReadAt    *time.Time             `json:"read_at"`
+		CreatedAt time.Time              `json:"created_at"`
+	}
+
+	ExportOwnedAi struct {
+		Id          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Used        int64  `json:"used"`
+		CreatedAt   string `json:"created_at"`
+	}
+
+	ExportRating struct {
+		AiId          string `json:"ai_id"`
+		Rate          int32  `json:"rate"`
+		VerifiedUsage bool   `json:"verified_usage"`
+		CreatedAt     string `json:"created_at"`
+	}
+
+	ExportExecution struct {
+		Id        string `json:"id"`
+		AiId      string `json:"ai_id"`
+		CommandId string `json:"command_id"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	ExportAuditEvent struct {
+		Action    string `json:"action"`
+		Target    string `json:"target"`
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+		Result    string `json:"result"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	// Данные пользователя из сервиса AI
+	AiUserData struct {
+		Owned      []ExportOwnedAi
+		Ratings    []ExportRating
+		Executions []ExportExecution
+	}
+)
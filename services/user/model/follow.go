@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Подписка пользователя на разработчика
+type UserFollow struct {
+	ID          uint      `json:"-" gorm:"primarykey"`
+	FollowerId  uuid.UUID `json:"follower_id" gorm:"type:uuid;not null"`
+	DeveloperId uuid.UUID `json:"developer_id" gorm:"type:uuid;not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+func (UserFollow) TableName() string {
+	return "user_follows"
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Ответов на отзывы в сервисе ИИ пока нет, поэтому и уведомления о них нет. Появятся ответы -
+// их событие нужно будет принимать так же, как AiRatingEvent.
+type NotificationType string
+
+const (
+	NotifyAiCreated      NotificationType = "ai_created"
+	NotifyCommandCreated NotificationType = "command_created"
+	NotifyRatingReceived NotificationType = "rating_received"
+	NotifyExportReady    NotificationType = "export_ready"
+	NotifyExportFailed   NotificationType = "export_failed"
+	NotifySecurityAlert  NotificationType = "security_alert"
+)
+
+// В почтовый дайджест попадают только публикации разработчиков, на которых подписан пользователь
+var DigestTypes = []NotificationType{NotifyAiCreated, NotifyCommandCreated}
+
+// Уведомление пользователю. Data зависит от типа, EventId защищает от дублей при повторной доставке события.
+type Notification struct {
+	ID        uint                   `json:"id" gorm:"primarykey"`
+	UserId    uuid.UUID              `json:"-" gorm:"type:uuid;not null"`
+	Type      NotificationType       `json:"type" gorm:"type:string;not null"`
+	EventId   string                 `json:"-" gorm:"type:string;not null"`
+	Data      map[string]interface{} `json:"data" gorm:"type:json;serializer:json;not null"`
+	ReadAt    *time.Time             `json:"read_at" gorm:"type:timestamp"`
+	EmailedAt *time.Time             `json:"-" gorm:"type:timestamp"`
+	CreatedAt time.Time              `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+type NotificationPage struct {
+	Items       []Notification `json:"items"`
+	NextCursor  string         `json:"next_cursor,omitempty"`
+	UnreadCount int64          `json:"unread_count"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Публичные данные пользователя, без почты и прочих личных полей
+type PublicUser struct {
+	ID          uuid.UUID `json:"id"`
+	Username    string    `json:"username"`
+	Picture     string    `json:"picture"`
+	Bio         string    `json:"bio"`
+	IsDeveloper bool      `json:"is_dev"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+type PublicProfile struct {
+	PublicUser
+	Followers int64      `json:"followers"`
+	Ais       []PublicAi `json:"ais"`
+}
+
+// Опубликованный ИИ разработчика с рейтингом из сервиса ИИ
+type PublicAi struct {
+	Id            string  `json:"id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	BackgroundUrl string  `json:"background_url"`
+	Used          int64   `json:"used"`
+	AverageRating float64 `json:"avg_rating"`
+	RatingCount   int64   `json:"count_rating"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+func NewPublicUser(user *User) PublicUser {
+	return PublicUser{
+		ID:          user.ID,
+		Username:    user.Username,
+		Picture:     user.Picture,
+		Bio:         user.Bio,
+		IsDeveloper: user.IsDeveloper,
+		JoinedAt:    user.CreatedAt,
+	}
+}
@@ -4,4 +4,14 @@ type QueueName string
 
 const (
 	Reject QueueName = "token_rejected"
+
+	AccountDeleteAuth  QueueName = "account_delete_auth"
+	AccountDeleteAi    QueueName = "account_delete_ai"
+	AccountDeleteReply QueueName = "account_delete_reply"
+
+	AiOwnership QueueName = "ai_ownership"
+	AiRelease   QueueName = "ai_release"
+	AiRating    QueueName = "ai_rating"
+
+	SecurityAlert QueueName = "security_alert"
 )
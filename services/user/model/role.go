@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type UserRole string
+
+const (
+	// Есть у всех и не хранится
+	RoleUser UserRole = "USER"
+	// Хранится флагом users.is_developer, а не в user_roles
+	RoleDeveloper UserRole = "DEVELOPER"
+	RoleModerator UserRole = "MODERATOR"
+	RoleAdmin     UserRole = "ADMIN"
+)
+
+type Permission string
+
+const (
+	PermissionPublishAi        Permission = "ai:publish"
+	PermissionModerateRatings  Permission = "ratings:moderate"
+	PermissionReadStat         Permission = "stat:read"
+	PermissionManageRoles      Permission = "roles:manage"
+	PermissionReviewDevelopers Permission = "developers:review"
+	PermissionReadAudit        Permission = "audit:read"
+)
+
+// Права задаются кодом: ai, user и stat проверяют права, а не роли
+var rolePermissions = map[UserRole][]Permission{
+	RoleUser:      {},
+	RoleDeveloper: {PermissionPublishAi},
+	RoleModerator: {PermissionModerateRatings, PermissionReadStat},
+	RoleAdmin:     {PermissionModerateRatings, PermissionReadStat, PermissionManageRoles, PermissionReviewDevelopers, PermissionReadAudit},
+}
+
+// Выданная роль. Права уходят в access-токены, поэтому отзыв роли полностью
+// вступает в силу, когда истекут уже выпущенные токены.
+type RoleGrant struct {
+	ID        uint      `json:"-" gorm:"primarykey"`
+	UserId    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_user_role"`
+	Role      UserRole  `json:"role" gorm:"type:string;not null;uniqueIndex:idx_user_role"`
+	GrantedBy uuid.UUID `json:"granted_by" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;default: now();not null"`
+}
+
+func (RoleGrant) TableName() string {
+	return "user_roles"
+}
+
+func IsKnownRole(role UserRole) bool {
+	_, ok := rolePermissions[role]
+
+	return ok
+}
+
+// Роли пользователя. Roles должны быть загружены через Preload("Roles").
+func (u *User) RoleNames() []string {
+	roles := []string{string(RoleUser)}
+
+	if u.IsDeveloper {
+		roles = append(roles, string(RoleDeveloper))
+	}
+
+	for _, grant := range u.Roles {
+		if grant.Role != RoleUser && grant.Role != RoleDeveloper {
+			roles = append(roles, string(grant.Role))
+		}
+	}
+
+	return roles
+}
+
+// Объединение прав всех ролей без повторов
+func Permissions(roles []string) []string {
+	seen := make(map[Permission]bool)
+	permissions := make([]string, 0)
+
+	for _, role := range roles {
+		for _, permission := range rolePermissions[UserRole(role)] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, string(permission))
+			}
+		}
+	}
+
+	return permissions
+}
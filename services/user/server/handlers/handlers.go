@@ -1,10 +1,22 @@
 package handlers
 
 import (
+	"time"
 	"warehouseai/user/adapter/broker"
 	"warehouseai/user/adapter/grpc/client/ai"
 	"warehouseai/user/adapter/grpc/client/auth"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	"warehouseai/user/dataservice/collectiondata"
+	"warehouseai/user/dataservice/deletiondata"
+	"warehouseai/user/dataservice/developerdata"
+	"warehouseai/user/dataservice/emailchangedata"
+	"warehouseai/user/dataservice/exportdata"
 	"warehouseai/user/dataservice/favoritesdata"
+	"warehouseai/user/dataservice/followdata"
+	"warehouseai/user/dataservice/notificationdata"
+	"warehouseai/user/dataservice/owneddata"
+	"warehouseai/user/dataservice/roledata"
 	"warehouseai/user/dataservice/userdata"
 	e "warehouseai/user/errors"
 	m "warehouseai/user/model"
@@ -12,15 +24,31 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
 )
 
 type Handler struct {
-	UserDB      *userdata.Database
-	FavoritesDB *favoritesdata.Database
-	Logger      *logrus.Logger
-	Broker      *broker.Broker
-	AiClient    *ai.AiGrpcClient
-	AuthClient  *auth.AuthGrpcClient
+	UserDB        *userdata.Database
+	FavoritesDB   *favoritesdata.Database
+	CollectionDB  *collectiondata.Database
+	OwnedDB       *owneddata.Database
+	FollowDB      *followdata.Database
+	NotifyDB      *notificationdata.Database
+	EmailDB       *emailchangedata.Database
+	RoleDB        *roledata.Database
+	DeveloperDB   *developerdata.Database
+	DeletionDB    *deletiondata.Database
+	ExportDB      *exportdata.Database
+	ExportStorage d.ExportStorageInterface
+	Logger        *logrus.Logger
+	Broker        *broker.Broker
+	AiClient      *ai.AiGrpcClient
+	AuthClient    *auth.AuthGrpcClient
+	DeveloperCfg  config.DeveloperCfg
+	DeletionCfg   config.DeletionCfg
+	ExportCfg     config.ExportCfg
+	NotifyCfg     config.NotificationCfg
+	Hub           *service.NotificationHub
 }
 
 func (h *Handler) UpdatePersonalDataHandler(c *fiber.Ctx) error {
@@ -48,7 +76,21 @@ func (h *Handler) UpdateEmailHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
 	}
 
-	if err := service.UpdateUserEmail(newEmail, userId, h.UserDB, h.Broker, h.Logger); err != nil {
+	err := service.UpdateUserEmail(newEmail, userId, h.UserDB, h.EmailDB, h.Broker, h.Logger)
+	h.audit(c, m.AuditEmailChangeSend, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) ConfirmEmailHandler(c *fiber.Ctx) error {
+	userId, err := service.ConfirmUserEmail(c.Query("token"), h.UserDB, h.EmailDB, h.Broker, h.Logger)
+	h.audit(c, m.AuditEmailChange, "", userId, err)
+
+	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
 	}
 
@@ -63,17 +105,122 @@ func (h *Handler) UpdatePasswordHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
 	}
 
-	if err := service.UpdateUserPassword(request, user, h.UserDB, h.Logger); err != nil {
+	err := service.UpdateUserPassword(request, user, c.Locals("sessionId").(string), h.UserDB, h.AuthClient, h.Logger)
+	h.audit(c, m.AuditPasswordChange, user.ID.String(), user.ID.String(), err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) DeleteAccountHandler(c *fiber.Ctx) error {
+	var request service.DeleteAccountRequest
+	user := c.Locals("user").(*m.User)
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	deletion, err := service.RequestAccountDeletion(request, user, h.DeletionDB, h.Broker, h.DeletionCfg, h.Logger)
+	h.audit(c, m.AuditAccountDeleteSend, user.ID.String(), user.ID.String(), err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(deletion)
+}
+
+func (h *Handler) ConfirmAccountDeletionHandler(c *fiber.Ctx) error {
+	userId, err := service.ConfirmAccountDeletion(c.Query("token"), h.UserDB, h.DeletionDB, h.Broker, h.DeletionCfg, h.Logger)
+	h.audit(c, m.AuditAccountDeleteConf, userId, userId, err)
+
+	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
 }
 
+func (h *Handler) CancelAccountDeletionHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+
+	deletion, err := service.CancelAccountDeletion(userId, h.DeletionDB, h.Logger)
+	h.audit(c, m.AuditAccountDeleteStop, userId, userId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deletion)
+}
+
+func (h *Handler) GetAccountDeletionHandler(c *fiber.Ctx) error {
+	deletion, err := service.GetAccountDeletion(c.Locals("userId").(string), h.DeletionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(deletion)
+}
+
+func (h *Handler) RequestExportHandler(c *fiber.Ctx) error {
+	export, err := service.RequestDataExport(c.Locals("userId").(string), h.ExportDB, h.ExportCfg, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(export)
+}
+
+func (h *Handler) GetExportHandler(c *fiber.Ctx) error {
+	export, err := service.GetDataExport(c.Locals("userId").(string), h.ExportDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(export)
+}
+
+// Без S3 архив отдаётся самим сервисом по ссылке из письма
+func (h *Handler) LocalDownloadHandler(c *fiber.Ctx) error {
+	storage, ok := h.ExportStorage.(d.LocalExportStorageInterface)
+
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	key := c.Query("key")
+
+	if err := storage.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		response := e.NewErrorResponse(e.HttpForbidden, err.Error())
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	file, err := storage.Open(key)
+
+	if err != nil {
+		h.Logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Local download")
+		response := e.NewErrorResponse(e.HttpNotFound, "Export file not found.")
+		return c.Status(response.ErrorCode).JSON(response)
+	}
+
+	c.Attachment("warehouseai-data.zip")
+
+	return c.SendStream(file)
+}
+
 func (h *Handler) GetFavoritesHandler(c *fiber.Ctx) error {
 	userId := c.Locals("userId").(string)
 
-	favorites, err := service.GetFavorites(&service.GetFavoritesRequest{UserId: userId}, h.FavoritesDB, h.Logger)
+	request := &service.GetFavoritesRequest{UserId: userId, Cursor: c.Query("cursor"), Limit: c.QueryInt("limit")}
+
+	favorites, err := service.GetFavorites(request, h.FavoritesDB, h.AiClient, h.Logger)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
@@ -105,17 +252,164 @@ func (h *Handler) RemoveFavoriteHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
 	}
 
-	if svcErr := service.RemoveFavorite(userId, request, h.FavoritesDB, h.Logger); svcErr != nil {
+	if svcErr := service.RemoveFavorite(userId, request, h.FavoritesDB, h.CollectionDB, h.Logger); svcErr != nil {
 		return c.Status(svcErr.ErrorCode).JSON(svcErr)
 	}
 
 	return c.SendStatus(fiber.StatusOK)
 }
 
+func (h *Handler) CreateCollectionHandler(c *fiber.Ctx) error {
+	var request service.CreateCollectionRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	collection, err := service.CreateCollection(c.Locals("userId").(string), request, h.CollectionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(collection)
+}
+
+func (h *Handler) GetCollectionsHandler(c *fiber.Ctx) error {
+	collections, err := service.GetCollections(c.Locals("userId").(string), h.CollectionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(collections)
+}
+
+func (h *Handler) UpdateCollectionHandler(c *fiber.Ctx) error {
+	var request service.UpdateCollectionRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	collection, err := service.UpdateCollection(c.Locals("userId").(string), request, h.CollectionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(collection)
+}
+
+func (h *Handler) DeleteCollectionHandler(c *fiber.Ctx) error {
+	var request service.DeleteCollectionRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.DeleteCollection(c.Locals("userId").(string), request, h.CollectionDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) ReorderCollectionsHandler(c *fiber.Ctx) error {
+	var request service.ReorderCollectionsRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	collections, err := service.ReorderCollections(c.Locals("userId").(string), request, h.CollectionDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(collections)
+}
+
+func (h *Handler) AddCollectionItemHandler(c *fiber.Ctx) error {
+	var request service.CollectionItemRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.AddCollectionItem(c.Locals("userId").(string), request, h.CollectionDB, h.FavoritesDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) RemoveCollectionItemHandler(c *fiber.Ctx) error {
+	var request service.CollectionItemRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.RemoveCollectionItem(c.Locals("userId").(string), request, h.CollectionDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) ReorderCollectionItemsHandler(c *fiber.Ctx) error {
+	var request service.ReorderCollectionItemsRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.ReorderCollectionItems(c.Locals("userId").(string), request, h.CollectionDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) GetCollectionItemsHandler(c *fiber.Ctx) error {
+	request := service.GetCollectionItemsRequest{CollectionId: c.Query("id"), Cursor: c.Query("cursor"), Limit: c.QueryInt("limit")}
+
+	page, err := service.GetCollectionItems(c.Locals("userId").(string), request, h.CollectionDB, h.AiClient, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+func (h *Handler) GetSharedCollectionHandler(c *fiber.Ctx) error {
+	request := service.GetCollectionItemsRequest{Cursor: c.Query("cursor"), Limit: c.QueryInt("limit")}
+
+	collection, err := service.GetSharedCollection(c.Query("token"), request, h.CollectionDB, h.AiClient, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(collection)
+}
+
+// Доступен всем, поэтому отдаёт только публичные поля. Свои данные целиком - /user/me.
 func (h *Handler) GetUserById(c *fiber.Ctx) error {
-	userId := c.Query("id")
+	publicUser, err := service.GetPublicUser(c.Query("id"), h.UserDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
 
-	existUser, err := service.GetById(userId, h.UserDB, h.Logger)
+	return c.Status(fiber.StatusOK).JSON(publicUser)
+}
+
+func (h *Handler) GetMeHandler(c *fiber.Ctx) error {
+	existUser, err := service.GetById(c.Locals("userId").(string), h.UserDB, h.Logger)
 
 	if err != nil {
 		return c.Status(err.ErrorCode).JSON(err)
@@ -123,3 +417,222 @@ func (h *Handler) GetUserById(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(existUser)
 }
+
+func (h *Handler) GetProfileHandler(c *fiber.Ctx) error {
+	profile, err := service.GetPublicProfile(c.Query("username"), h.UserDB, h.OwnedDB, h.FollowDB, h.AiClient, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(profile)
+}
+
+func (h *Handler) FollowHandler(c *fiber.Ctx) error {
+	var request service.FollowRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.Follow(c.Locals("userId").(string), request, h.UserDB, h.FollowDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) UnfollowHandler(c *fiber.Ctx) error {
+	var request service.FollowRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.Unfollow(c.Locals("userId").(string), request, h.FollowDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) GetNotificationsHandler(c *fiber.Ctx) error {
+	request := service.GetNotificationsRequest{UserId: c.Locals("userId").(string), Cursor: c.Query("cursor"), Limit: c.QueryInt("limit"), UnreadOnly: c.QueryBool("unread")}
+
+	page, err := service.GetNotifications(request, h.NotifyDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
+// Server-Sent Events: браузер сам переподключается и присылает Last-Event-ID, пропущенное отдаётся сразу
+func (h *Handler) StreamNotificationsHandler(c *fiber.Ctx) error {
+	lastEventId := c.Get("Last-Event-ID", c.Query("last_id"))
+
+	tokenExpiresAt, _ := c.Locals("tokenExpiresAt").(int64)
+	stream, err := service.OpenNotificationStream(c.Locals("userId").(string), c.Locals("sessionId").(string), time.Unix(tokenExpiresAt, 0), lastEventId, h.NotifyCfg, h.Hub, h.AuthClient, h.NotifyDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(stream.Serve))
+
+	return nil
+}
+
+func (h *Handler) MarkNotificationsReadHandler(c *fiber.Ctx) error {
+	var request service.MarkNotificationsReadRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.MarkNotificationsRead(c.Locals("userId").(string), request, h.NotifyDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) UpdateNotificationSettingsHandler(c *fiber.Ctx) error {
+	var request service.NotificationSettingsRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	if err := service.UpdateNotificationSettings(c.Locals("userId").(string), request, h.UserDB, h.Logger); err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *Handler) GetRolesHandler(c *fiber.Ctx) error {
+	response, err := service.GetUserRoles(c.Query("user_id"), h.UserDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) GrantRoleHandler(c *fiber.Ctx) error {
+	adminId := c.Locals("userId").(string)
+	var request service.RoleRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	response, err := service.GrantRole(request, adminId, h.UserDB, h.RoleDB, h.Logger)
+	h.audit(c, m.AuditRoleGrant, adminId, request.UserId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) RevokeRoleHandler(c *fiber.Ctx) error {
+	adminId := c.Locals("userId").(string)
+	var request service.RoleRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	response, err := service.RevokeRole(request, adminId, h.UserDB, h.RoleDB, h.Logger)
+	h.audit(c, m.AuditRoleRevoke, adminId, request.UserId, err)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *Handler) DeveloperApplyHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+	var request service.DeveloperApplyRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	application, err := service.ApplyForDeveloper(request, userId, h.UserDB, h.DeveloperDB, h.Broker, h.DeveloperCfg, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(application)
+}
+
+func (h *Handler) GetDeveloperApplicationHandler(c *fiber.Ctx) error {
+	userId := c.Locals("userId").(string)
+
+	application, err := service.GetDeveloperApplication(userId, h.DeveloperDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(application)
+}
+
+func (h *Handler) GetPendingApplicationsHandler(c *fiber.Ctx) error {
+	applications, err := service.GetPendingApplications(h.DeveloperDB, h.Logger)
+
+	if err != nil {
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(applications)
+}
+
+func (h *Handler) ReviewApplicationHandler(c *fiber.Ctx) error {
+	reviewerId := c.Locals("userId").(string)
+	var request service.ReviewApplicationRequest
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(e.NewErrorResponse(e.HttpBadRequest, "Invalid request body."))
+	}
+
+	application, err := service.ReviewDeveloperApplication(request, reviewerId, h.UserDB, h.DeveloperDB, h.Broker, h.Logger)
+
+	if err != nil {
+		h.audit(c, m.AuditDeveloperReview, reviewerId, request.ApplicationId, err)
+		return c.Status(err.ErrorCode).JSON(err)
+	}
+
+	h.audit(c, m.AuditDeveloperReview, reviewerId, application.UserId.String(), nil)
+
+	return c.Status(fiber.StatusOK).JSON(application)
+}
+
+// Журнал ведёт auth. Если он недоступен, действие пользователя всё равно выполняется.
+func (h *Handler) audit(c *fiber.Ctx, action m.AuditAction, actorId string, target string, err *e.ErrorResponse) {
+	event := m.AuditEvent{
+		ActorId:   actorId,
+		Action:    action,
+		Target:    target,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Success:   err == nil,
+	}
+
+	if auditErr := h.AuthClient.RecordAuditEvent(event); auditErr != nil {
+		h.Logger.WithFields(logrus.Fields{"time": time.Now(), "error": auditErr.ErrorMessage, "action": action}).Info("Record audit event")
+	}
+}
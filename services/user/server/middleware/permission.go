@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	e "warehouseai/user/errors"
+	"warehouseai/user/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Ставится после Session: права берутся из access-токена или ответа Authenticate
+func RequirePermission(permission model.Permission) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		permissions, _ := c.Locals("permissions").([]string)
+
+		for _, granted := range permissions {
+			if granted == string(permission) {
+				return c.Next()
+			}
+		}
+
+		return c.Status(e.HttpForbidden).JSON(e.NewErrorResponse(e.HttpForbidden, "Not enough permissions."))
+	}
+}
@@ -1,36 +1,88 @@
 package middleware
 
 import (
+	"time"
 	"warehouseai/user/adapter"
+	"warehouseai/user/config"
 	e "warehouseai/user/errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
-func Session(logger *logrus.Logger, auth adapter.AuthGrpcInterface) func(c *fiber.Ctx) error {
+func Session(logger *logrus.Logger, auth adapter.AuthGrpcInterface, cookie config.CookieCfg) func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		sessionId := c.Cookies("sessionId")
 
+		// Клиент с токеном в Authorization работает без сессии
+		if claims, err := auth.VerifyAccessToken(BearerToken(c)); err == nil {
+			c.Locals("userId", claims.Subject)
+			c.Locals("sessionId", "")
+			c.Locals("roles", claims.Roles)
+			c.Locals("permissions", claims.Permissions)
+			c.Locals("isDeveloper", claims.IsDeveloper)
+			c.Locals("tokenExpiresAt", claims.ExpiresAt)
+			return c.Next()
+		}
+
+		// Access-токен проверяется локально. Если его нет или он истёк, идём в auth по gRPC.
+		if claims, err := auth.VerifyAccessToken(c.Cookies("accessToken")); err == nil {
+			c.Locals("userId", claims.Subject)
+			c.Locals("sessionId", sessionId)
+			c.Locals("roles", claims.Roles)
+			c.Locals("permissions", claims.Permissions)
+			c.Locals("isDeveloper", claims.IsDeveloper)
+			c.Locals("tokenExpiresAt", claims.ExpiresAt)
+			return c.Next()
+		}
+
 		if sessionId == "" {
 			return c.Status(e.HttpUnauthorized).JSON(e.NewErrorResponse(e.HttpUnauthorized, "Empty session key."))
 		}
 
-		userId, newSessionId, authErr := auth.Authenticate(sessionId)
+		resp, authErr := auth.Authenticate(sessionId)
 
 		if authErr != nil {
 			return c.Status(authErr.ErrorCode).JSON(authErr)
 		}
 
-		c.ClearCookie("sessionId")
-		c.Cookie(&fiber.Cookie{
-			Name:     "sessionId",
-			Value:    newSessionId,
-			SameSite: fiber.CookieSameSiteNoneMode,
-			Secure:   true,
-		})
+		// Сессия продлевается на стороне auth, cookie меняется только после ротации ID
+		if resp.SessionId != sessionId {
+			c.Cookie(&fiber.Cookie{
+				Name:     "sessionId",
+				Value:    resp.SessionId,
+				SameSite: cookie.SameSite,
+				Secure:   true,
+			})
+		}
+
+		c.Locals("userId", resp.UserId)
+		c.Locals("sessionId", resp.SessionId)
+		c.Locals("roles", resp.Roles)
+		c.Locals("permissions", resp.Permissions)
+
+		// Свежий токен сразу проверяем: так заодно подтягиваются ключи для следующих запросов
+		if claims, err := auth.VerifyAccessToken(resp.AccessToken); err == nil {
+			c.Cookie(&fiber.Cookie{
+				Name:     "accessToken",
+				Value:    resp.AccessToken,
+				Expires:  time.Unix(claims.ExpiresAt, 0),
+				HTTPOnly: true,
+				SameSite: cookie.SameSite,
+				Secure:   true,
+			})
+
+			c.Locals("isDeveloper", claims.IsDeveloper)
+		}
 
-		c.Locals("userId", userId)
 		return c.Next()
 	}
 }
+
+// Для Csrf: Bearer-токен засчитывается, только если его подпись и срок действительны
+func AccessTokenVerifier(auth adapter.AuthGrpcInterface) func(token string) bool {
+	return func(token string) bool {
+		_, err := auth.VerifyAccessToken(token)
+		return err == nil
+	}
+}
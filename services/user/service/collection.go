@@ -0,0 +1,423 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"warehouseai/user/adapter"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxCollections          = 50
+	maxCollectionNameLength = 64
+)
+
+type CreateCollectionRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateCollectionRequest struct {
+	Id       string  `json:"id"`
+	Name     *string `json:"name"`
+	IsPublic *bool   `json:"is_public"`
+}
+
+type DeleteCollectionRequest struct {
+	Id string `json:"id"`
+}
+
+type ReorderCollectionsRequest struct {
+	Ids []string `json:"ids"`
+}
+
+type CollectionItemRequest struct {
+	CollectionId string `json:"collection_id"`
+	AiId         string `json:"ai_id"`
+}
+
+type ReorderCollectionItemsRequest struct {
+	CollectionId string   `json:"collection_id"`
+	AiIds        []string `json:"ai_ids"`
+}
+
+type GetCollectionItemsRequest struct {
+	CollectionId string `json:"collection_id"`
+	Cursor       string `json:"cursor"`
+	Limit        int    `json:"limit"`
+}
+
+func CreateCollection(userId string, request CreateCollectionRequest, collection d.CollectionInterface, logger *logrus.Logger) (*m.FavoriteCollection, *e.ErrorResponse) {
+	name, nameErr := validateCollectionName(request.Name)
+
+	if nameErr != nil {
+		return nil, nameErr
+	}
+
+	count, dbErr := collection.Count(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Create collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if count >= maxCollections {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf("You can have at most %d collections.", maxCollections))
+	}
+
+	position, dbErr := collection.NextPosition(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Create collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	// Новая коллекция встаёт в конец списка
+	newCollection := &m.FavoriteCollection{
+		UserId:   uuid.FromStringOrNil(userId),
+		Name:     name,
+		Position: position,
+	}
+
+	if dbErr := collection.Create(newCollection); dbErr != nil {
+		if dbErr.ErrorType == e.DbExist {
+			return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Collection with this name already exists.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Create collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return newCollection, nil
+}
+
+func GetCollections(userId string, collection d.CollectionInterface, logger *logrus.Logger) (*[]m.FavoriteCollection, *e.ErrorResponse) {
+	collections, dbErr := collection.GetMany(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get collections")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	for i := range *collections {
+		withShareLink(&(*collections)[i])
+	}
+
+	return collections, nil
+}
+
+// Переименование и включение/выключение доступа по ссылке. При повторном включении ссылка меняется.
+func UpdateCollection(userId string, request UpdateCollectionRequest, collection d.CollectionInterface, logger *logrus.Logger) (*m.FavoriteCollection, *e.ErrorResponse) {
+	existCollection, err := getUserCollection(userId, request.Id, collection, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedFields := map[string]interface{}{}
+
+	if request.Name != nil {
+		name, nameErr := validateCollectionName(*request.Name)
+
+		if nameErr != nil {
+			return nil, nameErr
+		}
+
+		updatedFields["name"] = name
+		existCollection.Name = name
+	}
+
+	if request.IsPublic != nil && *request.IsPublic != existCollection.IsPublic {
+		existCollection.IsPublic = *request.IsPublic
+		existCollection.ShareToken = nil
+
+		if *request.IsPublic {
+			token, keyErr := generateKey(32)
+
+			if keyErr != nil {
+				logger.WithFields(logrus.Fields{"time": time.Now(), "error": keyErr.Error()}).Info("Update collection")
+				return nil, e.NewErrorResponse(e.HttpInternalError, "Can't create share link.")
+			}
+
+			existCollection.ShareToken = &token
+		}
+
+		updatedFields["is_public"] = existCollection.IsPublic
+		updatedFields["share_token"] = existCollection.ShareToken
+	}
+
+	if len(updatedFields) == 0 {
+		return withShareLink(existCollection), nil
+	}
+
+	if dbErr := collection.Update(request.Id, updatedFields); dbErr != nil {
+		if dbErr.ErrorType == e.DbExist {
+			return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Collection with this name already exists.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return withShareLink(existCollection), nil
+}
+
+func DeleteCollection(userId string, request DeleteCollectionRequest, collection d.CollectionInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := getUserCollection(userId, request.Id, collection, logger); err != nil {
+		return err
+	}
+
+	if dbErr := collection.Delete(request.Id); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Delete collection")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Ids - все коллекции пользователя в новом порядке
+func ReorderCollections(userId string, request ReorderCollectionsRequest, collection d.CollectionInterface, logger *logrus.Logger) (*[]m.FavoriteCollection, *e.ErrorResponse) {
+	collections, dbErr := collection.GetMany(userId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reorder collections")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	existIds := make([]string, 0, len(*collections))
+
+	for _, existCollection := range *collections {
+		existIds = append(existIds, existCollection.ID.String())
+	}
+
+	if !sameIds(existIds, request.Ids) {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Ids must list every collection exactly once.")
+	}
+
+	if dbErr := collection.Reorder(userId, request.Ids); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reorder collections")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return GetCollections(userId, collection, logger)
+}
+
+// В коллекцию можно положить только ИИ из избранного
+func AddCollectionItem(userId string, request CollectionItemRequest, collection d.CollectionInterface, favorites d.FavoritesInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := getUserCollection(userId, request.CollectionId, collection, logger); err != nil {
+		return err
+	}
+
+	if _, dbErr := favorites.GetFavorite(userId, request.AiId); dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return e.NewErrorResponse(e.HttpBadRequest, "AI is not in favorites.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Add collection item")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	position, dbErr := collection.NextItemPosition(request.CollectionId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Add collection item")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	newItem := &m.CollectionItem{
+		CollectionId: uuid.FromStringOrNil(request.CollectionId),
+		AiId:         uuid.FromStringOrNil(request.AiId),
+		Position:     position,
+	}
+
+	if dbErr := collection.AddItem(newItem); dbErr != nil {
+		if dbErr.ErrorType == e.DbExist {
+			return e.NewErrorResponse(e.HttpAlreadyExist, "AI is already in the collection.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Add collection item")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+func RemoveCollectionItem(userId string, request CollectionItemRequest, collection d.CollectionInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := getUserCollection(userId, request.CollectionId, collection, logger); err != nil {
+		return err
+	}
+
+	if dbErr := collection.DeleteItem(request.CollectionId, request.AiId); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Remove collection item")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// AiIds - все ИИ коллекции в новом порядке
+func ReorderCollectionItems(userId string, request ReorderCollectionItemsRequest, collection d.CollectionInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := getUserCollection(userId, request.CollectionId, collection, logger); err != nil {
+		return err
+	}
+
+	existIds, dbErr := collection.GetItemIds(request.CollectionId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reorder collection items")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	// Частичный список оставил бы старые позиции остальным и дал бы дубли
+	if !sameIds(existIds, request.AiIds) {
+		return e.NewErrorResponse(e.HttpBadRequest, "Ai ids must list every item of the collection exactly once.")
+	}
+
+	if dbErr := collection.ReorderItems(request.CollectionId, request.AiIds); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Reorder collection items")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+func GetCollectionItems(userId string, request GetCollectionItemsRequest, collection d.CollectionInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) (*m.AiPage, *e.ErrorResponse) {
+	if _, err := getUserCollection(userId, request.CollectionId, collection, logger); err != nil {
+		return nil, err
+	}
+
+	return getCollectionPage(request, collection, ai, logger)
+}
+
+// Публичная коллекция по ссылке, вход не нужен
+func GetSharedCollection(token string, request GetCollectionItemsRequest, collection d.CollectionInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) (*m.SharedCollection, *e.ErrorResponse) {
+	if token == "" {
+		return nil, e.NewErrorResponse(e.HttpNotFound, "Collection not found.")
+	}
+
+	existCollection, dbErr := collection.Get(map[string]interface{}{"share_token": token, "is_public": true})
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "Collection not found.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get shared collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	request.CollectionId = existCollection.ID.String()
+	page, err := getCollectionPage(request, collection, ai, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &m.SharedCollection{Name: existCollection.Name, AiPage: *page}, nil
+}
+
+func getCollectionPage(request GetCollectionItemsRequest, collection d.CollectionInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) (*m.AiPage, *e.ErrorResponse) {
+	after, cursorErr := decodeCursor(request.Cursor)
+
+	if cursorErr != nil {
+		return nil, cursorErr
+	}
+
+	limit := pageLimit(request.Limit)
+	items, dbErr := collection.GetItems(request.CollectionId, after, limit)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get collection items")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	ids := make([]string, 0, len(*items))
+
+	for _, item := range *items {
+		ids = append(ids, item.AiId.String())
+	}
+
+	ais, gwErr := hydrateAis(ids, ai)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Get collection items")
+		return nil, gwErr
+	}
+
+	page := &m.AiPage{Items: ais}
+
+	if len(*items) == limit {
+		last := (*items)[limit-1]
+		page.NextCursor = encodeCursor(m.Cursor{Position: last.Position, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// Чужая коллекция для пользователя не существует
+func getUserCollection(userId string, collectionId string, collection d.CollectionInterface, logger *logrus.Logger) (*m.FavoriteCollection, *e.ErrorResponse) {
+	if _, err := uuid.FromString(collectionId); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid collection id.")
+	}
+
+	existCollection, dbErr := collection.Get(map[string]interface{}{"id": collectionId, "user_id": userId})
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "Collection not found.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get collection")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return existCollection, nil
+}
+
+func validateCollectionName(name string) (string, *e.ErrorResponse) {
+	name = strings.TrimSpace(name)
+
+	if name == "" || utf8.RuneCountInString(name) > maxCollectionNameLength {
+		return "", e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf("Collection name must be from 1 to %d characters.", maxCollectionNameLength))
+	}
+
+	return name, nil
+}
+
+func withShareLink(collection *m.FavoriteCollection) *m.FavoriteCollection {
+	if collection.IsPublic && collection.ShareToken != nil {
+		collection.ShareLink = fmt.Sprintf("%s/api/user/collections/shared?token=%s", os.Getenv("DOMAIN"), *collection.ShareToken)
+	}
+
+	return collection
+}
+
+// Одинаковый набор без повторов, порядок не важен
+func sameIds(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[string]int, len(a))
+
+	for _, id := range a {
+		counts[id]++
+	}
+
+	for _, id := range b {
+		if counts[id] == 0 {
+			return false
+		}
+
+		counts[id]--
+	}
+
+	return true
+}
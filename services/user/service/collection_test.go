@@ -0,0 +1,286 @@
+package service
+
+import (
+	"testing"
+	aMock "warehouseai/user/adapter/mocks"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReorderCollectionItems(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	collectionId := uuid.Must(uuid.NewV4()).String()
+	first := uuid.Must(uuid.NewV4()).String()
+	second := uuid.Must(uuid.NewV4()).String()
+	third := uuid.Must(uuid.NewV4()).String()
+	conditions := map[string]interface{}{"id": collectionId, "user_id": userId}
+
+	cases := []struct {
+		name    string
+		request ReorderCollectionItemsRequest
+		setup   func(collectionMock *dMock.MockCollectionInterface)
+		errCode int
+	}{
+		{
+			name:    "full set in new order",
+			request: ReorderCollectionItemsRequest{CollectionId: collectionId, AiIds: []string{third, first, second}},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{}, nil).Times(1)
+				collectionMock.EXPECT().GetItemIds(collectionId).Return([]string{first, second, third}, nil).Times(1)
+				collectionMock.EXPECT().ReorderItems(collectionId, []string{third, first, second}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "partial list",
+			request: ReorderCollectionItemsRequest{CollectionId: collectionId, AiIds: []string{third, first}},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{}, nil).Times(1)
+				collectionMock.EXPECT().GetItemIds(collectionId).Return([]string{first, second, third}, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "unknown id",
+			request: ReorderCollectionItemsRequest{CollectionId: collectionId, AiIds: []string{third, first, uuid.Must(uuid.NewV4()).String()}},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{}, nil).Times(1)
+				collectionMock.EXPECT().GetItemIds(collectionId).Return([]string{first, second, third}, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "duplicate id",
+			request: ReorderCollectionItemsRequest{CollectionId: collectionId, AiIds: []string{first, first, second}},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{}, nil).Times(1)
+				collectionMock.EXPECT().GetItemIds(collectionId).Return([]string{first, second, third}, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "foreign collection",
+			request: ReorderCollectionItemsRequest{CollectionId: collectionId, AiIds: []string{first}},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(nil, e.NewDBError(e.DbNotFound, "Collection not found.", "record not found")).Times(1)
+			},
+			errCode: e.HttpNotFound,
+		},
+		{
+			name:    "invalid collection id",
+			request: ReorderCollectionItemsRequest{CollectionId: "text", AiIds: []string{first}},
+			setup:   func(collectionMock *dMock.MockCollectionInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			collectionMock := dMock.NewMockCollectionInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(collectionMock)
+
+			err := ReorderCollectionItems(userId, tc.request, collectionMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestCreateCollection(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	cases := []struct {
+		name     string
+		request  CreateCollectionRequest
+		setup    func(collectionMock *dMock.MockCollectionInterface)
+		expected int
+		errCode  int
+	}{
+		{
+			name:    "appended after the last position",
+			request: CreateCollectionRequest{Name: " Text "},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Count(userId).Return(int64(2), nil).Times(1)
+				// Коллекцию с позицией 1 удалили: Count дал бы занятую позицию 2
+				collectionMock.EXPECT().NextPosition(userId).Return(3, nil).Times(1)
+				collectionMock.EXPECT().Create(&m.FavoriteCollection{UserId: uuid.FromStringOrNil(userId), Name: "Text", Position: 3}).Return(nil).Times(1)
+			},
+			expected: 3,
+		},
+		{
+			name:    "limit reached",
+			request: CreateCollectionRequest{Name: "Text"},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Count(userId).Return(int64(maxCollections), nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "duplicate name",
+			request: CreateCollectionRequest{Name: "Text"},
+			setup: func(collectionMock *dMock.MockCollectionInterface) {
+				collectionMock.EXPECT().Count(userId).Return(int64(1), nil).Times(1)
+				collectionMock.EXPECT().NextPosition(userId).Return(1, nil).Times(1)
+				collectionMock.EXPECT().Create(gomock.Any()).Return(e.NewDBError(e.DbExist, "Collection already exists.", "duplicate key")).Times(1)
+			},
+			errCode: e.HttpAlreadyExist,
+		},
+		{
+			name:    "empty name",
+			request: CreateCollectionRequest{Name: "  "},
+			setup:   func(collectionMock *dMock.MockCollectionInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			collectionMock := dMock.NewMockCollectionInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(collectionMock)
+
+			collection, err := CreateCollection(userId, tc.request, collectionMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, collection.Position)
+		})
+	}
+}
+
+func TestAddCollectionItem(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	collectionMock := dMock.NewMockCollectionInterface(ctl)
+	favoritesMock := dMock.NewMockFavoritesInterface(ctl)
+	logger := logrus.New()
+
+	userId := uuid.Must(uuid.NewV4()).String()
+	request := CollectionItemRequest{CollectionId: uuid.Must(uuid.NewV4()).String(), AiId: uuid.Must(uuid.NewV4()).String()}
+
+	collectionMock.EXPECT().Get(map[string]interface{}{"id": request.CollectionId, "user_id": userId}).Return(&m.FavoriteCollection{}, nil).Times(1)
+	favoritesMock.EXPECT().GetFavorite(userId, request.AiId).Return(&m.UserFavorite{}, nil).Times(1)
+	collectionMock.EXPECT().NextItemPosition(request.CollectionId).Return(5, nil).Times(1)
+	collectionMock.EXPECT().AddItem(&m.CollectionItem{CollectionId: uuid.FromStringOrNil(request.CollectionId), AiId: uuid.FromStringOrNil(request.AiId), Position: 5}).Return(nil).Times(1)
+
+	require.Nil(t, AddCollectionItem(userId, request, collectionMock, favoritesMock, logger))
+}
+
+func TestGetCollectionItems(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	collectionId := uuid.Must(uuid.NewV4())
+	first := m.CollectionItem{ID: 7, CollectionId: collectionId, AiId: uuid.Must(uuid.NewV4()), Position: 0}
+	second := m.CollectionItem{ID: 3, CollectionId: collectionId, AiId: uuid.Must(uuid.NewV4()), Position: 1}
+	third := m.CollectionItem{ID: 9, CollectionId: collectionId, AiId: uuid.Must(uuid.NewV4()), Position: 2}
+	conditions := map[string]interface{}{"id": collectionId.String(), "user_id": userId}
+
+	cases := []struct {
+		name       string
+		request    GetCollectionItemsRequest
+		setup      func(collectionMock *dMock.MockCollectionInterface, aiMock *aMock.MockAiGrpcInterface)
+		expected   []m.PublicAi
+		nextCursor string
+		errCode    int
+	}{
+		{
+			name:    "first page",
+			request: GetCollectionItemsRequest{CollectionId: collectionId.String(), Limit: 2},
+			setup: func(collectionMock *dMock.MockCollectionInterface, aiMock *aMock.MockAiGrpcInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{ID: collectionId}, nil).Times(1)
+				collectionMock.EXPECT().GetItems(collectionId.String(), nil, 2).Return(&[]m.CollectionItem{first, second}, nil).Times(1)
+				// Сервис ИИ отдаёт карточки в своём порядке
+				aiMock.EXPECT().GetPublishedAis([]string{first.AiId.String(), second.AiId.String()}).Return([]m.PublicAi{{Id: second.AiId.String()}, {Id: first.AiId.String()}}, nil).Times(1)
+			},
+			expected:   []m.PublicAi{{Id: first.AiId.String()}, {Id: second.AiId.String()}},
+			nextCursor: encodeCursor(m.Cursor{Position: 1, ID: 3}),
+		},
+		{
+			name:    "last page skips unpublished AI",
+			request: GetCollectionItemsRequest{CollectionId: collectionId.String(), Cursor: encodeCursor(m.Cursor{Position: 1, ID: 3}), Limit: 2},
+			setup: func(collectionMock *dMock.MockCollectionInterface, aiMock *aMock.MockAiGrpcInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{ID: collectionId}, nil).Times(1)
+				collectionMock.EXPECT().GetItems(collectionId.String(), &m.Cursor{Position: 1, ID: 3}, 2).Return(&[]m.CollectionItem{third}, nil).Times(1)
+				aiMock.EXPECT().GetPublishedAis([]string{third.AiId.String()}).Return([]m.PublicAi{}, nil).Times(1)
+			},
+			expected: []m.PublicAi{},
+		},
+		{
+			name:    "default limit",
+			request: GetCollectionItemsRequest{CollectionId: collectionId.String()},
+			setup: func(collectionMock *dMock.MockCollectionInterface, aiMock *aMock.MockAiGrpcInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{ID: collectionId}, nil).Times(1)
+				collectionMock.EXPECT().GetItems(collectionId.String(), nil, defaultPageLimit).Return(&[]m.CollectionItem{}, nil).Times(1)
+			},
+			expected: []m.PublicAi{},
+		},
+		{
+			name:    "invalid cursor",
+			request: GetCollectionItemsRequest{CollectionId: collectionId.String(), Cursor: "not a cursor"},
+			setup: func(collectionMock *dMock.MockCollectionInterface, aiMock *aMock.MockAiGrpcInterface) {
+				collectionMock.EXPECT().Get(conditions).Return(&m.FavoriteCollection{ID: collectionId}, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			collectionMock := dMock.NewMockCollectionInterface(ctl)
+			aiMock := aMock.NewMockAiGrpcInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(collectionMock, aiMock)
+
+			page, err := GetCollectionItems(userId, tc.request, collectionMock, aiMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, page.Items)
+			require.Equal(t, tc.nextCursor, page.NextCursor)
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	cursor, err := decodeCursor(encodeCursor(m.Cursor{Position: 12, ID: 345}))
+
+	require.Nil(t, err)
+	require.Equal(t, &m.Cursor{Position: 12, ID: 345}, cursor)
+
+	for _, value := range []string{"!!!", "MTI"} {
+		_, err := decodeCursor(value)
+
+		require.NotNil(t, err)
+		require.Equal(t, e.HttpBadRequest, err.ErrorCode)
+	}
+}
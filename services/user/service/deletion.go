@@ -0,0 +1,593 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+	"warehouseai/user/adapter"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const dueDeletionsLimit = 50
+
+// Пароль Google-аккаунта случайный и пользователю неизвестен, поэтому вместо него можно подтвердить удаление по почте
+type DeleteAccountRequest struct {
+	Password       string `json:"password"`
+	ConfirmByEmail bool   `json:"confirm_by_email"`
+}
+
+// Планирует удаление аккаунта. До ScheduledFor пользователь может его отменить.
+func RequestAccountDeletion(request DeleteAccountRequest, existUser *m.User, deletion d.DeletionInterface, mail adapter.MailProducerInterface, cfg config.DeletionCfg, logger *logrus.Logger) (*m.AccountDeletion, *e.ErrorResponse) {
+	if request.ConfirmByEmail {
+		return requestDeletionConfirmation(existUser, deletion, mail, cfg, logger)
+	}
+
+	if request.Password == "" {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Password is required. Without a password confirm the deletion by email.")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(request.Password)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Request account deletion")
+
+		if existUser.ViaGoogle {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid password. If you have not set a password, confirm the deletion by email.")
+		}
+
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid password.")
+	}
+
+	newDeletion := newAccountDeletion(existUser, m.DeletionScheduled, cfg)
+
+	if err := deletion.Create(newDeletion); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Request account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if err := mail.SendEmail(deletionScheduledEmail(existUser, newDeletion)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+
+	return newDeletion, nil
+}
+
+// Удаление без пароля попадает в расписание только после перехода по ссылке из письма.
+// Неподтверждённых запросов может быть несколько, в расписание попадёт первый подтверждённый.
+func requestDeletionConfirmation(existUser *m.User, deletion d.DeletionInterface, mail adapter.MailProducerInterface, cfg config.DeletionCfg, logger *logrus.Logger) (*m.AccountDeletion, *e.ErrorResponse) {
+	activeDeletion, err := deletion.Get(map[string]interface{}{"user_id": existUser.ID, "status": []m.DeletionStatus{m.DeletionScheduled, m.DeletionInProgress, m.DeletionCompensating}})
+
+	if err != nil && err.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Request account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if activeDeletion != nil {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Account deletion is already scheduled.")
+	}
+
+	key, keyErr := generateKey(64)
+
+	if keyErr != nil {
+		return nil, e.NewErrorResponse(e.HttpInternalError, keyErr.Error())
+	}
+
+	tokenHash := hashKey(key)
+	newDeletion := newAccountDeletion(existUser, m.DeletionUnconfirmed, cfg)
+	newDeletion.ConfirmToken = &tokenHash
+
+	if err := deletion.Create(newDeletion); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Request account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if err := mail.SendEmail(deletionConfirmEmail(existUser, key, cfg.ConfirmTTL)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+		return nil, e.NewErrorResponse(e.HttpInternalError, "Failed to send email.")
+	}
+
+	return newDeletion, nil
+}
+
+// Подтверждение удаления по ссылке из письма. Период ожидания отсчитывается с подтверждения.
+// Возвращает id пользователя для журнала безопасности.
+func ConfirmAccountDeletion(key string, user d.UserInterface, deletion d.DeletionInterface, mail adapter.MailProducerInterface, cfg config.DeletionCfg, logger *logrus.Logger) (string, *e.ErrorResponse) {
+	if key == "" {
+		return "", e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link.")
+	}
+
+	existDeletion, err := deletion.Get(map[string]interface{}{"confirm_token_hash": hashKey(key), "status": m.DeletionUnconfirmed})
+
+	if err != nil {
+		if err.ErrorType == e.DbNotFound {
+			return "", e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Confirm account deletion")
+		return "", e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	userId := existDeletion.UserId.String()
+
+	if time.Since(existDeletion.CreatedAt) > cfg.ConfirmTTL {
+		return userId, e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link.")
+	}
+
+	existUser, userErr := GetById(userId, user, logger)
+
+	if userErr != nil {
+		return userId, userErr
+	}
+
+	existDeletion.ScheduledFor = time.Now().Add(cfg.GracePeriod)
+
+	// Уникальный индекс активных удалений не даст запланировать второе
+	if err := deletion.Transition(existDeletion.ID.String(), m.DeletionUnconfirmed, map[string]interface{}{"status": m.DeletionScheduled, "scheduled_for": existDeletion.ScheduledFor, "confirm_token_hash": nil}); err != nil {
+		switch err.ErrorType {
+		case e.DbNotFound:
+			return userId, e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link.")
+		case e.DbExist:
+			return userId, e.NewErrorResponse(e.HttpAlreadyExist, "Account deletion is already scheduled.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Confirm account deletion")
+		return userId, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if err := mail.SendEmail(deletionScheduledEmail(existUser, existDeletion)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+
+	return userId, nil
+}
+
+func newAccountDeletion(existUser *m.User, status m.DeletionStatus, cfg config.DeletionCfg) *m.AccountDeletion {
+	newDeletion := &m.AccountDeletion{
+		UserId:       existUser.ID,
+		Status:       status,
+		ScheduledFor: time.Now().Add(cfg.GracePeriod),
+		Steps:        make([]m.AccountDeletionStep, 0, len(m.DeletionSteps)),
+	}
+
+	for _, step := range m.DeletionSteps {
+		newDeletion.Steps = append(newDeletion.Steps, m.AccountDeletionStep{Step: step, Status: m.StepPending})
+	}
+
+	return newDeletion
+}
+
+func CancelAccountDeletion(userId string, deletion d.DeletionInterface, logger *logrus.Logger) (*m.AccountDeletion, *e.ErrorResponse) {
+	existDeletion, err := deletion.Get(map[string]interface{}{"user_id": userId, "status": m.DeletionScheduled})
+
+	if err != nil {
+		if err.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "No scheduled account deletion.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Cancel account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	// Удаление могло запуститься по расписанию между чтением и отменой
+	if err := deletion.Transition(existDeletion.ID.String(), m.DeletionScheduled, map[string]interface{}{"status": m.DeletionCancelled}); err != nil {
+		if err.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Account deletion has already started.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Cancel account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	existDeletion.Status = m.DeletionCancelled
+	sortDeletionSteps(existDeletion)
+
+	return existDeletion, nil
+}
+
+// Последнее удаление пользователя. После завершения саги пользователя уже нет, поэтому статус доступен только до конца.
+func GetAccountDeletion(userId string, deletion d.DeletionInterface, logger *logrus.Logger) (*m.AccountDeletion, *e.ErrorResponse) {
+	existDeletion, err := deletion.Get(map[string]interface{}{"user_id": userId})
+
+	if err != nil {
+		if err.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "Account deletion was not requested.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get account deletion")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	sortDeletionSteps(existDeletion)
+
+	return existDeletion, nil
+}
+
+// Запускает удаления, у которых закончился период ожидания, и возобновляет зависшие. Работает до закрытия stop.
+func StartDeletionScheduler(cfg config.DeletionCfg, user d.UserInterface, deletion d.DeletionInterface, producer adapter.DeletionProducerInterface, mail adapter.MailProducerInterface, logger *logrus.Logger, stop <-chan struct{}) {
+	if cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	RunDueDeletions(user, deletion, producer, mail, logger)
+	RunStaleDeletions(cfg.StepTimeout, user, deletion, producer, mail, logger)
+
+	for {
+		select {
+		case <-ticker.C:
+			RunDueDeletions(user, deletion, producer, mail, logger)
+			RunStaleDeletions(cfg.StepTimeout, user, deletion, producer, mail, logger)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func RunDueDeletions(user d.UserInterface, deletion d.DeletionInterface, producer adapter.DeletionProducerInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) {
+	due, err := deletion.GetDue(time.Now(), dueDeletionsLimit)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run account deletions")
+		return
+	}
+
+	saga := deletionSaga{user: user, deletion: deletion, producer: producer, mail: mail, logger: logger}
+
+	for i := range *due {
+		current := &(*due)[i]
+
+		// Удаление уже отменили или запустил другой экземпляр сервиса
+		if err := deletion.Transition(current.ID.String(), m.DeletionScheduled, map[string]interface{}{"status": m.DeletionInProgress}); err != nil {
+			continue
+		}
+
+		current.Status = m.DeletionInProgress
+		saga.execute(current, 0)
+	}
+}
+
+// Возобновляет удаления, которые дольше timeout ждут ответа на шаг: команда или ответ могли потеряться,
+// а сервис - упасть посреди саги. Шаги на стороне сервисов идемпотентны, поэтому текущий шаг просто отправляется ещё раз.
+func RunStaleDeletions(timeout time.Duration, user d.UserInterface, deletion d.DeletionInterface, producer adapter.DeletionProducerInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) {
+	if timeout <= 0 {
+		return
+	}
+
+	stale, err := deletion.GetStale(time.Now().Add(-timeout), dueDeletionsLimit)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Resume account deletions")
+		return
+	}
+
+	saga := deletionSaga{user: user, deletion: deletion, producer: producer, mail: mail, logger: logger}
+
+	for i := range *stale {
+		current := &(*stale)[i]
+		logger.WithFields(logrus.Fields{"time": time.Now(), "deletion_id": current.ID, "status": current.Status, "step": current.Step}).Info("Resume account deletion")
+
+		switch current.Status {
+		case m.DeletionInProgress:
+			index := firstPendingStep(current)
+
+			// Все шаги выполнены, не успели только сменить статус
+			if index == len(m.DeletionSteps) {
+				saga.finish(current, m.DeletionCompleted, map[string]interface{}{})
+				continue
+			}
+
+			saga.execute(current, index)
+		case m.DeletionCompensating:
+			// Откатывается последний выполненный шаг, откаченные уже в статусе compensated
+			saga.compensate(current, len(m.DeletionSteps)-1)
+		}
+	}
+}
+
+// Ответ сервиса на шаг саги. Ответы на уже пройденные шаги (повторная доставка) игнорируются.
+func HandleDeletionReply(reply m.AccountDeletionReply, user d.UserInterface, deletion d.DeletionInterface, producer adapter.DeletionProducerInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) {
+	current, err := deletion.Get(map[string]interface{}{"id": reply.DeletionId})
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload, "deletion_id": reply.DeletionId}).Info("Handle account deletion reply")
+		return
+	}
+
+	saga := deletionSaga{user: user, deletion: deletion, producer: producer, mail: mail, logger: logger}
+	index := deletionStepIndex(reply.Step)
+
+	switch {
+	case reply.Step != current.Step || index < 0:
+		return
+	case current.Status == m.DeletionInProgress && reply.Action == m.DeletionExecute:
+		if !reply.Success {
+			saga.fail(current, reply.Step, reply.Error)
+			return
+		}
+
+		saga.setStep(current, reply.Step, m.StepDone, "")
+		saga.execute(current, index+1)
+	case current.Status == m.DeletionCompensating && reply.Action == m.DeletionCompensate:
+		if !reply.Success {
+			// Откатить шаг не удалось: данные пользователя в промежуточном состоянии, нужен разбор вручную
+			saga.setStep(current, reply.Step, m.StepFailed, reply.Error)
+			saga.finish(current, m.DeletionFailed, map[string]interface{}{"error": fmt.Sprintf("compensation of %s failed: %s", reply.Step, reply.Error)})
+			return
+		}
+
+		// Файлы аватара не вернуть, поэтому убираем ссылку на них
+		if reply.Step == m.DeletionStepAvatar {
+			if err := user.Update(current.UserId.String(), map[string]interface{}{"picture": ""}); err != nil {
+				logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Compensate account deletion")
+			}
+		}
+
+		saga.setStep(current, reply.Step, m.StepCompensated, "")
+		saga.compensate(current, index-1)
+	}
+}
+
+type deletionSaga struct {
+	user     d.UserInterface
+	deletion d.DeletionInterface
+	producer adapter.DeletionProducerInterface
+	mail     adapter.MailProducerInterface
+	logger   *logrus.Logger
+}
+
+// Выполняет шаги начиная с index: локальные сразу, на удалённом останавливается до ответа
+func (s deletionSaga) execute(current *m.AccountDeletion, index int) {
+	for ; index < len(m.DeletionSteps); index++ {
+		step := m.DeletionSteps[index]
+
+		switch step {
+		case m.DeletionStepUser:
+			s.deleteUser(current)
+			return
+		case m.DeletionStepAvatar:
+			existUser, err := s.user.GetOneBy(map[string]interface{}{"id": current.UserId.String()})
+
+			if err != nil {
+				s.fail(current, step, err.Message)
+				return
+			}
+
+			if existUser.Picture == "" {
+				s.setStep(current, step, m.StepSkipped, "")
+				continue
+			}
+
+			s.send(current, m.AccountDeletionCommand{Step: step, Action: m.DeletionExecute, Picture: existUser.Picture})
+			return
+		default:
+			s.send(current, m.AccountDeletionCommand{Step: step, Action: m.DeletionExecute})
+			return
+		}
+	}
+}
+
+// Откатывает выполненные шаги в обратном порядке, начиная с index
+func (s deletionSaga) compensate(current *m.AccountDeletion, index int) {
+	for ; index >= 0; index-- {
+		step := m.DeletionSteps[index]
+
+		if deletionStepStatus(current, step) != m.StepDone {
+			continue
+		}
+
+		s.send(current, m.AccountDeletionCommand{Step: step, Action: m.DeletionCompensate})
+		return
+	}
+
+	if s.finish(current, m.DeletionFailed, map[string]interface{}{}) {
+		if existUser, err := s.user.GetOneBy(map[string]interface{}{"id": current.UserId.String()}); err == nil {
+			s.sendEmail(deletionFailedEmail(existUser))
+		}
+	}
+}
+
+func (s deletionSaga) fail(current *m.AccountDeletion, step m.DeletionStep, stepError string) {
+	s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": stepError, "deletion_id": current.ID, "step": step}).Info("Account deletion step failed")
+	s.setStep(current, step, m.StepFailed, stepError)
+
+	if err := s.deletion.Transition(current.ID.String(), current.Status, map[string]interface{}{"status": m.DeletionCompensating, "error": stepError}); err != nil {
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Compensate account deletion")
+		return
+	}
+
+	current.Status = m.DeletionCompensating
+	s.compensate(current, deletionStepIndex(step)-1)
+}
+
+func (s deletionSaga) deleteUser(current *m.AccountDeletion) {
+	userId := current.UserId.String()
+	existUser, err := s.user.GetOneBy(map[string]interface{}{"id": userId})
+
+	if err == nil {
+		err = s.user.Delete(map[string]interface{}{"id": userId})
+	}
+
+	if err != nil {
+		s.fail(current, m.DeletionStepUser, err.Message)
+		return
+	}
+
+	s.setStep(current, m.DeletionStepUser, m.StepDone, "")
+
+	if !s.finish(current, m.DeletionCompleted, map[string]interface{}{"step": m.DeletionStepUser}) {
+		return
+	}
+
+	// Пользователя больше нет: удаляем то, что держали ради возможного отката
+	for _, step := range []m.DeletionStep{m.DeletionStepAuth, m.DeletionStepAi} {
+		command := m.AccountDeletionCommand{DeletionId: current.ID.String(), UserId: userId, Step: step, Action: m.DeletionPurge}
+
+		if err := s.producer.SendDeletionCommand(command); err != nil {
+			s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error(), "step": step}).Info("Purge account")
+		}
+	}
+
+	s.sendEmail(deletionCompletedEmail(existUser))
+}
+
+func (s deletionSaga) send(current *m.AccountDeletion, command m.AccountDeletionCommand) {
+	command.DeletionId = current.ID.String()
+	command.UserId = current.UserId.String()
+
+	// Шаг фиксируется до отправки, иначе быстрый ответ может прийти раньше
+	if err := s.deletion.Transition(command.DeletionId, current.Status, map[string]interface{}{"step": command.Step}); err != nil {
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Account deletion step")
+		return
+	}
+
+	current.Step = command.Step
+
+	if err := s.producer.SendDeletionCommand(command); err != nil {
+		if command.Action == m.DeletionExecute {
+			s.fail(current, command.Step, err.Error())
+			return
+		}
+
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Compensate account deletion")
+		s.setStep(current, command.Step, m.StepFailed, err.Error())
+		s.finish(current, m.DeletionFailed, map[string]interface{}{"error": fmt.Sprintf("compensation of %s failed: %s", command.Step, err.Error())})
+	}
+}
+
+func (s deletionSaga) finish(current *m.AccountDeletion, status m.DeletionStatus, updatedFields map[string]interface{}) bool {
+	updatedFields["status"] = status
+
+	if err := s.deletion.Transition(current.ID.String(), current.Status, updatedFields); err != nil {
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Finish account deletion")
+		return false
+	}
+
+	current.Status = status
+
+	return true
+}
+
+func (s deletionSaga) setStep(current *m.AccountDeletion, step m.DeletionStep, status m.DeletionStepStatus, stepError string) {
+	if err := s.deletion.UpdateStep(current.ID.String(), step, status, stepError); err != nil {
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Update account deletion step")
+	}
+
+	for i := range current.Steps {
+		if current.Steps[i].Step == step {
+			current.Steps[i].Status = status
+			current.Steps[i].Error = stepError
+		}
+	}
+}
+
+func (s deletionSaga) sendEmail(email m.Email) {
+	if err := s.mail.SendEmail(email); err != nil {
+		s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+}
+
+func deletionStepIndex(step m.DeletionStep) int {
+	for index, existStep := range m.DeletionSteps {
+		if existStep == step {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// Первый шаг, который ещё не выполнен. Шаги выполняются по порядку, поэтому с него сага и продолжается.
+func firstPendingStep(current *m.AccountDeletion) int {
+	for index, step := range m.DeletionSteps {
+		if status := deletionStepStatus(current, step); status != m.StepDone && status != m.StepSkipped {
+			return index
+		}
+	}
+
+	return len(m.DeletionSteps)
+}
+
+func deletionStepStatus(current *m.AccountDeletion, step m.DeletionStep) m.DeletionStepStatus {
+	for _, existStep := range current.Steps {
+		if existStep.Step == step {
+			return existStep.Status
+		}
+	}
+
+	return ""
+}
+
+func sortDeletionSteps(current *m.AccountDeletion) {
+	sort.Slice(current.Steps, func(i, j int) bool {
+		return deletionStepIndex(current.Steps[i].Step) < deletionStepIndex(current.Steps[j].Step)
+	})
+}
+
+func deletionConfirmEmail(existUser *m.User, key string, ttl time.Duration) m.Email {
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Подтверждение удаления аккаунта",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Мы получили запрос на удаление аккаунта %s.
+      Перейдите по этой ссылке, чтобы подтвердить удаление: %s
+      Ссылка действует %s.
+
+      Если вы не делали этот запрос - проигнорируйте данное письмо.
+
+      WarehouseAI Team
+      `, existUser.Firstname, existUser.Username, fmt.Sprintf("%s/api/user/delete/confirm?token=%s", os.Getenv("DOMAIN"), key), ttl),
+	}
+}
+
+func deletionScheduledEmail(existUser *m.User, deletion *m.AccountDeletion) m.Email {
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Удаление аккаунта запланировано",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Ваш аккаунт WarehouseAI будет удалён %s вместе с вашими оценками, а ваши AI будут сняты с публикации.
+      Если вы передумали, войдите в аккаунт и отмените удаление до этого времени.
+
+      WarehouseAI Team
+      `, existUser.Firstname, deletion.ScheduledFor.Format("02.01.2006 15:04")),
+	}
+}
+
+func deletionCompletedEmail(existUser *m.User) m.Email {
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Аккаунт удалён",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Ваш аккаунт WarehouseAI удалён. Спасибо, что были с нами.
+
+      WarehouseAI Team
+      `, existUser.Firstname),
+	}
+}
+
+func deletionFailedEmail(existUser *m.User) m.Email {
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Не удалось удалить аккаунт",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Нам не удалось удалить ваш аккаунт, все ваши данные сохранены.
+      Запросите удаление ещё раз или обратитесь в поддержку.
+
+      WarehouseAI Team
+      `, existUser.Firstname),
+	}
+}
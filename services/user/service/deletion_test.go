@@ -0,0 +1,404 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	aMock "warehouseai/user/adapter/mocks"
+	"warehouseai/user/config"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type deletionMocks struct {
+	user     *dMock.MockUserInterface
+	deletion *dMock.MockDeletionInterface
+	producer *aMock.MockDeletionProducerInterface
+	mail     *aMock.MockMailProducerInterface
+}
+
+func newDeletionMocks(ctl *gomock.Controller) deletionMocks {
+	return deletionMocks{
+		user:     dMock.NewMockUserInterface(ctl),
+		deletion: dMock.NewMockDeletionInterface(ctl),
+		producer: aMock.NewMockDeletionProducerInterface(ctl),
+		mail:     aMock.NewMockMailProducerInterface(ctl),
+	}
+}
+
+// Удаление в статусе status на шаге step. Шаги до step выполнены, остальные ждут.
+func newDeletion(status m.DeletionStatus, step m.DeletionStep) *m.AccountDeletion {
+	current := &m.AccountDeletion{ID: uuid.Must(uuid.NewV4()), UserId: uuid.Must(uuid.NewV4()), Status: status, Step: step}
+	index := deletionStepIndex(step)
+
+	for i, existStep := range m.DeletionSteps {
+		stepStatus := m.StepPending
+
+		if i < index {
+			stepStatus = m.StepDone
+		}
+
+		current.Steps = append(current.Steps, m.AccountDeletionStep{Step: existStep, Status: stepStatus})
+	}
+
+	return current
+}
+
+func expectCommand(mocks deletionMocks, current *m.AccountDeletion, from m.DeletionStatus, step m.DeletionStep, action m.DeletionAction) {
+	mocks.deletion.EXPECT().Transition(current.ID.String(), from, map[string]interface{}{"step": step}).Return(nil).Times(1)
+	mocks.producer.EXPECT().SendDeletionCommand(m.AccountDeletionCommand{DeletionId: current.ID.String(), UserId: current.UserId.String(), Step: step, Action: action}).Return(nil).Times(1)
+}
+
+func TestHandleDeletionReply(t *testing.T) {
+	cases := []struct {
+		name    string
+		current *m.AccountDeletion
+		reply   func(current *m.AccountDeletion) m.AccountDeletionReply
+		setup   func(mocks deletionMocks, current *m.AccountDeletion)
+	}{
+		{
+			name:    "auth done, ai is sent",
+			current: newDeletion(m.DeletionInProgress, m.DeletionStepAuth),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAuth, Action: m.DeletionExecute, Success: true}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAuth, m.StepDone, "").Return(nil).Times(1)
+				expectCommand(mocks, current, m.DeletionInProgress, m.DeletionStepAi, m.DeletionExecute)
+			},
+		},
+		{
+			name:    "ai done, avatar skipped, user deleted and purged",
+			current: newDeletion(m.DeletionInProgress, m.DeletionStepAi),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAi, Action: m.DeletionExecute, Success: true}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				userId := current.UserId.String()
+				existUser := &m.User{ID: current.UserId, Email: "ivan@example.com"}
+
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAi, m.StepDone, "").Return(nil).Times(1)
+				mocks.user.EXPECT().GetOneBy(map[string]interface{}{"id": userId}).Return(existUser, nil).Times(2)
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAvatar, m.StepSkipped, "").Return(nil).Times(1)
+				mocks.user.EXPECT().Delete(map[string]interface{}{"id": userId}).Return(nil).Times(1)
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepUser, m.StepDone, "").Return(nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionInProgress, map[string]interface{}{"step": m.DeletionStepUser, "status": m.DeletionCompleted}).Return(nil).Times(1)
+
+				for _, step := range []m.DeletionStep{m.DeletionStepAuth, m.DeletionStepAi} {
+					mocks.producer.EXPECT().SendDeletionCommand(m.AccountDeletionCommand{DeletionId: current.ID.String(), UserId: userId, Step: step, Action: m.DeletionPurge}).Return(nil).Times(1)
+				}
+
+				mocks.mail.EXPECT().SendEmail(deletionCompletedEmail(existUser)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "ai failed, auth is compensated",
+			current: newDeletion(m.DeletionInProgress, m.DeletionStepAi),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAi, Action: m.DeletionExecute, Error: "archive AI: connection refused"}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAi, m.StepFailed, "archive AI: connection refused").Return(nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionInProgress, map[string]interface{}{"status": m.DeletionCompensating, "error": "archive AI: connection refused"}).Return(nil).Times(1)
+				expectCommand(mocks, current, m.DeletionCompensating, m.DeletionStepAuth, m.DeletionCompensate)
+			},
+		},
+		{
+			name:    "avatar compensated, picture link removed and ai is compensated",
+			current: newDeletion(m.DeletionCompensating, m.DeletionStepAvatar),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				current.Steps[2].Status = m.StepDone
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAvatar, Action: m.DeletionCompensate, Success: true}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.user.EXPECT().Update(current.UserId.String(), map[string]interface{}{"picture": ""}).Return(nil).Times(1)
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAvatar, m.StepCompensated, "").Return(nil).Times(1)
+				expectCommand(mocks, current, m.DeletionCompensating, m.DeletionStepAi, m.DeletionCompensate)
+			},
+		},
+		{
+			name:    "last step compensated, deletion failed",
+			current: newDeletion(m.DeletionCompensating, m.DeletionStepAuth),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				current.Steps[0].Status = m.StepDone
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAuth, Action: m.DeletionCompensate, Success: true}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				existUser := &m.User{ID: current.UserId, Email: "ivan@example.com"}
+
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAuth, m.StepCompensated, "").Return(nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionCompensating, map[string]interface{}{"status": m.DeletionFailed}).Return(nil).Times(1)
+				mocks.user.EXPECT().GetOneBy(map[string]interface{}{"id": current.UserId.String()}).Return(existUser, nil).Times(1)
+				mocks.mail.EXPECT().SendEmail(deletionFailedEmail(existUser)).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "compensation failed",
+			current: newDeletion(m.DeletionCompensating, m.DeletionStepAuth),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAuth, Action: m.DeletionCompensate, Error: "restore sessions: timeout"}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().UpdateStep(current.ID.String(), m.DeletionStepAuth, m.StepFailed, "restore sessions: timeout").Return(nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionCompensating, map[string]interface{}{"status": m.DeletionFailed, "error": "compensation of auth failed: restore sessions: timeout"}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "redelivered reply for a passed step",
+			current: newDeletion(m.DeletionInProgress, m.DeletionStepAi),
+			reply: func(current *m.AccountDeletion) m.AccountDeletionReply {
+				return m.AccountDeletionReply{DeletionId: current.ID.String(), Step: m.DeletionStepAuth, Action: m.DeletionExecute, Success: true}
+			},
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			mocks := newDeletionMocks(ctl)
+			logger := logrus.New()
+
+			reply := tc.reply(tc.current)
+			mocks.deletion.EXPECT().Get(map[string]interface{}{"id": tc.current.ID.String()}).Return(tc.current, nil).Times(1)
+			tc.setup(mocks, tc.current)
+
+			HandleDeletionReply(reply, mocks.user, mocks.deletion, mocks.producer, mocks.mail, logger)
+		})
+	}
+}
+
+func TestRunDueDeletions(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	mocks := newDeletionMocks(ctl)
+	logger := logrus.New()
+
+	started := newDeletion(m.DeletionScheduled, "")
+	cancelled := newDeletion(m.DeletionScheduled, "")
+
+	mocks.deletion.EXPECT().GetDue(gomock.Any(), dueDeletionsLimit).Return(&[]m.AccountDeletion{*started, *cancelled}, nil).Times(1)
+	mocks.deletion.EXPECT().Transition(started.ID.String(), m.DeletionScheduled, map[string]interface{}{"status": m.DeletionInProgress}).Return(nil).Times(1)
+	// Отменено между выборкой и запуском
+	mocks.deletion.EXPECT().Transition(cancelled.ID.String(), m.DeletionScheduled, map[string]interface{}{"status": m.DeletionInProgress}).Return(e.NewDBError(e.DbNotFound, "Account deletion not found.", "")).Times(1)
+	expectCommand(mocks, started, m.DeletionInProgress, m.DeletionStepAuth, m.DeletionExecute)
+
+	RunDueDeletions(mocks.user, mocks.deletion, mocks.producer, mocks.mail, logger)
+}
+
+func TestRunStaleDeletions(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	mocks := newDeletionMocks(ctl)
+	logger := logrus.New()
+
+	executing := newDeletion(m.DeletionInProgress, m.DeletionStepAi)
+	compensating := newDeletion(m.DeletionCompensating, m.DeletionStepAi)
+	finished := newDeletion(m.DeletionInProgress, m.DeletionStepUser)
+	finished.Steps[2].Status = m.StepSkipped
+	finished.Steps[3].Status = m.StepDone
+
+	mocks.deletion.EXPECT().GetStale(gomock.Any(), dueDeletionsLimit).Return(&[]m.AccountDeletion{*executing, *compensating, *finished}, nil).Times(1)
+	// Ответ на ai потерялся: шаг отправляется ещё раз
+	expectCommand(mocks, executing, m.DeletionInProgress, m.DeletionStepAi, m.DeletionExecute)
+	// Откат ai потерялся, ai ещё не выполнен: откатывается auth
+	expectCommand(mocks, compensating, m.DeletionCompensating, m.DeletionStepAuth, m.DeletionCompensate)
+	mocks.deletion.EXPECT().Transition(finished.ID.String(), m.DeletionInProgress, map[string]interface{}{"status": m.DeletionCompleted}).Return(nil).Times(1)
+
+	RunStaleDeletions(time.Minute, mocks.user, mocks.deletion, mocks.producer, mocks.mail, logger)
+}
+
+func TestRequestAccountDeletion(t *testing.T) {
+	passwordHash, _ := bcrypt.GenerateFromPassword([]byte("Password1!"), bcrypt.MinCost)
+	existUser := &m.User{ID: uuid.Must(uuid.NewV4()), Email: "ivan@example.com", Password: string(passwordHash)}
+	googleUser := &m.User{ID: uuid.Must(uuid.NewV4()), Email: "anna@example.com", Password: string(passwordHash), ViaGoogle: true}
+	cfg := config.DeletionCfg{GracePeriod: time.Hour, ConfirmTTL: time.Hour}
+	active := map[string]interface{}{"user_id": existUser.ID, "status": []m.DeletionStatus{m.DeletionScheduled, m.DeletionInProgress, m.DeletionCompensating}}
+
+	cases := []struct {
+		name     string
+		request  DeleteAccountRequest
+		user     *m.User
+		setup    func(mocks deletionMocks)
+		expected m.DeletionStatus
+		errCode  int
+	}{
+		{
+			name:    "password confirmed",
+			request: DeleteAccountRequest{Password: "Password1!"},
+			user:    existUser,
+			setup: func(mocks deletionMocks) {
+				mocks.deletion.EXPECT().Create(gomock.Cond(func(x any) bool {
+					deletion := x.(*m.AccountDeletion)
+					return deletion.Status == m.DeletionScheduled && deletion.ConfirmToken == nil && len(deletion.Steps) == len(m.DeletionSteps)
+				})).Return(nil).Times(1)
+				mocks.mail.EXPECT().SendEmail(gomock.Any()).Return(nil).Times(1)
+			},
+			expected: m.DeletionScheduled,
+		},
+		{
+			name:    "wrong password",
+			request: DeleteAccountRequest{Password: "Password2!"},
+			user:    existUser,
+			setup:   func(mocks deletionMocks) {},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "google account without password",
+			request: DeleteAccountRequest{},
+			user:    googleUser,
+			setup:   func(mocks deletionMocks) {},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "confirm by email",
+			request: DeleteAccountRequest{ConfirmByEmail: true},
+			user:    existUser,
+			setup: func(mocks deletionMocks) {
+				mocks.deletion.EXPECT().Get(active).Return(nil, e.NewDBError(e.DbNotFound, "Account deletion not found.", "record not found")).Times(1)
+				mocks.deletion.EXPECT().Create(gomock.Cond(func(x any) bool {
+					deletion := x.(*m.AccountDeletion)
+					return deletion.Status == m.DeletionUnconfirmed && deletion.ConfirmToken != nil
+				})).Return(nil).Times(1)
+				mocks.mail.EXPECT().SendEmail(gomock.Cond(func(x any) bool {
+					return x.(m.Email).Subject == "Подтверждение удаления аккаунта"
+				})).Return(nil).Times(1)
+			},
+			expected: m.DeletionUnconfirmed,
+		},
+		{
+			name:    "confirm by email with deletion already scheduled",
+			request: DeleteAccountRequest{ConfirmByEmail: true},
+			user:    existUser,
+			setup: func(mocks deletionMocks) {
+				mocks.deletion.EXPECT().Get(active).Return(&m.AccountDeletion{Status: m.DeletionScheduled}, nil).Times(1)
+			},
+			errCode: e.HttpAlreadyExist,
+		},
+		{
+			name:    "confirmation email not sent",
+			request: DeleteAccountRequest{ConfirmByEmail: true},
+			user:    existUser,
+			setup: func(mocks deletionMocks) {
+				mocks.deletion.EXPECT().Get(active).Return(nil, e.NewDBError(e.DbNotFound, "Account deletion not found.", "record not found")).Times(1)
+				mocks.deletion.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+				mocks.mail.EXPECT().SendEmail(gomock.Any()).Return(errors.New("channel closed")).Times(1)
+			},
+			errCode: e.HttpInternalError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			mocks := newDeletionMocks(ctl)
+			logger := logrus.New()
+
+			tc.setup(mocks)
+
+			deletion, err := RequestAccountDeletion(tc.request, tc.user, mocks.deletion, mocks.mail, cfg, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, deletion.Status)
+		})
+	}
+}
+
+func TestConfirmAccountDeletion(t *testing.T) {
+	existUser := &m.User{ID: uuid.Must(uuid.NewV4()), Email: "ivan@example.com"}
+	cfg := config.DeletionCfg{GracePeriod: time.Hour, ConfirmTTL: time.Hour}
+	conditions := map[string]interface{}{"confirm_token_hash": hashKey("key"), "status": m.DeletionUnconfirmed}
+	scheduled := gomock.Cond(func(x any) bool {
+		fields := x.(map[string]interface{})
+		return fields["status"] == m.DeletionScheduled && fields["confirm_token_hash"] == nil
+	})
+
+	cases := []struct {
+		name    string
+		key     string
+		setup   func(mocks deletionMocks, current *m.AccountDeletion)
+		errCode int
+	}{
+		{
+			name: "confirmed",
+			key:  "key",
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().Get(conditions).Return(current, nil).Times(1)
+				mocks.user.EXPECT().GetOneByPreload(map[string]interface{}{"id": existUser.ID.String()}, "Roles").Return(existUser, nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionUnconfirmed, scheduled).Return(nil).Times(1)
+				mocks.mail.EXPECT().SendEmail(gomock.Cond(func(x any) bool {
+					return x.(m.Email).Subject == "Удаление аккаунта запланировано"
+				})).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "link expired",
+			key:  "key",
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				current.CreatedAt = time.Now().Add(-2 * time.Hour)
+				mocks.deletion.EXPECT().Get(conditions).Return(current, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name: "another deletion already scheduled",
+			key:  "key",
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().Get(conditions).Return(current, nil).Times(1)
+				mocks.user.EXPECT().GetOneByPreload(map[string]interface{}{"id": existUser.ID.String()}, "Roles").Return(existUser, nil).Times(1)
+				mocks.deletion.EXPECT().Transition(current.ID.String(), m.DeletionUnconfirmed, scheduled).Return(e.NewDBError(e.DbExist, "Account deletion already exists.", "duplicate key")).Times(1)
+			},
+			errCode: e.HttpAlreadyExist,
+		},
+		{
+			name: "unknown key",
+			key:  "key",
+			setup: func(mocks deletionMocks, current *m.AccountDeletion) {
+				mocks.deletion.EXPECT().Get(conditions).Return(nil, e.NewDBError(e.DbNotFound, "Account deletion not found.", "record not found")).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "empty key",
+			setup:   func(mocks deletionMocks, current *m.AccountDeletion) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			mocks := newDeletionMocks(ctl)
+			logger := logrus.New()
+
+			current := &m.AccountDeletion{ID: uuid.Must(uuid.NewV4()), UserId: existUser.ID, Status: m.DeletionUnconfirmed, CreatedAt: time.Now()}
+			tc.setup(mocks, current)
+
+			_, err := ConfirmAccountDeletion(tc.key, mocks.user, mocks.deletion, mocks.mail, cfg, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"warehouseai/user/adapter"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const pendingApplicationsLimit = 100
+
+type DeveloperApplyRequest struct {
+	DisplayName string `json:"display_name"`
+	Website     string `json:"website"`
+	AcceptTerms bool   `json:"accept_terms"`
+}
+
+type ReviewApplicationRequest struct {
+	ApplicationId string `json:"application_id"`
+	Approve       bool   `json:"approve"`
+	Comment       string `json:"comment"`
+}
+
+func validateDeveloperApplyRequest(request *DeveloperApplyRequest) *e.ErrorResponse {
+	request.DisplayName = strings.TrimSpace(request.DisplayName)
+	request.Website = strings.TrimSpace(request.Website)
+
+	if length := utf8.RuneCountInString(request.DisplayName); length < 2 || length > 64 {
+		return e.NewErrorResponse(e.HttpBadRequest, "Display name must be between 2 and 64 characters.")
+	}
+
+	if request.Website != "" {
+		website, err := url.Parse(request.Website)
+
+		if err != nil || (website.Scheme != "http" && website.Scheme != "https") || website.Host == "" {
+			return e.NewErrorResponse(e.HttpBadRequest, "Website must be a valid http(s) link.")
+		}
+	}
+
+	if !request.AcceptTerms {
+		return e.NewErrorResponse(e.HttpBadRequest, "Accept the developer terms to apply.")
+	}
+
+	return nil
+}
+
+// Подаёт заявку на статус разработчика. При AutoApprove заявка одобряется сразу.
+// Отклонённую заявку можно подать заново, ожидающую - нет.
+func ApplyForDeveloper(
+	request DeveloperApplyRequest,
+	userId string,
+	user d.UserInterface,
+	developer d.DeveloperInterface,
+	mail adapter.MailProducerInterface,
+	cfg config.DeveloperCfg,
+	logger *logrus.Logger,
+) (*m.DeveloperApplication, *e.ErrorResponse) {
+	if err := validateDeveloperApplyRequest(&request); err != nil {
+		return nil, err
+	}
+
+	existUser, err := GetById(userId, user, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if existUser.IsDeveloper {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "You are already a developer.")
+	}
+
+	existApplication, dbErr := developer.Get(map[string]interface{}{"user_id": userId})
+
+	if dbErr != nil && dbErr.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Apply for developer")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if dbErr == nil && existApplication.Status == m.ApplicationPending {
+		return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Your application is already under review.")
+	}
+
+	application := m.DeveloperApplication{
+		UserId:          existUser.ID,
+		DisplayName:     request.DisplayName,
+		Website:         request.Website,
+		TermsAcceptedAt: time.Now(),
+		Status:          m.ApplicationPending,
+	}
+
+	if dbErr := developer.Replace(&application); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Apply for developer")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if !cfg.AutoApprove {
+		return &application, nil
+	}
+
+	return reviewApplication(application.ID.String(), m.ApplicationApproved, uuid.NullUUID{}, "", user, developer, mail, logger)
+}
+
+func GetDeveloperApplication(userId string, developer d.DeveloperInterface, logger *logrus.Logger) (*m.DeveloperApplication, *e.ErrorResponse) {
+	application, dbErr := developer.Get(map[string]interface{}{"user_id": userId})
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get developer application")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return application, nil
+}
+
+func GetPendingApplications(developer d.DeveloperInterface, logger *logrus.Logger) (*[]m.DeveloperApplication, *e.ErrorResponse) {
+	applications, dbErr := developer.GetMany(map[string]interface{}{"status": m.ApplicationPending}, pendingApplicationsLimit)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get developer applications")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return applications, nil
+}
+
+func ReviewDeveloperApplication(
+	request ReviewApplicationRequest,
+	reviewerId string,
+	user d.UserInterface,
+	developer d.DeveloperInterface,
+	mail adapter.MailProducerInterface,
+	logger *logrus.Logger,
+) (*m.DeveloperApplication, *e.ErrorResponse) {
+	if _, err := uuid.FromString(request.ApplicationId); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid application id.")
+	}
+
+	if len(request.Comment) > 500 {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Comment is too long, provide less than 500 characters.")
+	}
+
+	status := m.ApplicationRejected
+
+	if request.Approve {
+		status = m.ApplicationApproved
+	}
+
+	reviewer := uuid.NullUUID{UUID: uuid.FromStringOrNil(reviewerId), Valid: true}
+
+	return reviewApplication(request.ApplicationId, status, reviewer, request.Comment, user, developer, mail, logger)
+}
+
+func reviewApplication(
+	applicationId string,
+	status m.ApplicationStatus,
+	reviewer uuid.NullUUID,
+	comment string,
+	user d.UserInterface,
+	developer d.DeveloperInterface,
+	mail adapter.MailProducerInterface,
+	logger *logrus.Logger,
+) (*m.DeveloperApplication, *e.ErrorResponse) {
+	application, dbErr := developer.Review(applicationId, status, reviewer, comment)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Review developer application")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	existUser, err := GetById(application.UserId.String(), user, logger)
+
+	if err != nil {
+		return application, nil
+	}
+
+	if err := mail.SendEmail(applicationReviewedEmail(existUser, application)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+
+	return application, nil
+}
+
+func applicationReviewedEmail(existUser *m.User, application *m.DeveloperApplication) m.Email {
+	if application.Status == m.ApplicationApproved {
+		return m.Email{
+			To:      existUser.Email,
+			Subject: "Заявка разработчика одобрена",
+			Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Ваша заявка на статус разработчика одобрена. Теперь вы можете публиковать AI от имени %s.
+
+      WarehouseAI Team
+      `, existUser.Firstname, application.DisplayName),
+		}
+	}
+
+	reason := ""
+
+	if application.Comment != "" {
+		reason = fmt.Sprintf("\n      Комментарий модератора: %s\n      ", application.Comment)
+	}
+
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Заявка разработчика отклонена",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      К сожалению, ваша заявка на статус разработчика отклонена.
+      %s
+      Вы можете исправить данные и подать заявку повторно.
+
+      WarehouseAI Team
+      `, existUser.Firstname, reason),
+	}
+}
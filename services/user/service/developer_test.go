@@ -0,0 +1,197 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	aMock "warehouseai/user/adapter/mocks"
+	"warehouseai/user/config"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReviewDeveloperApplication(t *testing.T) {
+	existUser := &m.User{ID: uuid.Must(uuid.NewV4()), Firstname: "Ivan", Email: "ivan@example.com"}
+	applicationId := uuid.Must(uuid.NewV4()).String()
+	reviewerId := uuid.Must(uuid.NewV4()).String()
+	reviewer := uuid.NullUUID{UUID: uuid.FromStringOrNil(reviewerId), Valid: true}
+
+	cases := []struct {
+		name     string
+		request  ReviewApplicationRequest
+		setup    func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface)
+		expected m.ApplicationStatus
+		errCode  int
+	}{
+		{
+			name:    "approve",
+			request: ReviewApplicationRequest{ApplicationId: applicationId, Approve: true},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				developerMock.EXPECT().Review(applicationId, m.ApplicationApproved, reviewer, "").Return(&m.DeveloperApplication{UserId: existUser.ID, Status: m.ApplicationApproved}, nil).Times(1)
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": existUser.ID.String()}, "Roles").Return(existUser, nil).Times(1)
+				mailMock.EXPECT().SendEmail(gomock.Cond(func(x any) bool {
+					return x.(m.Email).Subject == "Заявка разработчика одобрена"
+				})).Return(nil).Times(1)
+			},
+			expected: m.ApplicationApproved,
+		},
+		{
+			name:    "reject with comment",
+			request: ReviewApplicationRequest{ApplicationId: applicationId, Comment: "No website."},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				developerMock.EXPECT().Review(applicationId, m.ApplicationRejected, reviewer, "No website.").Return(&m.DeveloperApplication{UserId: existUser.ID, Status: m.ApplicationRejected, Comment: "No website."}, nil).Times(1)
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": existUser.ID.String()}, "Roles").Return(existUser, nil).Times(1)
+				mailMock.EXPECT().SendEmail(gomock.Cond(func(x any) bool { return strings.Contains(x.(m.Email).Message, "No website.") })).Return(nil).Times(1)
+			},
+			expected: m.ApplicationRejected,
+		},
+		{
+			name:    "already reviewed",
+			request: ReviewApplicationRequest{ApplicationId: applicationId, Approve: true},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				developerMock.EXPECT().Review(applicationId, m.ApplicationApproved, reviewer, "").Return(nil, e.NewDBError(e.DbNotFound, "Pending application not found.", "record not found")).Times(1)
+			},
+			errCode: e.HttpNotFound,
+		},
+		{
+			name:    "invalid application id",
+			request: ReviewApplicationRequest{ApplicationId: "not-a-uuid", Approve: true},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "comment too long",
+			request: ReviewApplicationRequest{ApplicationId: applicationId, Comment: strings.Repeat("a", 501)},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+			},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			userMock := dMock.NewMockUserInterface(ctl)
+			developerMock := dMock.NewMockDeveloperInterface(ctl)
+			mailMock := aMock.NewMockMailProducerInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(userMock, developerMock, mailMock)
+
+			application, err := ReviewDeveloperApplication(tc.request, reviewerId, userMock, developerMock, mailMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, application.Status)
+		})
+	}
+}
+
+func TestApplyForDeveloper(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	applicationId := uuid.Must(uuid.NewV4())
+	request := DeveloperApplyRequest{DisplayName: " Ivan Labs ", Website: "https://ivan.dev", AcceptTerms: true}
+
+	cases := []struct {
+		name     string
+		request  DeveloperApplyRequest
+		cfg      config.DeveloperCfg
+		setup    func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface)
+		expected m.ApplicationStatus
+		errCode  int
+	}{
+		{
+			name:    "pending review",
+			request: request,
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": userId}, "Roles").Return(&m.User{ID: uuid.FromStringOrNil(userId)}, nil).Times(1)
+				developerMock.EXPECT().Get(map[string]interface{}{"user_id": userId}).Return(nil, e.NewDBError(e.DbNotFound, "Application not found.", "record not found")).Times(1)
+				developerMock.EXPECT().Replace(gomock.Cond(func(x any) bool { return x.(*m.DeveloperApplication).DisplayName == "Ivan Labs" })).Return(nil).Times(1)
+			},
+			expected: m.ApplicationPending,
+		},
+		{
+			name:    "auto approve",
+			request: request,
+			cfg:     config.DeveloperCfg{AutoApprove: true},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": userId}, "Roles").Return(&m.User{ID: uuid.FromStringOrNil(userId)}, nil).Times(2)
+				developerMock.EXPECT().Get(map[string]interface{}{"user_id": userId}).Return(&m.DeveloperApplication{Status: m.ApplicationRejected}, nil).Times(1)
+				developerMock.EXPECT().Replace(gomock.Any()).DoAndReturn(func(application *m.DeveloperApplication) *e.DBError {
+					application.ID = applicationId
+					return nil
+				}).Times(1)
+				developerMock.EXPECT().Review(applicationId.String(), m.ApplicationApproved, uuid.NullUUID{}, "").Return(&m.DeveloperApplication{UserId: uuid.FromStringOrNil(userId), Status: m.ApplicationApproved}, nil).Times(1)
+				mailMock.EXPECT().SendEmail(gomock.Any()).Return(nil).Times(1)
+			},
+			expected: m.ApplicationApproved,
+		},
+		{
+			name:    "already pending",
+			request: request,
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": userId}, "Roles").Return(&m.User{ID: uuid.FromStringOrNil(userId)}, nil).Times(1)
+				developerMock.EXPECT().Get(map[string]interface{}{"user_id": userId}).Return(&m.DeveloperApplication{Status: m.ApplicationPending}, nil).Times(1)
+			},
+			errCode: e.HttpAlreadyExist,
+		},
+		{
+			name:    "already developer",
+			request: request,
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+				userMock.EXPECT().GetOneByPreload(map[string]interface{}{"id": userId}, "Roles").Return(&m.User{ID: uuid.FromStringOrNil(userId), IsDeveloper: true}, nil).Times(1)
+			},
+			errCode: e.HttpAlreadyExist,
+		},
+		{
+			name:    "terms not accepted",
+			request: DeveloperApplyRequest{DisplayName: "Ivan Labs"},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "invalid website",
+			request: DeveloperApplyRequest{DisplayName: "Ivan Labs", Website: "ftp://ivan.dev", AcceptTerms: true},
+			setup: func(userMock *dMock.MockUserInterface, developerMock *dMock.MockDeveloperInterface, mailMock *aMock.MockMailProducerInterface) {
+			},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			userMock := dMock.NewMockUserInterface(ctl)
+			developerMock := dMock.NewMockDeveloperInterface(ctl)
+			mailMock := aMock.NewMockMailProducerInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(userMock, developerMock, mailMock)
+
+			application, err := ApplyForDeveloper(tc.request, userId, userMock, developerMock, mailMock, tc.cfg, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, application.Status)
+		})
+	}
+}
@@ -0,0 +1,535 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"warehouseai/user/adapter"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const exportBatchLimit = 20
+
+// Ставит выгрузку персональных данных в очередь. Архив собирает StartExportWorker.
+func RequestDataExport(userId string, export d.ExportInterface, cfg config.ExportCfg, logger *logrus.Logger) (*m.DataExport, *e.ErrorResponse) {
+	lastExport, err := export.Get(map[string]interface{}{"user_id": userId})
+
+	if err != nil && err.ErrorType != e.DbNotFound {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Request data export")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	if lastExport != nil {
+		if lastExport.Status == m.ExportPending || lastExport.Status == m.ExportProcessing {
+			return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Data export is already in progress.")
+		}
+
+		// Неудачная выгрузка не считается: пользователь может сразу запросить новую
+		if lastExport.Status != m.ExportFailed && time.Since(lastExport.CreatedAt) < cfg.Cooldown {
+			return nil, e.NewErrorResponse(e.HttpAlreadyExist, "Data export can be requested once per "+cfg.Cooldown.String()+".")
+		}
+	}
+
+	newExport := &m.DataExport{UserId: uuid.FromStringOrNil(userId), Status: m.ExportPending}
+
+	if err := export.Create(newExport); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Request data export")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return newExport, nil
+}
+
+func GetDataExport(userId string, export d.ExportInterface, logger *logrus.Logger) (*m.DataExport, *e.ErrorResponse) {
+	existExport, err := export.Get(map[string]interface{}{"user_id": userId})
+
+	if err != nil {
+		if err.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "Data export was not requested.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get data export")
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	return existExport, nil
+}
+
+type exportWorker struct {
+	cfg        config.ExportCfg
+	user       d.UserInterface
+	favorites  d.FavoritesInterface
+	collection d.CollectionInterface
+	follow     d.FollowInterface
+	export     d.ExportInterface
+	storage    d.ExportStorageInterface
+	ai         adapter.AiGrpcInterface
+	auth       adapter.AuthGrpcInterface
+	mail       adapter.MailProducerInterface
+	notify     d.NotificationInterface
+	hub        *NotificationHub
+	logger     *logrus.Logger
+}
+
+// Собирает запрошенные выгрузки, снимает зависшие и удаляет архивы с истёкшей ссылкой. Работает до закрытия stop.
+func StartExportWorker(cfg config.ExportCfg, user d.UserInterface, favorites d.FavoritesInterface, collection d.CollectionInterface, follow d.FollowInterface, export d.ExportInterface, storage d.ExportStorageInterface, ai adapter.AiGrpcInterface, auth adapter.AuthGrpcInterface, mail adapter.MailProducerInterface, notification d.NotificationInterface, hub *NotificationHub, logger *logrus.Logger, stop <-chan struct{}) {
+	if cfg.Interval <= 0 {
+		return
+	}
+
+	worker := exportWorker{cfg: cfg, user: user, favorites: favorites, collection: collection, follow: follow, export: export, storage: storage, ai: ai, auth: auth, mail: mail, notify: notification, hub: hub, logger: logger}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	worker.failStale()
+	worker.runPending()
+	worker.purgeExpired()
+
+	for {
+		select {
+		case <-ticker.C:
+			worker.failStale()
+			worker.runPending()
+			worker.purgeExpired()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (w exportWorker) runPending() {
+	pending, err := w.export.GetMany(map[string]interface{}{"status": m.ExportPending}, exportBatchLimit)
+
+	if err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run data exports")
+		return
+	}
+
+	for i := range *pending {
+		current := &(*pending)[i]
+
+		// Выгрузку уже забрал другой экземпляр сервиса
+		if err := w.export.Transition(current.ID.String(), m.ExportPending, map[string]interface{}{"status": m.ExportProcessing}); err != nil {
+			continue
+		}
+
+		w.process(current)
+	}
+}
+
+// Экземпляр, собиравший выгрузку, упал. Без этого выгрузка навсегда осталась бы в processing,
+// а новую нельзя было бы запросить. После failed пользователь может сразу запросить новую.
+func (w exportWorker) failStale() {
+	if w.cfg.ProcessingTimeout <= 0 {
+		return
+	}
+
+	stale, err := w.export.GetStale(time.Now().Add(-w.cfg.ProcessingTimeout), exportBatchLimit)
+
+	if err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run data exports")
+		return
+	}
+
+	for i := range *stale {
+		w.fail(&(*stale)[i], "export processing timed out")
+	}
+}
+
+func (w exportWorker) process(current *m.DataExport) {
+	existUser, err := w.user.GetOneBy(map[string]interface{}{"id": current.UserId})
+
+	if err != nil {
+		w.fail(current, err.Message)
+		return
+	}
+
+	data, collectErr := w.collect(existUser)
+
+	if collectErr != nil {
+		w.fail(current, collectErr.ErrorMessage)
+		return
+	}
+
+	archive, archiveErr := buildExportArchive(data)
+
+	if archiveErr != nil {
+		w.fail(current, archiveErr.Error())
+		return
+	}
+
+	key := fmt.Sprintf("exports/%s/%s.zip", current.UserId, current.ID)
+
+	if err := w.storage.Save(key, bytes.NewReader(archive)); err != nil {
+		w.fail(current, err.Error())
+		return
+	}
+
+	link, linkErr := w.storage.PresignDownload(key, w.cfg.LinkTTL)
+
+	if linkErr != nil {
+		w.storage.Delete(key)
+		w.fail(current, linkErr.Error())
+		return
+	}
+
+	expiresAt := time.Now().Add(w.cfg.LinkTTL)
+
+	if err := w.export.Transition(current.ID.String(), m.ExportProcessing, map[string]interface{}{"status": m.ExportReady, "file_key": key, "expires_at": expiresAt}); err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run data exports")
+		w.storage.Delete(key)
+		return
+	}
+
+	if err := w.mail.SendEmail(exportReadyEmail(existUser, link, expiresAt)); err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+
+	w.notifyUser(current, m.NotifyExportReady, map[string]interface{}{"export_id": current.ID.String(), "expires_at": expiresAt})
+}
+
+func (w exportWorker) collect(existUser *m.User) (*m.ExportData, *e.ErrorResponse) {
+	userId := existUser.ID.String()
+
+	favorites, err := w.favorites.GetUserFavorites(userId)
+
+	if err != nil {
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	collections, err := w.collection.GetMany(userId)
+
+	if err != nil {
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	follows, err := w.follow.GetFollowing(userId)
+
+	if err != nil {
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	notifications, err := w.notify.GetAll(userId)
+
+	if err != nil {
+		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
+	aiData, aiErr := w.ai.GetUserData(userId)
+
+	if aiErr != nil {
+		return nil, aiErr
+	}
+
+	auditEvents, auditErr := w.auth.GetUserAuditEvents(userId, existUser.Email)
+
+	if auditErr != nil {
+		return nil, auditErr
+	}
+
+	data := &m.ExportData{
+		ExportedAt: time.Now(),
+		Profile: m.ExportProfile{
+			Id:          userId,
+			Firstname:   existUser.Firstname,
+			Lastname:    existUser.Lastname,
+			Username:    existUser.Username,
+			Email:       existUser.Email,
+			Picture:     existUser.Picture,
+			Bio:         existUser.Bio,
+			ViaGoogle:   existUser.ViaGoogle,
+			Verified:    existUser.Verified,
+			IsDeveloper: existUser.IsDeveloper,
+			CreatedAt:   existUser.CreatedAt,
+			UpdatedAt:   existUser.UpdatedAt,
+		},
+		Favorites:     make([]string, 0, len(*favorites)),
+		Collections:   make([]m.ExportCollection, 0, len(*collections)),
+		Follows:       make([]m.ExportFollow, 0, len(*follows)),
+		Notifications: make([]m.ExportNotification, 0, len(*notifications)),
+		Owned:         aiData.Owned,
+		Ratings:       aiData.Ratings,
+		Executions:    aiData.Executions,
+		AuditEvents:   auditEvents,
+	}
+
+	for _, favorite := range *favorites {
+		data.Favorites = append(data.Favorites, favorite.AiId.String())
+	}
+
+	for _, collection := range *collections {
+		aiIds, err := w.collection.GetItemIds(collection.ID.String())
+
+		if err != nil {
+			return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+		}
+
+		data.Collections = append(data.Collections, m.ExportCollection{
+			Id:        collection.ID.String(),
+			Name:      collection.Name,
+			Position:  collection.Position,
+			IsPublic:  collection.IsPublic,
+			AiIds:     aiIds,
+			CreatedAt: collection.CreatedAt,
+		})
+	}
+
+	for _, follow := range *follows {
+		data.Follows = append(data.Follows, m.ExportFollow{DeveloperId: follow.DeveloperId.String(), CreatedAt: follow.CreatedAt})
+	}
+
+	for _, notification := range *notifications {
+		data.Notifications = append(data.Notifications, m.ExportNotification{
+			Type:      notification.Type,
+			Data:      notification.Data,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	return data, nil
+}
+
+func (w exportWorker) fail(current *m.DataExport, reason string) {
+	w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": reason}).Info("Run data exports")
+
+	if err := w.export.Transition(current.ID.String(), m.ExportProcessing, map[string]interface{}{"status": m.ExportFailed, "error": reason}); err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run data exports")
+		return
+	}
+
+	w.notifyUser(current, m.NotifyExportFailed, map[string]interface{}{"export_id": current.ID.String()})
+}
+
+// Ссылку на архив уведомление не содержит: её отдаёт /user/export/status, пока архив не удалён
+func (w exportWorker) notifyUser(current *m.DataExport, notificationType m.NotificationType, data map[string]interface{}) {
+	newNotification := m.Notification{
+		UserId:  current.UserId,
+		Type:    notificationType,
+		EventId: fmt.Sprintf("export.%s.%s", current.ID, notificationType),
+		Data:    data,
+	}
+
+	if err := deliver([]m.Notification{newNotification}, w.notify, w.hub); err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Run data exports")
+	}
+}
+
+func (w exportWorker) purgeExpired() {
+	expired, err := w.export.GetExpired(time.Now(), exportBatchLimit)
+
+	if err != nil {
+		w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge data exports")
+		return
+	}
+
+	for _, current := range *expired {
+		// Если архив не удалился, попробуем на следующем тике
+		if err := w.storage.Delete(current.FileKey); err != nil {
+			w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Purge data exports")
+			continue
+		}
+
+		if err := w.export.Transition(current.ID.String(), m.ExportReady, map[string]interface{}{"status": m.ExportExpired, "file_key": ""}); err != nil {
+			w.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Purge data exports")
+		}
+	}
+}
+
+// data.json со всеми данными и по CSV на каждую таблицу
+func buildExportArchive(data *m.ExportData) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	dataJson, err := json.MarshalIndent(data, "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		rows [][]string
+	}{
+		{"profile.csv", profileRows(data.Profile)},
+		{"favorites.csv", favoriteRows(data.Favorites)},
+		{"collections.csv", collectionRows(data.Collections)},
+		{"follows.csv", followRows(data.Follows)},
+		{"notifications.csv", notificationRows(data.Notifications)},
+		{"owned_ai.csv", ownedAiRows(data.Owned)},
+		{"ratings.csv", ratingRows(data.Ratings)},
+		{"executions.csv", executionRows(data.Executions)},
+		{"audit_events.csv", auditEventRows(data.AuditEvents)},
+	}
+
+	file, err := archive.Create("data.json")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Write(dataJson); err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		file, err := archive.Create(f.name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := csv.NewWriter(file).WriteAll(f.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func profileRows(profile m.ExportProfile) [][]string {
+	return [][]string{
+		{"id", "firstname", "lastname", "username", "email", "picture", "bio", "via_google", "verified", "is_developer", "created_at", "updated_at"},
+		{
+			profile.Id,
+			profile.Firstname,
+			profile.Lastname,
+			profile.Username,
+			profile.Email,
+			profile.Picture,
+			profile.Bio,
+			strconv.FormatBool(profile.ViaGoogle),
+			strconv.FormatBool(profile.Verified),
+			strconv.FormatBool(profile.IsDeveloper),
+			profile.CreatedAt.Format(time.RFC3339),
+			profile.UpdatedAt.Format(time.RFC3339),
+		},
+	}
+}
+
+func favoriteRows(favorites []string) [][]string {
+	rows := [][]string{{"ai_id"}}
+
+	for _, aiId := range favorites {
+		rows = append(rows, []string{aiId})
+	}
+
+	return rows
+}
+
+// ИИ коллекции в одной ячейке через пробел, в порядке коллекции
+func collectionRows(collections []m.ExportCollection) [][]string {
+	rows := [][]string{{"id", "name", "position", "is_public", "ai_ids", "created_at"}}
+
+	for _, collection := range collections {
+		rows = append(rows, []string{
+			collection.Id,
+			collection.Name,
+			strconv.Itoa(collection.Position),
+			strconv.FormatBool(collection.IsPublic),
+			strings.Join(collection.AiIds, " "),
+			collection.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return rows
+}
+
+func followRows(follows []m.ExportFollow) [][]string {
+	rows := [][]string{{"developer_id", "created_at"}}
+
+	for _, follow := range follows {
+		rows = append(rows, []string{follow.DeveloperId, follow.CreatedAt.Format(time.RFC3339)})
+	}
+
+	return rows
+}
+
+// Data у каждого типа своя, поэтому в CSV она лежит JSON'ом
+func notificationRows(notifications []m.ExportNotification) [][]string {
+	rows := [][]string{{"type", "data", "read_at", "created_at"}}
+
+	for _, notification := range notifications {
+		data, _ := json.Marshal(notification.Data)
+		readAt := ""
+
+		if notification.ReadAt != nil {
+			readAt = notification.ReadAt.Format(time.RFC3339)
+		}
+
+		rows = append(rows, []string{string(notification.Type), string(data), readAt, notification.CreatedAt.Format(time.RFC3339)})
+	}
+
+	return rows
+}
+
+func ownedAiRows(owned []m.ExportOwnedAi) [][]string {
+	rows := [][]string{{"id", "name", "description", "used", "created_at"}}
+
+	for _, ai := range owned {
+		rows = append(rows, []string{ai.Id, ai.Name, ai.Description, strconv.FormatInt(ai.Used, 10), ai.CreatedAt})
+	}
+
+	return rows
+}
+
+func ratingRows(ratings []m.ExportRating) [][]string {
+	rows := [][]string{{"ai_id", "rate", "verified_usage", "created_at"}}
+
+	for _, rate := range ratings {
+		rows = append(rows, []string{rate.AiId, strconv.Itoa(int(rate.Rate)), strconv.FormatBool(rate.VerifiedUsage), rate.CreatedAt})
+	}
+
+	return rows
+}
+
+func executionRows(executions []m.ExportExecution) [][]string {
+	rows := [][]string{{"id", "ai_id", "command_id", "created_at"}}
+
+	for _, execution := range executions {
+		rows = append(rows, []string{execution.Id, execution.AiId, execution.CommandId, execution.CreatedAt})
+	}
+
+	return rows
+}
+
+func auditEventRows(events []m.ExportAuditEvent) [][]string {
+	rows := [][]string{{"action", "target", "ip", "user_agent", "result", "created_at"}}
+
+	for _, event := range events {
+		rows = append(rows, []string{event.Action, event.Target, event.IP, event.UserAgent, event.Result, event.CreatedAt})
+	}
+
+	return rows
+}
+
+func exportReadyEmail(existUser *m.User, link string, expiresAt time.Time) m.Email {
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Ваши данные готовы к скачиванию",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Архив с вашими данными WarehouseAI готов: %s
+      Ссылка действует до %s, после этого архив будет удалён.
+
+      WarehouseAI Team
+      `, existUser.Firstname, link, expiresAt.Format("02.01.2006 15:04")),
+	}
+}
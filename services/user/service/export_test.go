@@ -0,0 +1,249 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+	aMock "warehouseai/user/adapter/mocks"
+	"warehouseai/user/config"
+	"warehouseai/user/dataservice/memory/exportdata"
+	dMock "warehouseai/user/dataservice/mocks"
+	"warehouseai/user/dataservice/signedlink"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequestDataExport(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	cfg := config.ExportCfg{Cooldown: 24 * time.Hour}
+
+	cases := []struct {
+		name       string
+		lastExport *m.DataExport
+		errCode    int
+	}{
+		{name: "first export"},
+		{name: "export in progress", lastExport: &m.DataExport{Status: m.ExportProcessing, CreatedAt: time.Now().Add(-48 * time.Hour)}, errCode: e.HttpAlreadyExist},
+		{name: "cooldown", lastExport: &m.DataExport{Status: m.ExportReady, CreatedAt: time.Now().Add(-time.Hour)}, errCode: e.HttpAlreadyExist},
+		{name: "cooldown passed", lastExport: &m.DataExport{Status: m.ExportExpired, CreatedAt: time.Now().Add(-48 * time.Hour)}},
+		{name: "failed export does not count", lastExport: &m.DataExport{Status: m.ExportFailed, CreatedAt: time.Now().Add(-time.Hour)}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			exportMock := dMock.NewMockExportInterface(ctl)
+			logger := logrus.New()
+
+			if tc.lastExport == nil {
+				exportMock.EXPECT().Get(map[string]interface{}{"user_id": userId}).Return(nil, e.NewDBError(e.DbNotFound, "Data export not found.", "record not found")).Times(1)
+			} else {
+				exportMock.EXPECT().Get(map[string]interface{}{"user_id": userId}).Return(tc.lastExport, nil).Times(1)
+			}
+
+			if tc.errCode == 0 {
+				exportMock.EXPECT().Create(&m.DataExport{UserId: uuid.FromStringOrNil(userId), Status: m.ExportPending}).Return(nil).Times(1)
+			}
+
+			export, err := RequestDataExport(userId, exportMock, cfg, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, m.ExportPending, export.Status)
+		})
+	}
+}
+
+func TestExportWorkerRunPending(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	userMock := dMock.NewMockUserInterface(ctl)
+	favoritesMock := dMock.NewMockFavoritesInterface(ctl)
+	collectionMock := dMock.NewMockCollectionInterface(ctl)
+	followMock := dMock.NewMockFollowInterface(ctl)
+	exportMock := dMock.NewMockExportInterface(ctl)
+	notificationMock := dMock.NewMockNotificationInterface(ctl)
+	aiMock := aMock.NewMockAiGrpcInterface(ctl)
+	authMock := aMock.NewMockAuthGrpcInterface(ctl)
+	mailMock := aMock.NewMockMailProducerInterface(ctl)
+	storage := exportdata.NewStorage(signedlink.Signer{Link: "http://localhost:8000/user/export/download", Key: "secret"})
+	logger := logrus.New()
+
+	existUser := &m.User{ID: uuid.Must(uuid.NewV4()), Firstname: "Ivan", Username: "ivan", Email: "ivan@example.com"}
+	userId := existUser.ID.String()
+	current := m.DataExport{ID: uuid.Must(uuid.NewV4()), UserId: existUser.ID, Status: m.ExportPending}
+	// Уже забрал другой экземпляр сервиса
+	taken := m.DataExport{ID: uuid.Must(uuid.NewV4()), UserId: uuid.Must(uuid.NewV4()), Status: m.ExportPending}
+	collection := m.FavoriteCollection{ID: uuid.Must(uuid.NewV4()), Name: "Text", IsPublic: true}
+	aiId := uuid.Must(uuid.NewV4())
+	developerId := uuid.Must(uuid.NewV4())
+	key := "exports/" + userId + "/" + current.ID.String() + ".zip"
+	var link string
+
+	exportMock.EXPECT().GetMany(map[string]interface{}{"status": m.ExportPending}, exportBatchLimit).Return(&[]m.DataExport{current, taken}, nil).Times(1)
+	exportMock.EXPECT().Transition(current.ID.String(), m.ExportPending, map[string]interface{}{"status": m.ExportProcessing}).Return(nil).Times(1)
+	exportMock.EXPECT().Transition(taken.ID.String(), m.ExportPending, map[string]interface{}{"status": m.ExportProcessing}).Return(e.NewDBError(e.DbNotFound, "Data export not found.", "")).Times(1)
+
+	userMock.EXPECT().GetOneBy(map[string]interface{}{"id": existUser.ID}).Return(existUser, nil).Times(1)
+	favoritesMock.EXPECT().GetUserFavorites(userId).Return(&[]m.UserFavorite{{AiId: aiId}}, nil).Times(1)
+	collectionMock.EXPECT().GetMany(userId).Return(&[]m.FavoriteCollection{collection}, nil).Times(1)
+	collectionMock.EXPECT().GetItemIds(collection.ID.String()).Return([]string{aiId.String()}, nil).Times(1)
+	followMock.EXPECT().GetFollowing(userId).Return(&[]m.UserFollow{{FollowerId: existUser.ID, DeveloperId: developerId}}, nil).Times(1)
+	notificationMock.EXPECT().GetAll(userId).Return(&[]m.Notification{{Type: m.NotifyAiCreated, Data: map[string]interface{}{"ai_id": aiId.String()}}}, nil).Times(1)
+	aiMock.EXPECT().GetUserData(userId).Return(&m.AiUserData{Ratings: []m.ExportRating{{AiId: aiId.String(), Rate: 5}}}, nil).Times(1)
+	authMock.EXPECT().GetUserAuditEvents(userId, existUser.Email).Return([]m.ExportAuditEvent{{Action: "login"}}, nil).Times(1)
+
+	exportMock.EXPECT().Transition(current.ID.String(), m.ExportProcessing, gomock.Cond(func(x any) bool {
+		fields := x.(map[string]interface{})
+		return fields["status"] == m.ExportReady && fields["file_key"] == key
+	})).Return(nil).Times(1)
+	mailMock.EXPECT().SendEmail(gomock.Any()).DoAndReturn(func(email m.Email) error {
+		link = regexp.MustCompile(`http://\S+`).FindString(email.Message)
+		return nil
+	}).Times(1)
+	notificationMock.EXPECT().CreateMany(gomock.Cond(func(x any) bool {
+		notifications := x.([]m.Notification)
+		return len(notifications) == 1 && notifications[0].Type == m.NotifyExportReady
+	})).Return(nil).Times(1)
+
+	worker := exportWorker{
+		cfg:        config.ExportCfg{LinkTTL: time.Hour},
+		user:       userMock,
+		favorites:  favoritesMock,
+		collection: collectionMock,
+		follow:     followMock,
+		export:     exportMock,
+		storage:    storage,
+		ai:         aiMock,
+		auth:       authMock,
+		mail:       mailMock,
+		notify:     notificationMock,
+		hub:        NewNotificationHub(),
+		logger:     logger,
+	}
+
+	worker.runPending()
+
+	// Ссылка из письма подписана и ведёт на архив
+	downloadLink, err := url.Parse(link)
+	require.NoError(t, err)
+
+	query := downloadLink.Query()
+	require.Equal(t, key, query.Get("key"))
+	require.NoError(t, storage.Verify(query.Get("key"), query.Get("expires"), query.Get("signature")))
+
+	file, err := storage.Open(key)
+	require.NoError(t, err)
+
+	payload, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	require.NoError(t, err)
+
+	var data m.ExportData
+	require.NoError(t, json.Unmarshal(readArchiveFile(t, archive, "data.json"), &data))
+	require.Equal(t, []string{aiId.String()}, data.Favorites)
+	require.Equal(t, []string{aiId.String()}, data.Collections[0].AiIds)
+	require.Equal(t, developerId.String(), data.Follows[0].DeveloperId)
+	require.Equal(t, m.NotifyAiCreated, data.Notifications[0].Type)
+	require.Equal(t, int32(5), data.Ratings[0].Rate)
+}
+
+func TestExportWorkerFailStale(t *testing.T) {
+	ctl := gomock.NewController(t)
+
+	exportMock := dMock.NewMockExportInterface(ctl)
+	notificationMock := dMock.NewMockNotificationInterface(ctl)
+	logger := logrus.New()
+
+	stale := m.DataExport{ID: uuid.Must(uuid.NewV4()), UserId: uuid.Must(uuid.NewV4()), Status: m.ExportProcessing}
+	// Успела закончиться между выборкой и снятием
+	finished := m.DataExport{ID: uuid.Must(uuid.NewV4()), UserId: uuid.Must(uuid.NewV4()), Status: m.ExportProcessing}
+
+	exportMock.EXPECT().GetStale(gomock.Any(), exportBatchLimit).Return(&[]m.DataExport{stale, finished}, nil).Times(1)
+	exportMock.EXPECT().Transition(stale.ID.String(), m.ExportProcessing, map[string]interface{}{"status": m.ExportFailed, "error": "export processing timed out"}).Return(nil).Times(1)
+	exportMock.EXPECT().Transition(finished.ID.String(), m.ExportProcessing, map[string]interface{}{"status": m.ExportFailed, "error": "export processing timed out"}).Return(e.NewDBError(e.DbNotFound, "Data export not found.", "")).Times(1)
+	notificationMock.EXPECT().CreateMany([]m.Notification{{
+		UserId:  stale.UserId,
+		Type:    m.NotifyExportFailed,
+		EventId: "export." + stale.ID.String() + ".export_failed",
+		Data:    map[string]interface{}{"export_id": stale.ID.String()},
+	}}).Return(nil).Times(1)
+
+	worker := exportWorker{cfg: config.ExportCfg{ProcessingTimeout: time.Minute}, export: exportMock, notify: notificationMock, hub: NewNotificationHub(), logger: logger}
+
+	worker.failStale()
+}
+
+func TestBuildExportArchive(t *testing.T) {
+	readAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := &m.ExportData{
+		Profile:       m.ExportProfile{Id: "user", Username: "ivan"},
+		Favorites:     []string{"ai-1", "ai-2"},
+		Collections:   []m.ExportCollection{{Id: "collection", Name: "Text", AiIds: []string{"ai-2", "ai-1"}}},
+		Follows:       []m.ExportFollow{{DeveloperId: "developer"}},
+		Notifications: []m.ExportNotification{{Type: m.NotifyRatingReceived, Data: map[string]interface{}{"rate": 5}, ReadAt: &readAt}},
+	}
+
+	payload, err := buildExportArchive(data)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(payload), int64(len(payload)))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(archive.File))
+
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+
+	require.Equal(t, []string{"data.json", "profile.csv", "favorites.csv", "collections.csv", "follows.csv", "notifications.csv", "owned_ai.csv", "ratings.csv", "executions.csv", "audit_events.csv"}, names)
+
+	cases := []struct {
+		file     string
+		expected [][]string
+	}{
+		{"favorites.csv", [][]string{{"ai_id"}, {"ai-1"}, {"ai-2"}}},
+		{"collections.csv", [][]string{{"id", "name", "position", "is_public", "ai_ids", "created_at"}, {"collection", "Text", "0", "false", "ai-2 ai-1", "0001-01-01T00:00:00Z"}}},
+		{"follows.csv", [][]string{{"developer_id", "created_at"}, {"developer", "0001-01-01T00:00:00Z"}}},
+		{"notifications.csv", [][]string{{"type", "data", "read_at", "created_at"}, {"rating_received", `{"rate":5}`, "2024-01-02T03:04:05Z", "0001-01-01T00:00:00Z"}}},
+		{"ratings.csv", [][]string{{"ai_id", "rate", "verified_usage", "created_at"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			rows, err := csv.NewReader(bytes.NewReader(readArchiveFile(t, archive, tc.file))).ReadAll()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, rows)
+		})
+	}
+}
+
+func readArchiveFile(t *testing.T, archive *zip.Reader, name string) []byte {
+	file, err := archive.Open(name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	payload, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return payload
+}
@@ -21,6 +21,8 @@ type RemoveFavoriteRequest struct {
 
 type GetFavoritesRequest struct {
 	UserId string `json:"user_id"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
 }
 
 func AddFavorite(userId string, request *AddFavoriteRequest, favorites dataservice.FavoritesInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) *e.ErrorResponse {
@@ -44,15 +46,42 @@ func AddFavorite(userId string, request *AddFavoriteRequest, favorites dataservi
 	return nil
 }
 
-func GetFavorites(request *GetFavoritesRequest, favorites dataservice.FavoritesInterface, logger *logrus.Logger) (*[]m.UserFavorite, *e.ErrorResponse) {
-	userFavorites, err := favorites.GetUserFavorites(request.UserId)
+// Страница избранного с карточками ИИ, новые сверху
+func GetFavorites(request *GetFavoritesRequest, favorites dataservice.FavoritesInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) (*m.AiPage, *e.ErrorResponse) {
+	after, cursorErr := decodeCursor(request.Cursor)
+
+	if cursorErr != nil {
+		return nil, cursorErr
+	}
+
+	limit := pageLimit(request.Limit)
+	userFavorites, err := favorites.GetUserFavoritesPage(request.UserId, after, limit)
 
 	if err != nil {
-		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Add favorite")
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get favorites")
 		return nil, e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
 
-	return userFavorites, nil
+	ids := make([]string, 0, len(*userFavorites))
+
+	for _, favorite := range *userFavorites {
+		ids = append(ids, favorite.AiId.String())
+	}
+
+	ais, gwErr := hydrateAis(ids, ai)
+
+	if gwErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": gwErr.ErrorMessage}).Info("Get favorites")
+		return nil, gwErr
+	}
+
+	page := &m.AiPage{Items: ais}
+
+	if len(*userFavorites) == limit {
+		page.NextCursor = encodeCursor(m.Cursor{ID: (*userFavorites)[limit-1].ID})
+	}
+
+	return page, nil
 }
 
 func GetFavorite(userId string, aiId string, favorites dataservice.FavoritesInterface, logger *logrus.Logger) (*m.UserFavorite, *e.ErrorResponse) {
@@ -66,11 +95,17 @@ func GetFavorite(userId string, aiId string, favorites dataservice.FavoritesInte
 	return existFavorite, nil
 }
 
-func RemoveFavorite(userId string, request *RemoveFavoriteRequest, favorites dataservice.FavoritesInterface, logger *logrus.Logger) *e.ErrorResponse {
+// ИИ убирается и из всех коллекций пользователя
+func RemoveFavorite(userId string, request *RemoveFavoriteRequest, favorites dataservice.FavoritesInterface, collection dataservice.CollectionInterface, logger *logrus.Logger) *e.ErrorResponse {
 	if err := favorites.Delete(userId, request.AiId); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Get user by Id")
 		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
 	}
 
+	if err := collection.DeleteUserItems(userId, request.AiId); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Payload}).Info("Remove favorite")
+		return e.NewErrorResponseFromDBError(err.ErrorType, err.Message)
+	}
+
 	return nil
 }
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"time"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type FollowRequest struct {
+	DeveloperId string `json:"developer_id"`
+}
+
+// Подписаться можно только на разработчика: уведомления приходят о его новых ИИ и командах
+func Follow(userId string, request FollowRequest, user d.UserInterface, follow d.FollowInterface, logger *logrus.Logger) *e.ErrorResponse {
+	developerId, err := uuid.FromString(request.DeveloperId)
+
+	if err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid developer id.")
+	}
+
+	if developerId.String() == userId {
+		return e.NewErrorResponse(e.HttpBadRequest, "You cannot follow yourself.")
+	}
+
+	existUser, dbErr := user.GetOneBy(map[string]interface{}{"id": request.DeveloperId})
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return e.NewErrorResponse(e.HttpNotFound, "Developer not found.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Follow developer")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if !existUser.IsDeveloper {
+		return e.NewErrorResponse(e.HttpBadRequest, "Only developers can be followed.")
+	}
+
+	if dbErr := follow.Add(&m.UserFollow{FollowerId: uuid.FromStringOrNil(userId), DeveloperId: developerId}); dbErr != nil {
+		if dbErr.ErrorType == e.DbSystem {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Follow developer")
+		}
+
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+func Unfollow(userId string, request FollowRequest, follow d.FollowInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := uuid.FromString(request.DeveloperId); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid developer id.")
+	}
+
+	if dbErr := follow.Delete(userId, request.DeveloperId); dbErr != nil {
+		if dbErr.ErrorType == e.DbSystem {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Unfollow developer")
+		}
+
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func GetByEmail(email string, user d.UserInterface, logger *logrus.Logger) (*m.User, *e.ErrorResponse) {
-	existUser, dbErr := user.GetOneBy(map[string]interface{}{"email": email})
+	existUser, dbErr := user.GetOneByPreload(map[string]interface{}{"email": email}, "Roles")
 
 	if dbErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get user by Email")
@@ -21,7 +21,7 @@ func GetByEmail(email string, user d.UserInterface, logger *logrus.Logger) (*m.U
 }
 
 func GetById(userId string, user d.UserInterface, logger *logrus.Logger) (*m.User, *e.ErrorResponse) {
-	existUser, dbErr := user.GetOneBy(map[string]interface{}{"id": userId})
+	existUser, dbErr := user.GetOneByPreload(map[string]interface{}{"id": userId}, "Roles")
 
 	if dbErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get user by Id")
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"warehouseai/user/adapter"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Сколько уведомлений дайджест забирает за один запуск. Остальные уйдут в следующий.
+const digestBatchLimit = 1000
+
+type GetNotificationsRequest struct {
+	UserId     string `json:"user_id"`
+	Cursor     string `json:"cursor"`
+	Limit      int    `json:"limit"`
+	UnreadOnly bool   `json:"unread"`
+}
+
+type MarkNotificationsReadRequest struct {
+	Ids []uint `json:"ids"`
+	All bool   `json:"all"`
+}
+
+type NotificationSettingsRequest struct {
+	EmailDigest *bool `json:"email_digest"`
+}
+
+// Событие сервиса ИИ о новом ИИ или команде. Каждый подписчик разработчика получает уведомление.
+func HandleReleaseEvent(event m.AiReleaseEvent, follow d.FollowInterface, notification d.NotificationInterface, hub *NotificationHub, logger *logrus.Logger) *e.ErrorResponse {
+	_, developerErr := uuid.FromString(event.DeveloperId)
+	_, aiErr := uuid.FromString(event.AiId)
+
+	if developerErr != nil || aiErr != nil || event.EventId == "" {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid release event.")
+	}
+
+	if event.Kind != m.ReleaseAiCreated && event.Kind != m.ReleaseCommandCreated {
+		return e.NewErrorResponse(e.HttpBadRequest, "Unknown release kind.")
+	}
+
+	data := map[string]interface{}{"developer_id": event.DeveloperId, "ai_id": event.AiId, "ai_name": event.AiName}
+
+	if event.CommandName != "" {
+		data["command_name"] = event.CommandName
+	}
+
+	followerIds, dbErr := follow.GetFollowerIds(event.DeveloperId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Handle release event")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	notifications := make([]m.Notification, 0, len(followerIds))
+
+	for _, followerId := range followerIds {
+		notifications = append(notifications, m.Notification{UserId: followerId, Type: m.NotificationType(event.Kind), EventId: event.EventId, Data: data})
+	}
+
+	if dbErr := deliver(notifications, notification, hub); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Handle release event")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Событие сервиса ИИ о новой оценке. Уведомление получает владелец ИИ.
+func HandleRatingEvent(event m.AiRatingEvent, notification d.NotificationInterface, hub *NotificationHub, logger *logrus.Logger) *e.ErrorResponse {
+	ownerId, ownerErr := uuid.FromString(event.OwnerId)
+
+	if ownerErr != nil || event.EventId == "" || event.Rate < 1 || event.Rate > 5 {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid rating event.")
+	}
+
+	newNotification := m.Notification{
+		UserId:  ownerId,
+		Type:    m.NotifyRatingReceived,
+		EventId: event.EventId,
+		Data:    map[string]interface{}{"ai_id": event.AiId, "ai_name": event.AiName, "rate": event.Rate},
+	}
+
+	if dbErr := deliver([]m.Notification{newNotification}, notification, hub); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Handle rating event")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Оповещение auth о важном изменении аккаунта: смена пароля, почты, 2FA или выход на других устройствах
+func HandleSecurityAlert(event m.SecurityAlertEvent, notification d.NotificationInterface, hub *NotificationHub, logger *logrus.Logger) *e.ErrorResponse {
+	userId, userErr := uuid.FromString(event.UserId)
+
+	if userErr != nil || event.EventId == "" || event.Action == "" {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid security alert.")
+	}
+
+	newNotification := m.Notification{
+		UserId:  userId,
+		Type:    m.NotifySecurityAlert,
+		EventId: event.EventId,
+		Data:    map[string]interface{}{"action": event.Action, "ip": event.IP, "user_agent": event.UserAgent, "at": event.CreatedAt},
+	}
+
+	if dbErr := deliver([]m.Notification{newNotification}, notification, hub); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Handle security alert")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Уведомления пользователя, новые сверху, вместе со счётчиком непрочитанных
+func GetNotifications(request GetNotificationsRequest, notification d.NotificationInterface, logger *logrus.Logger) (*m.NotificationPage, *e.ErrorResponse) {
+	after, cursorErr := decodeCursor(request.Cursor)
+
+	if cursorErr != nil {
+		return nil, cursorErr
+	}
+
+	limit := pageLimit(request.Limit)
+	notifications, dbErr := notification.GetPage(request.UserId, after, limit, request.UnreadOnly)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get notifications")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	unread, dbErr := notification.CountUnread(request.UserId)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get notifications")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	page := &m.NotificationPage{Items: *notifications, UnreadCount: unread}
+
+	if len(*notifications) == limit {
+		page.NextCursor = encodeCursor(m.Cursor{ID: (*notifications)[limit-1].ID})
+	}
+
+	return page, nil
+}
+
+func MarkNotificationsRead(userId string, request MarkNotificationsReadRequest, notification d.NotificationInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if !request.All && len(request.Ids) == 0 {
+		return e.NewErrorResponse(e.HttpBadRequest, "Specify notifications to mark as read.")
+	}
+
+	ids := request.Ids
+
+	if request.All {
+		ids = nil
+	}
+
+	if dbErr := notification.MarkRead(userId, ids); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Mark notifications read")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+func UpdateNotificationSettings(userId string, request NotificationSettingsRequest, user d.UserInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if request.EmailDigest == nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Nothing to update.")
+	}
+
+	if dbErr := user.Update(userId, map[string]interface{}{"email_digest": *request.EmailDigest}); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update notification settings")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
+
+// Раз в DigestInterval отправляет пользователям с включённым дайджестом письмо с непрочитанными уведомлениями
+func StartDigestWorker(cfg config.NotificationCfg, user d.UserInterface, notification d.NotificationInterface, mail adapter.MailProducerInterface, logger *logrus.Logger, stop <-chan struct{}) {
+	if cfg.DigestInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(cfg.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sendDigests(user, notification, mail, logger)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func sendDigests(user d.UserInterface, notification d.NotificationInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) {
+	pending, dbErr := notification.GetDigestPending(digestBatchLimit)
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Notification digest")
+		return
+	}
+
+	// Уведомления отсортированы по пользователю, поэтому группы идут подряд
+	for start := 0; start < len(*pending); {
+		end := start
+
+		for end < len(*pending) && (*pending)[end].UserId == (*pending)[start].UserId {
+			end++
+		}
+
+		sendDigest((*pending)[start:end], user, notification, mail, logger)
+		start = end
+	}
+}
+
+// Если письмо не ушло, уведомления останутся в очереди дайджеста до следующего запуска
+func sendDigest(notifications []m.Notification, user d.UserInterface, notification d.NotificationInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) {
+	existUser, dbErr := user.GetOneBy(map[string]interface{}{"id": notifications[0].UserId.String()})
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Notification digest")
+		return
+	}
+
+	if err := mail.SendEmail(digestEmail(existUser, notifications)); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Notification digest")
+		return
+	}
+
+	ids := make([]uint, 0, len(notifications))
+
+	for _, item := range notifications {
+		ids = append(ids, item.ID)
+	}
+
+	if dbErr := notification.MarkEmailed(ids); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Notification digest")
+	}
+}
+
+func digestEmail(existUser *m.User, notifications []m.Notification) m.Email {
+	var releases strings.Builder
+
+	for _, item := range notifications {
+		switch item.Type {
+		case m.NotifyCommandCreated:
+			fmt.Fprintf(&releases, "      - новая команда «%v» у ИИ «%v»\n", item.Data["command_name"], item.Data["ai_name"])
+		default:
+			fmt.Fprintf(&releases, "      - новый ИИ «%v»\n", item.Data["ai_name"])
+		}
+	}
+
+	return m.Email{
+		To:      existUser.Email,
+		Subject: "Новое от разработчиков, на которых вы подписаны",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+
+      Разработчики, на которых вы подписаны, опубликовали:
+%s
+      Отключить эти письма можно в настройках уведомлений.
+
+      WarehouseAI Team
+      `, existUser.Firstname, releases.String()),
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	aMock "warehouseai/user/adapter/mocks"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleReleaseEvent(t *testing.T) {
+	developerId := uuid.Must(uuid.NewV4()).String()
+	aiId := uuid.Must(uuid.NewV4()).String()
+	followers := []uuid.UUID{uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())}
+
+	cases := []struct {
+		name    string
+		event   m.AiReleaseEvent
+		setup   func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface)
+		errCode int
+	}{
+		{
+			name:  "every follower gets the same event id",
+			event: m.AiReleaseEvent{EventId: "release-1", Kind: m.ReleaseCommandCreated, AiId: aiId, AiName: "Translator", DeveloperId: developerId, CommandName: "translate"},
+			setup: func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface) {
+				data := map[string]interface{}{"developer_id": developerId, "ai_id": aiId, "ai_name": "Translator", "command_name": "translate"}
+
+				followMock.EXPECT().GetFollowerIds(developerId).Return(followers, nil).Times(1)
+				// Повторная доставка события даст те же пары (user_id, event_id), дубли отсечёт база
+				notificationMock.EXPECT().CreateMany([]m.Notification{
+					{UserId: followers[0], Type: m.NotifyCommandCreated, EventId: "release-1", Data: data},
+					{UserId: followers[1], Type: m.NotifyCommandCreated, EventId: "release-1", Data: data},
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:  "no followers",
+			event: m.AiReleaseEvent{EventId: "release-2", Kind: m.ReleaseAiCreated, AiId: aiId, AiName: "Translator", DeveloperId: developerId},
+			setup: func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface) {
+				followMock.EXPECT().GetFollowerIds(developerId).Return([]uuid.UUID{}, nil).Times(1)
+				notificationMock.EXPECT().CreateMany([]m.Notification{}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:  "database unavailable",
+			event: m.AiReleaseEvent{EventId: "release-3", Kind: m.ReleaseAiCreated, AiId: aiId, DeveloperId: developerId},
+			setup: func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface) {
+				followMock.EXPECT().GetFollowerIds(developerId).Return(followers, nil).Times(1)
+				notificationMock.EXPECT().CreateMany(gomock.Any()).Return(e.NewDBError(e.DbSystem, "Something went wrong.", "connection refused")).Times(1)
+			},
+			errCode: e.HttpInternalError,
+		},
+		{
+			name:    "missing event id",
+			event:   m.AiReleaseEvent{Kind: m.ReleaseAiCreated, AiId: aiId, DeveloperId: developerId},
+			setup:   func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "unknown kind",
+			event:   m.AiReleaseEvent{EventId: "release-4", Kind: "ai_deleted", AiId: aiId, DeveloperId: developerId},
+			setup:   func(followMock *dMock.MockFollowInterface, notificationMock *dMock.MockNotificationInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			followMock := dMock.NewMockFollowInterface(ctl)
+			notificationMock := dMock.NewMockNotificationInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(followMock, notificationMock)
+
+			err := HandleReleaseEvent(tc.event, followMock, notificationMock, NewNotificationHub(), logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	first := &m.User{ID: uuid.Must(uuid.NewV4()), Firstname: "Ivan", Email: "ivan@example.com"}
+	second := &m.User{ID: uuid.Must(uuid.NewV4()), Firstname: "Anna", Email: "anna@example.com"}
+
+	pending := []m.Notification{
+		{ID: 1, UserId: first.ID, Type: m.NotifyAiCreated, Data: map[string]interface{}{"ai_name": "Translator"}},
+		{ID: 4, UserId: first.ID, Type: m.NotifyCommandCreated, Data: map[string]interface{}{"ai_name": "Translator", "command_name": "translate"}},
+		{ID: 2, UserId: second.ID, Type: m.NotifyAiCreated, Data: map[string]interface{}{"ai_name": "Painter"}},
+	}
+
+	cases := []struct {
+		name  string
+		setup func(userMock *dMock.MockUserInterface, notificationMock *dMock.MockNotificationInterface, mailMock *aMock.MockMailProducerInterface)
+	}{
+		{
+			name: "one email per user",
+			setup: func(userMock *dMock.MockUserInterface, notificationMock *dMock.MockNotificationInterface, mailMock *aMock.MockMailProducerInterface) {
+				notificationMock.EXPECT().GetDigestPending(digestBatchLimit).Return(&pending, nil).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": first.ID.String()}).Return(first, nil).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": second.ID.String()}).Return(second, nil).Times(1)
+				mailMock.EXPECT().SendEmail(digestEmail(first, pending[:2])).Return(nil).Times(1)
+				mailMock.EXPECT().SendEmail(digestEmail(second, pending[2:])).Return(nil).Times(1)
+				notificationMock.EXPECT().MarkEmailed([]uint{1, 4}).Return(nil).Times(1)
+				notificationMock.EXPECT().MarkEmailed([]uint{2}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "failed email stays pending",
+			setup: func(userMock *dMock.MockUserInterface, notificationMock *dMock.MockNotificationInterface, mailMock *aMock.MockMailProducerInterface) {
+				notificationMock.EXPECT().GetDigestPending(digestBatchLimit).Return(&pending, nil).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": first.ID.String()}).Return(first, nil).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": second.ID.String()}).Return(second, nil).Times(1)
+				mailMock.EXPECT().SendEmail(digestEmail(first, pending[:2])).Return(errors.New("broker unavailable")).Times(1)
+				mailMock.EXPECT().SendEmail(digestEmail(second, pending[2:])).Return(nil).Times(1)
+				notificationMock.EXPECT().MarkEmailed([]uint{2}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "deleted user is skipped",
+			setup: func(userMock *dMock.MockUserInterface, notificationMock *dMock.MockNotificationInterface, mailMock *aMock.MockMailProducerInterface) {
+				notificationMock.EXPECT().GetDigestPending(digestBatchLimit).Return(&pending, nil).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": first.ID.String()}).Return(nil, e.NewDBError(e.DbNotFound, "User not found.", "record not found")).Times(1)
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": second.ID.String()}).Return(second, nil).Times(1)
+				mailMock.EXPECT().SendEmail(digestEmail(second, pending[2:])).Return(nil).Times(1)
+				notificationMock.EXPECT().MarkEmailed([]uint{2}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "nothing pending",
+			setup: func(userMock *dMock.MockUserInterface, notificationMock *dMock.MockNotificationInterface, mailMock *aMock.MockMailProducerInterface) {
+				notificationMock.EXPECT().GetDigestPending(digestBatchLimit).Return(&[]m.Notification{}, nil).Times(1)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			userMock := dMock.NewMockUserInterface(ctl)
+			notificationMock := dMock.NewMockNotificationInterface(ctl)
+			mailMock := aMock.NewMockMailProducerInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(userMock, notificationMock, mailMock)
+
+			sendDigests(userMock, notificationMock, mailMock, logger)
+		})
+	}
+}
+
+func TestDigestEmail(t *testing.T) {
+	existUser := &m.User{Firstname: "Ivan", Email: "ivan@example.com"}
+
+	email := digestEmail(existUser, []m.Notification{
+		{Type: m.NotifyAiCreated, Data: map[string]interface{}{"ai_name": "Translator"}},
+		{Type: m.NotifyCommandCreated, Data: map[string]interface{}{"ai_name": "Translator", "command_name": "translate"}},
+	})
+
+	require.Equal(t, "ivan@example.com", email.To)
+	require.Contains(t, email.Message, "Здравствуйте, Ivan!")
+	require.Contains(t, email.Message, "- новый ИИ «Translator»\n")
+	require.Contains(t, email.Message, "- новая команда «translate» у ИИ «Translator»\n")
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"warehouseai/user/adapter"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+
+	return limit
+}
+
+// Курсор непрозрачен для клиента: base64 от "позиция.id"
+func encodeCursor(cursor m.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", cursor.Position, cursor.ID)))
+}
+
+func decodeCursor(value string) (*m.Cursor, *e.ErrorResponse) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid cursor.")
+	}
+
+	var cursor m.Cursor
+
+	if _, err := fmt.Sscanf(string(raw), "%d.%d", &cursor.Position, &cursor.ID); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid cursor.")
+	}
+
+	return &cursor, nil
+}
+
+// Карточки ИИ одним запросом в сервис ИИ в порядке ids. Снятые с публикации ИИ пропускаются.
+func hydrateAis(ids []string, ai adapter.AiGrpcInterface) ([]m.PublicAi, *e.ErrorResponse) {
+	if len(ids) == 0 {
+		return []m.PublicAi{}, nil
+	}
+
+	ais, err := ai.GetPublishedAis(ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]m.PublicAi, len(ais))
+
+	for _, existAi := range ais {
+		byId[existAi.Id] = existAi
+	}
+
+	hydrated := make([]m.PublicAi, 0, len(ids))
+
+	for _, id := range ids {
+		if existAi, ok := byId[id]; ok {
+			hydrated = append(hydrated, existAi)
+		}
+	}
+
+	return hydrated, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"time"
+	"warehouseai/user/adapter"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+func GetPublicUser(userId string, user d.UserInterface, logger *logrus.Logger) (*m.PublicUser, *e.ErrorResponse) {
+	if _, err := uuid.FromString(userId); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid user id.")
+	}
+
+	existUser, dbErr := user.GetOneBy(map[string]interface{}{"id": userId})
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "User not found.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get public user")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	publicUser := m.NewPublicUser(existUser)
+
+	return &publicUser, nil
+}
+
+// Профиль разработчика с его опубликованными ИИ
+func GetPublicProfile(username string, user d.UserInterface, owned d.OwnedInterface, follow d.FollowInterface, ai adapter.AiGrpcInterface, logger *logrus.Logger) (*m.PublicProfile, *e.ErrorResponse) {
+	if username == "" {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Username is required.")
+	}
+
+	existUser, dbErr := user.GetOneBy(map[string]interface{}{"username": username})
+
+	if dbErr != nil {
+		if dbErr.ErrorType == e.DbNotFound {
+			return nil, e.NewErrorResponse(e.HttpNotFound, "User not found.")
+		}
+
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get public profile")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	followers, dbErr := follow.CountFollowers(existUser.ID.String())
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get public profile")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	profile := &m.PublicProfile{PublicUser: m.NewPublicUser(existUser), Followers: followers, Ais: []m.PublicAi{}}
+
+	existOwned, dbErr := owned.GetUserOwned(existUser.ID.String())
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Get public profile")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if len(*existOwned) == 0 {
+		return profile, nil
+	}
+
+	ids := make([]string, 0, len(*existOwned))
+
+	for _, own := range *existOwned {
+		ids = append(ids, own.AiId.String())
+	}
+
+	ais, err := ai.GetPublishedAis(ids)
+
+	if err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("Get public profile")
+		return nil, err
+	}
+
+	profile.Ais = ais
+
+	return profile, nil
+}
+
+// Событие сервиса ИИ о создании или удалении ИИ. Повторная доставка ничего не меняет.
+func HandleOwnershipEvent(event m.AiOwnershipEvent, owned d.OwnedInterface, logger *logrus.Logger) *e.ErrorResponse {
+	aiId, aiErr := uuid.FromString(event.AiId)
+	userId, userErr := uuid.FromString(event.UserId)
+
+	if aiErr != nil || userErr != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid ownership event.")
+	}
+
+	var dbErr *e.DBError
+
+	switch event.Action {
+	case m.AiCreated:
+		dbErr = owned.Add(&m.UserOwn{AiId: aiId, UserId: userId})
+
+		// Уже добавлен или пользователь удалён - синхронизировать нечего
+		if dbErr != nil && (dbErr.ErrorType == e.DbExist || dbErr.ErrorType == e.DbNotFound) {
+			dbErr = nil
+		}
+
+	case m.AiDeleted:
+		dbErr = owned.Delete(event.UserId, event.AiId)
+
+	default:
+		return e.NewErrorResponse(e.HttpBadRequest, "Unknown ownership action.")
+	}
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Handle ownership event")
+		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"testing"
+	aMock "warehouseai/user/adapter/mocks"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetPublicUser(t *testing.T) {
+	existUser := &m.User{ID: uuid.Must(uuid.NewV4()), Username: "ivan", Email: "ivan@example.com", IsDeveloper: true}
+
+	cases := []struct {
+		name     string
+		userId   string
+		setup    func(userMock *dMock.MockUserInterface)
+		expected *m.PublicUser
+		errCode  int
+	}{
+		{
+			name:   "public fields only",
+			userId: existUser.ID.String(),
+			setup: func(userMock *dMock.MockUserInterface) {
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": existUser.ID.String()}).Return(existUser, nil).Times(1)
+			},
+			expected: &m.PublicUser{ID: existUser.ID, Username: "ivan", IsDeveloper: true},
+		},
+		{
+			name:   "unknown user",
+			userId: existUser.ID.String(),
+			setup: func(userMock *dMock.MockUserInterface) {
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"id": existUser.ID.String()}).Return(nil, e.NewDBError(e.DbNotFound, "User not found.", "record not found")).Times(1)
+			},
+			errCode: e.HttpNotFound,
+		},
+		{
+			name:    "invalid id",
+			userId:  "ivan",
+			setup:   func(userMock *dMock.MockUserInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "empty id",
+			setup:   func(userMock *dMock.MockUserInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			userMock := dMock.NewMockUserInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(userMock)
+
+			publicUser, err := GetPublicUser(tc.userId, userMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, publicUser)
+		})
+	}
+}
+
+func TestGetPublicProfile(t *testing.T) {
+	developer := &m.User{ID: uuid.Must(uuid.NewV4()), Username: "ivan", IsDeveloper: true}
+	aiId := uuid.Must(uuid.NewV4())
+	published := []m.PublicAi{{Id: aiId.String(), Name: "Translator"}}
+
+	cases := []struct {
+		name     string
+		username string
+		setup    func(userMock *dMock.MockUserInterface, ownedMock *dMock.MockOwnedInterface, followMock *dMock.MockFollowInterface, aiMock *aMock.MockAiGrpcInterface)
+		expected *m.PublicProfile
+		errCode  int
+	}{
+		{
+			name:     "developer with published AI",
+			username: "ivan",
+			setup: func(userMock *dMock.MockUserInterface, ownedMock *dMock.MockOwnedInterface, followMock *dMock.MockFollowInterface, aiMock *aMock.MockAiGrpcInterface) {
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"username": "ivan"}).Return(developer, nil).Times(1)
+				followMock.EXPECT().CountFollowers(developer.ID.String()).Return(int64(3), nil).Times(1)
+				ownedMock.EXPECT().GetUserOwned(developer.ID.String()).Return(&[]m.UserOwn{{AiId: aiId, UserId: developer.ID}}, nil).Times(1)
+				aiMock.EXPECT().GetPublishedAis([]string{aiId.String()}).Return(published, nil).Times(1)
+			},
+			expected: &m.PublicProfile{PublicUser: m.NewPublicUser(developer), Followers: 3, Ais: published},
+		},
+		{
+			name:     "user without AI",
+			username: "ivan",
+			setup: func(userMock *dMock.MockUserInterface, ownedMock *dMock.MockOwnedInterface, followMock *dMock.MockFollowInterface, aiMock *aMock.MockAiGrpcInterface) {
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"username": "ivan"}).Return(developer, nil).Times(1)
+				followMock.EXPECT().CountFollowers(developer.ID.String()).Return(int64(0), nil).Times(1)
+				ownedMock.EXPECT().GetUserOwned(developer.ID.String()).Return(&[]m.UserOwn{}, nil).Times(1)
+			},
+			expected: &m.PublicProfile{PublicUser: m.NewPublicUser(developer), Ais: []m.PublicAi{}},
+		},
+		{
+			name:     "unknown username",
+			username: "anna",
+			setup: func(userMock *dMock.MockUserInterface, ownedMock *dMock.MockOwnedInterface, followMock *dMock.MockFollowInterface, aiMock *aMock.MockAiGrpcInterface) {
+				userMock.EXPECT().GetOneBy(map[string]interface{}{"username": "anna"}).Return(nil, e.NewDBError(e.DbNotFound, "User not found.", "record not found")).Times(1)
+			},
+			errCode: e.HttpNotFound,
+		},
+		{
+			name: "empty username",
+			setup: func(userMock *dMock.MockUserInterface, ownedMock *dMock.MockOwnedInterface, followMock *dMock.MockFollowInterface, aiMock *aMock.MockAiGrpcInterface) {
+			},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			userMock := dMock.NewMockUserInterface(ctl)
+			ownedMock := dMock.NewMockOwnedInterface(ctl)
+			followMock := dMock.NewMockFollowInterface(ctl)
+			aiMock := aMock.NewMockAiGrpcInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(userMock, ownedMock, followMock, aiMock)
+
+			profile, err := GetPublicProfile(tc.username, userMock, ownedMock, followMock, aiMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, profile)
+		})
+	}
+}
+
+func TestHandleOwnershipEvent(t *testing.T) {
+	aiId := uuid.Must(uuid.NewV4())
+	userId := uuid.Must(uuid.NewV4())
+
+	cases := []struct {
+		name    string
+		event   m.AiOwnershipEvent
+		setup   func(ownedMock *dMock.MockOwnedInterface)
+		errCode int
+	}{
+		{
+			name:  "created",
+			event: m.AiOwnershipEvent{AiId: aiId.String(), UserId: userId.String(), Action: m.AiCreated},
+			setup: func(ownedMock *dMock.MockOwnedInterface) {
+				ownedMock.EXPECT().Add(&m.UserOwn{AiId: aiId, UserId: userId}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:  "redelivered created",
+			event: m.AiOwnershipEvent{AiId: aiId.String(), UserId: userId.String(), Action: m.AiCreated},
+			setup: func(ownedMock *dMock.MockOwnedInterface) {
+				ownedMock.EXPECT().Add(&m.UserOwn{AiId: aiId, UserId: userId}).Return(e.NewDBError(e.DbExist, "AI is already owned.", "duplicate key")).Times(1)
+			},
+		},
+		{
+			name:  "deleted",
+			event: m.AiOwnershipEvent{AiId: aiId.String(), UserId: userId.String(), Action: m.AiDeleted},
+			setup: func(ownedMock *dMock.MockOwnedInterface) {
+				ownedMock.EXPECT().Delete(userId.String(), aiId.String()).Return(nil).Times(1)
+			},
+		},
+		{
+			name:  "database unavailable",
+			event: m.AiOwnershipEvent{AiId: aiId.String(), UserId: userId.String(), Action: m.AiCreated},
+			setup: func(ownedMock *dMock.MockOwnedInterface) {
+				ownedMock.EXPECT().Add(&m.UserOwn{AiId: aiId, UserId: userId}).Return(e.NewDBError(e.DbSystem, "Something went wrong.", "connection refused")).Times(1)
+			},
+			errCode: e.HttpInternalError,
+		},
+		{
+			name:    "invalid ai id",
+			event:   m.AiOwnershipEvent{AiId: "ai", UserId: userId.String(), Action: m.AiCreated},
+			setup:   func(ownedMock *dMock.MockOwnedInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:    "unknown action",
+			event:   m.AiOwnershipEvent{AiId: aiId.String(), UserId: userId.String(), Action: "updated"},
+			setup:   func(ownedMock *dMock.MockOwnedInterface) {},
+			errCode: e.HttpBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			ownedMock := dMock.NewMockOwnedInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(ownedMock)
+
+			err := HandleOwnershipEvent(tc.event, ownedMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"time"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleRequest struct {
+	UserId string     `json:"user_id"`
+	Role   m.UserRole `json:"role"`
+}
+
+type UserRolesResponse struct {
+	UserId      string   `json:"user_id"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+func validateRoleRequest(request RoleRequest) *e.ErrorResponse {
+	if _, err := uuid.FromString(request.UserId); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid user id.")
+	}
+
+	if !m.IsKnownRole(request.Role) {
+		return e.NewErrorResponse(e.HttpBadRequest, "Unknown role.")
+	}
+
+	if request.Role == m.RoleUser {
+		return e.NewErrorResponse(e.HttpBadRequest, "Role USER belongs to every user and can't be changed.")
+	}
+
+	return nil
+}
+
+func GetUserRoles(userId string, user d.UserInterface, logger *logrus.Logger) (*UserRolesResponse, *e.ErrorResponse) {
+	if _, err := uuid.FromString(userId); err != nil {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid user id.")
+	}
+
+	existUser, err := GetById(userId, user, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	roles := existUser.RoleNames()
+
+	return &UserRolesResponse{UserId: userId, Roles: roles, Permissions: m.Permissions(roles)}, nil
+}
+
+// Выдача уже выданной роли не ошибка
+func GrantRole(request RoleRequest, adminId string, user d.UserInterface, role d.RoleInterface, logger *logrus.Logger) (*UserRolesResponse, *e.ErrorResponse) {
+	if err := validateRoleRequest(request); err != nil {
+		return nil, err
+	}
+
+	if _, err := GetById(request.UserId, user, logger); err != nil {
+		return nil, err
+	}
+
+	if request.Role == m.RoleDeveloper {
+		if dbErr := user.Update(request.UserId, map[string]interface{}{"is_developer": true}); dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Grant role")
+			return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+		}
+
+		return GetUserRoles(request.UserId, user, logger)
+	}
+
+	grant := m.RoleGrant{
+		UserId:    uuid.FromStringOrNil(request.UserId),
+		Role:      request.Role,
+		GrantedBy: uuid.FromStringOrNil(adminId),
+	}
+
+	if dbErr := role.Grant(&grant); dbErr != nil && dbErr.ErrorType != e.DbExist {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Grant role")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return GetUserRoles(request.UserId, user, logger)
+}
+
+// Свою роль администратора снять нельзя, чтобы не остаться без администраторов
+func RevokeRole(request RoleRequest, adminId string, user d.UserInterface, role d.RoleInterface, logger *logrus.Logger) (*UserRolesResponse, *e.ErrorResponse) {
+	if err := validateRoleRequest(request); err != nil {
+		return nil, err
+	}
+
+	if request.Role == m.RoleAdmin && request.UserId == adminId {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, "You can't revoke your own admin role.")
+	}
+
+	if _, err := GetById(request.UserId, user, logger); err != nil {
+		return nil, err
+	}
+
+	var dbErr *e.DBError
+
+	if request.Role == m.RoleDeveloper {
+		dbErr = user.Update(request.UserId, map[string]interface{}{"is_developer": false})
+	} else {
+		dbErr = role.Revoke(request.UserId, request.Role)
+	}
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Revoke role")
+		return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	return GetUserRoles(request.UserId, user, logger)
+}
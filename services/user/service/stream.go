@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"warehouseai/user/adapter"
+	"warehouseai/user/config"
+	d "warehouseai/user/dataservice"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Вкладок одного пользователя больше этого числа не бывает, остальное - утечка соединений
+	maxStreamsPerUser = 10
+	streamReplayLimit = 100
+	streamHeartbeat   = 25 * time.Second
+)
+
+// Сигналы открытым потокам уведомлений. Сами уведомления поток читает из базы после своего последнего id,
+// поэтому сигналы можно склеивать. Хаб живёт в памяти: уведомления, созданные другим экземпляром сервиса,
+// поток заберёт на ближайшем heartbeat.
+type NotificationHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func NewNotificationHub() *NotificationHub {
+	return &NotificationHub{subscribers: map[string]map[chan struct{}]struct{}{}}
+}
+
+func (h *NotificationHub) subscribe(userId string) (chan struct{}, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscribers[userId]) >= maxStreamsPerUser {
+		return nil, false
+	}
+
+	if h.subscribers[userId] == nil {
+		h.subscribers[userId] = map[chan struct{}]struct{}{}
+	}
+
+	signal := make(chan struct{}, 1)
+	h.subscribers[userId][signal] = struct{}{}
+
+	return signal, true
+}
+
+func (h *NotificationHub) unsubscribe(userId string, signal chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers[userId], signal)
+
+	if len(h.subscribers[userId]) == 0 {
+		delete(h.subscribers, userId)
+	}
+}
+
+// Будит все открытые вкладки пользователей. Не блокируется: если сигнал уже ждёт, новый не нужен.
+func (h *NotificationHub) Signal(userIds ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userId := range userIds {
+		for signal := range h.subscribers[userId] {
+			select {
+			case signal <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Поток уведомлений одной вкладки в формате Server-Sent Events.
+// Живёт не дольше сессии (или access-токена, если клиент пришёл без сессии), сессия перепроверяется на каждом heartbeat.
+type NotificationStream struct {
+	userId       string
+	sessionId    string
+	expiresAt    time.Time
+	lastId       uint
+	signal       chan struct{}
+	heartbeat    time.Duration
+	hub          *NotificationHub
+	auth         adapter.AuthGrpcInterface
+	notification d.NotificationInterface
+	logger       *logrus.Logger
+}
+
+// Открывает поток с места lastEventId (заголовок Last-Event-ID при переподключении).
+// Без него поток начинается с новых уведомлений - прошлые отдаёт GET /user/notifications.
+// tokenExpiresAt - срок access-токена, которым прошёл запрос, используется, если сессии нет.
+func OpenNotificationStream(userId string, sessionId string, tokenExpiresAt time.Time, lastEventId string, cfg config.NotificationCfg, hub *NotificationHub, auth adapter.AuthGrpcInterface, notification d.NotificationInterface, logger *logrus.Logger) (*NotificationStream, *e.ErrorResponse) {
+	stream := &NotificationStream{
+		userId:       userId,
+		sessionId:    sessionId,
+		expiresAt:    tokenExpiresAt,
+		heartbeat:    cfg.StreamHeartbeat,
+		hub:          hub,
+		auth:         auth,
+		notification: notification,
+		logger:       logger,
+	}
+
+	if stream.heartbeat <= 0 {
+		stream.heartbeat = streamHeartbeat
+	}
+
+	if !stream.alive() {
+		return nil, e.NewErrorResponse(e.HttpUnauthorized, "Session expired.")
+	}
+
+	var lastId uint
+
+	if lastEventId != "" {
+		parsed, err := strconv.ParseUint(lastEventId, 10, 64)
+
+		if err != nil {
+			return nil, e.NewErrorResponse(e.HttpBadRequest, "Invalid last event id.")
+		}
+
+		lastId = uint(parsed)
+	} else {
+		existLastId, dbErr := notification.GetLastId(userId)
+
+		if dbErr != nil {
+			logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Open notification stream")
+			return nil, e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+		}
+
+		lastId = existLastId
+	}
+
+	signal, ok := hub.subscribe(userId)
+
+	if !ok {
+		return nil, e.NewErrorResponse(e.HttpTooManyRequests, "Too many open notification streams.")
+	}
+
+	stream.lastId = lastId
+	stream.signal = signal
+
+	return stream, nil
+}
+
+// Пишет уведомления, пока клиент не отключится. Ошибка записи - единственный признак отключения.
+func (s *NotificationStream) Serve(w *bufio.Writer) {
+	defer s.hub.unsubscribe(s.userId, s.signal)
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+
+	fmt.Fprintf(w, "retry: %d\n\n", (3 * time.Second).Milliseconds())
+
+	for {
+		if !s.sendPending(w) {
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-s.signal:
+		case <-ticker.C:
+			// После выхода или отзыва сессии поток закрывается, переподключиться без сессии браузер не сможет
+			if !s.alive() {
+				return
+			}
+
+			fmt.Fprint(w, ": ping\n\n")
+		}
+	}
+}
+
+// Сессия ещё действует и принадлежит тому же пользователю. Ротацию ID поток переживает: дальше проверяется новый ID.
+func (s *NotificationStream) alive() bool {
+	if s.sessionId != "" {
+		state, err := s.auth.CheckSession(s.sessionId)
+
+		if err != nil {
+			if err.ErrorCode != e.HttpUnauthorized {
+				s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("Notification stream")
+			}
+
+			return false
+		}
+
+		if state.UserId != s.userId {
+			return false
+		}
+
+		s.sessionId = state.SessionId
+		s.expiresAt = state.ExpiresAt
+	}
+
+	return time.Now().Before(s.expiresAt)
+}
+
+func (s *NotificationStream) sendPending(w *bufio.Writer) bool {
+	for {
+		notifications, dbErr := s.notification.GetAfter(s.userId, s.lastId, streamReplayLimit)
+
+		if dbErr != nil {
+			s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Notification stream")
+			return false
+		}
+
+		for _, item := range *notifications {
+			data, err := json.Marshal(item)
+
+			if err != nil {
+				s.logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Notification stream")
+				return false
+			}
+
+			fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", item.ID, data)
+			s.lastId = item.ID
+		}
+
+		if len(*notifications) < streamReplayLimit {
+			return true
+		}
+	}
+}
+
+// Сохраняет уведомления и будит открытые потоки получателей
+func deliver(notifications []m.Notification, notification d.NotificationInterface, hub *NotificationHub) *e.DBError {
+	if err := notification.CreateMany(notifications); err != nil {
+		return err
+	}
+
+	userIds := make([]string, 0, len(notifications))
+
+	for _, item := range notifications {
+		userIds = append(userIds, item.UserId.String())
+	}
+
+	hub.Signal(userIds...)
+
+	return nil
+}
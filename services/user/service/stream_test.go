@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	aMock "warehouseai/user/adapter/mocks"
+	"warehouseai/user/config"
+	dMock "warehouseai/user/dataservice/mocks"
+	e "warehouseai/user/errors"
+	m "warehouseai/user/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOpenNotificationStream(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	sessionExpiresAt := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name           string
+		sessionId      string
+		tokenExpiresAt time.Time
+		lastEventId    string
+		streams        int
+		setup          func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface)
+		expectedLastId uint
+		expectedExpiry time.Time
+		errCode        int
+	}{
+		{
+			name:        "replay after last event id",
+			sessionId:   "session",
+			lastEventId: "42",
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: userId, SessionId: "session", ExpiresAt: sessionExpiresAt}, nil).Times(1)
+			},
+			expectedLastId: 42,
+			expectedExpiry: sessionExpiresAt,
+		},
+		{
+			name:      "new notifications only",
+			sessionId: "session",
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: userId, SessionId: "session", ExpiresAt: sessionExpiresAt}, nil).Times(1)
+				notificationMock.EXPECT().GetLastId(userId).Return(uint(17), nil).Times(1)
+			},
+			expectedLastId: 17,
+			expectedExpiry: sessionExpiresAt,
+		},
+		{
+			name:           "access token without session",
+			tokenExpiresAt: sessionExpiresAt,
+			lastEventId:    "3",
+			setup:          func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {},
+			expectedLastId: 3,
+			expectedExpiry: sessionExpiresAt,
+		},
+		{
+			name:           "expired access token",
+			tokenExpiresAt: time.Now().Add(-time.Minute),
+			setup:          func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {},
+			errCode:        e.HttpUnauthorized,
+		},
+		{
+			name:      "expired session",
+			sessionId: "session",
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(nil, e.NewErrorResponse(e.HttpUnauthorized, "Session expired.")).Times(1)
+			},
+			errCode: e.HttpUnauthorized,
+		},
+		{
+			name:      "session of another user",
+			sessionId: "session",
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: uuid.Must(uuid.NewV4()).String(), SessionId: "session", ExpiresAt: sessionExpiresAt}, nil).Times(1)
+			},
+			errCode: e.HttpUnauthorized,
+		},
+		{
+			name:        "invalid last event id",
+			sessionId:   "session",
+			lastEventId: "last",
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: userId, SessionId: "session", ExpiresAt: sessionExpiresAt}, nil).Times(1)
+			},
+			errCode: e.HttpBadRequest,
+		},
+		{
+			name:        "too many streams",
+			sessionId:   "session",
+			lastEventId: "1",
+			streams:     maxStreamsPerUser,
+			setup: func(authMock *aMock.MockAuthGrpcInterface, notificationMock *dMock.MockNotificationInterface) {
+				authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: userId, SessionId: "session", ExpiresAt: sessionExpiresAt}, nil).Times(1)
+			},
+			errCode: e.HttpTooManyRequests,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			authMock := aMock.NewMockAuthGrpcInterface(ctl)
+			notificationMock := dMock.NewMockNotificationInterface(ctl)
+			logger := logrus.New()
+			hub := NewNotificationHub()
+
+			for i := 0; i < tc.streams; i++ {
+				hub.subscribe(userId)
+			}
+
+			tc.setup(authMock, notificationMock)
+
+			stream, err := OpenNotificationStream(userId, tc.sessionId, tc.tokenExpiresAt, tc.lastEventId, config.NotificationCfg{}, hub, authMock, notificationMock, logger)
+
+			if tc.errCode != 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tc.errCode, err.ErrorCode)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedLastId, stream.lastId)
+			require.Equal(t, tc.expectedExpiry, stream.expiresAt)
+			require.Equal(t, streamHeartbeat, stream.heartbeat)
+			require.Len(t, hub.subscribers[userId], 1)
+		})
+	}
+}
+
+func TestNotificationStreamAlive(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+	rotatedExpiresAt := time.Now().Add(time.Hour)
+
+	ctl := gomock.NewController(t)
+
+	authMock := aMock.NewMockAuthGrpcInterface(ctl)
+	stream := &NotificationStream{userId: userId, sessionId: "session", expiresAt: time.Now().Add(time.Minute), auth: authMock, logger: logrus.New()}
+
+	// ID сессии сменился после ротации - следующая проверка идёт по новому
+	authMock.EXPECT().CheckSession("session").Return(&m.SessionState{UserId: userId, SessionId: "rotated", ExpiresAt: rotatedExpiresAt}, nil).Times(1)
+	authMock.EXPECT().CheckSession("rotated").Return(nil, e.NewErrorResponse(e.HttpUnauthorized, "Session expired.")).Times(1)
+
+	require.True(t, stream.alive())
+	require.Equal(t, "rotated", stream.sessionId)
+	require.Equal(t, rotatedExpiresAt, stream.expiresAt)
+
+	require.False(t, stream.alive())
+}
+
+func TestNotificationStreamSendPending(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4()).String()
+
+	notificationsFrom := func(from uint, count int) *[]m.Notification {
+		notifications := make([]m.Notification, 0, count)
+
+		for i := 0; i < count; i++ {
+			notifications = append(notifications, m.Notification{ID: from + uint(i), Type: m.NotifyRatingReceived, Data: map[string]interface{}{"rate": 5}})
+		}
+
+		return &notifications
+	}
+
+	cases := []struct {
+		name           string
+		lastId         uint
+		setup          func(notificationMock *dMock.MockNotificationInterface)
+		expectedLastId uint
+		expectedEvents int
+		expectedOk     bool
+	}{
+		{
+			name:   "replay over several pages",
+			lastId: 10,
+			setup: func(notificationMock *dMock.MockNotificationInterface) {
+				notificationMock.EXPECT().GetAfter(userId, uint(10), streamReplayLimit).Return(notificationsFrom(11, streamReplayLimit), nil).Times(1)
+				notificationMock.EXPECT().GetAfter(userId, uint(110), streamReplayLimit).Return(notificationsFrom(111, 2), nil).Times(1)
+			},
+			expectedLastId: 112,
+			expectedEvents: streamReplayLimit + 2,
+			expectedOk:     true,
+		},
+		{
+			name:   "full last page",
+			lastId: 0,
+			setup: func(notificationMock *dMock.MockNotificationInterface) {
+				notificationMock.EXPECT().GetAfter(userId, uint(0), streamReplayLimit).Return(notificationsFrom(1, streamReplayLimit), nil).Times(1)
+				notificationMock.EXPECT().GetAfter(userId, uint(streamReplayLimit), streamReplayLimit).Return(notificationsFrom(0, 0), nil).Times(1)
+			},
+			expectedLastId: streamReplayLimit,
+			expectedEvents: streamReplayLimit,
+			expectedOk:     true,
+		},
+		{
+			name:   "database unavailable",
+			lastId: 5,
+			setup: func(notificationMock *dMock.MockNotificationInterface) {
+				notificationMock.EXPECT().GetAfter(userId, uint(5), streamReplayLimit).Return(nil, e.NewDBError(e.DbSystem, "Something went wrong.", "connection refused")).Times(1)
+			},
+			expectedLastId: 5,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+
+			notificationMock := dMock.NewMockNotificationInterface(ctl)
+			logger := logrus.New()
+
+			tc.setup(notificationMock)
+
+			var buffer bytes.Buffer
+			w := bufio.NewWriter(&buffer)
+			stream := &NotificationStream{userId: userId, lastId: tc.lastId, notification: notificationMock, logger: logger}
+
+			ok := stream.sendPending(w)
+			require.NoError(t, w.Flush())
+
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedLastId, stream.lastId)
+			require.Equal(t, tc.expectedEvents, strings.Count(buffer.String(), "event: notification\n"))
+
+			if tc.expectedEvents != 0 {
+				require.True(t, strings.HasPrefix(buffer.String(), fmt.Sprintf("id: %d\nevent: notification\ndata: {\"id\":%d,", tc.lastId+1, tc.lastId+1)))
+			}
+		})
+	}
+}
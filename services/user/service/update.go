@@ -2,10 +2,15 @@ package service
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	netmail "net/mail"
 	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"warehouseai/user/adapter"
 	d "warehouseai/user/dataservice"
 	e "warehouseai/user/errors"
@@ -16,6 +21,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const maxBioLength = 500
+
 type UpdateVerificationRequest struct {
 	Verified bool `json:"verified"`
 }
@@ -24,6 +31,7 @@ type UpdatePersonalDataRequest struct {
 	Firstname string `json:"firstname"`
 	Lastname  string `json:"lastname"`
 	Username  string `json:"username"`
+	Bio       string `json:"bio"`
 }
 
 type UpdateUserPasswordRequest struct {
@@ -32,9 +40,7 @@ type UpdateUserPasswordRequest struct {
 }
 
 type UpdateUserEmailRequest struct {
-	Email            string `json:"email"`
-	VerificationCode string `json:"-"`
-	Verified         bool   `json:"-"`
+	Email string `json:"email"`
 }
 
 type UserUpdater interface {
@@ -68,6 +74,10 @@ func UpdateUserVerification(userId string, user d.UserInterface, logger *logrus.
 }
 
 func UpdateUserPersonalData(request UpdatePersonalDataRequest, userId string, user d.UserInterface, logger *logrus.Logger) (*m.User, *e.ErrorResponse) {
+	if utf8.RuneCountInString(request.Bio) > maxBioLength {
+		return nil, e.NewErrorResponse(e.HttpBadRequest, fmt.Sprintf("Bio must be at most %d characters.", maxBioLength))
+	}
+
 	updatedUser, dbErr := user.RawUpdate(userId, request)
 
 	if dbErr != nil {
@@ -78,12 +88,17 @@ func UpdateUserPersonalData(request UpdatePersonalDataRequest, userId string, us
 	return updatedUser, nil
 }
 
-func UpdateUserPassword(request UpdateUserPasswordRequest, existUser *m.User, user d.UserInterface, logger *logrus.Logger) *e.ErrorResponse {
+func UpdateUserPassword(request UpdateUserPasswordRequest, existUser *m.User, sessionId string, user d.UserInterface, auth adapter.AuthGrpcInterface, logger *logrus.Logger) *e.ErrorResponse {
 	if err := bcrypt.CompareHashAndPassword([]byte(existUser.Password), []byte(request.OldPassword)); err != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Update user password")
 		return e.NewErrorResponse(e.HttpBadRequest, err.Error())
 	}
 
+	if err := auth.ValidatePassword(request.Password, existUser.Username, existUser.Email); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("Update user password")
+		return err
+	}
+
 	hash, _ := bcrypt.GenerateFromPassword([]byte(request.Password), 12)
 	request.Password = string(hash)
 
@@ -92,22 +107,44 @@ func UpdateUserPassword(request UpdateUserPasswordRequest, existUser *m.User, us
 		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
 
+	// Остальные устройства должны войти заново с новым паролем, текущая сессия остаётся
+	if err := auth.RevokeSessions(existUser.ID.String(), sessionId); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.ErrorMessage}).Info("Update user password")
+		return err
+	}
+
 	return nil
 }
 
-func UpdateUserEmail(request UpdateUserEmailRequest, userId string, user d.UserInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) *e.ErrorResponse {
-	key, err := generateKey(64)
+// Новая почта сохраняется отдельно и попадает в профиль только после перехода по ссылке из письма
+func UpdateUserEmail(request UpdateUserEmailRequest, userId string, user d.UserInterface, emailChange d.EmailChangeInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) *e.ErrorResponse {
+	if _, err := netmail.ParseAddress(request.Email); err != nil {
+		return e.NewErrorResponse(e.HttpBadRequest, "Invalid email address")
+	}
+
+	existUser, err := GetById(userId, user, logger)
 
 	if err != nil {
-		return e.NewErrorResponse(e.HttpInternalError, err.Error())
+		return err
+	}
+
+	if strings.EqualFold(existUser.Email, request.Email) {
+		return e.NewErrorResponse(e.HttpBadRequest, "This is already your email")
 	}
 
-	request.Verified = false
-	request.VerificationCode = key
+	key, keyErr := generateKey(64)
 
-	existUser, dbErr := user.RawUpdate(userId, request)
+	if keyErr != nil {
+		return e.NewErrorResponse(e.HttpInternalError, keyErr.Error())
+	}
 
-	if dbErr != nil {
+	change := &m.EmailChange{
+		UserId:    existUser.ID,
+		NewEmail:  request.Email,
+		TokenHash: hashKey(key),
+	}
+
+	if dbErr := emailChange.Replace(change); dbErr != nil {
 		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Update email")
 		return e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
 	}
@@ -124,7 +161,7 @@ func UpdateUserEmail(request UpdateUserEmailRequest, userId string, user d.UserI
       Если изменение электронной почты больше не требуется, или вы не делали этот запрос - проигнорируйте данное письмо.
       
       WarehouseAI Team
-      `, existUser.Firstname, existUser.Username, fmt.Sprintf("%s/api/user/verify/%s", os.Getenv("DOMAIN"), key)),
+      `, existUser.Firstname, existUser.Username, fmt.Sprintf("%s/api/user/email/confirm?token=%s", os.Getenv("DOMAIN"), key)),
 	}
 
 	if err := mail.SendEmail(message); err != nil {
@@ -135,6 +172,67 @@ func UpdateUserEmail(request UpdateUserEmailRequest, userId string, user d.UserI
 	return nil
 }
 
+// Подтверждение новой почты по ссылке. Старый адрес получает уведомление о смене.
+// Возвращает id пользователя для журнала безопасности.
+func ConfirmUserEmail(key string, user d.UserInterface, emailChange d.EmailChangeInterface, mail adapter.MailProducerInterface, logger *logrus.Logger) (string, *e.ErrorResponse) {
+	if key == "" {
+		return "", e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link")
+	}
+
+	change, dbErr := emailChange.GetByToken(hashKey(key))
+
+	if dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Confirm email")
+
+		if dbErr.ErrorType == e.DbNotFound {
+			return "", e.NewErrorResponse(e.HttpBadRequest, "Invalid or expired confirmation link")
+		}
+
+		return "", e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	existUser, err := GetById(change.UserId.String(), user, logger)
+
+	if err != nil {
+		return "", err
+	}
+
+	// Новая почта подтверждена ссылкой, поэтому пользователь остаётся верифицированным
+	if dbErr := user.Update(existUser.ID.String(), map[string]interface{}{"email": change.NewEmail, "verified": true}); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Confirm email")
+
+		if dbErr.ErrorType == e.DbExist {
+			return "", e.NewErrorResponse(e.HttpAlreadyExist, "Email is already in use")
+		}
+
+		return "", e.NewErrorResponseFromDBError(dbErr.ErrorType, dbErr.Message)
+	}
+
+	if dbErr := emailChange.Delete(map[string]interface{}{"id": change.ID}); dbErr != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": dbErr.Payload}).Info("Confirm email")
+	}
+
+	message := model.Email{
+		To:      existUser.Email,
+		Subject: "Электронная почта изменена",
+		Message: fmt.Sprintf(`
+      Здравствуйте, %s!
+      
+      Электронная почта аккаунта %s изменена на %s.
+      
+      Если вы не делали этот запрос - срочно восстановите доступ к аккаунту и смените пароль.
+      
+      WarehouseAI Team
+      `, existUser.Firstname, existUser.Username, change.NewEmail),
+	}
+
+	if err := mail.SendEmail(message); err != nil {
+		logger.WithFields(logrus.Fields{"time": time.Now(), "error": err.Error()}).Info("Send email")
+	}
+
+	return existUser.ID.String(), nil
+}
+
 func generateKey(length int) (string, error) {
 	randomBytes := make([]byte, length)
 
@@ -147,3 +245,10 @@ func generateKey(length int) (string, error) {
 
 	return key, nil
 }
+
+// В базе хранится только хеш ссылки
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}